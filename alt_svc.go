@@ -0,0 +1,266 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultAltSvcMaxAge is the default freshness lifetime of the alternative
+// service, see RFC 7838 section 3.1
+const defaultAltSvcMaxAge = 24 * time.Hour
+
+var hdrAltSvcKey = http.CanonicalHeaderKey("Alt-Svc")
+
+// AltSvc struct is the alternative service advertised by the origin via the
+// `Alt-Svc` response header, see [RFC 7838].
+//
+// [RFC 7838]: https://datatracker.ietf.org/doc/html/rfc7838
+type AltSvc struct {
+	// Protocol is the ALPN protocol ID, e.g., `h2`, `h3`
+	Protocol string
+
+	// Authority is the `host:port` of the alternative service, the empty
+	// host denotes the origin host
+	Authority string
+
+	// MaxAge is the freshness lifetime, default value is `24` hours
+	MaxAge time.Duration
+}
+
+// ParseAltSvc function parses the `Alt-Svc` header value in the order of
+// preference; clear is true if the value is `clear`, i.e., all the
+// alternative services of the origin are invalidated.
+//
+//	alts, clear := resty.ParseAltSvc(`h3=":443"; ma=86400, h2="alt.example.com:8443"`)
+func ParseAltSvc(v string) (alts []AltSvc, clear bool) {
+	v = strings.TrimSpace(v)
+	if strings.EqualFold(v, "clear") {
+		return nil, true
+	}
+
+	for _, value := range strings.Split(v, ",") {
+		parts := strings.Split(value, ";")
+		protocol, authority, found := strings.Cut(strings.TrimSpace(parts[0]), "=")
+		if !found {
+			continue
+		}
+		alt := AltSvc{
+			Protocol:  strings.TrimSpace(protocol),
+			Authority: strings.Trim(strings.TrimSpace(authority), `"`),
+			MaxAge:    defaultAltSvcMaxAge,
+		}
+		if _, _, err := net.SplitHostPort(alt.Authority); err != nil {
+			continue
+		}
+		for _, p := range parts[1:] {
+			k, pv, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "ma") {
+				if ma, err := strconv.ParseInt(strings.Trim(pv, `"`), 10, 64); err == nil && ma >= 0 {
+					alt.MaxAge = time.Duration(ma) * time.Second
+				}
+			}
+		}
+		alts = append(alts, alt)
+	}
+	return alts, false
+}
+
+// NewAltSvcCache function creates the new, empty alternative services cache.
+func NewAltSvcCache() *AltSvcCache {
+	return &AltSvcCache{entries: make(map[string][]altSvcEntry)}
+}
+
+// AltSvcCache struct caches the alternative services per origin with their
+// lifetimes, see [Client.SetAltSvcCache]. The origin is the `host:port`
+// of the HTTPS request URL, e.g., `example.com:443`.
+type AltSvcCache struct {
+	lock    sync.RWMutex
+	entries map[string][]altSvcEntry
+}
+
+// Update method replaces the alternative services of the origin with the ones
+// advertised in the given `Alt-Svc` header values, if any.
+func (c *AltSvcCache) Update(origin string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	origin = strings.ToLower(origin)
+	now := time.Now()
+	var entries []altSvcEntry
+	for _, v := range values {
+		alts, clear := ParseAltSvc(v)
+		if clear {
+			c.Remove(origin)
+			return
+		}
+		for _, alt := range alts {
+			if alt.MaxAge <= 0 {
+				continue
+			}
+			entries = append(entries, altSvcEntry{
+				protocol: alt.Protocol,
+				addr:     resolveAltSvcAuthority(origin, alt.Authority),
+				expires:  now.Add(alt.MaxAge),
+			})
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(entries) == 0 {
+		delete(c.entries, origin)
+		return
+	}
+	c.entries[origin] = entries
+}
+
+// Lookup method returns the `host:port` and protocol of the most preferred,
+// fresh alternative service of the origin among the given protocols.
+//
+//	addr, protocol, found := cache.Lookup("example.com:443", "h3")
+func (c *AltSvcCache) Lookup(origin string, protocols ...string) (addr, protocol string, found bool) {
+	origin = strings.ToLower(origin)
+	now := time.Now()
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, e := range c.entries[origin] {
+		if now.Before(e.expires) && slices.Contains(protocols, e.protocol) {
+			return e.addr, e.protocol, true
+		}
+	}
+	return "", "", false
+}
+
+// Remove method removes the alternative services of the origin for the given
+// protocols, or all of them if none given, e.g., the alternative is broken.
+func (c *AltSvcCache) Remove(origin string, protocols ...string) {
+	origin = strings.ToLower(origin)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(protocols) > 0 {
+		entries := slices.DeleteFunc(slices.Clone(c.entries[origin]), func(e altSvcEntry) bool {
+			return slices.Contains(protocols, e.protocol)
+		})
+		if len(entries) > 0 {
+			c.entries[origin] = entries
+			return
+		}
+	}
+	delete(c.entries, origin)
+}
+
+// Clear method removes all the alternative services from the cache.
+func (c *AltSvcCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	clear(c.entries)
+}
+
+// AltSvcCache method returns the alternative services cache from the client instance.
+func (c *Client) AltSvcCache() *AltSvcCache {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.dialer == nil {
+		return nil
+	}
+	return c.dialer.altSvc
+}
+
+// SetAltSvcCache method sets the alternative services cache into the client.
+// The `Alt-Svc` headers of the HTTPS responses are cached per origin, and the
+// subsequent connections to the origin are dialed to its advertised `h2` or
+// `http/1.1` alternative; the origin is dialed if the alternative fails. The
+// TLS server name and `Host` header remain the origin host.
+//
+// It is enabled by default for the clients created with the Resty transport;
+// the nil value disables it.
+//
+//	client.SetAltSvcCache(nil) // opt-out
+//
+// NOTE: The `h3` alternatives are used by the HTTP/3 transport of the
+// `github.com/rockcookies/go-resty/http3` module, which shares this cache.
+func (c *Client) SetAltSvcCache(cache *AltSvcCache) *Client {
+	return c.updateDialer(func(d *transportDialer) {
+		d.altSvc = cache
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// altSvcProtocols are the alternative service protocols dialed over TCP
+var altSvcProtocols = []string{"h2", "http/1.1"}
+
+type altSvcEntry struct {
+	protocol string
+	addr     string
+	expires  time.Time
+}
+
+// resolveAltSvcAuthority function returns the alternative authority as
+// `host:port`; the empty host denotes the same host as the origin.
+func resolveAltSvcAuthority(origin, authority string) string {
+	host, port, _ := net.SplitHostPort(authority)
+	if host == "" {
+		host, _, _ = net.SplitHostPort(origin)
+	}
+	return net.JoinHostPort(strings.ToLower(host), port)
+}
+
+// altSvcOrigin function returns the origin `host:port` of the HTTPS URL;
+// otherwise, it is empty.
+func altSvcOrigin(u *url.URL) string {
+	if u == nil || !strings.EqualFold(u.Scheme, "https") {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(strings.ToLower(u.Hostname()), port)
+}
+
+// updateAltSvc method caches the alternative services advertised in the
+// HTTPS response, if enabled.
+func (c *Client) updateAltSvc(res *http.Response) {
+	if res == nil || res.TLS == nil || res.Request == nil {
+		return
+	}
+	values := res.Header.Values(hdrAltSvcKey)
+	if len(values) == 0 {
+		return
+	}
+	if cache := c.AltSvcCache(); cache != nil {
+		if origin := altSvcOrigin(res.Request.URL); origin != "" {
+			cache.Update(origin, values...)
+		}
+	}
+}
+
+// dialAltSvc method dials the advertised alternative service of the origin,
+// if any; the alternative is removed from the cache on failure.
+func (d *transportDialer) dialAltSvc(ctx context.Context, network, addr string) (net.Conn, bool) {
+	alt, protocol, found := d.altSvc.Lookup(addr, altSvcProtocols...)
+	if !found || alt == strings.ToLower(addr) {
+		return nil, false
+	}
+	conn, err := d.dialAddr(ctx, network, alt)
+	if err != nil {
+		d.altSvc.Remove(addr, protocol)
+		return nil, false
+	}
+	return conn, true
+}
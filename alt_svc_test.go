@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseAltSvc(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		alts  []AltSvc
+		clear bool
+	}{
+		{value: "clear", clear: true},
+		{value: `h3=":443"`, alts: []AltSvc{{"h3", ":443", defaultAltSvcMaxAge}}},
+		{
+			value: `h3="alt.example.com:8443"; ma=3600; persist=1, h2=":443"; ma="60"`,
+			alts: []AltSvc{
+				{"h3", "alt.example.com:8443", time.Hour},
+				{"h2", ":443", time.Minute},
+			},
+		},
+		{value: `h3=443, invalid`},
+	} {
+		alts, clear := ParseAltSvc(tc.value)
+		assertEqual(t, tc.clear, clear)
+		assertEqual(t, tc.alts, alts)
+	}
+
+	assertEqual(t, "example.com:8443", resolveAltSvcAuthority("example.com:443", ":8443"))
+	assertEqual(t, "alt.example.com:443", resolveAltSvcAuthority("example.com:443", "Alt.Example.com:443"))
+}
+
+func TestAltSvcCache(t *testing.T) {
+	cache := NewAltSvcCache()
+	origin := "Example.com:443"
+
+	cache.Update(origin, `h3=":8443", h2="alt.example.com:443"; ma=60`)
+	addr, protocol, found := cache.Lookup("example.com:443", "h2", "http/1.1")
+	assertEqual(t, true, found)
+	assertEqual(t, "alt.example.com:443", addr)
+	assertEqual(t, "h2", protocol)
+	addr, _, _ = cache.Lookup(origin, "h3")
+	assertEqual(t, "example.com:8443", addr)
+
+	cache.Remove(origin, "h2")
+	_, _, found = cache.Lookup(origin, "h2")
+	assertEqual(t, false, found)
+	_, _, found = cache.Lookup(origin, "h3")
+	assertEqual(t, true, found)
+
+	// new advertisement replaces the previous one
+	cache.Update(origin, `h2=":8443"`)
+	_, _, found = cache.Lookup(origin, "h3")
+	assertEqual(t, false, found)
+
+	cache.Update(origin, `h2=":8443"; ma=0`)
+	_, _, found = cache.Lookup(origin, "h2")
+	assertEqual(t, false, found)
+
+	cache.Update(origin, `h2=":8443"`)
+	cache.Update(origin, "clear")
+	_, _, found = cache.Lookup(origin, "h2")
+	assertEqual(t, false, found)
+
+	cache.Update(origin, `h2=":8443"`)
+	cache.Clear()
+	_, _, found = cache.Lookup(origin, "h2")
+	assertEqual(t, false, found)
+
+	for u, expected := range map[string]string{
+		"https://EXAMPLE.com/path":  "example.com:443",
+		"https://example.com:8443/": "example.com:8443",
+		"http://example.com/":       "",
+	} {
+		pu, _ := url.Parse(u)
+		assertEqual(t, expected, altSvcOrigin(pu))
+	}
+}
+
+func TestClientAltSvc(t *testing.T) {
+	alt := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "alternative")
+	}))
+	defer alt.Close()
+	_, altPort, _ := net.SplitHostPort(alt.Listener.Addr().String())
+
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(hdrAltSvcKey, `h3=":1", h2=":`+altPort+`"; ma=60`)
+		_, _ = io.WriteString(w, "origin")
+	}))
+	defer origin.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(origin.Certificate())
+	c := New().
+		SetTLSClientConfig(&tls.Config{RootCAs: pool}).
+		SetCloseConnection(true)
+	defer c.Close()
+	assertNotNil(t, c.AltSvcCache())
+
+	res, err := c.R().Get(origin.URL)
+	assertNil(t, err)
+	assertEqual(t, "origin", res.String())
+
+	res, err = c.R().Get(origin.URL)
+	assertNil(t, err)
+	assertEqual(t, "alternative", res.String())
+
+	// origin is dialed on the alternative failure
+	alt.Close()
+	res, err = c.R().Get(origin.URL)
+	assertNil(t, err)
+	assertEqual(t, "origin", res.String())
+
+	t.Run("disabled", func(t *testing.T) {
+		c := New().
+			SetTLSClientConfig(&tls.Config{RootCAs: pool}).
+			SetCloseConnection(true).
+			SetAltSvcCache(nil)
+		defer c.Close()
+		assertNil(t, c.AltSvcCache())
+
+		for i := 0; i < 2; i++ {
+			res, err := c.R().Get(origin.URL)
+			assertNil(t, err)
+			assertEqual(t, "origin", res.String())
+		}
+	})
+
+	t.Run("custom transport", func(t *testing.T) {
+		c := NewWithClient(&http.Client{})
+		assertNil(t, c.AltSvcCache())
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"fmt"
+)
+
+// AsyncResponse type is the promise-like handle of the request executed
+// asynchronously, see [Request.Async].
+type AsyncResponse struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	res    *Response
+	err    error
+}
+
+// Async method sends the request asynchronously using the method and URL
+// already defined for current [Request], see [Request.Send]. It returns
+// immediately with the [AsyncResponse] handle, so the caller can launch many
+// requests and gather the results without the goroutine and [sync.WaitGroup]
+// boilerplate.
+//
+//	handles := make([]*resty.AsyncResponse, 0, len(ids))
+//	for _, id := range ids {
+//		handles = append(handles, client.R().
+//			SetMethod(resty.MethodGet).
+//			SetURL("/users/{id}").
+//			SetPathParam("id", id).
+//			SetResult(&User{}).
+//			Async())
+//	}
+//
+//	for _, h := range handles {
+//		res, err := h.Result() // blocks until the request is done
+//		// ...
+//	}
+//
+// NOTE:
+//   - The request must not be modified or reused until it is done.
+//   - The request panic is returned as an error by [AsyncResponse.Result].
+//   - The request context is canceled once the request is done, unless
+//     [Request.SetDoNotParseResponse] or [Request.SetResponseBodyStream] is
+//     used; then it is canceled only via [AsyncResponse.Cancel].
+func (r *Request) Async() *AsyncResponse {
+	ctx, cancel := context.WithCancel(r.Context())
+	r.SetContext(ctx)
+	ar := &AsyncResponse{done: make(chan struct{}), cancel: cancel}
+
+	go func() {
+		defer close(ar.done)
+		defer func() {
+			if rec := recover(); rec != nil {
+				ar.err = fmt.Errorf("resty: async request panic: %v", rec)
+			}
+		}()
+
+		ar.res, ar.err = r.Send()
+		if !r.DoNotParseResponse && !r.responseBodyStream {
+			cancel()
+		}
+	}()
+	return ar
+}
+
+// Done method returns the channel that is closed once the request is done,
+// successfully or not.
+//
+//	select {
+//	case <-handle.Done():
+//		res, err := handle.Result()
+//	case <-time.After(time.Second):
+//		handle.Cancel()
+//	}
+func (ar *AsyncResponse) Done() <-chan struct{} {
+	return ar.done
+}
+
+// Result method waits for the request to be done and returns its response and
+// error, the same as [Request.Send] would. It can be called multiple times.
+func (ar *AsyncResponse) Result() (*Response, error) {
+	<-ar.done
+	return ar.res, ar.err
+}
+
+// Cancel method cancels the request context; the in-flight request is aborted
+// with the [context.Canceled] error. It has no effect once the request is done,
+// except for the response body streaming.
+func (ar *AsyncResponse) Cancel() {
+	ar.cancel()
+}
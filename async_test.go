@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestAsync(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	t.Run("gather results", func(t *testing.T) {
+		handles := make([]*AsyncResponse, 0, 5)
+		for i := range 5 {
+			handles = append(handles, c.R().
+				SetMethod(MethodGet).
+				SetURL("/users/{id}").
+				SetPathParam("id", strconv.Itoa(i)).
+				Async())
+		}
+
+		for i, h := range handles {
+			<-h.Done()
+			res, err := h.Result()
+			assertNil(t, err)
+			assertEqual(t, "/users/"+strconv.Itoa(i), res.String())
+
+			// result can be read again
+			res2, _ := h.Result()
+			assertEqual(t, true, res == res2)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		h := c.R().SetMethod(MethodGet).SetURL("/slow").Async()
+		select {
+		case <-h.Done():
+			t.Fatal("request done before cancel")
+		case <-time.After(50 * time.Millisecond):
+			h.Cancel()
+		}
+		_, err := h.Result()
+		assertEqual(t, true, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		pc := dcnl().SetBaseURL(ts.URL).
+			AddRequestMiddleware(func(*Client, *Request) error {
+				panic("boom")
+			})
+		defer pc.Close()
+
+		_, err := pc.R().SetMethod(MethodGet).SetURL("/").Async().Result()
+		assertNotNil(t, err)
+		assertEqual(t, true, strings.Contains(err.Error(), "resty: async request panic: boom"))
+	})
+}
@@ -0,0 +1,791 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheMaxEntries   = 1000
+	defaultCacheMaxEntrySize = 1 << 20 // 1 MiB
+)
+
+var (
+	hdrAgeKey             = http.CanonicalHeaderKey("Age")
+	hdrDateKey            = http.CanonicalHeaderKey("Date")
+	hdrETagKey            = http.CanonicalHeaderKey("ETag")
+	hdrExpiresKey         = http.CanonicalHeaderKey("Expires")
+	hdrIfModifiedSinceKey = http.CanonicalHeaderKey("If-Modified-Since")
+	hdrIfNoneMatchKey     = http.CanonicalHeaderKey("If-None-Match")
+	hdrLastModifiedKey    = http.CanonicalHeaderKey("Last-Modified")
+	hdrLocationKey        = http.CanonicalHeaderKey("Location")
+	hdrContentLocationKey = http.CanonicalHeaderKey("Content-Location")
+	hdrVaryKey            = http.CanonicalHeaderKey("Vary")
+)
+
+// ErrCacheMiss error is returned when the request with [CacheModeCacheOnly] is
+// not found in the response cache.
+var ErrCacheMiss = errors.New("resty: cache miss")
+
+// CacheMode type represents how the request interacts with the client response
+// cache, similar to the Fetch API cache modes.
+//
+// See [Request.SetCacheMode]
+type CacheMode uint8
+
+// Response cache modes
+const (
+	// CacheModeDefault follows the HTTP caching semantics.
+	CacheModeDefault CacheMode = iota
+
+	// CacheModeCacheOnly serves the request only from the cache regardless of
+	// its freshness, never goes to the network. It returns [ErrCacheMiss]
+	// if the response is not in the cache.
+	CacheModeCacheOnly
+
+	// CacheModeNetworkOnly always goes to the network, the cache is not
+	// consulted; however, the response is stored in the cache.
+	CacheModeNetworkOnly
+
+	// CacheModeCacheFirst serves the request from the cache regardless of its
+	// freshness if present; otherwise, it goes to the network.
+	CacheModeCacheFirst
+)
+
+// CacheStatus type represents how the [Response] relates to the client
+// response cache.
+//
+// See [Response.CacheStatus]
+type CacheStatus string
+
+// Response cache statuses
+const (
+	// CacheStatusNone means the response cache was not consulted, either it is
+	// not enabled or the request is not cacheable.
+	CacheStatusNone CacheStatus = ""
+
+	// CacheStatusMiss means the response was not found in the cache and
+	// was served from the origin.
+	CacheStatusMiss CacheStatus = "MISS"
+
+	// CacheStatusHit means the response was served from the cache while fresh.
+	CacheStatusHit CacheStatus = "HIT"
+
+	// CacheStatusStale means a stale cached response was served, either per
+	// `stale-while-revalidate` or `stale-if-error` Cache-Control extensions.
+	CacheStatusStale CacheStatus = "STALE"
+
+	// CacheStatusRevalidated means the cached response was validated with the
+	// origin (304 Not Modified) and served from the cache.
+	CacheStatusRevalidated CacheStatus = "REVALIDATED"
+)
+
+// String method returns the string value of the cache status.
+func (cs CacheStatus) String() string {
+	return string(cs)
+}
+
+// CacheEvent type represents the event that occurred in the [Cache].
+//
+// See [Cache.SetOnEvent]
+type CacheEvent string
+
+// Response cache events
+const (
+	// CacheEventHit is emitted when a fresh response is served from the cache.
+	CacheEventHit CacheEvent = "hit"
+
+	// CacheEventStale is emitted when a stale response is served from the cache.
+	CacheEventStale CacheEvent = "stale"
+
+	// CacheEventMiss is emitted when a cacheable request is served from the origin.
+	CacheEventMiss CacheEvent = "miss"
+
+	// CacheEventRevalidated is emitted when a cached response is validated
+	// with the origin.
+	CacheEventRevalidated CacheEvent = "revalidated"
+
+	// CacheEventStored is emitted when a response is stored in the cache.
+	CacheEventStored CacheEvent = "stored"
+
+	// CacheEventEvicted is emitted when a response is removed from the cache
+	// to make room, per max entries limit.
+	CacheEventEvicted CacheEvent = "evicted"
+
+	// CacheEventInvalidated is emitted when a response is removed from the
+	// cache by invalidation.
+	CacheEventInvalidated CacheEvent = "invalidated"
+)
+
+// CacheEventFunc type is for the [Cache] event callback, it receives the
+// event and the cache key of the response.
+type CacheEventFunc func(event CacheEvent, key string)
+
+// CacheKeyFunc type is for the cache key computation function of the request.
+//
+// See [Cache.SetKeyFunc]
+type CacheKeyFunc func(r *http.Request) string
+
+// Cache struct is an in-memory, private HTTP response cache for the Resty
+// client. It honors the response `Cache-Control` (`max-age`, `no-cache`,
+// `no-store`, `stale-while-revalidate`, `stale-if-error`), `Expires`, `Age`,
+// and `Vary` headers, and revalidates the stale entries using `ETag` and
+// `Last-Modified` validators. Only the `GET` requests are cached.
+//
+// Entries are evicted in least-recently-used order once the max entries
+// limit is reached.
+//
+//	client.SetCache(resty.NewCache().SetMaxEntries(500))
+type Cache struct {
+	lock         *sync.Mutex
+	maxEntries   int
+	maxEntrySize int64
+	keyFunc      CacheKeyFunc
+	onEvent      CacheEventFunc
+	entries      map[string]*list.Element
+	lru          *list.List
+	revalidating map[string]struct{}
+}
+
+// NewCache method creates a new [Cache] with default settings.
+//
+// The default settings are:
+//   - MaxEntries: 1000
+//   - MaxEntrySize: 1 MiB
+func NewCache() *Cache {
+	return &Cache{
+		lock:         &sync.Mutex{},
+		maxEntries:   defaultCacheMaxEntries,
+		maxEntrySize: defaultCacheMaxEntrySize,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+		revalidating: make(map[string]struct{}),
+	}
+}
+
+// SetMaxEntries method sets the maximum number of responses held by the [Cache].
+// The least recently used entry is evicted once the limit is reached.
+func (ch *Cache) SetMaxEntries(n int) *Cache {
+	ch.lock.Lock()
+	ch.maxEntries = n
+	evicted := ch.evict()
+	ch.lock.Unlock()
+	ch.emit(CacheEventEvicted, evicted...)
+	return ch
+}
+
+// SetMaxEntrySize method sets the maximum response body size in bytes that
+// can be stored in the [Cache]; bigger responses are not cached.
+func (ch *Cache) SetMaxEntrySize(size int64) *Cache {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	ch.maxEntrySize = size
+	return ch
+}
+
+// SetKeyFunc method sets the function used to compute the cache key of the
+// request. The default cache key is the request method and the URL. It is
+// helpful to include the auth principal into the key or to ignore certain
+// query parameters.
+//
+//	cache.SetKeyFunc(func(r *http.Request) string {
+//		u := *r.URL
+//		q := u.Query()
+//		q.Del("_ts") // cache busting param
+//		u.RawQuery = q.Encode()
+//		return r.Header.Get("X-User-Id") + " " + r.Method + " " + u.String()
+//	})
+func (ch *Cache) SetKeyFunc(fn CacheKeyFunc) *Cache {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	ch.keyFunc = fn
+	return ch
+}
+
+// SetOnEvent method sets the callback function that gets called on each
+// [Cache] event, which is helpful for metrics and to verify the cache is
+// actually saving traffic.
+//
+//	cache.SetOnEvent(func(event resty.CacheEvent, key string) {
+//		cacheEvents.WithLabelValues(string(event)).Inc()
+//	})
+func (ch *Cache) SetOnEvent(fn CacheEventFunc) *Cache {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	ch.onEvent = fn
+	return ch
+}
+
+// Invalidate method removes the cached responses whose request URL matches the
+// given pattern and returns the number of removed responses. In the pattern, `*`
+// matches any sequence of characters; otherwise, the URL must be equal.
+//
+//	client.Cache().Invalidate("https://api.example.com/users/*")
+//
+// NOTE: Resty automatically invalidates the cached responses of the URL, when a
+// non-safe method request (e.g., POST, PUT, PATCH, DELETE) on it succeeds.
+func (ch *Cache) Invalidate(pattern string) int {
+	ch.lock.Lock()
+	removed := make([]string, 0)
+	for key, el := range ch.entries {
+		if matchCachePattern(pattern, el.Value.(*cacheEntry).url) {
+			ch.lru.Remove(el)
+			delete(ch.entries, key)
+			removed = append(removed, key)
+		}
+	}
+	ch.lock.Unlock()
+	ch.emit(CacheEventInvalidated, removed...)
+	return len(removed)
+}
+
+// Len method returns the number of responses held by the [Cache].
+func (ch *Cache) Len() int {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	return ch.lru.Len()
+}
+
+// Clear method removes all the responses from the [Cache].
+func (ch *Cache) Clear() {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	clear(ch.entries)
+	ch.lru.Init()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//_______________________________________________________________________
+
+type cacheEntry struct {
+	key          string
+	url          string
+	statusCode   int
+	status       string
+	proto        string
+	header       http.Header
+	body         []byte
+	vary         http.Header
+	requestTime  time.Time
+	responseTime time.Time
+}
+
+func (ch *Cache) get(key string) *cacheEntry {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	if el, found := ch.entries[key]; found {
+		ch.lru.MoveToFront(el)
+		return el.Value.(*cacheEntry)
+	}
+	return nil
+}
+
+func (ch *Cache) set(e *cacheEntry) {
+	ch.lock.Lock()
+	if el, found := ch.entries[e.key]; found {
+		el.Value = e
+		ch.lru.MoveToFront(el)
+		ch.lock.Unlock()
+		return
+	}
+	ch.entries[e.key] = ch.lru.PushFront(e)
+	evicted := ch.evict()
+	ch.lock.Unlock()
+	ch.emit(CacheEventEvicted, evicted...)
+}
+
+// evict method must be called with the lock held, it returns the evicted keys
+func (ch *Cache) evict() []string {
+	var evicted []string
+	for ch.maxEntries > 0 && ch.lru.Len() > ch.maxEntries {
+		el := ch.lru.Back()
+		ch.lru.Remove(el)
+		key := el.Value.(*cacheEntry).key
+		delete(ch.entries, key)
+		evicted = append(evicted, key)
+	}
+	return evicted
+}
+
+// emit method calls the event callback for each given key, it must be
+// called without the lock held.
+func (ch *Cache) emit(event CacheEvent, keys ...string) {
+	ch.lock.Lock()
+	fn := ch.onEvent
+	ch.lock.Unlock()
+	if fn == nil {
+		return
+	}
+	for _, key := range keys {
+		fn(event, key)
+	}
+}
+
+func (ch *Cache) key(r *http.Request) string {
+	ch.lock.Lock()
+	keyFunc := ch.keyFunc
+	ch.lock.Unlock()
+	if keyFunc != nil {
+		return keyFunc(r)
+	}
+	return r.Method + " " + r.URL.String()
+}
+
+// invalidateFor method removes the cached responses of the request URL and
+// the same origin `Location` and `Content-Location` URLs, see RFC 9111 section 4.4
+func (ch *Cache) invalidateFor(rawReq *http.Request, resp *http.Response) {
+	ch.Invalidate(rawReq.URL.String())
+	for _, hdr := range []string{hdrLocationKey, hdrContentLocationKey} {
+		v := resp.Header.Get(hdr)
+		if v == "" {
+			continue
+		}
+		u, err := rawReq.URL.Parse(v)
+		if err != nil || u.Host != rawReq.URL.Host {
+			continue
+		}
+		ch.Invalidate(u.String())
+	}
+}
+
+func (ch *Cache) startRevalidation(key string) bool {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	if _, found := ch.revalidating[key]; found {
+		return false
+	}
+	ch.revalidating[key] = struct{}{}
+	return true
+}
+
+func (ch *Cache) endRevalidation(key string) {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	delete(ch.revalidating, key)
+}
+
+// do method executes the request through the cache, it returns the
+// response served either from the cache or from the origin.
+func (ch *Cache) do(c *Client, req *Request) (*http.Response, CacheStatus, error) {
+	rawReq := req.RawRequest
+	reqCC := parseCacheControl(rawReq.Header)
+	_, noStore := reqCC["no-store"]
+	if req.cacheMode == CacheModeCacheOnly && (noStore || rawReq.Method != MethodGet) {
+		return nil, CacheStatusNone, ErrCacheMiss
+	}
+	hc, err := c.httpClientFor(req)
+	if err != nil {
+		return nil, CacheStatusNone, err
+	}
+
+	if !isSafeMethod(rawReq.Method) {
+		resp, err := c.roundTrip(req, hc)
+		if err == nil && resp.StatusCode < 400 {
+			ch.invalidateFor(rawReq, resp)
+		}
+		return resp, CacheStatusNone, err
+	}
+
+	// the partial content is not cached
+	if noStore || rawReq.Method != MethodGet || len(rawReq.Header.Get(hdrRangeKey)) > 0 {
+		resp, err := c.roundTrip(req, hc)
+		return resp, CacheStatusNone, err
+	}
+
+	key := ch.key(rawReq)
+	now := timeNow()
+	entry := ch.get(key)
+	if entry != nil && !entry.matchVary(rawReq.Header) {
+		entry = nil
+	}
+
+	switch req.cacheMode {
+	case CacheModeCacheOnly, CacheModeCacheFirst:
+		if entry != nil {
+			if entry.age(now) < entry.freshnessLifetime() {
+				ch.emit(CacheEventHit, key)
+				return entry.httpResponse(rawReq), CacheStatusHit, nil
+			}
+			ch.emit(CacheEventStale, key)
+			return entry.httpResponse(rawReq), CacheStatusStale, nil
+		}
+		if req.cacheMode == CacheModeCacheOnly {
+			ch.emit(CacheEventMiss, key)
+			return nil, CacheStatusMiss, ErrCacheMiss
+		}
+	case CacheModeNetworkOnly:
+		entry = nil
+	}
+
+	addedValidators := false
+	if entry != nil {
+		_, noCache := reqCC["no-cache"]
+		age := entry.age(now)
+		lifetime := entry.freshnessLifetime()
+		if !noCache && age < lifetime {
+			ch.emit(CacheEventHit, key)
+			return entry.httpResponse(rawReq), CacheStatusHit, nil
+		}
+		if !noCache && age < lifetime+entry.staleWhileRevalidate() {
+			ch.emit(CacheEventStale, key)
+			ch.revalidate(c, req, rawReq, entry)
+			return entry.httpResponse(rawReq), CacheStatusStale, nil
+		}
+		addedValidators = entry.addValidators(rawReq.Header)
+	}
+
+	resp, err := c.roundTrip(req, hc)
+	if entry != nil && (err != nil || resp.StatusCode > 499) &&
+		entry.age(timeNow()) < entry.freshnessLifetime()+entry.staleIfError() {
+		if resp != nil {
+			drainHTTPBody(resp)
+		}
+		ch.emit(CacheEventStale, key)
+		return entry.httpResponse(rawReq), CacheStatusStale, nil
+	}
+	if err != nil {
+		ch.emit(CacheEventMiss, key)
+		return resp, CacheStatusMiss, err
+	}
+
+	if entry != nil && addedValidators && resp.StatusCode == http.StatusNotModified {
+		drainHTTPBody(resp)
+		entry = entry.refresh(resp, now)
+		ch.set(entry)
+		ch.emit(CacheEventRevalidated, key)
+		return entry.httpResponse(rawReq), CacheStatusRevalidated, nil
+	}
+
+	ch.emit(CacheEventMiss, key)
+	ch.capture(key, rawReq, resp, now)
+	return resp, CacheStatusMiss, nil
+}
+
+// capture method wraps the response body to store the response in the cache
+// once it is read completely, if the response is cacheable.
+func (ch *Cache) capture(key string, rawReq *http.Request, resp *http.Response, requestTime time.Time) {
+	if !isCacheableResponse(resp) {
+		return
+	}
+
+	e := &cacheEntry{
+		key:          key,
+		url:          rawReq.URL.String(),
+		statusCode:   resp.StatusCode,
+		status:       resp.Status,
+		proto:        resp.Proto,
+		header:       resp.Header.Clone(),
+		vary:         varyRequestHeader(resp.Header, rawReq.Header),
+		requestTime:  requestTime,
+		responseTime: timeNow(),
+	}
+	if e.freshnessLifetime() == 0 && !e.hasValidators() && e.staleIfError() == 0 {
+		return
+	}
+
+	ch.lock.Lock()
+	maxSize := ch.maxEntrySize
+	ch.lock.Unlock()
+	if resp.ContentLength > maxSize {
+		return
+	}
+
+	resp.Body = &cacheCaptureReadCloser{
+		ReadCloser: resp.Body,
+		maxSize:    maxSize,
+		onEOF: func(b []byte) {
+			e.body = b
+			ch.set(e)
+			ch.emit(CacheEventStored, e.key)
+		},
+	}
+}
+
+// revalidate method refreshes the given stale entry in the background.
+func (ch *Cache) revalidate(c *Client, req *Request, rawReq *http.Request, entry *cacheEntry) {
+	if c.IsClosed() || !ch.startRevalidation(entry.key) {
+		return
+	}
+
+	// the request level transport and TLS settings apply to the refresh as well
+	hc, err := c.httpClientFor(req)
+	if err != nil {
+		ch.endRevalidation(entry.key)
+		req.log.Warnf("Cache revalidation failed for '%s': %v", rawReq.URL, err)
+		return
+	}
+
+	hr := rawReq.Clone(context.WithoutCancel(rawReq.Context()))
+	entry.addValidators(hr.Header)
+	go func() {
+		defer ch.endRevalidation(entry.key)
+		requestTime := timeNow()
+		resp, err := hc.Do(hr)
+		if err != nil {
+			req.log.Warnf("Cache revalidation failed for '%s': %v", hr.URL, err)
+			return
+		}
+		if resp.StatusCode == http.StatusNotModified {
+			drainHTTPBody(resp)
+			ch.set(entry.refresh(resp, requestTime))
+			ch.emit(CacheEventRevalidated, entry.key)
+			return
+		}
+		ch.capture(entry.key, hr, resp, requestTime)
+		drainHTTPBody(resp)
+	}()
+}
+
+func (e *cacheEntry) httpResponse(rawReq *http.Request) *http.Response {
+	header := e.header.Clone()
+	header.Set(hdrAgeKey, strconv.FormatInt(int64(e.age(timeNow())/time.Second), 10))
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         e.proto,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       rawReq,
+	}
+}
+
+// refresh method returns a copy of the entry updated with the headers of the
+// 304 Not Modified response.
+func (e *cacheEntry) refresh(resp *http.Response, requestTime time.Time) *cacheEntry {
+	ne := *e
+	ne.header = e.header.Clone()
+	for k, v := range resp.Header {
+		if k == hdrContentLengthKey {
+			continue
+		}
+		ne.header[k] = v
+	}
+	ne.requestTime = requestTime
+	ne.responseTime = timeNow()
+	return &ne
+}
+
+func (e *cacheEntry) hasValidators() bool {
+	return e.header.Get(hdrETagKey) != "" || e.header.Get(hdrLastModifiedKey) != ""
+}
+
+func (e *cacheEntry) addValidators(h http.Header) bool {
+	if h.Get(hdrIfNoneMatchKey) != "" || h.Get(hdrIfModifiedSinceKey) != "" {
+		return false
+	}
+	added := false
+	if v := e.header.Get(hdrETagKey); v != "" {
+		h.Set(hdrIfNoneMatchKey, v)
+		added = true
+	}
+	if v := e.header.Get(hdrLastModifiedKey); v != "" {
+		h.Set(hdrIfModifiedSinceKey, v)
+		added = true
+	}
+	return added
+}
+
+func (e *cacheEntry) matchVary(h http.Header) bool {
+	for k, v := range e.vary {
+		if strings.Join(h.Values(k), ",") != strings.Join(v, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// freshnessLifetime method computes the entry lifetime from the `max-age`
+// directive, falls back to `Expires` header; heuristic freshness is not used.
+func (e *cacheEntry) freshnessLifetime() time.Duration {
+	cc := parseCacheControl(e.header)
+	if _, found := cc["no-cache"]; found {
+		return 0
+	}
+	if v, found := cc["max-age"]; found {
+		return parseDeltaSeconds(v)
+	}
+	if v := e.header.Get(hdrExpiresKey); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0
+		}
+		date := e.date()
+		if expires.After(date) {
+			return expires.Sub(date)
+		}
+	}
+	return 0
+}
+
+func (e *cacheEntry) staleWhileRevalidate() time.Duration {
+	return parseDeltaSeconds(parseCacheControl(e.header)["stale-while-revalidate"])
+}
+
+func (e *cacheEntry) staleIfError() time.Duration {
+	return parseDeltaSeconds(parseCacheControl(e.header)["stale-if-error"])
+}
+
+func (e *cacheEntry) date() time.Time {
+	if d, err := http.ParseTime(e.header.Get(hdrDateKey)); err == nil {
+		return d
+	}
+	return e.responseTime
+}
+
+// age method computes the current age of the entry per RFC 9111 section 4.2.3
+func (e *cacheEntry) age(now time.Time) time.Duration {
+	apparentAge := max(0, e.responseTime.Sub(e.date()))
+	ageValue := parseDeltaSeconds(e.header.Get(hdrAgeKey))
+	correctedAge := ageValue + e.responseTime.Sub(e.requestTime)
+	return max(apparentAge, correctedAge) + now.Sub(e.responseTime)
+}
+
+// cacheCaptureReadCloser struct buffers the body while being read and calls
+// onEOF with the body bytes once completely read.
+type cacheCaptureReadCloser struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	maxSize  int64
+	overflow bool
+	onEOF    func([]byte)
+}
+
+func (cr *cacheCaptureReadCloser) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	if n > 0 && !cr.overflow {
+		if int64(cr.buf.Len()+n) > cr.maxSize {
+			cr.overflow = true
+			cr.buf = bytes.Buffer{}
+		} else {
+			cr.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !cr.overflow && cr.onEOF != nil {
+		cr.onEOF(bytes.Clone(cr.buf.Bytes()))
+		cr.onEOF = nil
+	}
+	return n, err
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case MethodGet, MethodHead, MethodOptions, MethodTrace:
+		return true
+	}
+	return false
+}
+
+// matchCachePattern method reports whether the value matches the pattern,
+// where `*` matches any sequence of characters.
+func matchCachePattern(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		idx := strings.Index(value, part)
+		if idx < 0 {
+			return false
+		}
+		value = value[idx+len(part):]
+	}
+	return len(value) >= len(parts[last]) && strings.HasSuffix(value, parts[last])
+}
+
+var cacheableStatusCodes = map[int]struct{}{
+	http.StatusOK:                   {},
+	http.StatusNonAuthoritativeInfo: {},
+	http.StatusNoContent:            {},
+	http.StatusMultipleChoices:      {},
+	http.StatusMovedPermanently:     {},
+	http.StatusPermanentRedirect:    {},
+	http.StatusNotFound:             {},
+	http.StatusMethodNotAllowed:     {},
+	http.StatusGone:                 {},
+	http.StatusRequestURITooLong:    {},
+	http.StatusNotImplemented:       {},
+}
+
+func isCacheableResponse(resp *http.Response) bool {
+	if _, found := cacheableStatusCodes[resp.StatusCode]; !found {
+		return false
+	}
+	if _, found := parseCacheControl(resp.Header)["no-store"]; found {
+		return false
+	}
+	return resp.Header.Get(hdrVaryKey) != "*"
+}
+
+func varyRequestHeader(respHeader, reqHeader http.Header) http.Header {
+	var vary http.Header
+	for _, v := range respHeader.Values(hdrVaryKey) {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if vary == nil {
+				vary = http.Header{}
+			}
+			vary[name] = reqHeader.Values(name)
+		}
+	}
+	return vary
+}
+
+// parseCacheControl method parses the `Cache-Control` header directives into
+// a map with lowercase directive names.
+func parseCacheControl(h http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, v := range h.Values(hdrCacheControlKey) {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(part, "=")
+			cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return cc
+}
+
+func parseDeltaSeconds(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	s, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || s < 0 {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
+
+func drainHTTPBody(resp *http.Response) {
+	if resp.Body != nil {
+		defer closeq(resp.Body)
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+}
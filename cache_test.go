@@ -0,0 +1,503 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testCacheTimeOffset(t *testing.T, d time.Duration) {
+	timeNow = func() time.Time {
+		return time.Now().Add(d)
+	}
+	t.Cleanup(func() {
+		timeNow = time.Now
+	})
+}
+
+func cacheRevalidating(ch *Cache) int {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	return len(ch.revalidating)
+}
+
+func TestCacheFreshHit(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = fmt.Fprintf(w, "response %d", n)
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	assertEqual(t, true, c.IsFeatureEnabled(FeatureResponseCache))
+
+	res1, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res1.CacheStatus())
+	assertEqual(t, false, res1.IsFromCache())
+	assertEqual(t, "response 1", res1.String())
+
+	res2, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusHit, res2.CacheStatus())
+	assertEqual(t, true, res2.IsFromCache())
+	assertEqual(t, false, res2.IsStale())
+	assertEqual(t, "response 1", res2.String())
+	assertEqual(t, int32(1), hits.Load())
+	assertEqual(t, 1, c.Cache().Len())
+
+	// non GET requests bypass the cache
+	res3, err := c.R().Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusNone, res3.CacheStatus())
+	assertEqual(t, int32(2), hits.Load())
+}
+
+func TestCacheFeatureDefaultInstance(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	res, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusNone, res.CacheStatus())
+	assertNil(t, c.Cache())
+
+	c.EnableFeature(FeatureResponseCache)
+	_, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertNotNil(t, c.Cache())
+	assertEqual(t, 1, c.Cache().Len())
+
+	c.DisableFeature(FeatureResponseCache)
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusNone, res.CacheStatus())
+}
+
+func TestCacheNoStore(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set(hdrCacheControlKey, "no-store, max-age=60")
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	for range 3 {
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusMiss, res.CacheStatus())
+	}
+	assertEqual(t, int32(3), hits.Load())
+	assertEqual(t, 0, c.Cache().Len())
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set(hdrCacheControlKey, "no-cache")
+		w.Header().Set(hdrETagKey, `"v1"`)
+		if r.Header.Get(hdrIfNoneMatchKey) == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("version 1"))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	res1, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res1.CacheStatus())
+
+	res2, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusRevalidated, res2.CacheStatus())
+	assertEqual(t, http.StatusOK, res2.StatusCode())
+	assertEqual(t, "version 1", res2.String())
+	assertEqual(t, int32(2), hits.Load())
+
+	// user supplied validators receives the origin response as-is
+	res3, err := c.R().SetHeader(hdrIfNoneMatchKey, `"v1"`).Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusNotModified, res3.StatusCode())
+	assertEqual(t, CacheStatusMiss, res3.CacheStatus())
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set(hdrDateKey, timeNow().UTC().Format(http.TimeFormat))
+		w.Header().Set(hdrCacheControlKey, "max-age=1, stale-while-revalidate=60")
+		w.Header().Set(hdrETagKey, `"v1"`)
+		if r.Header.Get(hdrIfNoneMatchKey) == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "response %d", n)
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	_, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+
+	testCacheTimeOffset(t, 10*time.Second)
+
+	res, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusStale, res.CacheStatus())
+	assertEqual(t, true, res.IsStale())
+	assertEqual(t, "response 1", res.String())
+
+	// wait for the background revalidation
+	for i := 0; i < 100 && hits.Load() < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEqual(t, int32(2), hits.Load())
+	for i := 0; i < 100 && cacheRevalidating(c.Cache()) > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusHit, res.CacheStatus())
+	assertEqual(t, "response 1", res.String())
+	assertEqual(t, int32(2), hits.Load())
+}
+
+func TestCacheStaleWhileRevalidateRequestTransport(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set(hdrDateKey, timeNow().UTC().Format(http.TimeFormat))
+		w.Header().Set(hdrCacheControlKey, "max-age=1, stale-while-revalidate=60")
+		w.Header().Set(hdrETagKey, `"v1"`)
+		if r.Header.Get(hdrIfNoneMatchKey) == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("response"))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	_, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+
+	testCacheTimeOffset(t, 10*time.Second)
+
+	rt := &countingRoundTripper{next: http.DefaultTransport}
+	res, err := c.R().SetTransport(rt).Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusStale, res.CacheStatus())
+
+	// wait for the background revalidation
+	for i := 0; i < 100 && (cacheRevalidating(c.Cache()) > 0 || hits.Load() < 2); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEqual(t, int32(2), hits.Load())
+	assertEqual(t, int32(1), rt.count.Load())
+}
+
+func TestCacheStaleIfError(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set(hdrCacheControlKey, "max-age=1, stale-if-error=30")
+		_, _ = w.Write([]byte("good response"))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	_, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+
+	t.Run("within stale-if-error window", func(t *testing.T) {
+		testCacheTimeOffset(t, 10*time.Second)
+
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+		assertEqual(t, CacheStatusStale, res.CacheStatus())
+		assertEqual(t, "good response", res.String())
+	})
+
+	t.Run("beyond stale-if-error window", func(t *testing.T) {
+		testCacheTimeOffset(t, time.Minute)
+
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, http.StatusServiceUnavailable, res.StatusCode())
+		assertEqual(t, CacheStatusMiss, res.CacheStatus())
+	})
+}
+
+func TestCacheVaryAndEviction(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		w.Header().Set(hdrVaryKey, "Accept-Language")
+		_, _ = w.Write([]byte(r.Header.Get("Accept-Language") + r.URL.Path))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache().SetMaxEntries(1))
+
+	res, err := c.R().SetHeader("Accept-Language", "en").Get(ts.URL + "/a")
+	assertNil(t, err)
+	assertEqual(t, "en/a", res.String())
+
+	res, err = c.R().SetHeader("Accept-Language", "fr").Get(ts.URL + "/a")
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res.CacheStatus())
+	assertEqual(t, "fr/a", res.String())
+
+	_, err = c.R().Get(ts.URL + "/b")
+	assertNil(t, err)
+	assertEqual(t, 1, c.Cache().Len())
+	assertEqual(t, int32(3), hits.Load())
+
+	c.Cache().Clear()
+	assertEqual(t, 0, c.Cache().Len())
+}
+
+func TestCacheKeyFunc(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = w.Write([]byte(r.Header.Get("X-User-Id")))
+	})
+	defer ts.Close()
+
+	cache := NewCache().SetKeyFunc(func(r *http.Request) string {
+		u := *r.URL
+		q := u.Query()
+		q.Del("_ts")
+		u.RawQuery = q.Encode()
+		return r.Header.Get("X-User-Id") + " " + r.Method + " " + u.String()
+	})
+	c := dcnl().SetCache(cache)
+
+	res, err := c.R().SetHeader("X-User-Id", "u1").SetQueryParam("_ts", "1").Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res.CacheStatus())
+
+	res, err = c.R().SetHeader("X-User-Id", "u1").SetQueryParam("_ts", "2").Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusHit, res.CacheStatus())
+	assertEqual(t, "u1", res.String())
+
+	res, err = c.R().SetHeader("X-User-Id", "u2").Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res.CacheStatus())
+	assertEqual(t, "u2", res.String())
+	assertEqual(t, int32(2), hits.Load())
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		if r.Method == MethodPost {
+			w.Header().Set(hdrLocationKey, "/users/2")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+	for _, p := range []string{"/users", "/users/1", "/users/2", "/orders/1"} {
+		_, err := c.R().Get(ts.URL + p)
+		assertNil(t, err)
+	}
+	assertEqual(t, 4, c.Cache().Len())
+
+	t.Run("auto invalidation on non-safe method", func(t *testing.T) {
+		res, err := c.R().Post(ts.URL + "/users")
+		assertNil(t, err)
+		assertEqual(t, http.StatusCreated, res.StatusCode())
+		assertEqual(t, 2, c.Cache().Len())
+
+		res, err = c.R().Get(ts.URL + "/users/1")
+		assertNil(t, err)
+		assertEqual(t, CacheStatusHit, res.CacheStatus())
+	})
+
+	t.Run("invalidate by pattern", func(t *testing.T) {
+		assertEqual(t, 0, c.Cache().Invalidate(ts.URL+"/users"))
+		assertEqual(t, 1, c.Cache().Invalidate(ts.URL+"/users/*"))
+		assertEqual(t, 1, c.Cache().Invalidate("*/orders/1"))
+		assertEqual(t, 0, c.Cache().Len())
+	})
+}
+
+func TestMatchCachePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"http://a/b", "http://a/b", true},
+		{"http://a/b", "http://a/bc", false},
+		{"http://a/*", "http://a/b/c?d=e", true},
+		{"*/b", "http://a/b", true},
+		{"*/b", "http://a/bc", false},
+		{"http://*/b/*", "http://a/b/c", true},
+		{"http://*/b/*", "http://a/c/b", false},
+		{"a*a", "a", false},
+		{"*", "anything", true},
+	}
+	for _, tc := range tests {
+		assertEqual(t, tc.want, matchCachePattern(tc.pattern, tc.value))
+	}
+}
+
+func TestCacheEvents(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+	defer ts.Close()
+
+	var lock sync.Mutex
+	events := make([]string, 0)
+	cache := NewCache().
+		SetMaxEntries(1).
+		SetOnEvent(func(event CacheEvent, key string) {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, string(event)+" "+strings.TrimPrefix(key, "GET "+ts.URL))
+		})
+	c := dcnl().SetCache(cache)
+
+	for _, p := range []string{"/a", "/a", "/b"} {
+		_, err := c.R().Get(ts.URL + p)
+		assertNil(t, err)
+	}
+	cache.Invalidate("*")
+
+	assertEqual(t, []string{
+		"miss /a", "stored /a",
+		"hit /a",
+		"miss /b", "evicted /a", "stored /b",
+		"invalidated /b",
+	}, events)
+}
+
+func TestCacheDebugLogStatus(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	c, logBuf := dcldb()
+	c.SetCache(NewCache())
+
+	_, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(logBuf.String(), "CACHE STATUS : MISS"))
+
+	logBuf.Reset()
+	_, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(logBuf.String(), "CACHE STATUS : HIT"))
+
+	logBuf.Reset()
+	c.SetDebugLogFormatter(DebugLogJSONFormatter)
+	_, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(logBuf.String(), `"cache_status":"HIT"`))
+}
+
+func TestCacheMode(t *testing.T) {
+	var hits atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set(hdrDateKey, timeNow().UTC().Format(http.TimeFormat))
+		w.Header().Set(hdrCacheControlKey, "max-age=1")
+		_, _ = fmt.Fprintf(w, "response %d", n)
+	})
+	defer ts.Close()
+
+	t.Run("cache only without cache", func(t *testing.T) {
+		res, err := dcnl().R().SetCacheMode(CacheModeCacheOnly).Get(ts.URL)
+		assertErrorIs(t, ErrCacheMiss, err)
+		assertEqual(t, CacheStatusNone, res.CacheStatus())
+		assertEqual(t, int32(0), hits.Load())
+	})
+
+	c := dcnl().SetCache(NewCache())
+
+	t.Run("cache only miss", func(t *testing.T) {
+		res, err := c.R().SetCacheMode(CacheModeCacheOnly).Get(ts.URL)
+		assertErrorIs(t, ErrCacheMiss, err)
+		assertEqual(t, CacheStatusMiss, res.CacheStatus())
+
+		_, err = c.R().SetCacheMode(CacheModeCacheOnly).Post(ts.URL)
+		assertErrorIs(t, ErrCacheMiss, err)
+		assertEqual(t, int32(0), hits.Load())
+	})
+
+	t.Run("cache first miss", func(t *testing.T) {
+		res, err := c.R().SetCacheMode(CacheModeCacheFirst).Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusMiss, res.CacheStatus())
+		assertEqual(t, "response 1", res.String())
+	})
+
+	testCacheTimeOffset(t, time.Minute)
+
+	t.Run("cache only stale", func(t *testing.T) {
+		res, err := c.R().SetCacheMode(CacheModeCacheOnly).Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusStale, res.CacheStatus())
+		assertEqual(t, "response 1", res.String())
+	})
+
+	t.Run("cache first stale", func(t *testing.T) {
+		res, err := c.R().SetCacheMode(CacheModeCacheFirst).Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusStale, res.CacheStatus())
+		assertEqual(t, "response 1", res.String())
+		assertEqual(t, int32(1), hits.Load())
+	})
+
+	t.Run("network only", func(t *testing.T) {
+		res, err := c.R().SetCacheMode(CacheModeNetworkOnly).Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusMiss, res.CacheStatus())
+		assertEqual(t, "response 2", res.String())
+
+		// network response is stored
+		res, err = c.R().SetCacheMode(CacheModeCacheOnly).Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, CacheStatusHit, res.CacheStatus())
+		assertEqual(t, "response 2", res.String())
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"hash/crc32"
+	"sync"
+)
+
+// NewCanary method creates the new canary request load balancer instance, it sends
+// the given percentage of the requests to the canary Base URL and the rest to the
+// stable load balancer.
+//
+//	stable, err := resty.NewRoundRobin("https://api1.example.com", "https://api2.example.com")
+//	if err != nil {
+//		return err
+//	}
+//	canary, err := resty.NewCanary(stable, "https://api-canary.example.com", 5)
+//	if err != nil {
+//		return err
+//	}
+//	client.SetLoadBalancer(canary)
+func NewCanary(stable LoadBalancer, canary string, percent float64) (*Canary, error) {
+	c := &Canary{
+		lock:   new(sync.Mutex),
+		stable: stable,
+	}
+	c.SetPercent(percent)
+	baseURL, err := extractBaseURL(canary)
+	if err != nil {
+		return c, err
+	}
+	c.canary = baseURL
+	return c, nil
+}
+
+var (
+	_ RequestLoadBalancer = (*Canary)(nil)
+	_ StatsLoadBalancer   = (*Canary)(nil)
+)
+
+// Canary struct used to implement the weighted canary routing on the client side,
+// i.e., without the service mesh. The requests are assigned to the canary by
+// the percentage at random; the requests with the key are assigned sticky, i.e.,
+// the same key is always assigned the same, see [Canary.SetKeyFunc] and
+// [Request.SetLoadBalancerKey]. The [Request.SetCanary] overrides the assignment.
+type Canary struct {
+	lock    *sync.Mutex
+	stable  LoadBalancer
+	canary  string
+	percent float64
+	keyFn   func(*Request) string
+	stats   lbStats
+}
+
+// Next method returns the canary Base URL at random by the percentage;
+// otherwise, the next Base URL of the stable load balancer.
+func (c *Canary) Next() (string, error) {
+	c.lock.Lock()
+	isCanary := c.randCanary(nil)
+	c.lock.Unlock()
+	return c.next(isCanary)
+}
+
+// NextRequest method returns the canary Base URL for the given request if it
+// is assigned to the canary; otherwise, the Base URL of the stable load balancer.
+// The random assignment uses the random source of the request's client, see
+// [Client.SetRandSource].
+func (c *Canary) NextRequest(r *Request) (string, error) {
+	if r.canary != nil {
+		return c.next(*r.canary)
+	}
+
+	var rnd *lockedRand
+	if r.client != nil {
+		rnd = r.client.randSource()
+	}
+
+	c.lock.Lock()
+	key := r.LoadBalancerKey()
+	if key == "" && c.keyFn != nil {
+		key = c.keyFn(r)
+	}
+	var isCanary bool
+	if key == "" {
+		isCanary = c.randCanary(rnd)
+	} else {
+		// the buckets of the keys, increasing the percentage adds the
+		// keys to the canary and keeps the assigned ones
+		isCanary = float64(crc32.ChecksumIEEE([]byte(key))%10000) < c.percent*100
+	}
+	c.lock.Unlock()
+
+	if rlb, ok := c.stable.(RequestLoadBalancer); ok && !isCanary {
+		return rlb.NextRequest(r)
+	}
+	return c.next(isCanary)
+}
+
+// Feedback method sends the request feedback to the stable load balancer, and
+// records the canary statistics
+func (c *Canary) Feedback(f *RequestFeedback) {
+	if f.BaseURL == c.canary {
+		c.stats.end(f)
+		return
+	}
+	c.stable.Feedback(f)
+}
+
+// Close method closes the stable load balancer
+func (c *Canary) Close() error {
+	return c.stable.Close()
+}
+
+// Stats method returns the statistics of the stable load balancer targets,
+// if it supports, and the canary Base URL as the last one.
+func (c *Canary) Stats() []TargetStats {
+	var result []TargetStats
+	if slb, ok := c.stable.(StatsLoadBalancer); ok {
+		result = slb.Stats()
+	}
+	return append(result, c.stats.snapshot(c.canary, HostStateActive))
+}
+
+// SetPercent method sets the percentage of the requests, between `0` and `100`,
+// to send to the canary; e.g., to ramp up the canary or roll it back.
+func (c *Canary) SetPercent(percent float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.percent = min(max(percent, 0), 100)
+}
+
+// SetKeyFunc method sets the function to derive the sticky assignment key from
+// the request, e.g., the user ID, when it is not set via [Request.SetLoadBalancerKey].
+//
+//	canary.SetKeyFunc(func(r *resty.Request) string {
+//		return r.Header.Get("X-User-ID")
+//	})
+func (c *Canary) SetKeyFunc(fn func(*Request) string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.keyFn = fn
+}
+
+// SetCanary method overrides the canary assignment of the [Canary] load balancer
+// for the request, e.g., to send the internal test traffic to the canary.
+//
+//	client.R().SetCanary(true).Get("/v1/orders")
+func (r *Request) SetCanary(enable bool) *Request {
+	r.canary = &enable
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func (c *Canary) next(isCanary bool) (string, error) {
+	if isCanary {
+		c.stats.begin(c.canary)
+		return c.canary, nil
+	}
+	return c.stable.Next()
+}
+
+// randCanary method must be called with the lock held, it assigns to the canary
+// at random by the percentage; the nil source uses the default one.
+func (c *Canary) randCanary(rnd *lockedRand) bool {
+	return rnd.Float64()*100 < c.percent
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCanary(t *testing.T) {
+	stable, _ := NewRoundRobin("https://api1.example.com", "https://api2.example.com")
+	c, err := NewCanary(stable, "https://canary.example.com/", 20)
+	assertNil(t, err)
+	defer c.Close()
+
+	// the random assignment uses the client's random source
+	client := dcnl().SetRandSource(&floatSequenceSource{values: []float64{0.1, 0.5, 0.19, 0.2, 0.99}})
+	var result []string
+	for i := 0; i < 5; i++ {
+		baseURL, err := c.NextRequest(client.R())
+		assertNil(t, err)
+		result = append(result, baseURL)
+	}
+	assertEqual(t, []string{
+		"https://canary.example.com", "https://api1.example.com", "https://canary.example.com",
+		"https://api2.example.com", "https://api1.example.com",
+	}, result)
+
+	c.Feedback(&RequestFeedback{BaseURL: "https://canary.example.com", Success: false, Err: errMockNetwork})
+	c.Feedback(&RequestFeedback{BaseURL: "https://api1.example.com", Success: true})
+	stats := c.Stats()
+	assertEqual(t, 3, len(stats))
+	assertEqual(t, int64(1), stats[0].Requests)
+	assertEqual(t, TargetStats{BaseURL: "https://canary.example.com", State: HostStateActive,
+		Requests: 1, Errors: 1, InFlight: 1, LastError: errMockNetwork, LastErrorAt: stats[2].LastErrorAt}, stats[2])
+
+	t.Run("sticky by key", func(t *testing.T) {
+		c.SetKeyFunc(func(r *Request) string { return r.Header.Get("X-User-ID") })
+		assigned := func() map[string]bool {
+			canary := make(map[string]bool)
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("user-%d", i)
+				baseURL, err := c.NextRequest(dcnl().R().SetHeader("X-User-ID", key))
+				assertNil(t, err)
+				again, _ := c.NextRequest(dcnl().R().SetLoadBalancerKey(key))
+				assertEqual(t, baseURL == "https://canary.example.com", again == "https://canary.example.com")
+				if baseURL == "https://canary.example.com" {
+					canary[key] = true
+				}
+			}
+			return canary
+		}
+
+		canary := assigned()
+		assertEqual(t, true, len(canary) > 150 && len(canary) < 250)
+
+		// ramp up keeps the assigned keys
+		c.SetPercent(50)
+		rampedUp := assigned()
+		assertEqual(t, true, len(rampedUp) > 450 && len(rampedUp) < 550)
+		for key := range canary {
+			assertEqual(t, true, rampedUp[key])
+		}
+
+		c.SetPercent(-1)
+		assertEqual(t, 0, len(assigned()))
+		c.SetPercent(200)
+		assertEqual(t, 1000, len(assigned()))
+	})
+
+	t.Run("request override", func(t *testing.T) {
+		c.SetPercent(0)
+		baseURL, _ := c.NextRequest(dcnl().R().SetCanary(true))
+		assertEqual(t, "https://canary.example.com", baseURL)
+
+		c.SetPercent(100)
+		baseURL, _ = c.NextRequest(dcnl().R().SetCanary(false).SetLoadBalancerKey("user-1"))
+		assertEqual(t, true, baseURL != "https://canary.example.com")
+	})
+
+	t.Run("request stable load balancer", func(t *testing.T) {
+		ch, _ := NewConsistentHash(nil, "https://api1.example.com", "https://api2.example.com")
+		c, _ := NewCanary(ch, "https://canary.example.com", 0)
+		expected, _ := ch.NextRequest(dcnl().R().SetLoadBalancerKey("user-1"))
+		for i := 0; i < 3; i++ {
+			baseURL, _ := c.NextRequest(dcnl().R().SetLoadBalancerKey("user-1"))
+			assertEqual(t, expected, baseURL)
+		}
+	})
+
+	t.Run("invalid canary url", func(t *testing.T) {
+		_, err := NewCanary(stable, "://canary.example.com", 10)
+		assertNotNil(t, err)
+	})
+}
+
+func TestCanaryClient(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, name)
+		}))
+	}
+	ts1, ts2 := newServer("stable"), newServer("canary")
+	defer ts1.Close()
+	defer ts2.Close()
+
+	stable, _ := NewRoundRobin(ts1.URL)
+	canary, err := NewCanary(stable, ts2.URL, 0)
+	assertNil(t, err)
+
+	c := dcnl().SetLoadBalancer(canary)
+	defer c.Close()
+
+	res, err := c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "stable", res.String())
+
+	res, err = c.R().SetCanary(true).Get("/")
+	assertNil(t, err)
+	assertEqual(t, "canary", res.String())
+	assertEqual(t, ts2.URL, res.BaseURL())
+
+	stats := canary.Stats()
+	assertEqual(t, int64(1), stats[0].Requests)
+	assertEqual(t, int64(1), stats[1].Requests)
+	assertEqual(t, int64(0), stats[1].InFlight)
+}
+
+// floatSequenceSource type is the random source that returns the given
+// sequence of float values from [rand.Rand.Float64].
+type floatSequenceSource struct {
+	values []float64
+}
+
+func (s *floatSequenceSource) Uint64() uint64 {
+	v := s.values[0]
+	s.values = s.values[1:]
+	return uint64(math.Ceil(v * (1 << 53)))
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrCassetteNotMatched error returned by the [Cassette] in the replay mode when
+// no recorded interaction matches the request.
+var ErrCassetteNotMatched = errors.New("resty: no cassette interaction matched")
+
+// CassetteMode type is the mode of the [Cassette]
+type CassetteMode uint8
+
+const (
+	// CassetteModeReplay mode serves the recorded interactions only, the
+	// request not matched by any interaction fails with [ErrCassetteNotMatched].
+	CassetteModeReplay CassetteMode = iota
+
+	// CassetteModeRecord mode sends all the requests, and records the
+	// interactions into the new cassette.
+	CassetteModeRecord
+
+	// CassetteModeNewEpisodes mode serves the recorded interactions, and
+	// sends and records the requests not matched by any interaction.
+	CassetteModeNewEpisodes
+)
+
+// CassetteMatcher type is the function to match the request with the recorded
+// interaction; the request body is read into the body.
+type CassetteMatcher func(r *http.Request, body []byte, i *CassetteInteraction) bool
+
+// DefaultCassetteMatcher function matches the request method and the URL.
+func DefaultCassetteMatcher(r *http.Request, _ []byte, i *CassetteInteraction) bool {
+	return r.Method == i.Request.Method && r.URL.String() == i.Request.URL
+}
+
+// CassetteMatchBody function matches the request method, the URL and the body.
+func CassetteMatchBody(r *http.Request, body []byte, i *CassetteInteraction) bool {
+	return DefaultCassetteMatcher(r, body, i) && bytes.Equal(body, i.Request.body())
+}
+
+// NewCassette function creates the new cassette to record the client traffic
+// into the given file, and replay it deterministically in the tests, i.e.,
+// without the server. It is set on the client via [Client.SetTransport], the
+// cassette file is loaded on the first request and written on [Cassette.Save].
+//
+//	cassette := resty.NewCassette("testdata/users.json", resty.CassetteModeNewEpisodes).
+//		SetTransport(client.Transport()).
+//		SetRedaction(resty.NewDebugLogRedaction())
+//	defer cassette.Save()
+//
+//	client.SetTransport(cassette)
+//
+// NOTE:
+//   - The cassette is stored in the JSON format by default, see [Cassette.SetCodec]
+//     for the other formats, e.g., YAML.
+//   - The request and response bodies are held in memory until the cassette is saved.
+func NewCassette(path string, mode CassetteMode) *Cassette {
+	return &Cassette{
+		lock:      new(sync.Mutex),
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		matcher:   DefaultCassetteMatcher,
+		marshal: func(v any) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		},
+		unmarshal: json.Unmarshal,
+	}
+}
+
+var _ http.RoundTripper = (*Cassette)(nil)
+
+// Cassette struct is the record-and-replay (VCR) [http.RoundTripper], see [NewCassette].
+// In the replay, the matching interactions are served in the order of the
+// recording, and the last one is repeated once they are served.
+type Cassette struct {
+	lock         *sync.Mutex
+	path         string
+	mode         CassetteMode
+	transport    http.RoundTripper
+	matcher      CassetteMatcher
+	redactors    []func(*CassetteInteraction)
+	marshal      func(any) ([]byte, error)
+	unmarshal    func([]byte, any) error
+	loaded       bool
+	modified     bool
+	interactions []*CassetteInteraction
+	served       []bool
+}
+
+// CassetteInteraction struct is the recorded request and response pair
+type CassetteInteraction struct {
+	Request    *CassetteRequest  `json:"request" yaml:"request"`
+	Response   *CassetteResponse `json:"response" yaml:"response"`
+	RecordedAt time.Time         `json:"recorded_at" yaml:"recorded_at"`
+}
+
+// CassetteRequest struct is the recorded request
+type CassetteRequest struct {
+	Method string      `json:"method" yaml:"method"`
+	URL    string      `json:"url" yaml:"url"`
+	Header http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+
+	// Body is the request body as-is, or Base64 encoded if the
+	// BodyEncoding is `base64`, e.g., the binary body
+	Body         string `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty" yaml:"body_encoding,omitempty"`
+}
+
+// CassetteResponse struct is the recorded response
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code" yaml:"status_code"`
+	Status     string      `json:"status" yaml:"status"`
+	Proto      string      `json:"proto,omitempty" yaml:"proto,omitempty"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+
+	// Body is the response body as-is, or Base64 encoded if the
+	// BodyEncoding is `base64`, e.g., the compressed body
+	Body         string `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty" yaml:"body_encoding,omitempty"`
+}
+
+// SetTransport method sets the transport to send the requests in the record
+// modes, default is [http.DefaultTransport].
+func (cs *Cassette) SetTransport(transport http.RoundTripper) *Cassette {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if transport != nil {
+		cs.transport = transport
+	}
+	return cs
+}
+
+// SetMatcher method sets the function to match the requests with the recorded
+// interactions, default is [DefaultCassetteMatcher].
+//
+//	cassette.SetMatcher(resty.CassetteMatchBody)
+func (cs *Cassette) SetMatcher(fn CassetteMatcher) *Cassette {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if fn != nil {
+		cs.matcher = fn
+	}
+	return cs
+}
+
+// AddRedactor method adds the hook to redact the sensitive data from the
+// interaction before it is recorded, e.g., the tokens in the body.
+//
+//	cassette.AddRedactor(func(i *resty.CassetteInteraction) {
+//		i.Response.Body = tokenRe.ReplaceAllString(i.Response.Body, "[REDACTED]")
+//	})
+func (cs *Cassette) AddRedactor(fn func(*CassetteInteraction)) *Cassette {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.redactors = append(cs.redactors, fn)
+	return cs
+}
+
+// SetRedaction method sets the rules to redact the sensitive values, i.e.,
+// the headers, the query parameters and the bodies, from the interaction
+// before it is recorded. See [NewDebugLogRedaction] for the default rules.
+//
+//	cassette.SetRedaction(resty.NewDebugLogRedaction().AddQueryParams("api_key"))
+//
+// NOTE: The URL with the redacted query parameters does not match the request
+// in the replay by [DefaultCassetteMatcher], see [Cassette.SetMatcher].
+func (cs *Cassette) SetRedaction(dr *DebugLogRedaction) *Cassette {
+	return cs.AddRedactor(func(i *CassetteInteraction) {
+		for _, name := range dr.Headers {
+			dr.redactHeader(i.Request.Header, name)
+			dr.redactHeader(i.Response.Header, name)
+		}
+		i.Request.URL = dr.redactQueryParams(i.Request.URL)
+		if i.Request.BodyEncoding == "" {
+			i.Request.Body = dr.redactBody(i.Request.Body)
+		}
+		if i.Response.BodyEncoding == "" {
+			i.Response.Body = dr.redactBody(i.Response.Body)
+		}
+	})
+}
+
+// SetCodec method sets the functions to encode and decode the cassette file,
+// default is JSON. The cassette types have the `yaml` struct tags, so the
+// YAML library can be plugged in.
+//
+//	cassette.SetCodec(yaml.Marshal, yaml.Unmarshal)
+func (cs *Cassette) SetCodec(marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) *Cassette {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.marshal, cs.unmarshal = marshal, unmarshal
+	return cs
+}
+
+// Interactions method returns the recorded interactions of the cassette
+func (cs *Cassette) Interactions() ([]*CassetteInteraction, error) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if err := cs.load(); err != nil {
+		return nil, err
+	}
+	return append([]*CassetteInteraction{}, cs.interactions...), nil
+}
+
+// Save method writes the cassette file if there are new interactions,
+// the parent directories are created if needed.
+func (cs *Cassette) Save() error {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if !cs.modified {
+		return nil
+	}
+
+	data, err := cs.marshal(&cassetteFile{Interactions: cs.interactions})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cs.path), 0o755); err != nil {
+		return err
+	}
+	if err = os.WriteFile(cs.path, data, 0o644); err != nil {
+		return err
+	}
+	cs.modified = false
+	return nil
+}
+
+// RoundTrip method implements the [http.RoundTripper] interface.
+func (cs *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := bufferRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.lock.Lock()
+	if err := cs.load(); err != nil {
+		cs.lock.Unlock()
+		return nil, err
+	}
+	if cs.mode != CassetteModeRecord {
+		if i := cs.find(req, body); i != nil {
+			cs.lock.Unlock()
+			return i.Response.response(req)
+		}
+	}
+	mode, transport := cs.mode, cs.transport
+	cs.lock.Unlock()
+
+	if mode == CassetteModeReplay {
+		return nil, fmt.Errorf("%w: %s %s", ErrCassetteNotMatched, req.Method, req.URL)
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	i := &CassetteInteraction{
+		Request: &CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+		},
+		Response: &CassetteResponse{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Proto:      res.Proto,
+			Header:     res.Header.Clone(),
+		},
+		RecordedAt: time.Now().UTC(),
+	}
+	i.Request.Body, i.Request.BodyEncoding = encodeCassetteBody(body)
+	i.Response.Body, i.Response.BodyEncoding = encodeCassetteBody(resBody)
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	for _, fn := range cs.redactors {
+		fn(i)
+	}
+	cs.interactions = append(cs.interactions, i)
+	cs.served = append(cs.served, true)
+	cs.modified = true
+	return res, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type cassetteFile struct {
+	Interactions []*CassetteInteraction `json:"interactions" yaml:"interactions"`
+}
+
+// load method loads the cassette file once, the missing file is an empty
+// cassette in the record modes. The caller must hold the lock.
+func (cs *Cassette) load() error {
+	if cs.loaded {
+		return nil
+	}
+	if cs.mode != CassetteModeRecord {
+		data, err := os.ReadFile(cs.path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && cs.mode == CassetteModeNewEpisodes:
+		case err != nil:
+			return err
+		default:
+			cf := &cassetteFile{}
+			if err = cs.unmarshal(data, cf); err != nil {
+				return err
+			}
+			cs.interactions = cf.Interactions
+			cs.served = make([]bool, len(cf.Interactions))
+		}
+	}
+	cs.loaded = true
+	return nil
+}
+
+// find method returns the first matching interaction not served yet,
+// otherwise the last matching one. The caller must hold the lock.
+func (cs *Cassette) find(req *http.Request, body []byte) *CassetteInteraction {
+	last := -1
+	for idx, i := range cs.interactions {
+		if !cs.matcher(req, body, i) {
+			continue
+		}
+		if !cs.served[idx] {
+			cs.served[idx] = true
+			return i
+		}
+		last = idx
+	}
+	if last == -1 {
+		return nil
+	}
+	return cs.interactions[last]
+}
+
+func (cr *CassetteRequest) body() []byte {
+	return decodeCassetteBody(cr.Body, cr.BodyEncoding)
+}
+
+func (cr *CassetteResponse) response(req *http.Request) (*http.Response, error) {
+	body := decodeCassetteBody(cr.Body, cr.BodyEncoding)
+	header := cr.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	proto := cr.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	major, minor, _ := http.ParseHTTPVersion(proto)
+	return &http.Response{
+		Status:        cr.Status,
+		StatusCode:    cr.StatusCode,
+		Proto:         proto,
+		ProtoMajor:    major,
+		ProtoMinor:    minor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func encodeCassetteBody(b []byte) (string, string) {
+	if utf8.Valid(b) {
+		return string(b), ""
+	}
+	return base64.StdEncoding.EncodeToString(b), "base64"
+}
+
+func decodeCassetteBody(s, encoding string) []byte {
+	if encoding == "base64" {
+		b, _ := base64.StdEncoding.DecodeString(s)
+		return b
+	}
+	return []byte(s)
+}
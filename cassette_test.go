@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = fmt.Fprintf(w, `{"hit":%d,"path":%q,"body":%q,"token":"t-123"}`, n, r.URL.Path, b)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "users.json")
+	redaction := NewDebugLogRedaction().AddBodyPatterns(regexp.MustCompile(`"token":"([^"]+)"`))
+
+	// record
+	cassette := NewCassette(path, CassetteModeRecord).SetRedaction(redaction)
+	c := dcnl().SetTransport(cassette).SetBaseURL(ts.URL)
+	res, err := c.R().SetAuthToken("secret").Get("/users/1")
+	assertNil(t, err)
+	assertEqual(t, `{"hit":1,"path":"/users/1","body":"","token":"t-123"}`, res.String())
+	res, err = c.R().SetBody("a").Post("/users")
+	assertNil(t, err)
+	assertEqual(t, `{"hit":2,"path":"/users","body":"a","token":"t-123"}`, res.String())
+	assertNil(t, cassette.Save())
+
+	data, err := os.ReadFile(path)
+	assertNil(t, err)
+	assertEqual(t, false, strings.Contains(string(data), "secret"))
+	assertEqual(t, false, strings.Contains(string(data), "t-123"))
+
+	// replay
+	cassette = NewCassette(path, CassetteModeReplay).SetMatcher(CassetteMatchBody)
+	c.SetTransport(cassette)
+	res, err = c.R().SetBody("a").Post("/users")
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, `{"hit":2,"path":"/users","body":"a","token":"[REDACTED]"}`, res.String())
+	assertEqual(t, "[REDACTED]", res.Header().Get("Set-Cookie"))
+	assertEqual(t, int32(2), hits.Load())
+
+	_, err = c.R().SetBody("b").Post("/users")
+	assertErrorIs(t, ErrCassetteNotMatched, err)
+
+	// new episodes
+	cassette = NewCassette(path, CassetteModeNewEpisodes).SetTransport(http.DefaultTransport)
+	c.SetTransport(cassette)
+	res, err = c.R().Get("/users/1")
+	assertNil(t, err)
+	assertEqual(t, `{"hit":1,"path":"/users/1","body":"","token":"[REDACTED]"}`, res.String())
+	res, err = c.R().Get("/users/2")
+	assertNil(t, err)
+	assertEqual(t, `{"hit":3,"path":"/users/2","body":"","token":"t-123"}`, res.String())
+	assertNil(t, cassette.Save())
+
+	interactions, err := NewCassette(path, CassetteModeReplay).Interactions()
+	assertNil(t, err)
+	assertEqual(t, 3, len(interactions))
+	assertEqual(t, ts.URL+"/users/2", interactions[2].Request.URL)
+
+	t.Run("replay sequence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sequence.json")
+		cassette := NewCassette(path, CassetteModeRecord)
+		c := dcnl().SetTransport(cassette).SetBaseURL(ts.URL)
+		for i := 0; i < 2; i++ {
+			_, err := c.R().Get("/counter")
+			assertNil(t, err)
+		}
+		assertNil(t, cassette.Save())
+
+		c.SetTransport(NewCassette(path, CassetteModeReplay))
+		var result []string
+		for i := 0; i < 3; i++ {
+			res, err := c.R().Get("/counter")
+			assertNil(t, err)
+			result = append(result, res.String()[:8])
+		}
+		assertEqual(t, []string{`{"hit":4`, `{"hit":5`, `{"hit":5`}, result)
+	})
+
+	t.Run("binary body", func(t *testing.T) {
+		i := &CassetteInteraction{Request: &CassetteRequest{}}
+		i.Request.Body, i.Request.BodyEncoding = encodeCassetteBody([]byte{0xff, 0x00, 0x01})
+		assertEqual(t, "base64", i.Request.BodyEncoding)
+		assertEqual(t, []byte{0xff, 0x00, 0x01}, i.Request.body())
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		c := dcnl().SetTransport(NewCassette(filepath.Join(t.TempDir(), "missing.json"), CassetteModeReplay))
+		_, err := c.R().Get(ts.URL)
+		assertErrorIs(t, os.ErrNotExist, err)
+	})
+}
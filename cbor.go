@@ -0,0 +1,767 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrCBORMalformed error is returned when the CBOR response body cannot be
+	// decoded, e.g., truncated or invalid data.
+	ErrCBORMalformed = errors.New("resty: cbor: malformed data")
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+const (
+	cborMajorUint byte = iota
+	cborMajorNegInt
+	cborMajorBytes
+	cborMajorText
+	cborMajorArray
+	cborMajorMap
+	cborMajorTag
+	cborMajorSimple
+
+	cborFalse      byte = 0xf4
+	cborTrue       byte = 0xf5
+	cborNull       byte = 0xf6
+	cborFloat32    byte = 0xfa
+	cborFloat64    byte = 0xfb
+	cborBreak      byte = 0xff
+	cborIndefinite byte = 31
+
+	cborMaxDepth = 10000
+)
+
+func encodeCBOR(w io.Writer, v any) error {
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	e := &cborEncoder{buf: buf}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func decodeCBOR(r io.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("resty: cbor: decode into non-pointer %T", v)
+	}
+
+	d := &cborDecoder{r: bufio.NewReader(r)}
+	if _, err := d.r.Peek(1); err == io.EOF {
+		return nil // no content
+	}
+	item, err := d.decode()
+	if err != nil {
+		return err
+	}
+	return cborAssign(rv.Elem(), item)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Encoder
+//_______________________________________________________________________
+
+type cborEncoder struct {
+	buf *bytes.Buffer
+}
+
+func (e *cborEncoder) writeHead(major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		e.buf.WriteByte(m | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.Write([]byte{m | 24, byte(n)})
+	case n <= math.MaxUint16:
+		e.buf.Write(binary.BigEndian.AppendUint16([]byte{m | 25}, uint16(n)))
+	case n <= math.MaxUint32:
+		e.buf.Write(binary.BigEndian.AppendUint32([]byte{m | 26}, uint32(n)))
+	default:
+		e.buf.Write(binary.BigEndian.AppendUint64([]byte{m | 27}, n))
+	}
+}
+
+func (e *cborEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf.WriteByte(cborNull)
+		return nil
+	}
+
+	if v.Type() == timeType && v.CanInterface() {
+		e.writeHead(cborMajorTag, 0)
+		s := v.Interface().(time.Time).Format(time.RFC3339Nano)
+		e.writeHead(cborMajorText, uint64(len(s)))
+		e.buf.WriteString(s)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteByte(cborNull)
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf.WriteByte(cborTrue)
+		} else {
+			e.buf.WriteByte(cborFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i >= 0 {
+			e.writeHead(cborMajorUint, uint64(i))
+		} else {
+			e.writeHead(cborMajorNegInt, uint64(-1-i))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeHead(cborMajorUint, v.Uint())
+	case reflect.Float32:
+		e.buf.Write(binary.BigEndian.AppendUint32([]byte{cborFloat32}, math.Float32bits(float32(v.Float()))))
+	case reflect.Float64:
+		e.buf.Write(binary.BigEndian.AppendUint64([]byte{cborFloat64}, math.Float64bits(v.Float())))
+	case reflect.String:
+		e.writeHead(cborMajorText, uint64(v.Len()))
+		e.buf.WriteString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf.WriteByte(cborNull)
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.writeHead(cborMajorBytes, uint64(v.Len()))
+			for i := range v.Len() {
+				e.buf.WriteByte(byte(v.Index(i).Uint()))
+			}
+			return nil
+		}
+		e.writeHead(cborMajorArray, uint64(v.Len()))
+		for i := range v.Len() {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf.WriteByte(cborNull)
+			return nil
+		}
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("resty: cbor: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// encodeMap method encodes the map with keys sorted in the bytewise
+// lexicographic order of their encoding, i.e., the deterministic encoding.
+func (e *cborEncoder) encodeMap(v reflect.Value) error {
+	type pair struct {
+		key   []byte
+		value reflect.Value
+	}
+	pairs := make([]pair, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		ke := &cborEncoder{buf: &bytes.Buffer{}}
+		if err := ke.encode(iter.Key()); err != nil {
+			return err
+		}
+		pairs = append(pairs, pair{key: ke.buf.Bytes(), value: iter.Value()})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return bytes.Compare(pairs[i].key, pairs[j].key) < 0
+	})
+
+	e.writeHead(cborMajorMap, uint64(len(pairs)))
+	for _, p := range pairs {
+		e.buf.Write(p.key)
+		if err := e.encode(p.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *cborEncoder) encodeStruct(v reflect.Value) error {
+	fields := structFields(v.Type(), "cbor", "json")
+	values := make([]reflect.Value, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		values = append(values, fv)
+		names = append(names, f.name)
+	}
+
+	e.writeHead(cborMajorMap, uint64(len(values)))
+	for i, fv := range values {
+		e.writeHead(cborMajorText, uint64(len(names[i])))
+		e.buf.WriteString(names[i])
+		if err := e.encode(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Decoder
+//_______________________________________________________________________
+
+// cborDecoder decodes the CBOR data item into the generic Go values, i.e.,
+// uint64, int64, float64, bool, nil, string, []byte, []any, map[string]any
+// (map[any]any for the non-string keys), and time.Time.
+type cborDecoder struct {
+	r     *bufio.Reader
+	depth int
+}
+
+func (d *cborDecoder) readByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+func (d *cborDecoder) readN(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// readArgument method reads the argument of the initial byte, i.e., the
+// value, length, or count.
+func (d *cborDecoder) readArgument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		b, err := d.readByte()
+		return uint64(b), err
+	case info == 25:
+		b, err := d.readN(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err := d.readN(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err := d.readN(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	}
+	return 0, ErrCBORMalformed
+}
+
+// isBreak method reports whether the next byte is the break stop code
+// and consumes it.
+func (d *cborDecoder) isBreak() (bool, error) {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		if err == io.EOF {
+			return false, io.ErrUnexpectedEOF
+		}
+		return false, err
+	}
+	if b[0] != cborBreak {
+		return false, nil
+	}
+	_, _ = d.r.ReadByte()
+	return true, nil
+}
+
+func (d *cborDecoder) decode() (any, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > cborMaxDepth {
+		return nil, fmt.Errorf("resty: cbor: exceeded max depth %d", cborMaxDepth)
+	}
+
+	ib, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	major, info := ib>>5, ib&0x1f
+
+	if major == cborMajorSimple {
+		return d.decodeSimple(info)
+	}
+	if info == cborIndefinite {
+		return d.decodeIndefinite(major)
+	}
+
+	n, err := d.readArgument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborMajorUint:
+		return n, nil
+	case cborMajorNegInt:
+		if n > math.MaxInt64 {
+			return nil, errors.New("resty: cbor: negative integer overflows int64")
+		}
+		return -1 - int64(n), nil
+	case cborMajorBytes:
+		return d.readString(n)
+	case cborMajorText:
+		b, err := d.readString(n)
+		return string(b), err
+	case cborMajorArray:
+		arr := make([]any, 0, min(n, 1024))
+		for range n {
+			item, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, item)
+		}
+		return arr, nil
+	case cborMajorMap:
+		pairs := make([][2]any, 0, min(n, 1024))
+		for range n {
+			pair, err := d.decodePair()
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, pair)
+		}
+		return cborMap(pairs)
+	default: // cborMajorTag
+		return d.decodeTag(n)
+	}
+}
+
+func (d *cborDecoder) readString(n uint64) ([]byte, error) {
+	if n > math.MaxInt32 {
+		return nil, errors.New("resty: cbor: string length exceeds the limit")
+	}
+	// read progressively, the declared length is not trusted for allocation
+	buf := &bytes.Buffer{}
+	if _, err := io.CopyN(buf, d.r, int64(n)); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *cborDecoder) decodeIndefinite(major byte) (any, error) {
+	switch major {
+	case cborMajorBytes, cborMajorText:
+		buf := &bytes.Buffer{}
+		for {
+			brk, err := d.isBreak()
+			if err != nil {
+				return nil, err
+			}
+			if brk {
+				break
+			}
+			ib, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			// chunks must be definite-length strings of the same major type
+			if ib>>5 != major || ib&0x1f == cborIndefinite {
+				return nil, ErrCBORMalformed
+			}
+			n, err := d.readArgument(ib & 0x1f)
+			if err != nil {
+				return nil, err
+			}
+			chunk, err := d.readString(n)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(chunk)
+		}
+		if major == cborMajorText {
+			return buf.String(), nil
+		}
+		return buf.Bytes(), nil
+	case cborMajorArray:
+		arr := make([]any, 0)
+		for {
+			brk, err := d.isBreak()
+			if err != nil {
+				return nil, err
+			}
+			if brk {
+				return arr, nil
+			}
+			item, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, item)
+		}
+	case cborMajorMap:
+		pairs := make([][2]any, 0)
+		for {
+			brk, err := d.isBreak()
+			if err != nil {
+				return nil, err
+			}
+			if brk {
+				return cborMap(pairs)
+			}
+			pair, err := d.decodePair()
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+	return nil, ErrCBORMalformed
+}
+
+func (d *cborDecoder) decodePair() ([2]any, error) {
+	k, err := d.decode()
+	if err != nil {
+		return [2]any{}, err
+	}
+	v, err := d.decode()
+	if err != nil {
+		return [2]any{}, err
+	}
+	return [2]any{k, v}, nil
+}
+
+func (d *cborDecoder) decodeTag(number uint64) (any, error) {
+	content, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch number {
+	case 0: // standard date/time string
+		if s, ok := content.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+		return nil, ErrCBORMalformed
+	case 1: // epoch-based date/time
+		switch t := content.(type) {
+		case uint64:
+			return time.Unix(int64(t), 0), nil
+		case int64:
+			return time.Unix(t, 0), nil
+		case float64:
+			sec, frac := math.Modf(t)
+			return time.Unix(int64(sec), int64(frac*1e9)), nil
+		}
+		return nil, ErrCBORMalformed
+	}
+	// other tags are transparent to the content
+	return content, nil
+}
+
+func (d *cborDecoder) decodeSimple(info byte) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 25:
+		b, err := d.readN(2)
+		if err != nil {
+			return nil, err
+		}
+		return halfToFloat64(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.readN(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case cborIndefinite:
+		return nil, errors.New("resty: cbor: unexpected break")
+	}
+	return nil, fmt.Errorf("resty: cbor: unsupported simple value %d", info)
+}
+
+func halfToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0: // subnormal
+		f = mant * math.Pow(2, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = (mant + 1024) * math.Pow(2, float64(exp-25))
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// cborMap function returns the map[string]any if all the keys are text
+// strings; otherwise, the map[any]any.
+func cborMap(pairs [][2]any) (any, error) {
+	allString := true
+	for _, p := range pairs {
+		if _, ok := p[0].(string); !ok {
+			allString = false
+			break
+		}
+	}
+	if allString {
+		m := make(map[string]any, len(pairs))
+		for _, p := range pairs {
+			m[p[0].(string)] = p[1]
+		}
+		return m, nil
+	}
+
+	m := make(map[any]any, len(pairs))
+	for _, p := range pairs {
+		k := p[0]
+		switch kt := k.(type) {
+		case []byte:
+			k = string(kt)
+		case []any, map[string]any, map[any]any:
+			return nil, fmt.Errorf("resty: cbor: unsupported map key type %T", k)
+		}
+		m[k] = p[1]
+	}
+	return m, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Assign the decoded values
+//_______________________________________________________________________
+
+func cborAssign(dst reflect.Value, src any) error {
+	if src == nil {
+		switch dst.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			dst.SetZero()
+		}
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return cborAssign(dst.Elem(), src)
+	}
+
+	if dst.Kind() == reflect.Interface {
+		sv := reflect.ValueOf(src)
+		if !sv.Type().AssignableTo(dst.Type()) {
+			return cborTypeError(src, dst)
+		}
+		dst.Set(sv)
+		return nil
+	}
+
+	if dst.Type() == timeType {
+		switch s := src.(type) {
+		case time.Time:
+			dst.Set(reflect.ValueOf(s))
+			return nil
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return cborTypeError(src, dst)
+	}
+
+	switch s := src.(type) {
+	case bool:
+		if dst.Kind() == reflect.Bool {
+			dst.SetBool(s)
+			return nil
+		}
+	case uint64:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if s > math.MaxInt64 || dst.OverflowInt(int64(s)) {
+				return cborOverflowError(src, dst)
+			}
+			dst.SetInt(int64(s))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if dst.OverflowUint(s) {
+				return cborOverflowError(src, dst)
+			}
+			dst.SetUint(s)
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(float64(s))
+			return nil
+		}
+	case int64:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if dst.OverflowInt(s) {
+				return cborOverflowError(src, dst)
+			}
+			dst.SetInt(s)
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(float64(s))
+			return nil
+		}
+	case float64:
+		if k := dst.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+			dst.SetFloat(s)
+			return nil
+		}
+	case string:
+		switch {
+		case dst.Kind() == reflect.String:
+			dst.SetString(s)
+			return nil
+		case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+			dst.SetBytes([]byte(s))
+			return nil
+		}
+	case []byte:
+		switch {
+		case dst.Kind() == reflect.String:
+			dst.SetString(string(s))
+			return nil
+		case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+			dst.SetBytes(bytes.Clone(s))
+			return nil
+		case dst.Kind() == reflect.Array && dst.Type().Elem().Kind() == reflect.Uint8:
+			reflect.Copy(dst, reflect.ValueOf(s))
+			return nil
+		}
+	case []any:
+		switch dst.Kind() {
+		case reflect.Slice:
+			sl := reflect.MakeSlice(dst.Type(), len(s), len(s))
+			for i, item := range s {
+				if err := cborAssign(sl.Index(i), item); err != nil {
+					return err
+				}
+			}
+			dst.Set(sl)
+			return nil
+		case reflect.Array:
+			for i := 0; i < dst.Len() && i < len(s); i++ {
+				if err := cborAssign(dst.Index(i), s[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case map[string]any:
+		m := make(map[any]any, len(s))
+		for k, v := range s {
+			m[k] = v
+		}
+		return cborAssignMap(dst, m, src)
+	case map[any]any:
+		return cborAssignMap(dst, s, src)
+	}
+	return cborTypeError(src, dst)
+}
+
+func cborAssignMap(dst reflect.Value, m map[any]any, src any) error {
+	switch dst.Kind() {
+	case reflect.Map:
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(m)))
+		}
+		kt, vt := dst.Type().Key(), dst.Type().Elem()
+		for k, v := range m {
+			kv := reflect.New(kt).Elem()
+			if err := cborAssign(kv, k); err != nil {
+				return err
+			}
+			vv := reflect.New(vt).Elem()
+			if err := cborAssign(vv, v); err != nil {
+				return err
+			}
+			dst.SetMapIndex(kv, vv)
+		}
+		return nil
+	case reflect.Struct:
+		fields := structFields(dst.Type(), "cbor", "json")
+		for k, v := range m {
+			name, ok := k.(string)
+			if !ok {
+				continue
+			}
+			f, found := fieldByName(fields, name)
+			if !found {
+				continue
+			}
+			if err := cborAssign(dst.FieldByIndex(f.index), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return cborTypeError(src, dst)
+}
+
+func cborTypeError(src any, dst reflect.Value) error {
+	return fmt.Errorf("resty: cbor: cannot decode %T into %s", src, dst.Type())
+}
+
+func cborOverflowError(src any, dst reflect.Value) error {
+	return fmt.Errorf("resty: cbor: value %v overflows %s", src, dst.Type())
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCBOREncode(t *testing.T) {
+	// test vectors from RFC 8949 Appendix A
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: 0, expected: "00"},
+		{value: 23, expected: "17"},
+		{value: 24, expected: "1818"},
+		{value: 1000, expected: "1903e8"},
+		{value: uint64(1000000000000), expected: "1b000000e8d4a51000"},
+		{value: -1, expected: "20"},
+		{value: -1000, expected: "3903e7"},
+		{value: 1.1, expected: "fb3ff199999999999a"},
+		{value: float32(100000.0), expected: "fa47c35000"},
+		{value: false, expected: "f4"},
+		{value: true, expected: "f5"},
+		{value: nil, expected: "f6"},
+		{value: "", expected: "60"},
+		{value: "IETF", expected: "6449455446"},
+		{value: "ü", expected: "62c3bc"},
+		{value: []byte{1, 2, 3, 4}, expected: "4401020304"},
+		{value: []int{1, 2, 3}, expected: "83010203"},
+		{value: []any{1, []int{2, 3}, []int{4, 5}}, expected: "8301820203820405"},
+		{value: map[string]int{"b": 2, "a": 1}, expected: "a2616101616202"},
+		{value: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), expected: "c074323031332d30332d32315432303a30343a30305a"},
+	}
+
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		assertNil(t, encodeCBOR(&buf, tc.value))
+		assertEqual(t, tc.expected, hex.EncodeToString(buf.Bytes()))
+	}
+
+	err := encodeCBOR(io.Discard, make(chan int))
+	assertEqual(t, "resty: cbor: unsupported type chan int", err.Error())
+}
+
+func TestCBORDecode(t *testing.T) {
+	// test vectors from RFC 8949 Appendix A
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{input: "00", expected: uint64(0)},
+		{input: "1bffffffffffffffff", expected: uint64(math.MaxUint64)},
+		{input: "3903e7", expected: int64(-1000)},
+		{input: "f93c00", expected: 1.0},
+		{input: "f9c400", expected: -4.0},
+		{input: "f90001", expected: 5.960464477539063e-8},
+		{input: "f97c00", expected: math.Inf(1)},
+		{input: "fa47c35000", expected: 100000.0},
+		{input: "fb3ff199999999999a", expected: 1.1},
+		{input: "f5", expected: true},
+		{input: "f6", expected: nil},
+		{input: "f7", expected: nil},
+		{input: "6449455446", expected: "IETF"},
+		{input: "4401020304", expected: []byte{1, 2, 3, 4}},
+		{input: "c11a514b67b0", expected: time.Unix(1363896240, 0)},
+		{input: "c249010000000000000000", expected: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{input: "8301820203820405", expected: []any{uint64(1), []any{uint64(2), uint64(3)}, []any{uint64(4), uint64(5)}}},
+		{input: "a201020304", expected: map[any]any{uint64(1): uint64(2), uint64(3): uint64(4)}},
+
+		// indefinite-length items
+		{input: "5f42010243030405ff", expected: []byte{1, 2, 3, 4, 5}},
+		{input: "7f657374726561646d696e67ff", expected: "streaming"},
+		{input: "9fff", expected: []any{}},
+		{input: "9f018202039f0405ffff", expected: []any{uint64(1), []any{uint64(2), uint64(3)}, []any{uint64(4), uint64(5)}}},
+		{input: "bf61610161629f0203ffff", expected: map[string]any{"a": uint64(1), "b": []any{uint64(2), uint64(3)}}},
+		{input: "826161bf61626163ff", expected: []any{"a", map[string]any{"b": "c"}}},
+	}
+
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc.input)
+		var v any
+		assertNil(t, decodeCBOR(bytes.NewReader(b), &v))
+		assertEqual(t, tc.expected, v)
+	}
+}
+
+func TestCBORDecodeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{input: "19", expected: io.ErrUnexpectedEOF},
+		{input: "62c3", expected: io.ErrUnexpectedEOF},
+		{input: "9f01", expected: io.ErrUnexpectedEOF},
+		{input: "1c", expected: ErrCBORMalformed},
+		{input: "5f6161ff", expected: ErrCBORMalformed},
+		{input: "df", expected: ErrCBORMalformed},
+	}
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc.input)
+		var v any
+		err := decodeCBOR(bytes.NewReader(b), &v)
+		assertEqual(t, true, errors.Is(err, tc.expected))
+	}
+
+	var v any
+	err := decodeCBOR(bytes.NewReader([]byte{0xff}), &v)
+	assertEqual(t, "resty: cbor: unexpected break", err.Error())
+
+	var i8 int8
+	err = decodeCBOR(bytes.NewReader([]byte{0x19, 0x03, 0xe8}), &i8)
+	assertEqual(t, "resty: cbor: value 1000 overflows int8", err.Error())
+
+	var s string
+	err = decodeCBOR(bytes.NewReader([]byte{0xf5}), &s)
+	assertEqual(t, "resty: cbor: cannot decode bool into string", err.Error())
+
+	err = decodeCBOR(bytes.NewReader([]byte{0xf5}), s)
+	assertEqual(t, "resty: cbor: decode into non-pointer string", err.Error())
+
+	// no content
+	assertNil(t, decodeCBOR(bytes.NewReader(nil), &v))
+}
+
+type cborTestBase struct {
+	ID int `cbor:"id"`
+}
+
+type cborTestReading struct {
+	cborTestBase
+	Sensor   string            `json:"sensor"`
+	Values   []float64         `cbor:"values"`
+	Labels   map[string]string `cbor:"labels,omitempty"`
+	Raw      []byte            `cbor:"raw"`
+	Time     time.Time         `cbor:"time"`
+	Next     *cborTestReading  `cbor:"next,omitempty"`
+	Ignored  string            `cbor:"-"`
+	internal string
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	in := &cborTestReading{
+		cborTestBase: cborTestBase{ID: 7},
+		Sensor:       "temp",
+		Values:       []float64{21.5, -3.25},
+		Raw:          []byte{0xca, 0xfe},
+		Time:         time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Next:         &cborTestReading{Sensor: "humidity"},
+		Ignored:      "ignored",
+		internal:     "internal",
+	}
+
+	var buf bytes.Buffer
+	assertNil(t, encodeCBOR(&buf, in))
+
+	out := &cborTestReading{}
+	assertNil(t, decodeCBOR(&buf, out))
+	assertEqual(t, 7, out.ID)
+	assertEqual(t, "temp", out.Sensor)
+	assertEqual(t, []float64{21.5, -3.25}, out.Values)
+	assertNil(t, out.Labels)
+	assertEqual(t, []byte{0xca, 0xfe}, out.Raw)
+	assertEqual(t, true, in.Time.Equal(out.Time))
+	assertEqual(t, "humidity", out.Next.Sensor)
+	assertEqual(t, "", out.Ignored)
+	assertEqual(t, "", out.internal)
+}
+
+func TestClientCBORCodec(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		in := &cborTestReading{}
+		if err := decodeCBOR(r.Body, in); err != nil || in.Sensor != "temp" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// streamed response with indefinite-length items
+		w.Header().Set(hdrContentTypeKey, cborContentType)
+		w.(http.Flusher).Flush()
+		chunks := []string{
+			"bf", "666c6162656c73", "bf", "6466726f6d", "7f", "63726573", "6374790a", "ff", "ff",
+			"6673656e736f72", "6474656d70",
+			"6676616c756573", "9f", "f94100", "f9c100", "ff",
+			"ff",
+		}
+		for _, c := range chunks {
+			b, _ := hex.DecodeString(c)
+			_, _ = w.Write(b)
+			w.(http.Flusher).Flush()
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	result := &cborTestReading{}
+	res, err := c.R().
+		SetHeader(hdrContentTypeKey, cborContentType).
+		SetBody(&cborTestReading{Sensor: "temp"}).
+		SetResult(result).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, map[string]string{"from": "resty\n"}, result.Labels)
+	assertEqual(t, []float64{2.5, -2.5}, result.Values)
+}
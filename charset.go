@@ -0,0 +1,260 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// CharsetDecoder type is for transcoding the response body of the charset
+// into UTF-8, see [Client.AddCharsetDecoder].
+type CharsetDecoder func(io.Reader) io.Reader
+
+const charsetSniffLen = 1024
+
+var (
+	htmlMetaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_:.\-]+)`)
+	xmlEncodingRegex     = regexp.MustCompile(`^\s*<\?xml[^>]+encoding\s*=\s*["']([a-zA-Z0-9_:.\-]+)["']`)
+
+	charsetAliases = map[string]string{
+		"utf8":        "utf-8",
+		"ascii":       "utf-8",
+		"us-ascii":    "utf-8",
+		"latin1":      "iso-8859-1",
+		"l1":          "iso-8859-1",
+		"iso8859-1":   "iso-8859-1",
+		"iso_8859-1":  "iso-8859-1",
+		"cp1252":      "windows-1252",
+		"x-cp1252":    "windows-1252",
+		"utf16le":     "utf-16le",
+		"utf16be":     "utf-16be",
+		"utf-16":      "utf-16le",
+		"shift-jis":   "shift_jis",
+		"sjis":        "shift_jis",
+		"x-sjis":      "shift_jis",
+		"gb2312":      "gbk",
+		"x-gbk":       "gbk",
+		"cp936":       "gbk",
+		"windows-936": "gbk",
+	}
+
+	// windows1252High holds the code points of the bytes 0x80-0x9F,
+	// the rest of the bytes map to the same code points.
+	windows1252High = [32]rune{
+		'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+		0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+	}
+)
+
+// normalizeCharset method returns the canonical lowercase name of the charset.
+func normalizeCharset(charset string) string {
+	charset = strings.ToLower(strings.Trim(strings.TrimSpace(charset), `"'`))
+	if alias, found := charsetAliases[charset]; found {
+		return alias
+	}
+	return charset
+}
+
+// detectCharset method detects the charset of the content in the order of
+// the byte order mark, Content-Type charset parameter, and the HTML meta or
+// XML declaration; it returns the BOM length to skip.
+func detectCharset(contentType string, br *bufio.Reader) (string, int) {
+	b, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8", 3
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return "utf-16le", 2
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return "utf-16be", 2
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if charset := params["charset"]; len(charset) > 0 {
+		return normalizeCharset(charset), 0
+	}
+
+	var re *regexp.Regexp
+	switch {
+	case strings.Contains(mediaType, "html"):
+		re = htmlMetaCharsetRegex
+	case strings.Contains(mediaType, "xml"):
+		re = xmlEncodingRegex
+	default:
+		return "", 0
+	}
+	b, _ = br.Peek(charsetSniffLen)
+	if m := re.FindSubmatch(b); m != nil {
+		return normalizeCharset(string(m[1])), 0
+	}
+	return "", 0
+}
+
+// wrapCharsetDecoder method transcodes the response body into UTF-8 if the
+// detected charset is not UTF-8, see [Client.SetResponseCharsetConversion].
+func (r *Response) wrapCharsetDecoder() {
+	if !r.Request.charsetConversion || r.Request.IsSaveResponse ||
+		r.Body == nil || r.Body == http.NoBody || !r.hasContent() {
+		return
+	}
+
+	ct := r.Header().Get(hdrContentTypeKey)
+	br := bufio.NewReaderSize(r.Body, charsetSniffLen)
+	charset, bomLen := detectCharset(ct, br)
+	_, _ = br.Discard(bomLen)
+	body := io.Reader(br)
+
+	if len(charset) > 0 && charset != "utf-8" {
+		dec, found := r.Request.client.CharsetDecoders()[charset]
+		if !found {
+			r.Request.log.Warnf("Charset decoder is not found for '%s', see Client.AddCharsetDecoder", charset)
+		} else {
+			body = dec(br)
+			r.Header().Del(hdrContentLengthKey)
+			r.RawResponse.ContentLength = -1
+			if mediaType, params, err := mime.ParseMediaType(ct); err == nil {
+				params["charset"] = "utf-8"
+				r.Header().Set(hdrContentTypeKey, mime.FormatMediaType(mediaType, params))
+				if strings.Contains(mediaType, "xml") {
+					body = rewriteXMLEncoding(body)
+				}
+			}
+		}
+	}
+
+	r.Body = &charsetReadCloser{r: body, c: r.Body}
+}
+
+// rewriteXMLEncoding method rewrites the encoding of the XML declaration to
+// UTF-8 on the transcoded body; otherwise, the XML decoder fails on it.
+func rewriteXMLEncoding(r io.Reader) io.Reader {
+	br := bufio.NewReaderSize(r, charsetSniffLen)
+	b, _ := br.Peek(charsetSniffLen)
+	loc := xmlEncodingRegex.FindSubmatchIndex(b)
+	if loc == nil {
+		return br
+	}
+	head := make([]byte, 0, loc[1])
+	head = append(head, b[:loc[2]]...)
+	head = append(head, "utf-8"...)
+	head = append(head, b[loc[3]:loc[1]]...)
+	_, _ = br.Discard(loc[1])
+	return io.MultiReader(bytes.NewReader(head), br)
+}
+
+type charsetReadCloser struct {
+	r io.Reader
+	c io.Closer
+}
+
+func (cr *charsetReadCloser) Read(p []byte) (int, error) {
+	return cr.r.Read(p)
+}
+
+func (cr *charsetReadCloser) Close() error {
+	return cr.c.Close()
+}
+
+// transcodeReader transcodes the source reader using the decode function,
+// the function returns the consumed count of the source bytes, the remaining
+// bytes are given again with the next bytes.
+type transcodeReader struct {
+	r      io.Reader
+	decode func(dst, src []byte, atEOF bool) ([]byte, int)
+	buf    [4096]byte
+	src    []byte
+	out    []byte
+	err    error
+}
+
+func newTranscodeReader(r io.Reader, decode func(dst, src []byte, atEOF bool) ([]byte, int)) io.Reader {
+	return &transcodeReader{r: r, decode: decode}
+}
+
+func (tr *transcodeReader) Read(p []byte) (int, error) {
+	for len(tr.out) == 0 {
+		if tr.err != nil {
+			return 0, tr.err
+		}
+		n, err := tr.r.Read(tr.buf[:])
+		tr.src = append(tr.src, tr.buf[:n]...)
+		tr.err = err
+		var c int
+		tr.out, c = tr.decode(tr.out[:0], tr.src, err != nil)
+		tr.src = tr.src[:copy(tr.src, tr.src[c:])]
+	}
+	n := copy(p, tr.out)
+	tr.out = tr.out[n:]
+	return n, nil
+}
+
+func decodeWindows1252(r io.Reader) io.Reader {
+	return newTranscodeReader(r, func(dst, src []byte, _ bool) ([]byte, int) {
+		for _, b := range src {
+			c := rune(b)
+			if b >= 0x80 && b <= 0x9F {
+				c = windows1252High[b-0x80]
+			}
+			dst = utf8.AppendRune(dst, c)
+		}
+		return dst, len(src)
+	})
+}
+
+// decodeEncoding function returns the charset decoder of the given
+// `golang.org/x/text` encoding.
+func decodeEncoding(e encoding.Encoding) CharsetDecoder {
+	return func(r io.Reader) io.Reader {
+		return e.NewDecoder().Reader(r)
+	}
+}
+
+func decodeUTF16(bigEndian bool) CharsetDecoder {
+	return func(r io.Reader) io.Reader {
+		return newTranscodeReader(r, func(dst, src []byte, atEOF bool) ([]byte, int) {
+			unit := func(i int) rune {
+				if bigEndian {
+					return rune(src[i])<<8 | rune(src[i+1])
+				}
+				return rune(src[i+1])<<8 | rune(src[i])
+			}
+			i := 0
+			for ; i+1 < len(src); i += 2 {
+				c := unit(i)
+				if utf16.IsSurrogate(c) {
+					if i+3 >= len(src) {
+						if !atEOF {
+							break // wait for the low surrogate
+						}
+						c = utf8.RuneError
+					} else if d := utf16.DecodeRune(c, unit(i+2)); d != utf8.RuneError {
+						c = d
+						i += 2
+					} else {
+						c = utf8.RuneError
+					}
+				}
+				dst = utf8.AppendRune(dst, c)
+			}
+			if atEOF && i < len(src) {
+				// odd byte at the end
+				dst = utf8.AppendRune(dst, utf8.RuneError)
+				i = len(src)
+			}
+			return dst, i
+		})
+	}
+}
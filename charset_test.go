@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+func encodeUTF16LE(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u), byte(u>>8))
+	}
+	return b
+}
+
+func TestCharsetDecoders(t *testing.T) {
+	tests := []struct {
+		name     string
+		dec      CharsetDecoder
+		input    []byte
+		expected string
+	}{
+		{"windows-1252", decodeWindows1252, []byte("caf\xe9 \x80 \x93ok\x94"), "café € “ok”"},
+		{"utf-16le", decodeUTF16(false), encodeUTF16LE("héllo 😀"), "héllo 😀"},
+		{"utf-16be", decodeUTF16(true), []byte{0x00, 'h', 0xD8, 0x3D, 0xDE, 0x00}, "h😀"},
+		{"lone surrogate", decodeUTF16(false), []byte{0x3D, 0xD8, 'a', 0x00}, "�a"},
+		{"odd byte", decodeUTF16(false), []byte{'a', 0x00, 'b'}, "a�"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := io.ReadAll(tc.dec(iotest.OneByteReader(bytes.NewReader(tc.input))))
+			assertNil(t, err)
+			assertEqual(t, tc.expected, string(b))
+
+			b, err = io.ReadAll(tc.dec(bytes.NewReader(tc.input)))
+			assertNil(t, err)
+			assertEqual(t, tc.expected, string(b))
+		})
+	}
+}
+
+func TestResponseCharsetConversion(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latin1":
+			w.Header().Set(hdrContentTypeKey, "text/plain; charset=ISO-8859-1")
+			_, _ = w.Write([]byte("caf\xe9"))
+		case "/html":
+			w.Header().Set(hdrContentTypeKey, "text/html")
+			_, _ = w.Write([]byte("<html><head><meta charset=\"windows-1252\"></head><body>na\xefve</body></html>"))
+		case "/json-bom":
+			w.Header().Set(hdrContentTypeKey, "application/json")
+			_, _ = w.Write(append([]byte{0xFF, 0xFE}, encodeUTF16LE(`{"name":"résty"}`)...))
+		case "/xml":
+			w.Header().Set(hdrContentTypeKey, "application/xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="ISO-8859-1"?><user><name>r` + "\xe9" + `sty</name></user>`))
+		case "/gbk":
+			w.Header().Set(hdrContentTypeKey, "text/plain; charset=GB2312")
+			_, _ = w.Write([]byte("\xd6\xd0\xce\xc4")) // 中文
+		case "/sjis":
+			w.Header().Set(hdrContentTypeKey, "text/html")
+			_, _ = w.Write([]byte("<meta charset=\"Shift_JIS\"><p>\x93\xfa\x96\x7b</p>")) // 日本
+		case "/koi8-r":
+			w.Header().Set(hdrContentTypeKey, "text/plain; charset=KOI8-R")
+			_, _ = w.Write([]byte("resty"))
+		}
+	})
+	defer ts.Close()
+
+	c, lb := dcldb()
+	assertEqual(t, false, c.IsResponseCharsetConversion())
+
+	// disabled, by default
+	res, err := c.R().Get(ts.URL + "/latin1")
+	assertNil(t, err)
+	assertEqual(t, "caf\xe9", res.String())
+
+	c.SetResponseCharsetConversion(true)
+	assertEqual(t, true, c.IsResponseCharsetConversion())
+
+	res, err = c.R().Get(ts.URL + "/latin1")
+	assertNil(t, err)
+	assertEqual(t, "café", res.String())
+	assertEqual(t, "text/plain; charset=utf-8", res.Header().Get(hdrContentTypeKey))
+
+	res, err = c.R().Get(ts.URL + "/html")
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(res.String(), "naïve"))
+
+	user := struct {
+		Name string `json:"name" xml:"name"`
+	}{}
+	_, err = c.R().SetResult(&user).Get(ts.URL + "/json-bom")
+	assertNil(t, err)
+	assertEqual(t, "résty", user.Name)
+
+	user.Name = ""
+	_, err = c.R().SetResult(&user).Get(ts.URL + "/xml")
+	assertNil(t, err)
+	assertEqual(t, "résty", user.Name)
+
+	// request level overrides the client
+	res, err = c.R().SetResponseCharsetConversion(false).Get(ts.URL + "/latin1")
+	assertNil(t, err)
+	assertEqual(t, "caf\xe9", res.String())
+
+	res, err = c.R().Get(ts.URL + "/gbk")
+	assertNil(t, err)
+	assertEqual(t, "中文", res.String())
+
+	res, err = c.R().Get(ts.URL + "/sjis")
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(res.String(), "<p>日本</p>"))
+
+	// no decoder for koi8-r
+	res, err = c.R().Get(ts.URL + "/koi8-r")
+	assertNil(t, err)
+	assertEqual(t, "resty", res.String())
+	assertEqual(t, true, strings.Contains(lb.String(), "Charset decoder is not found for 'koi8-r'"))
+
+	c.AddCharsetDecoder("KOI8-R", func(r io.Reader) io.Reader {
+		b, _ := io.ReadAll(r)
+		return bytes.NewReader(bytes.ToUpper(b))
+	})
+	res, err = c.R().Get(ts.URL + "/koi8-r")
+	assertNil(t, err)
+	assertEqual(t, "RESTY", res.String())
+}
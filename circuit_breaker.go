@@ -112,6 +112,17 @@ const (
 	circuitBreakerStateHalfOpen
 )
 
+func (s circuitBreakerState) String() string {
+	switch s {
+	case circuitBreakerStateOpen:
+		return "open"
+	case circuitBreakerStateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
 func (cb *CircuitBreaker) getState() circuitBreakerState {
 	return cb.state.Load().(circuitBreakerState)
 }
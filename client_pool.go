@@ -0,0 +1,364 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultClientPoolMaxClients  = 100
+	defaultClientPoolIdleTimeout = 30 * time.Minute
+	defaultClientPoolCloseDelay  = time.Minute
+)
+
+// ErrClientPoolClosed error is returned by the [ClientPool] methods after the
+// pool is closed.
+var ErrClientPoolClosed = errors.New("resty: client pool is closed")
+
+// ClientFactoryFunc type is for creating the client of the given key, e.g.,
+// the tenant ID, see [NewClientPool].
+type ClientFactoryFunc func(key string) (*Client, error)
+
+// ClientPool struct is the bounded pool of the Resty clients keyed by, e.g.,
+// the tenant ID or the config hash, for the multi-tenant services. The clients
+// are created on demand, and the least recently used clients are evicted and
+// closed once the max clients limit is reached or they are idle longer than
+// the idle timeout, so the transports and connections are not leaked.
+//
+//	pool := resty.NewClientPool(func(tenantID string) (*resty.Client, error) {
+//		cfg, err := loadTenantConfig(tenantID)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return resty.NewFromConfig(cfg)
+//	}).SetMaxClients(500)
+//	defer pool.Close()
+//
+//	client, err := pool.Get(tenantID)
+//
+// NOTE: The evicted client is closed after the close delay, see
+// [ClientPool.SetCloseDelay] and [Client.Close], so do not hold the client
+// beyond the unit of work; get it from the pool each time.
+type ClientPool struct {
+	lock        *sync.Mutex
+	factory     ClientFactoryFunc
+	maxClients  int
+	idleTimeout time.Duration
+	closeDelay  time.Duration
+	entries     map[string]*list.Element
+	lru         *list.List
+	calls       map[string]*clientPoolCall
+	closing     map[*Client]*time.Timer
+	closed      bool
+}
+
+// NewClientPool function creates a new [ClientPool] with the given client
+// factory and default settings.
+//
+// The default settings are:
+//   - MaxClients: 100
+//   - IdleTimeout: 30 minutes
+//   - CloseDelay: 1 minute
+func NewClientPool(factory ClientFactoryFunc) *ClientPool {
+	return &ClientPool{
+		lock:        &sync.Mutex{},
+		factory:     factory,
+		maxClients:  defaultClientPoolMaxClients,
+		idleTimeout: defaultClientPoolIdleTimeout,
+		closeDelay:  defaultClientPoolCloseDelay,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		calls:       make(map[string]*clientPoolCall),
+		closing:     make(map[*Client]*time.Timer),
+	}
+}
+
+// SetMaxClients method sets the maximum number of clients held by the
+// [ClientPool]. The least recently used client is evicted once the limit is
+// reached. Zero means no limit.
+func (p *ClientPool) SetMaxClients(n int) *ClientPool {
+	p.lock.Lock()
+	p.maxClients = n
+	evicted := p.evict()
+	p.lock.Unlock()
+	p.closeEvicted(evicted)
+	return p
+}
+
+// SetIdleTimeout method sets the duration after which the unused client is
+// evicted from the [ClientPool]. Zero means no idle timeout.
+func (p *ClientPool) SetIdleTimeout(d time.Duration) *ClientPool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.idleTimeout = d
+	return p
+}
+
+// SetCloseDelay method sets the delay to close the evicted client, so the
+// requests of the client just returned by [ClientPool.Get], e.g., on another
+// goroutine, are not failed with [ErrClientClosed]. Zero means the evicted
+// client is closed immediately.
+func (p *ClientPool) SetCloseDelay(d time.Duration) *ClientPool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.closeDelay = d
+	return p
+}
+
+// Get method returns the client of the given key, the client is created using
+// the factory if it does not exist. The factory is called once for the
+// concurrent calls of the same key, and it does not block the other keys.
+func (p *ClientPool) Get(key string) (*Client, error) {
+	return p.get(key, func() (*Client, error) {
+		if p.factory == nil {
+			return nil, fmt.Errorf("resty: client pool factory is nil, key '%s'", key)
+		}
+		return p.factory(key)
+	})
+}
+
+// GetFromConfig method returns the client of the given [Config], keyed by the
+// config hash; the client is created using [NewFromConfig] if it does not
+// exist. The clients of the equal configs are shared.
+func (p *ClientPool) GetFromConfig(cfg Config) (*Client, error) {
+	key, err := configHash(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.get(key, func() (*Client, error) {
+		return NewFromConfig(cfg)
+	})
+}
+
+// Remove method evicts the client of the given key, it is closed after the
+// close delay. It returns true if the client existed.
+func (p *ClientPool) Remove(key string) bool {
+	p.lock.Lock()
+	el, found := p.entries[key]
+	if found {
+		p.lru.Remove(el)
+		delete(p.entries, key)
+	}
+	p.lock.Unlock()
+	if found {
+		p.closeEvicted([]*Client{el.Value.(*clientPoolEntry).client})
+	}
+	return found
+}
+
+// EvictIdle method evicts the clients idle longer than the idle timeout and
+// returns the number of evicted clients; they are closed after the close
+// delay. It is done on each [ClientPool.Get] as well; call it periodically to
+// release the clients of the inactive keys sooner.
+func (p *ClientPool) EvictIdle() int {
+	p.lock.Lock()
+	evicted := p.evictIdle()
+	p.lock.Unlock()
+	p.closeEvicted(evicted)
+	return len(evicted)
+}
+
+// Len method returns the number of clients held by the [ClientPool].
+func (p *ClientPool) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.lru.Len()
+}
+
+// Close method closes all the clients of the [ClientPool], including the
+// evicted ones waiting for the close delay; after that, [ClientPool.Get]
+// returns [ErrClientPoolClosed].
+func (p *ClientPool) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	clients := make([]*Client, 0, p.lru.Len()+len(p.closing))
+	for el := p.lru.Front(); el != nil; el = el.Next() {
+		clients = append(clients, el.Value.(*clientPoolEntry).client)
+	}
+	for c, timer := range p.closing {
+		if timer.Stop() {
+			clients = append(clients, c)
+		}
+	}
+	clear(p.entries)
+	clear(p.closing)
+	p.lru.Init()
+	p.lock.Unlock()
+
+	var errs []error
+	for _, c := range clients {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type clientPoolEntry struct {
+	key      string
+	client   *Client
+	lastUsed time.Time
+}
+
+// clientPoolCall type is the in-progress client creation of the key, the
+// concurrent calls of the same key wait for it.
+type clientPoolCall struct {
+	done   chan struct{}
+	client *Client
+	err    error
+}
+
+func (p *ClientPool) get(key string, create func() (*Client, error)) (*Client, error) {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil, ErrClientPoolClosed
+	}
+
+	evicted := p.evictIdle()
+	if el, found := p.entries[key]; found {
+		e := el.Value.(*clientPoolEntry)
+		e.lastUsed = timeNow()
+		p.lru.MoveToFront(el)
+		p.lock.Unlock()
+		p.closeEvicted(evicted)
+		return e.client, nil
+	}
+
+	// the client is created without the lock held, so the factory does not
+	// block the other keys; the concurrent calls of the same key wait for it
+	if call, found := p.calls[key]; found {
+		p.lock.Unlock()
+		p.closeEvicted(evicted)
+		<-call.done
+		return call.client, call.err
+	}
+	call := &clientPoolCall{done: make(chan struct{})}
+	p.calls[key] = call
+	p.lock.Unlock()
+	p.closeEvicted(evicted)
+
+	p.createClient(key, call, create)
+	return call.client, call.err
+}
+
+func (p *ClientPool) createClient(key string, call *clientPoolCall, create func() (*Client, error)) {
+	created := false
+	defer func() {
+		if !created {
+			// the factory panicked
+			call.err = fmt.Errorf("resty: client pool factory failed, key '%s'", key)
+		}
+
+		var evicted []*Client
+		p.lock.Lock()
+		delete(p.calls, key)
+		switch {
+		case call.err != nil:
+		case p.closed:
+			// not returned to the caller, it is closed immediately
+			evicted = []*Client{call.client}
+			call.client, call.err = nil, ErrClientPoolClosed
+		default:
+			p.entries[key] = p.lru.PushFront(&clientPoolEntry{key: key, client: call.client, lastUsed: timeNow()})
+			evicted = p.evict()
+		}
+		close(call.done)
+		p.lock.Unlock()
+		p.closeEvicted(evicted)
+	}()
+
+	call.client, call.err = create()
+	created = true
+}
+
+// evict method must be called with the lock held, it returns the evicted clients
+func (p *ClientPool) evict() []*Client {
+	var evicted []*Client
+	for p.maxClients > 0 && p.lru.Len() > p.maxClients {
+		evicted = append(evicted, p.remove(p.lru.Back()))
+	}
+	return evicted
+}
+
+// evictIdle method must be called with the lock held, it returns the evicted
+// clients
+func (p *ClientPool) evictIdle() []*Client {
+	if p.idleTimeout <= 0 {
+		return nil
+	}
+	var evicted []*Client
+	now := timeNow()
+	for el := p.lru.Back(); el != nil; el = p.lru.Back() {
+		if now.Sub(el.Value.(*clientPoolEntry).lastUsed) < p.idleTimeout {
+			break
+		}
+		evicted = append(evicted, p.remove(el))
+	}
+	return evicted
+}
+
+func (p *ClientPool) remove(el *list.Element) *Client {
+	e := el.Value.(*clientPoolEntry)
+	p.lru.Remove(el)
+	delete(p.entries, e.key)
+	return e.client
+}
+
+// closeEvicted method closes the evicted clients after the close delay, it
+// must be called without the lock held.
+func (p *ClientPool) closeEvicted(clients []*Client) {
+	if len(clients) == 0 {
+		return
+	}
+	p.lock.Lock()
+	delay := p.closeDelay
+	if delay > 0 && !p.closed {
+		for _, c := range clients {
+			p.closing[c] = time.AfterFunc(delay, func() {
+				p.lock.Lock()
+				delete(p.closing, c)
+				p.lock.Unlock()
+				closeClients([]*Client{c})
+			})
+		}
+		p.lock.Unlock()
+		return
+	}
+	p.lock.Unlock()
+	closeClients(clients)
+}
+
+// closeClients function closes the given clients, it must be called without
+// the lock held, since the close hooks may take time.
+func closeClients(clients []*Client) {
+	for _, c := range clients {
+		silently(c.Close())
+	}
+}
+
+func configHash(cfg Config) (string, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	sum := sha256.Sum256(b)
+	return "config:" + hex.EncodeToString(sum[:16]), nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientPool(t *testing.T) {
+	var created []string
+	pool := NewClientPool(func(key string) (*Client, error) {
+		if key == "bad" {
+			return nil, errors.New("unknown tenant")
+		}
+		created = append(created, key)
+		return dcnl().SetHeader("X-Tenant-ID", key), nil
+	}).SetMaxClients(2).SetCloseDelay(0)
+
+	a, err := pool.Get("a")
+	assertError(t, err)
+	assertEqual(t, "a", a.Header().Get("X-Tenant-ID"))
+
+	a2, err := pool.Get("a")
+	assertError(t, err)
+	assertEqual(t, true, a == a2)
+
+	b, _ := pool.Get("b")
+	_, _ = pool.Get("a") // a is the most recently used
+	c, _ := pool.Get("c")
+	assertEqual(t, 2, pool.Len())
+	assertEqual(t, true, b.IsClosed())
+	assertEqual(t, false, a.IsClosed())
+	assertEqual(t, false, c.IsClosed())
+	assertEqual(t, []string{"a", "b", "c"}, created)
+
+	_, err = pool.Get("bad")
+	assertNotNil(t, err)
+	assertEqual(t, 2, pool.Len())
+
+	assertEqual(t, true, pool.Remove("a"))
+	assertEqual(t, false, pool.Remove("a"))
+	assertEqual(t, true, a.IsClosed())
+
+	assertNil(t, pool.Close())
+	assertEqual(t, true, c.IsClosed())
+	assertEqual(t, 0, pool.Len())
+
+	_, err = pool.Get("a")
+	assertErrorIs(t, ErrClientPoolClosed, err)
+}
+
+func TestClientPoolIdleTimeout(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	pool := NewClientPool(func(string) (*Client, error) { return dcnl(), nil }).
+		SetIdleTimeout(time.Minute).
+		SetCloseDelay(0)
+	defer pool.Close()
+
+	a, _ := pool.Get("a")
+	now = now.Add(30 * time.Second)
+	b, _ := pool.Get("b")
+
+	now = now.Add(45 * time.Second)
+	assertEqual(t, 1, pool.EvictIdle())
+	assertEqual(t, true, a.IsClosed())
+	assertEqual(t, false, b.IsClosed())
+
+	now = now.Add(time.Minute)
+	_, _ = pool.Get("c")
+	assertEqual(t, true, b.IsClosed())
+	assertEqual(t, 1, pool.Len())
+}
+
+func TestClientPoolGetFromConfig(t *testing.T) {
+	pool := NewClientPool(nil)
+	defer pool.Close()
+
+	c1, err := pool.GetFromConfig(Config{BaseURL: "https://a.example.com"})
+	assertError(t, err)
+	c2, _ := pool.GetFromConfig(Config{BaseURL: "https://a.example.com"})
+	c3, _ := pool.GetFromConfig(Config{BaseURL: "https://b.example.com"})
+	assertEqual(t, true, c1 == c2)
+	assertEqual(t, false, c1 == c3)
+	assertEqual(t, "https://b.example.com", c3.BaseURL())
+
+	_, err = pool.GetFromConfig(Config{Proxy: "ftp://proxy"})
+	assertErrorIs(t, ErrInvalidConfig, err)
+
+	_, err = pool.Get("tenant")
+	assertNotNil(t, err)
+}
+
+func TestClientPoolCloseDelay(t *testing.T) {
+	pool := NewClientPool(func(string) (*Client, error) { return dcnl(), nil }).
+		SetMaxClients(1).
+		SetCloseDelay(50 * time.Millisecond)
+
+	a, _ := pool.Get("a")
+	_, _ = pool.Get("b")
+	assertEqual(t, 1, pool.Len())
+
+	// the evicted client is still usable until the close delay
+	assertEqual(t, false, a.IsClosed())
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, true, a.IsClosed())
+
+	// pool close does not wait for the close delay
+	pool.SetCloseDelay(time.Hour)
+	b, _ := pool.Get("b")
+	assertEqual(t, true, pool.Remove("b"))
+	assertEqual(t, false, b.IsClosed())
+	assertNil(t, pool.Close())
+	assertEqual(t, true, b.IsClosed())
+}
+
+func TestClientPoolConcurrentCreate(t *testing.T) {
+	var created atomic.Int32
+	release := make(chan struct{})
+	var pool *ClientPool
+	pool = NewClientPool(func(key string) (*Client, error) {
+		created.Add(1)
+		if key == "slow" {
+			<-release
+		}
+		// the factory may use the pool
+		_ = pool.Len()
+		return dcnl(), nil
+	})
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	clients := make([]*Client, 10)
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := pool.Get("slow")
+			assertNil(t, err)
+			clients[i] = c
+		}(i)
+	}
+
+	// the slow factory does not block the other keys
+	_, err := pool.Get("fast")
+	assertNil(t, err)
+
+	close(release)
+	wg.Wait()
+	assertEqual(t, int32(2), created.Load())
+	for _, c := range clients {
+		assertEqual(t, true, c == clients[0])
+	}
+
+	// the factory panic does not leave the key stuck
+	panicked := false
+	pool2 := NewClientPool(func(string) (*Client, error) {
+		if !panicked {
+			panicked = true
+			panic("boom")
+		}
+		return dcnl(), nil
+	})
+	defer pool2.Close()
+	func() {
+		defer func() { assertNotNil(t, recover()) }()
+		_, _ = pool2.Get("a")
+	}()
+	_, err = pool2.Get("a")
+	assertNil(t, err)
+}
@@ -12,20 +12,27 @@ import (
 	"context"
 	cryprand "crypto/rand"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -648,6 +655,107 @@ func TestClientPreRequestMiddlewareError(t *testing.T) {
 	assertNil(t, resp)
 }
 
+func TestClientNamedMiddlewares(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c, lb := dcldb()
+	assertEqual(t, []string{PrepareRequestMiddlewareName}, c.RequestMiddlewareNames())
+	assertEqual(t, []string{AutoParseResponseMiddlewareName, SaveToFileResponseMiddlewareName},
+		c.ResponseMiddlewareNames())
+
+	var order []string
+	record := func(name string) RequestMiddleware {
+		return func(_ *Client, r *Request) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	c.AddRequestMiddlewareNamed("auth", Before(PrepareRequestMiddlewareName), record("auth")).
+		AddRequestMiddlewareNamed("first", First(), record("first")).
+		AddRequestMiddlewareNamed("signer", After(PrepareRequestMiddlewareName), record("signer")).
+		AddRequestMiddleware(record("unnamed")).
+		AddRequestMiddlewareNamed("tracing", After("auth"), record("tracing")).
+		AddRequestMiddlewareNamed("last", Last(), record("last"))
+	assertEqual(t, []string{"first", "auth", "tracing", "", PrepareRequestMiddlewareName, "signer", "last"},
+		c.RequestMiddlewareNames())
+
+	// replace the existing middleware of the same name
+	c.AddRequestMiddlewareNamed("first", Before("last"), record("first-replaced"))
+	assertEqual(t, []string{"auth", "tracing", "", PrepareRequestMiddlewareName, "signer", "first", "last"},
+		c.RequestMiddlewareNames())
+
+	// unknown reference is added at the default position
+	c.AddRequestMiddlewareNamed("orphan", After("not-exists"), record("orphan"))
+	assertEqual(t, true, strings.Contains(lb.String(), "Request middleware 'not-exists' not found, adding 'orphan' at the default position"))
+	assertEqual(t, []string{"auth", "tracing", "", "orphan", PrepareRequestMiddlewareName, "signer", "first", "last"},
+		c.RequestMiddlewareNames())
+
+	res, err := c.R().Get(ts.URL + "/")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, []string{"auth", "tracing", "unnamed", "orphan", "signer", "first-replaced", "last"}, order)
+
+	c.RemoveMiddleware("auth", "orphan", "not-exists", "")
+	assertEqual(t, []string{"tracing", "", PrepareRequestMiddlewareName, "signer", "first", "last"},
+		c.RequestMiddlewareNames())
+
+	var parsed bool
+	c.AddResponseMiddlewareNamed("audit", After(AutoParseResponseMiddlewareName), func(_ *Client, res *Response) error {
+		parsed = res.Result() != nil
+		return nil
+	})
+	assertEqual(t, []string{AutoParseResponseMiddlewareName, "audit", SaveToFileResponseMiddlewareName},
+		c.ResponseMiddlewareNames())
+
+	c.RemoveMiddleware(SaveToFileResponseMiddlewareName)
+	c.AddResponseMiddlewareNamed("cleanup", Before("not-exists"), func(*Client, *Response) error { return nil })
+	assertEqual(t, true, strings.Contains(lb.String(), "Response middleware 'not-exists' not found, adding 'cleanup' at the default position"))
+	assertEqual(t, []string{AutoParseResponseMiddlewareName, "audit", "cleanup"}, c.ResponseMiddlewareNames())
+
+	_, err = c.R().SetResult(&map[string]any{}).Get(ts.URL + "/json")
+	assertError(t, err)
+	assertEqual(t, true, parsed)
+
+	// the clone does not affect the original client
+	cc := c.Clone(context.Background())
+	cc.RemoveMiddleware("audit")
+	cc.AddRequestMiddlewareNamed("clone", First(), record("clone"))
+	assertEqual(t, []string{AutoParseResponseMiddlewareName, "audit", "cleanup"}, c.ResponseMiddlewareNames())
+	assertEqual(t, "tracing", c.RequestMiddlewareNames()[0])
+}
+
+func TestRequestSkipMiddleware(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	var calls []string
+	c := dcnl().
+		AddRequestMiddlewareNamed("auth", MiddlewarePosition{}, func(_ *Client, r *Request) error {
+			calls = append(calls, "auth")
+			r.SetAuthToken("token")
+			return nil
+		}).
+		AddResponseMiddlewareNamed("audit", MiddlewarePosition{}, func(*Client, *Response) error {
+			calls = append(calls, "audit")
+			return nil
+		})
+
+	res, err := c.R().Get(ts.URL + "/")
+	assertError(t, err)
+	assertEqual(t, "Bearer token", res.Request.RawRequest.Header.Get(hdrAuthorizationKey))
+	assertEqual(t, []string{"auth", "audit"}, calls)
+
+	calls = nil
+	req := c.R().SkipMiddleware("auth").SkipMiddleware("audit", PrepareRequestMiddlewareName)
+	res, err = req.Clone(context.Background()).Get(ts.URL + "/")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "", res.Request.RawRequest.Header.Get(hdrAuthorizationKey))
+	assertEqual(t, 0, len(calls))
+}
+
 func TestClientAllowMethodGetPayload(t *testing.T) {
 	ts := createGetServer(t)
 	defer ts.Close()
@@ -838,6 +946,28 @@ func TestDeflateCompress(t *testing.T) {
 	}
 }
 
+func TestZstdCompress(t *testing.T) {
+	ts := createGenericServer(t)
+	defer ts.Close()
+
+	c := dcnl()
+	testcases := []struct{ url, want string }{
+		{ts.URL + "/zstd-test", "This is Zstd response testing"},
+		{ts.URL + "/zstd-test-empty-body", ""},
+		{ts.URL + "/zstd-test-no-body", ""},
+	}
+	for _, tc := range testcases {
+		resp, err := c.R().Get(tc.url)
+
+		assertError(t, err)
+		assertEqual(t, http.StatusOK, resp.StatusCode())
+		assertEqual(t, "200 OK", resp.Status())
+		assertEqual(t, tc.want, resp.String())
+
+		logResponse(t, resp)
+	}
+}
+
 type lzwReader struct {
 	s io.ReadCloser
 	r io.ReadCloser
@@ -1311,6 +1441,42 @@ func TestUnixSocket(t *testing.T) {
 	assertEqual(t, "Hello resty client from a server running on endpoint /hello!", res.String())
 }
 
+func TestClientSetUnixSocket(t *testing.T) {
+	unixSocketAddr := createUnixSocketEchoServer(t)
+	defer os.Remove(unixSocketAddr)
+
+	client := dcnl().SetProxy("http://localhost:8888").SetUnixSocket(unixSocketAddr)
+	assertEqual(t, unixSocketAddr, client.UnixSocket())
+	assertEqual(t, "http://localhost", client.BaseURL())
+	assertNil(t, client.ProxyURL())
+
+	res, err := client.R().Get("/hello")
+	assertNil(t, err)
+	assertEqual(t, "Hello resty client from a server running on endpoint /hello!", res.String())
+	assertEqual(t, "localhost", res.Request.RawRequest.Host)
+
+	// base URL is kept
+	client = dcnl().SetBaseURL("http://docker").SetUnixSocket(unixSocketAddr)
+	assertEqual(t, "http://docker", client.BaseURL())
+	res, err = client.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "Hi resty client from a server running on Unix domain socket!", res.String())
+
+	// dialer settings afterward keep the unix socket dial
+	client.SetHostMapping(map[string]string{"example.com": "127.0.0.1"}).
+		SetResolver(&net.Resolver{}).
+		SetDNSCache(NewDNSCache()).
+		SetDialPreference(DialPreference{Family: IPFamilyIPv4Only})
+	client.Client().CloseIdleConnections()
+	res, err = client.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "Hi resty client from a server running on Unix domain socket!", res.String())
+
+	// non http.Transport
+	client = dcnl().SetTransport(&CustomRoundTripper1{}).SetUnixSocket(unixSocketAddr)
+	assertEqual(t, "", client.UnixSocket())
+}
+
 func TestClientClone(t *testing.T) {
 	parent := New()
 
@@ -1556,3 +1722,611 @@ func TestClientOnCloseMultipleHooks(t *testing.T) {
 	assertNil(t, err)
 	assertEqual(t, []string{"first", "second", "third"}, executionOrder)
 }
+
+func TestClientCloseUnusable(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	var hookCount int
+	c := dcnl().SetBaseURL(ts.URL)
+	clone := c.Clone(context.Background())
+	c.OnClose(func() { hookCount++ })
+	derived := c.With(WithHeader("X-Tenant-ID", "acme"))
+
+	res, err := c.R().Get("/")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+
+	assertNil(t, clone.Close())
+	assertEqual(t, false, c.IsClosed())
+
+	assertNil(t, c.Close())
+	assertNil(t, c.Close())
+	assertEqual(t, 1, hookCount)
+	assertEqual(t, true, c.IsClosed())
+	assertEqual(t, true, derived.IsClosed())
+
+	res, err = c.R().Get("/")
+	assertErrorIs(t, ErrClientClosed, err)
+	assertNil(t, res)
+
+	_, err = derived.R().Get("/")
+	assertErrorIs(t, ErrClientClosed, err)
+}
+
+func TestClientFeatures(t *testing.T) {
+	c, lb := dcldb()
+	assertEqual(t, 0, len(c.Features()))
+	assertEqual(t, false, c.IsFeatureEnabled(FeatureAdaptiveRetry))
+
+	c.EnableFeature(FeatureResponseCache, FeatureAdaptiveRetry, Feature("not-exists"))
+	assertEqual(t, []Feature{FeatureAdaptiveRetry, FeatureResponseCache}, c.Features())
+	assertEqual(t, true, c.IsFeatureEnabled(FeatureAdaptiveRetry))
+	assertEqual(t, false, c.IsFeatureEnabled(Feature("not-exists")))
+	assertEqual(t, true, strings.Contains(lb.String(), "Ignoring unknown feature 'not-exists'"))
+
+	cc := c.Clone(context.Background())
+	cc.DisableFeature(FeatureResponseCache)
+	assertEqual(t, []Feature{FeatureAdaptiveRetry}, cc.Features())
+	assertEqual(t, true, c.IsFeatureEnabled(FeatureResponseCache))
+	assertEqual(t, "http3", FeatureHTTP3.String())
+}
+
+func TestClientRequestTemplate(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c, lb := dcldb()
+	c.SetBaseURL(ts.URL).
+		SetRequestTemplate("json", func(r *Request) *Request {
+			return r.SetHeader(hdrAcceptKey, "application/json").
+				SetQueryParam("page_size", "100").
+				SetResult(&map[string]any{})
+		}).
+		SetRequestTemplate("plain", nil)
+	assertEqual(t, []string{"json", "plain"}, c.RequestTemplateNames())
+
+	r1 := c.RT("json")
+	r2 := c.RT("json").SetQueryParam("page", "2")
+	assertEqual(t, "application/json", r1.Header.Get(hdrAcceptKey))
+	assertEqual(t, "100", r1.QueryParams.Get("page_size"))
+	assertEqual(t, "", r1.QueryParams.Get("page"))
+	assertEqual(t, "2", r2.QueryParams.Get("page"))
+	assertEqual(t, false, r1.Result == r2.Result)
+
+	res, err := c.RT("json").Get("/json")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "JSON response", (*res.Result().(*map[string]any))["TestGet"])
+
+	r3 := c.RT("plain")
+	assertEqual(t, 0, len(r3.Header))
+
+	r4 := c.RT("not-exists")
+	assertNotNil(t, r4)
+	assertEqual(t, true, strings.Contains(lb.String(), "Ignoring unknown request template 'not-exists'"))
+
+	cc := c.Clone(context.Background())
+	cc.RemoveRequestTemplate("json")
+	assertEqual(t, []string{"plain"}, cc.RequestTemplateNames())
+	assertEqual(t, []string{"json", "plain"}, c.RequestTemplateNames())
+}
+
+func TestClientSlogLogger(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New().SetSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})))
+	_, ok := c.Logger().(*slogLogger)
+	assertEqual(t, true, ok)
+
+	t.Run("log levels", func(t *testing.T) {
+		buf.Reset()
+		c.Logger().Warnf("warn %d", 1)
+		c.Logger().Errorf("error %d", 2)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assertEqual(t, 2, len(lines))
+
+		entry := map[string]any{}
+		assertNil(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assertEqual(t, "WARN", entry["level"])
+		assertEqual(t, "warn 1", entry["msg"])
+		assertNil(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assertEqual(t, "ERROR", entry["level"])
+		assertEqual(t, "error 2", entry["msg"])
+	})
+
+	t.Run("structured debug log", func(t *testing.T) {
+		buf.Reset()
+		res, err := c.R().
+			SetDebug(true).
+			EnableTrace().
+			SetHeader("X-Custom", "value").
+			Get(ts.URL + "/json")
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+
+		entry := struct {
+			Level   string `json:"level"`
+			Msg     string `json:"msg"`
+			Request struct {
+				Method string      `json:"method"`
+				URI    string      `json:"uri"`
+				Header http.Header `json:"header"`
+			} `json:"request"`
+			Response struct {
+				StatusCode int    `json:"status_code"`
+				Body       string `json:"body"`
+			} `json:"response"`
+			TraceInfo map[string]any `json:"trace_info"`
+		}{}
+		assertNil(t, json.Unmarshal(buf.Bytes(), &entry))
+		assertEqual(t, "DEBUG", entry.Level)
+		assertEqual(t, "resty debug log", entry.Msg)
+		assertEqual(t, MethodGet, entry.Request.Method)
+		assertEqual(t, "/json", entry.Request.URI)
+		assertEqual(t, "value", entry.Request.Header.Get("X-Custom"))
+		assertEqual(t, http.StatusOK, entry.Response.StatusCode)
+		assertEqual(t, true, strings.Contains(entry.Response.Body, "JSON response"))
+		assertNotNil(t, entry.TraceInfo["total_time"])
+	})
+}
+
+func TestClientDebugLogJSONFormatter(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "text/html")
+		_, _ = w.Write([]byte("<p>hello</p>"))
+	})
+	defer ts.Close()
+
+	c, logBuf := dcldb()
+	c.SetDebugLogFormatter(DebugLogJSONFormatter)
+
+	for range 2 {
+		_, err := c.R().SetBody(map[string]string{"q": "<b>"}).Post(ts.URL + "/search")
+		assertNil(t, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	assertEqual(t, 2, len(lines))
+	for _, line := range lines {
+		_, jsonStr, found := strings.Cut(line, "DEBUG RESTY ")
+		assertEqual(t, true, found)
+
+		dl := &DebugLog{}
+		assertNil(t, json.Unmarshal([]byte(jsonStr), dl))
+		assertEqual(t, MethodPost, dl.Request.Method)
+		assertEqual(t, "/search", dl.Request.URI)
+		assertEqual(t, true, strings.Contains(dl.Request.Body, `"q": "<b>"`))
+		assertEqual(t, http.StatusOK, dl.Response.StatusCode)
+		assertEqual(t, "<p>hello</p>", dl.Response.Body)
+		assertNil(t, dl.TraceInfo)
+
+		// no HTML escaping and trace info is omitted
+		assertEqual(t, true, strings.Contains(jsonStr, `"body":"<p>hello</p>"`))
+		assertEqual(t, false, strings.Contains(jsonStr, "trace_info"))
+	}
+}
+
+func TestClientDebugLogRedaction(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "server-secret"})
+		w.Header().Set(hdrContentTypeKey, jsonContentType)
+		_, _ = w.Write([]byte(`{"token":"response-secret","name":"resty"}`))
+	})
+	defer ts.Close()
+
+	c, logBuf := dcldb()
+	c.SetDebugLogRedaction(
+		NewDebugLogRedaction().
+			AddHeaders("x-api-key").
+			AddQueryParams("access_token").
+			AddBodyPatterns(
+				regexp.MustCompile(`"(?:password|token)":\s*"([^"]*)"`),
+				regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`),
+			),
+	)
+	assertNotNil(t, c.DebugLogRedaction())
+
+	var callbackDebugLog *DebugLog
+	c.OnDebugLog(func(dl *DebugLog, _ *Response) {
+		callbackDebugLog = dl
+	})
+
+	res, err := c.R().
+		SetAuthToken("request-secret").
+		SetHeader("X-Api-Key", "key-secret").
+		SetQueryParams(map[string]string{"access_token": "query-secret", "page": "1"}).
+		SetBody(`{"password":"body-secret","card":"1234-5678-9012-3456","user":"jeeva"}`).
+		SetRetryCount(1).
+		SetAllowNonIdempotentRetry(true).
+		AddRetryConditions(func(r *Response, _ error) bool { return r.Request.Attempt == 1 }).
+		Post(ts.URL + "/login")
+	assertNil(t, err)
+	assertEqual(t, 2, res.Request.Attempt)
+
+	logContent := logBuf.String()
+	for _, secret := range []string{
+		"request-secret", "key-secret", "query-secret", "body-secret",
+		"1234-5678-9012-3456", "server-secret", "response-secret",
+	} {
+		assertEqual(t, false, strings.Contains(logContent, secret))
+	}
+	assertEqual(t, 2, strings.Count(logContent, "access_token=%5BREDACTED%5D"))
+	assertEqual(t, true, strings.Contains(logContent, "page=1"))
+	assertEqual(t, true, strings.Contains(logContent, `"user":"jeeva"`))
+	assertEqual(t, true, strings.Contains(logContent, `"name": "resty"`))
+
+	assertEqual(t, "[REDACTED]", callbackDebugLog.Request.Header.Get(hdrAuthorizationKey))
+	assertEqual(t, "[REDACTED]", callbackDebugLog.Response.Header.Get("Set-Cookie"))
+
+	// the actual request and response are untouched
+	assertEqual(t, "Bearer request-secret", res.Request.Header.Get(hdrAuthorizationKey))
+	assertEqual(t, true, strings.Contains(res.String(), "response-secret"))
+}
+
+func TestDebugLogRedactionCurlCmd(t *testing.T) {
+	dl := &DebugLog{
+		Request: &DebugLogRequest{
+			URI:     "/path?token=abc&x=1",
+			Header:  http.Header{hdrAuthorizationKey: []string{"Bearer secret"}},
+			CurlCmd: `curl -X GET -H 'Authorization: Bearer secret' http://host/path?token=abc&x=1`,
+		},
+	}
+	NewDebugLogRedaction().AddQueryParams("token").apply(dl)
+	assertEqual(t, "/path?token=%5BREDACTED%5D&x=1", dl.Request.URI)
+	assertEqual(t,
+		`curl -X GET -H 'Authorization: [REDACTED]' http://host/path?token=%5BREDACTED%5D&x=1`,
+		dl.Request.CurlCmd,
+	)
+}
+
+func TestClientDebugIf(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c, logBuf := dcldb()
+	c.SetDebug(false).
+		SetDebugIf(func(r *Request) bool {
+			return strings.HasSuffix(r.RawRequest.URL.Path, "/json")
+		})
+
+	res, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, false, res.Request.Debug)
+	assertEqual(t, 0, logBuf.Len())
+
+	res, err = c.R().Get(ts.URL + "/json")
+	assertNil(t, err)
+	assertEqual(t, true, res.Request.Debug)
+	assertEqual(t, true, strings.Contains(logBuf.String(), "/json"))
+
+	// condition and sampling both apply
+	logBuf.Reset()
+	c.SetDebugSampleRate(0)
+	res, err = c.R().Get(ts.URL + "/json")
+	assertNil(t, err)
+	assertEqual(t, false, res.Request.Debug)
+	assertEqual(t, 0, logBuf.Len())
+}
+
+func TestClientDebugSampleRate(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c := dcnl().SetDebugSampleRate(0.5)
+	sampled := 0
+	for range 200 {
+		res, err := c.R().Get(ts.URL + "/")
+		assertNil(t, err)
+		if res.Request.Debug {
+			sampled++
+		}
+	}
+	assertEqual(t, true, sampled > 0 && sampled < 200)
+
+	// out of range values are clamped
+	c.SetDebugSampleRate(5)
+	res, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, false, res.Request.Debug)
+
+	// enabled debug mode is not sampled
+	c.SetDebugSampleRate(0).SetDebug(true)
+	res, err = c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, true, res.Request.Debug)
+}
+
+func TestClientSetJSONMarshalerUnmarshaler(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set(hdrContentTypeKey, jsonContentType)
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write(b)
+	})
+	defer ts.Close()
+
+	var marshalCalls, unmarshalCalls atomic.Int32
+	marshal := func(v any) ([]byte, error) {
+		marshalCalls.Add(1)
+		return json.Marshal(v)
+	}
+	unmarshal := func(b []byte, v any) error {
+		unmarshalCalls.Add(1)
+		return json.Unmarshal(b, v)
+	}
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	c, logBuf := dcldb()
+	c.SetJSONMarshaler(marshal).SetJSONUnmarshaler(unmarshal)
+
+	result := &user{}
+	res, err := c.R().
+		SetBody(&user{Name: "<resty>"}).
+		SetResult(result).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "<resty>", result.Name)
+	assertEqual(t, int32(2), marshalCalls.Load()) // request body and debug log
+	assertEqual(t, int32(1), unmarshalCalls.Load())
+	assertEqual(t, true, strings.Contains(logBuf.String(), `"name": "\u003cresty\u003e"`))
+
+	// error decoding
+	errResult := &user{}
+	res, err = c.R().
+		SetBody(map[string]string{"name": "failed"}).
+		SetError(errResult).
+		Post(ts.URL + "/error")
+	assertNil(t, err)
+	assertEqual(t, http.StatusBadRequest, res.StatusCode())
+	assertEqual(t, "failed", errResult.Name)
+	assertEqual(t, int32(2), unmarshalCalls.Load())
+}
+
+func TestClientSetMsgpackCodec(t *testing.T) {
+	// the test codec frames the JSON payload to tell it apart
+	marshal := func(v any) ([]byte, error) {
+		b, err := json.Marshal(v)
+		return append([]byte("MP:"), b...), err
+	}
+	unmarshal := func(b []byte, v any) error {
+		return json.Unmarshal(bytes.TrimPrefix(b, []byte("MP:")), v)
+	}
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if !bytes.HasPrefix(b, []byte("MP:")) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set(hdrContentTypeKey, "application/vnd.msgpack; charset=binary")
+		_, _ = w.Write(b)
+	})
+	defer ts.Close()
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	c := dcnl().SetMsgpackCodec(marshal, unmarshal)
+	result := &user{}
+	res, err := c.R().
+		SetHeader(hdrContentTypeKey, "application/msgpack").
+		SetBody(&user{Name: "resty"}).
+		SetResult(result).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "resty", result.Name)
+}
+
+// testProtoMessage mimics the generated protobuf message.
+type testProtoMessage struct {
+	Name string `json:"name"`
+}
+
+func (m *testProtoMessage) ProtoReflect() any { return m }
+
+func TestClientSetProtobufCodec(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(hdrContentTypeKey) != protobufContentType ||
+			r.Header.Get(hdrAcceptKey) != protobufContentType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set(hdrContentTypeKey, "application/protobuf")
+		_, _ = w.Write(b)
+	})
+	defer ts.Close()
+
+	// JSON stands in for the wire format
+	c := dcnl().SetProtobufCodec(json.Marshal, json.Unmarshal)
+	result := &testProtoMessage{}
+	res, err := c.R().
+		SetBody(&testProtoMessage{Name: "resty"}).
+		SetResult(result).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "resty", result.Name)
+
+	// codec is not registered
+	_, err = dcnl().R().SetBody(&testProtoMessage{}).Post(ts.URL)
+	assertNotNil(t, err)
+	assertEqual(t, "resty: content-type encoder not found for application/x-protobuf", err.Error())
+}
+
+func TestClientSetDebugOutput(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	var full, headers, basic, jsonBuf bytes.Buffer
+	c, logBuf := dcldb()
+	c.SetDebugOutput(
+		DebugSink{Writer: &full},
+		DebugSink{Writer: &headers, Level: DebugLevelHeaders},
+		DebugSink{Writer: &basic, Level: DebugLevelBasic},
+		DebugSink{Writer: &jsonBuf, Level: DebugLevelHeaders, Formatter: DebugLogJSONFormatter},
+	)
+
+	res, err := c.R().
+		SetHeader("X-Custom", "custom-value").
+		SetBody(`{"request":"body"}`).
+		Post(ts.URL + "/json")
+	assertNil(t, err)
+	assertNotNil(t, res)
+	assertEqual(t, 0, logBuf.Len())
+
+	assertEqual(t, true, strings.Contains(full.String(), "custom-value"))
+	assertEqual(t, true, strings.Contains(full.String(), `{"request":"body"}`))
+
+	assertEqual(t, true, strings.Contains(headers.String(), "custom-value"))
+	assertEqual(t, false, strings.Contains(headers.String(), `{"request":"body"}`))
+
+	assertEqual(t, true, strings.Contains(basic.String(), "POST  /json"))
+	assertEqual(t, false, strings.Contains(basic.String(), "custom-value"))
+
+	dl := &DebugLog{}
+	assertNil(t, json.Unmarshal(jsonBuf.Bytes(), dl))
+	assertEqual(t, "custom-value", dl.Request.Header.Get("X-Custom"))
+	assertEqual(t, "", dl.Request.Body)
+
+	// restore the logger output
+	c.SetDebugOutput()
+	_, err = c.R().Get(ts.URL + "/json")
+	assertNil(t, err)
+	assertEqual(t, true, logBuf.Len() > 0)
+}
+
+func TestClientTraceHooks(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	var lock sync.Mutex
+	events := make([]string, 0)
+	add := func(r *Request, event string) {
+		lock.Lock()
+		defer lock.Unlock()
+		assertEqual(t, "trace-hooks", r.Header.Get("X-Test"))
+		events = append(events, event)
+	}
+
+	c := dcnl().
+		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+		SetTraceHooks(&TraceHooks{
+			DNSStart: func(r *Request, info httptrace.DNSStartInfo) {
+				add(r, "DNSStart:"+info.Host)
+			},
+			DNSDone: func(r *Request, _ httptrace.DNSDoneInfo) { add(r, "DNSDone") },
+			ConnectStart: func(r *Request, network, _ string) {
+				add(r, "ConnectStart:"+network)
+			},
+			ConnectDone:       func(r *Request, _, _ string, _ error) { add(r, "ConnectDone") },
+			TLSHandshakeStart: func(r *Request) { add(r, "TLSHandshakeStart") },
+			TLSHandshakeDone: func(r *Request, state tls.ConnectionState, err error) {
+				assertNil(t, err)
+				add(r, "TLSHandshakeDone")
+			},
+			GotConn: func(r *Request, info httptrace.GotConnInfo) {
+				add(r, "GotConn:"+strconv.FormatBool(info.Reused))
+			},
+			WroteRequest: func(r *Request, _ httptrace.WroteRequestInfo) {
+				add(r, "WroteRequest")
+			},
+			GotFirstResponseByte: func(r *Request) { add(r, "GotFirstResponseByte") },
+		})
+	assertNotNil(t, c.TraceHooks())
+
+	url := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+	for range 2 {
+		res, err := c.R().SetHeader("X-Test", "trace-hooks").Get(url)
+		assertNil(t, err)
+		assertEqual(t, "ok", res.String())
+		assertEqual(t, TraceInfo{}, res.Request.TraceInfo())
+	}
+
+	for _, expected := range []string{
+		"DNSStart:localhost", "DNSDone", "ConnectStart:tcp", "ConnectDone",
+		"TLSHandshakeStart", "TLSHandshakeDone", "GotConn:false", "GotConn:true",
+		"WroteRequest", "GotFirstResponseByte",
+	} {
+		assertEqual(t, true, slices.Contains(events, expected))
+	}
+	assertEqual(t, 1, slices.Index(events, "DNSDone"))
+	assertEqual(t, "GotFirstResponseByte", events[len(events)-1])
+}
+
+func TestClientRemoveAndReset(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "abc123"})
+			return
+		}
+		var cookies []string
+		for _, c := range r.Cookies() {
+			cookies = append(cookies, c.Name)
+		}
+		_, _ = fmt.Fprintf(w, "%s|%s|%s|%s", r.Header.Get("X-Tenant-ID"), r.Header.Get(hdrAuthorizationKey),
+			r.URL.RawQuery, strings.Join(cookies, ","))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL).
+		SetHeaders(map[string]string{"X-Tenant-ID": "acme", "X-Keep": "yes"}).
+		SetQueryParams(map[string]string{"api_key": "secret", "lang": "en"}).
+		SetCookie(&http.Cookie{Name: "pref", Value: "dark"}).
+		SetAuthToken("token")
+	defer c.Close()
+
+	_, err := c.R().Get("/login")
+	assertNil(t, err)
+	res, err := c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "acme|Bearer token|api_key=secret&lang=en|pref,session_id", res.String())
+
+	c.RemoveHeader("X-Tenant-ID").RemoveQueryParam("api_key")
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "|Bearer token|lang=en|pref,session_id", res.String())
+	assertEqual(t, "yes", c.Header().Get("X-Keep"))
+
+	c.ClearCookies()
+	assertEqual(t, 0, len(c.Cookies()))
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "|Bearer token|lang=en|", res.String())
+
+	c.SetCookie(&http.Cookie{Name: "pref", Value: "dark"}).SetAuthScheme("Token").SetBasicAuth("user", "pass")
+	c.Reset()
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "|||", res.String())
+	assertEqual(t, 0, len(c.Header()))
+	assertEqual(t, defaultAuthScheme, c.AuthScheme())
+	assertEqual(t, ts.URL, c.BaseURL())
+
+	// custom cookie jar is left as-is
+	jar := &customCookieJar{}
+	c.SetCookieJar(jar).ClearCookies()
+	assertEqual(t, true, c.CookieJar() == http.CookieJar(jar))
+}
+
+type customCookieJar struct{}
+
+func (*customCookieJar) SetCookies(*url.URL, []*http.Cookie) {}
+
+func (*customCookieJar) Cookies(*url.URL) []*http.Cookie { return nil }
@@ -0,0 +1,237 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig error is returned by [NewFromConfig] and [ConfigFromEnv] when
+// the configuration is invalid.
+var ErrInvalidConfig = errors.New("resty: invalid config")
+
+// Config type is the plain configuration of the Resty client, so it can be
+// hydrated from the configuration system of the service, e.g., YAML, JSON, or
+// the environment variables, see [NewFromConfig] and [ConfigFromEnv]. The zero
+// value fields are not applied, i.e., the client defaults are kept.
+//
+// The durations are the Go duration strings in the YAML and the environment
+// variables, e.g., `5s`, and the nanoseconds in JSON.
+type Config struct {
+	// BaseURL is the base URL of the client, see [Client.SetBaseURL].
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty" env:"BASE_URL"`
+
+	// Timeout is the request timeout, see [Client.SetTimeout].
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" env:"TIMEOUT"`
+
+	// RetryCount is the retry count, see [Client.SetRetryCount].
+	RetryCount int `json:"retry_count,omitempty" yaml:"retry_count,omitempty" env:"RETRY_COUNT"`
+
+	// RetryWaitTime is the retry wait time, see [Client.SetRetryWaitTime].
+	RetryWaitTime time.Duration `json:"retry_wait_time,omitempty" yaml:"retry_wait_time,omitempty" env:"RETRY_WAIT_TIME"`
+
+	// RetryMaxWaitTime is the retry max wait time, see [Client.SetRetryMaxWaitTime].
+	RetryMaxWaitTime time.Duration `json:"retry_max_wait_time,omitempty" yaml:"retry_max_wait_time,omitempty" env:"RETRY_MAX_WAIT_TIME"`
+
+	// Proxy is the proxy URL, see [Client.SetProxy].
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty" env:"PROXY"`
+
+	// RootCertFiles are the root certificate PEM files, see
+	// [Client.SetRootCertificates]. It is comma-separated in the environment
+	// variable.
+	RootCertFiles []string `json:"root_cert_files,omitempty" yaml:"root_cert_files,omitempty" env:"ROOT_CERT_FILES"`
+
+	// ClientCertFile and ClientKeyFile are the client certificate and key PEM
+	// files, see [Client.SetCertificateFromFile].
+	ClientCertFile string `json:"client_cert_file,omitempty" yaml:"client_cert_file,omitempty" env:"CLIENT_CERT_FILE"`
+	ClientKeyFile  string `json:"client_key_file,omitempty" yaml:"client_key_file,omitempty" env:"CLIENT_KEY_FILE"`
+
+	// InsecureSkipVerify disables the TLS certificate verification, see
+	// [tls.Config].InsecureSkipVerify. Use it for testing only.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty" env:"INSECURE_SKIP_VERIFY"`
+
+	// Headers are the default headers, see [Client.SetHeaders]. It is the
+	// comma-separated `key=value` pairs in the environment variable.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" env:"HEADERS"`
+}
+
+// NewFromConfig function creates a new Resty client from the given [Config].
+// Unlike the client setters, the invalid configuration, e.g., the missing
+// certificate file or the invalid proxy URL, is returned as an error wrapping
+// [ErrInvalidConfig].
+//
+//	var cfg resty.Config
+//	if err := yaml.Unmarshal(data, &cfg); err != nil {
+//		return err
+//	}
+//
+//	client, err := resty.NewFromConfig(cfg)
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+func NewFromConfig(cfg Config) (*Client, error) {
+	c := New()
+	if err := c.applyConfig(cfg); err != nil {
+		silently(c.Close())
+		return nil, err
+	}
+	return c, nil
+}
+
+// ConfigFromEnv function returns the [Config] read from the environment
+// variables of the given prefix, the variable names are in the `env` tags of
+// the [Config] fields.
+//
+//	// MYAPP_BASE_URL=https://api.example.com
+//	// MYAPP_TIMEOUT=5s
+//	// MYAPP_HEADERS=Accept=application/json,X-Tenant-ID=acme
+//	cfg, err := resty.ConfigFromEnv("MYAPP_")
+func ConfigFromEnv(prefix string) (Config, error) {
+	var cfg Config
+	v := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := prefix + sf.Tag.Get("env")
+		value, found := os.LookupEnv(name)
+		if !found || len(value) == 0 {
+			continue
+		}
+		if err := setConfigField(v.Field(i), value); err != nil {
+			return cfg, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+		}
+	}
+	return cfg, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func (c *Client) applyConfig(cfg Config) error {
+	if len(cfg.Proxy) > 0 {
+		pURL, err := parseProxyURL(cfg.Proxy)
+		if err == nil && pURL == nil {
+			err = fmt.Errorf("%w: %s", ErrUnsupportedProxyScheme, cfg.Proxy)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: proxy: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	var rootCerts [][]byte
+	for _, fp := range cfg.RootCertFiles {
+		data, err := os.ReadFile(fp)
+		if err != nil {
+			return fmt.Errorf("%w: root certificate: %w", ErrInvalidConfig, err)
+		}
+		rootCerts = append(rootCerts, data)
+	}
+
+	var clientCert *tls.Certificate
+	if len(cfg.ClientCertFile) > 0 || len(cfg.ClientKeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return fmt.Errorf("%w: client certificate: %w", ErrInvalidConfig, err)
+		}
+		clientCert = &cert
+	}
+
+	var tlsConfig *tls.Config
+	if len(rootCerts) > 0 || clientCert != nil || cfg.InsecureSkipVerify {
+		var err error
+		if tlsConfig, err = c.tlsConfig(); err != nil {
+			return fmt.Errorf("%w: tls: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	if len(cfg.BaseURL) > 0 {
+		c.SetBaseURL(cfg.BaseURL)
+	}
+	if cfg.Timeout > 0 {
+		c.SetTimeout(cfg.Timeout)
+	}
+	if cfg.RetryCount > 0 {
+		c.SetRetryCount(cfg.RetryCount)
+	}
+	if cfg.RetryWaitTime > 0 {
+		c.SetRetryWaitTime(cfg.RetryWaitTime)
+	}
+	if cfg.RetryMaxWaitTime > 0 {
+		c.SetRetryMaxWaitTime(cfg.RetryMaxWaitTime)
+	}
+	if len(cfg.Proxy) > 0 {
+		c.SetProxy(cfg.Proxy)
+	}
+	for _, data := range rootCerts {
+		c.handleCAs("root", data)
+	}
+	if clientCert != nil {
+		c.SetCertificates(*clientCert)
+	}
+	if cfg.InsecureSkipVerify {
+		c.lock.Lock()
+		tlsConfig.InsecureSkipVerify = true
+		c.lock.Unlock()
+	}
+	if len(cfg.Headers) > 0 {
+		c.SetHeaders(cfg.Headers)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setConfigField(f reflect.Value, value string) error {
+	switch {
+	case f.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+	case f.Kind() == reflect.String:
+		f.SetString(value)
+	case f.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case f.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case f.Kind() == reflect.Slice:
+		var values []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); len(s) > 0 {
+				values = append(values, s)
+			}
+		}
+		f.Set(reflect.ValueOf(values))
+	case f.Kind() == reflect.Map:
+		values := make(map[string]string)
+		for _, s := range strings.Split(value, ",") {
+			k, v, ok := strings.Cut(s, "=")
+			if !ok {
+				return fmt.Errorf("invalid key=value pair '%s'", strings.TrimSpace(s))
+			}
+			values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		f.Set(reflect.ValueOf(values))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	data := `{
+		"base_url": "https://api.example.com/",
+		"timeout": 5000000000,
+		"retry_count": 3,
+		"retry_wait_time": 200000000,
+		"proxy": "http://127.0.0.1:3128",
+		"root_cert_files": ["` + filepath.Join(getTestDataPath(), "sample-root.pem") + `"],
+		"client_cert_file": "` + filepath.Join(getTestDataPath(), "cert.pem") + `",
+		"client_key_file": "` + filepath.Join(getTestDataPath(), "key.pem") + `",
+		"insecure_skip_verify": true,
+		"headers": {"X-Tenant-ID": "acme"}
+	}`
+	var cfg Config
+	assertNil(t, json.Unmarshal([]byte(data), &cfg))
+
+	c, err := NewFromConfig(cfg)
+	assertNil(t, err)
+	defer c.Close()
+
+	assertEqual(t, "https://api.example.com", c.BaseURL())
+	assertEqual(t, 5*time.Second, c.Timeout())
+	assertEqual(t, 3, c.RetryCount())
+	assertEqual(t, 200*time.Millisecond, c.RetryWaitTime())
+	assertEqual(t, 2*time.Second, c.RetryMaxWaitTime()) // default is kept
+	assertEqual(t, "http://127.0.0.1:3128", c.ProxyURL().String())
+	assertEqual(t, "acme", c.Header().Get("X-Tenant-ID"))
+
+	tc, err := c.tlsConfig()
+	assertNil(t, err)
+	assertNotNil(t, tc.RootCAs)
+	assertEqual(t, 1, len(tc.Certificates))
+	assertEqual(t, true, tc.InsecureSkipVerify)
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"proxy", Config{Proxy: "ftp://127.0.0.1"}},
+		{"root certificate", Config{RootCertFiles: []string{"not-exists.pem"}}},
+		{"client certificate", Config{ClientCertFile: "not-exists.pem"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewFromConfig(tc.cfg)
+			assertNil(t, c)
+			assertErrorIs(t, ErrInvalidConfig, err)
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("MYAPP_BASE_URL", "https://api.example.com")
+	t.Setenv("MYAPP_TIMEOUT", "5s")
+	t.Setenv("MYAPP_RETRY_COUNT", "2")
+	t.Setenv("MYAPP_ROOT_CERT_FILES", "a.pem, b.pem")
+	t.Setenv("MYAPP_INSECURE_SKIP_VERIFY", "true")
+	t.Setenv("MYAPP_HEADERS", "Accept=application/json, X-Tenant-ID=acme")
+
+	cfg, err := ConfigFromEnv("MYAPP_")
+	assertNil(t, err)
+	assertEqual(t, Config{
+		BaseURL:            "https://api.example.com",
+		Timeout:            5 * time.Second,
+		RetryCount:         2,
+		RootCertFiles:      []string{"a.pem", "b.pem"},
+		InsecureSkipVerify: true,
+		Headers:            map[string]string{"Accept": "application/json", "X-Tenant-ID": "acme"},
+	}, cfg)
+
+	t.Setenv("MYAPP_TIMEOUT", "5 seconds")
+	_, err = ConfigFromEnv("MYAPP_")
+	assertErrorIs(t, ErrInvalidConfig, err)
+	assertEqual(t, true, strings.Contains(err.Error(), "MYAPP_TIMEOUT"))
+
+	t.Setenv("MYAPP_TIMEOUT", "")
+	t.Setenv("MYAPP_HEADERS", "Accept")
+	_, err = ConfigFromEnv("MYAPP_")
+	assertErrorIs(t, ErrInvalidConfig, err)
+}
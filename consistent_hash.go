@@ -0,0 +1,230 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"cmp"
+	"hash/crc32"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ConsistentHashKeyFunc type is used to derive the consistent hashing key
+// from the request, e.g., tenant ID; the empty key denotes no affinity.
+// The key set via [Request.SetLoadBalancerKey] takes precedence over it.
+type ConsistentHashKeyFunc func(r *Request) string
+
+// ConsistentHashKeyHeader function returns the [ConsistentHashKeyFunc] that
+// derives the key from the given request header.
+//
+//	lb, err := resty.NewConsistentHash(
+//		resty.ConsistentHashKeyHeader("X-Tenant-ID"),
+//		"https://api1.example.com",
+//		"https://api2.example.com",
+//	)
+func ConsistentHashKeyHeader(name string) ConsistentHashKeyFunc {
+	return func(r *Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// NewConsistentHash method creates the new consistent hashing request load
+// balancer instance with given key function and base URLs.
+func NewConsistentHash(keyFn ConsistentHashKeyFunc, baseURLs ...string) (*ConsistentHash, error) {
+	ch := &ConsistentHash{
+		lock:     new(sync.Mutex),
+		keyFn:    keyFn,
+		replicas: 160, // default is 160 virtual nodes per base URL
+	}
+	if err := ch.Refresh(baseURLs...); err != nil {
+		return ch, err
+	}
+	return ch, nil
+}
+
+var (
+	_ RequestLoadBalancer = (*ConsistentHash)(nil)
+	_ StatsLoadBalancer   = (*ConsistentHash)(nil)
+)
+
+// ConsistentHash struct used to implement the consistent hashing request load
+// balancer algorithm; the requests with the same key are sent to the same
+// Base URL, e.g., to improve the backend cache hit rates. Adding or removing
+// the Base URL remaps only the keys of its share.
+//
+// The requests without the key are balanced on the Round-Robin(RR).
+type ConsistentHash struct {
+	lock     *sync.Mutex
+	keyFn    ConsistentHashKeyFunc
+	replicas int
+	baseURLs []string
+	ring     []hashRingNode
+	current  int
+	stats    lbStats
+}
+
+// Next method returns the next Base URL based on the Round-Robin(RR) algorithm,
+// it is used when the request is not available.
+func (ch *ConsistentHash) Next() (string, error) {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	return ch.next()
+}
+
+// NextRequest method returns the Base URL for the key of the given request.
+func (ch *ConsistentHash) NextRequest(r *Request) (string, error) {
+	key := r.LoadBalancerKey()
+	if key == "" && ch.keyFn != nil {
+		key = ch.keyFn(r)
+	}
+
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	if key == "" {
+		return ch.next()
+	}
+	return ch.lookup(key)
+}
+
+// Feedback method records the request statistics in consistent hashing request load balancer
+func (ch *ConsistentHash) Feedback(f *RequestFeedback) {
+	ch.stats.end(f)
+}
+
+// Close method does nothing in consistent hashing request load balancer
+func (ch *ConsistentHash) Close() error { return nil }
+
+// Refresh method reset the existing Base URLs with the given Base URLs slice to refresh it
+func (ch *ConsistentHash) Refresh(baseURLs ...string) error {
+	result := make([]string, 0, len(baseURLs))
+	for _, u := range baseURLs {
+		baseURL, err := extractBaseURL(u)
+		if err != nil {
+			return err
+		}
+		result = append(result, baseURL)
+	}
+
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	ch.baseURLs = result
+	ch.current = 0
+	ch.build()
+	ch.stats.retain(result...)
+	return nil
+}
+
+// Stats method returns the statistics of the Base URLs
+func (ch *ConsistentHash) Stats() []TargetStats {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	result := make([]TargetStats, 0, len(ch.baseURLs))
+	for _, baseURL := range ch.baseURLs {
+		result = append(result, ch.stats.snapshot(baseURL, HostStateActive))
+	}
+	return result
+}
+
+// AddTarget method adds the given Base URL, if not exists; only the keys of its
+// share are remapped, and the in-flight requests are not affected. It is safe
+// for concurrent use, e.g., to apply the endpoint changes from the service discovery.
+func (ch *ConsistentHash) AddTarget(baseURL string) error {
+	baseURL, err := extractBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	if !slices.Contains(ch.baseURLs, baseURL) {
+		ch.baseURLs = append(slices.Clip(ch.baseURLs), baseURL)
+		ch.build()
+	}
+	return nil
+}
+
+// RemoveTarget method removes the given Base URL, if exists; only its keys are
+// remapped, and the in-flight requests to it are not affected.
+func (ch *ConsistentHash) RemoveTarget(baseURL string) {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	ch.baseURLs = removeTarget(ch.baseURLs, baseURL)
+	ch.current = 0
+	ch.build()
+	ch.stats.retain(ch.baseURLs...)
+}
+
+// SetTargets method replaces the Base URLs with the given ones, the statistics
+// of the existing Base URLs are retained. See [ConsistentHash.AddTarget]
+func (ch *ConsistentHash) SetTargets(baseURLs ...string) error {
+	return ch.Refresh(baseURLs...)
+}
+
+// SetReplicas method sets the number of the virtual nodes per Base URL on the
+// hash ring, default is `160`; more virtual nodes distribute the keys evenly.
+func (ch *ConsistentHash) SetReplicas(n int) {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+	if n > 0 {
+		ch.replicas = n
+		ch.build()
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type hashRingNode struct {
+	hash    uint32
+	baseURL string
+}
+
+// build method builds the hash ring. The caller must hold the lock.
+func (ch *ConsistentHash) build() {
+	ring := make([]hashRingNode, 0, len(ch.baseURLs)*ch.replicas)
+	for _, baseURL := range ch.baseURLs {
+		for i := 0; i < ch.replicas; i++ {
+			ring = append(ring, hashRingNode{
+				hash:    crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + baseURL)),
+				baseURL: baseURL,
+			})
+		}
+	}
+	slices.SortFunc(ring, func(a, b hashRingNode) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.baseURL, b.baseURL))
+	})
+	ch.ring = ring
+}
+
+// lookup method returns the Base URL of the first node clockwise from the
+// key hash on the ring. The caller must hold the lock.
+func (ch *ConsistentHash) lookup(key string) (string, error) {
+	if len(ch.ring) == 0 {
+		return "", ErrNoActiveHost
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+	idx, _ := slices.BinarySearchFunc(ch.ring, h, func(n hashRingNode, h uint32) int {
+		return cmp.Compare(n.hash, h)
+	})
+	if idx == len(ch.ring) {
+		idx = 0
+	}
+	ch.stats.begin(ch.ring[idx].baseURL)
+	return ch.ring[idx].baseURL, nil
+}
+
+// next method returns the next Base URL on the Round-Robin(RR). The caller
+// must hold the lock.
+func (ch *ConsistentHash) next() (string, error) {
+	if len(ch.baseURLs) == 0 {
+		return "", ErrNoActiveHost
+	}
+	baseURL := ch.baseURLs[ch.current]
+	ch.current = (ch.current + 1) % len(ch.baseURLs)
+	ch.stats.begin(baseURL)
+	return baseURL, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConsistentHash(t *testing.T) {
+	baseURLs := []string{"https://api1.example.com", "https://api2.example.com", "https://api3.example.com"}
+	ch, err := NewConsistentHash(ConsistentHashKeyHeader("X-Tenant-ID"), baseURLs...)
+	assertNil(t, err)
+
+	keys := make(map[string]string)
+	used := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("tenant-%d", i)
+		baseURL, err := ch.NextRequest(dcnl().R().SetHeader("X-Tenant-ID", key))
+		assertNil(t, err)
+		keys[key] = baseURL
+		used[baseURL]++
+
+		// same key, same base URL
+		again, _ := ch.NextRequest(dcnl().R().SetHeader("X-Tenant-ID", key))
+		assertEqual(t, baseURL, again)
+	}
+	assertEqual(t, 3, len(used))
+	for _, n := range used {
+		assertEqual(t, true, n > 50)
+	}
+
+	// removing the base URL remaps only its keys
+	assertNil(t, ch.Refresh(baseURLs[0], baseURLs[1]))
+	for key, baseURL := range keys {
+		remapped, _ := ch.NextRequest(dcnl().R().SetHeader("X-Tenant-ID", key))
+		if baseURL != baseURLs[2] {
+			assertEqual(t, baseURL, remapped)
+		} else {
+			assertEqual(t, true, remapped != baseURLs[2])
+		}
+	}
+
+	t.Run("request key", func(t *testing.T) {
+		for key, baseURL := range keys {
+			r := dcnl().R().SetLoadBalancerKey(key).SetHeader("X-Tenant-ID", "other")
+			assertEqual(t, key, r.LoadBalancerKey())
+			remapped, _ := ch.NextRequest(r)
+			expected, _ := ch.NextRequest(dcnl().R().SetHeader("X-Tenant-ID", key))
+			assertEqual(t, expected, remapped)
+			if baseURL != baseURLs[2] {
+				assertEqual(t, baseURL, remapped)
+			}
+		}
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		ch, _ := NewConsistentHash(ConsistentHashKeyHeader("X-Tenant-ID"), baseURLs...)
+		var result []string
+		for i := 0; i < 4; i++ {
+			baseURL, err := ch.NextRequest(dcnl().R())
+			assertNil(t, err)
+			result = append(result, baseURL)
+		}
+		assertEqual(t, append(baseURLs, baseURLs[0]), result)
+
+		baseURL, _ := ch.Next()
+		assertEqual(t, baseURLs[1], baseURL)
+	})
+
+	t.Run("no base urls", func(t *testing.T) {
+		ch, _ := NewConsistentHash(nil)
+		_, err := ch.NextRequest(dcnl().R())
+		assertErrorIs(t, ErrNoActiveHost, err)
+
+		ch, _ = NewConsistentHash(func(*Request) string { return "key" })
+		_, err = ch.NextRequest(dcnl().R())
+		assertErrorIs(t, ErrNoActiveHost, err)
+	})
+
+	t.Run("invalid base url", func(t *testing.T) {
+		_, err := NewConsistentHash(nil, "://example.com")
+		assertNotNil(t, err)
+	})
+}
+
+func TestConsistentHashClient(t *testing.T) {
+	var servers []string
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("server%d", i)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, name)
+		}))
+		defer ts.Close()
+		servers = append(servers, ts.URL)
+	}
+
+	ch, err := NewConsistentHash(ConsistentHashKeyHeader("X-Tenant-ID"), servers...)
+	assertNil(t, err)
+	ch.SetReplicas(50)
+	c := dcnl().SetLoadBalancer(ch)
+	defer c.Close()
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("tenant-%d", i)
+		res1, err := c.R().SetHeader("X-Tenant-ID", key).Get("/")
+		assertNil(t, err)
+		res2, err := c.R().SetHeader("X-Tenant-ID", key).Get("/")
+		assertNil(t, err)
+		assertEqual(t, res1.String(), res2.String())
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	hdrContentDigestKey = http.CanonicalHeaderKey("Content-Digest")
+	hdrContentMD5Key    = http.CanonicalHeaderKey("Content-MD5")
+
+	// ErrContentDigestMismatch is wrapped by [ContentDigestError].
+	ErrContentDigestMismatch = errors.New("resty: content digest mismatch")
+
+	// ErrContentDigestAlgNotSupported error is returned by the request
+	// execution when the algorithm of [Request.ExpectDigest] is not supported.
+	ErrContentDigestAlgNotSupported = errors.New("resty: content digest: algorithm is not supported")
+)
+
+// Reference: https://www.iana.org/assignments/http-digest-hash-alg/http-digest-hash-alg.xhtml
+var contentDigestHashFuncs = map[string]func() hash.Hash{
+	"md5":     md5.New,
+	"sha":     sha1.New,
+	"sha-1":   sha1.New,
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// ContentDigestError is returned when the digest of the response body does not
+// match the expected value, see [Request.ExpectDigest].
+//
+//	var digestErr *resty.ContentDigestError
+//	if errors.As(err, &digestErr) {
+//		fmt.Println(digestErr.Algorithm, digestErr.Expected, digestErr.Actual)
+//	}
+type ContentDigestError struct {
+	// Algorithm is the digest algorithm, e.g., sha-256
+	Algorithm string
+
+	// Source is the source of the expected digest, i.e., `Content-Digest`,
+	// `Content-MD5` header, or `ExpectDigest`
+	Source string
+
+	// Expected and Actual are the hex-encoded digest values
+	Expected string
+	Actual   string
+}
+
+func (e *ContentDigestError) Error() string {
+	return fmt.Sprintf("resty: %s %s digest mismatch: expected %s, got %s",
+		e.Source, e.Algorithm, e.Expected, e.Actual)
+}
+
+func (e *ContentDigestError) Unwrap() error {
+	return ErrContentDigestMismatch
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type contentDigest struct {
+	alg    string
+	source string
+	sum    []byte
+}
+
+// newContentDigest method parses the digest value, it accepts hex, base64,
+// and the RFC 9530 byte sequence, i.e., `:base64:`.
+func newContentDigest(alg, source, value string) (*contentDigest, error) {
+	alg = strings.ToLower(strings.TrimSpace(alg))
+	hf, found := contentDigestHashFuncs[alg]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrContentDigestAlgNotSupported, alg)
+	}
+	size := hf().Size()
+
+	value = strings.TrimSpace(value)
+	if b, err := hex.DecodeString(value); err == nil && len(b) == size {
+		return &contentDigest{alg: alg, source: source, sum: b}, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.Trim(value, ":"))
+	if err != nil || len(b) != size {
+		return nil, fmt.Errorf("resty: content digest: invalid %s value %q", alg, value)
+	}
+	return &contentDigest{alg: alg, source: source, sum: b}, nil
+}
+
+// parseContentDigestHeaders method parses the supported digests of the
+// `Content-Digest` and `Content-MD5` response headers; unsupported
+// algorithms and malformed values are ignored.
+func parseContentDigestHeaders(hdr http.Header) []*contentDigest {
+	var digests []*contentDigest
+	for _, v := range hdr.Values(hdrContentDigestKey) {
+		for _, item := range strings.Split(v, ",") {
+			alg, value, found := strings.Cut(item, "=")
+			if !found {
+				continue
+			}
+			if d, err := newContentDigest(alg, hdrContentDigestKey, value); err == nil {
+				digests = append(digests, d)
+			}
+		}
+	}
+	if v := hdr.Get(hdrContentMD5Key); len(v) > 0 {
+		if d, err := newContentDigest("md5", hdrContentMD5Key, v); err == nil {
+			digests = append(digests, d)
+		}
+	}
+	return digests
+}
+
+// digestReadCloser computes the digests while the body is read and verifies
+// them at the end of the body; the mismatch is returned instead of [io.EOF].
+type digestReadCloser struct {
+	r       io.ReadCloser
+	digests []*contentDigest
+	hashes  []hash.Hash
+	err     error
+}
+
+func newDigestReadCloser(r io.ReadCloser, digests []*contentDigest) *digestReadCloser {
+	dr := &digestReadCloser{r: r, digests: digests}
+	for _, d := range digests {
+		dr.hashes = append(dr.hashes, contentDigestHashFuncs[d.alg]())
+	}
+	return dr
+}
+
+func (dr *digestReadCloser) Read(p []byte) (n int, err error) {
+	if dr.err != nil {
+		return 0, dr.err
+	}
+	n, err = dr.r.Read(p)
+	for _, h := range dr.hashes {
+		_, _ = h.Write(p[:n])
+	}
+	if err == io.EOF {
+		if verr := dr.verify(); verr != nil {
+			dr.err = verr
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+func (dr *digestReadCloser) verify() error {
+	for i, d := range dr.digests {
+		if sum := dr.hashes[i].Sum(nil); !bytes.Equal(sum, d.sum) {
+			return &ContentDigestError{
+				Algorithm: d.alg,
+				Source:    d.source,
+				Expected:  hex.EncodeToString(d.sum),
+				Actual:    hex.EncodeToString(sum),
+			}
+		}
+	}
+	return nil
+}
+
+func (dr *digestReadCloser) Close() error {
+	return dr.r.Close()
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseContentDigestHeaders(t *testing.T) {
+	body := []byte("resty")
+	s256 := sha256.Sum256(body)
+	s512 := sha512.Sum512(body)
+	m5 := md5.Sum(body)
+
+	hdr := http.Header{}
+	hdr.Set(hdrContentDigestKey, "sha-256=:"+base64.StdEncoding.EncodeToString(s256[:])+":, "+
+		"unixsum=:MTIz:, SHA-512=:"+base64.StdEncoding.EncodeToString(s512[:])+":, sha-256=:bad:")
+	hdr.Set(hdrContentMD5Key, base64.StdEncoding.EncodeToString(m5[:]))
+
+	digests := parseContentDigestHeaders(hdr)
+	assertEqual(t, 3, len(digests))
+	assertEqual(t, "sha-256", digests[0].alg)
+	assertEqual(t, s256[:], digests[0].sum)
+	assertEqual(t, "sha-512", digests[1].alg)
+	assertEqual(t, hdrContentMD5Key, digests[2].source)
+
+	_, err := newContentDigest("crc32", "ExpectDigest", "abc")
+	assertErrorIs(t, ErrContentDigestAlgNotSupported, err)
+}
+
+func TestRequestExpectDigest(t *testing.T) {
+	content := []byte(`{"artifact":"` + strings.Repeat("resty ", 1000) + `"}`)
+	sum := sha256.Sum256(content)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		_, _ = w.Write(content)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	fp := filepath.Join(t.TempDir(), "artifact.bin")
+	res, err := c.R().
+		SetOutputFileName(fp).
+		ExpectDigest("SHA-256", hex.EncodeToString(sum[:])).
+		Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, int64(len(content)), res.Size())
+	b, err := os.ReadFile(fp)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+
+	// mismatch, the corrupted body never reaches the saved file
+	_, err = c.R().
+		SetOutputFileName(fp).
+		ExpectDigest("sha-256", base64.StdEncoding.EncodeToString(make([]byte, 32))).
+		Get(ts.URL)
+	var digestErr *ContentDigestError
+	assertEqual(t, true, errors.As(err, &digestErr))
+	assertErrorIs(t, ErrContentDigestMismatch, err)
+	assertEqual(t, "ExpectDigest", digestErr.Source)
+	assertEqual(t, hex.EncodeToString(sum[:]), digestErr.Actual)
+	b, err = os.ReadFile(fp)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+
+	// in-memory body
+	_, err = c.R().
+		ExpectDigest("md5", hex.EncodeToString(make([]byte, 16))).
+		Get(ts.URL)
+	assertErrorIs(t, ErrContentDigestMismatch, err)
+
+	// fail closed on the unsupported algorithm and the invalid value
+	var hits atomic.Int32
+	ts2 := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write(content)
+	})
+	defer ts2.Close()
+
+	_, err = c.R().ExpectDigest("sha256", hex.EncodeToString(sum[:])).Get(ts2.URL)
+	assertErrorIs(t, ErrContentDigestAlgNotSupported, err)
+
+	_, err = c.R().ExpectDigest("sha-256", "abc").Get(ts2.URL)
+	assertNotNil(t, err)
+	assertEqual(t, true, strings.Contains(err.Error(), "invalid sha-256 value"))
+
+	_, err = c.R().ExpectDigest("crc32", "abc").Clone(context.Background()).Get(ts2.URL)
+	assertErrorIs(t, ErrContentDigestAlgNotSupported, err)
+	assertEqual(t, int32(0), hits.Load())
+}
+
+func TestResponseContentDigestHeaders(t *testing.T) {
+	content := []byte(`{"name":"resty"}`)
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, _ = gw.Write(content)
+	_ = gw.Close()
+
+	digest := func(b []byte) string {
+		s := sha256.Sum256(b)
+		return "sha-256=:" + base64.StdEncoding.EncodeToString(s[:]) + ":"
+	}
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		switch r.URL.Path {
+		case "/ok":
+			w.Header().Set(hdrContentDigestKey, digest(content))
+			_, _ = w.Write(content)
+		case "/gzip":
+			// the digest is computed over the encoded content
+			w.Header().Set(hdrContentEncodingKey, "gzip")
+			w.Header().Set(hdrContentDigestKey, digest(gzipped.Bytes()))
+			_, _ = w.Write(gzipped.Bytes())
+		case "/corrupted":
+			w.Header().Set(hdrContentDigestKey, digest(content))
+			_, _ = w.Write([]byte(`{"name":"rusty"}`))
+		case "/md5":
+			w.Header().Set(hdrContentMD5Key, "AAAAAAAAAAAAAAAAAAAAAA==")
+			_, _ = w.Write(content)
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	result := map[string]string{}
+	res, err := c.R().SetResult(&result).Get(ts.URL + "/ok")
+	assertNil(t, err)
+	assertEqual(t, "resty", result["name"])
+
+	res, err = c.R().Get(ts.URL + "/gzip")
+	assertNil(t, err)
+	assertEqual(t, string(content), res.String())
+
+	_, err = c.R().SetResult(&result).Get(ts.URL + "/corrupted")
+	var digestErr *ContentDigestError
+	assertEqual(t, true, errors.As(err, &digestErr))
+	assertEqual(t, hdrContentDigestKey, digestErr.Source)
+	assertEqual(t, "sha-256", digestErr.Algorithm)
+
+	_, err = c.R().Get(ts.URL + "/md5")
+	assertErrorIs(t, ErrContentDigestMismatch, err)
+
+	// HEAD response has no content to verify
+	_, err = c.R().Head(ts.URL + "/corrupted")
+	assertNil(t, err)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrContentTypeMismatch is wrapped by [ContentTypeError].
+var ErrContentTypeMismatch = errors.New("resty: content type mismatch")
+
+// ContentTypeError is returned when the response `Content-Type` does not match
+// any of the expected content types, see [Request.ExpectContentType].
+//
+//	var ctErr *resty.ContentTypeError
+//	if errors.As(err, &ctErr) {
+//		fmt.Println(ctErr.Expected, ctErr.Actual, ctErr.Sniffed)
+//	}
+type ContentTypeError struct {
+	// Expected is the list of the expected content types
+	Expected []string
+
+	// Actual is the response content type; it is empty if the server omits
+	// the `Content-Type` header and the sniffing is disabled
+	Actual string
+
+	// Sniffed is true if the Actual is detected from the response body
+	Sniffed bool
+}
+
+func (e *ContentTypeError) Error() string {
+	actual := e.Actual
+	if isStringEmpty(actual) {
+		actual = "none"
+	} else if e.Sniffed {
+		actual += " (sniffed)"
+	}
+	return fmt.Sprintf("resty: content type mismatch: expected %s, got %s",
+		strings.Join(e.Expected, ", "), actual)
+}
+
+func (e *ContentTypeError) Unwrap() error {
+	return ErrContentTypeMismatch
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+const sniffLen = 512
+
+// verifyContentType method verifies the response content type against the
+// expected content types; the body is read on mismatch, so it is available
+// for the inspection, e.g., HTML error page.
+func (r *Response) verifyContentType() error {
+	if !r.hasContent() {
+		return nil
+	}
+
+	ct := r.Header().Get(hdrContentTypeKey)
+	if isStringEmpty(ct) && r.Request.contentTypeSniffing {
+		r.sniffedContentType = r.sniffContentType()
+		ct = r.sniffedContentType
+	}
+
+	expected := r.Request.expectedContentTypes
+	if len(expected) == 0 {
+		return nil
+	}
+	for _, e := range expected {
+		if matchContentType(e, ct) {
+			return nil
+		}
+	}
+
+	if !r.Request.DoNotParseResponse && !r.Request.IsSaveResponse {
+		_ = r.readAll()
+	}
+	return &ContentTypeError{
+		Expected: expected,
+		Actual:   ct,
+		Sniffed:  len(r.sniffedContentType) > 0,
+	}
+}
+
+// sniffContentType method detects the content type from the first 512 bytes
+// of the response body, see [http.DetectContentType]; additionally, it detects
+// the JSON content.
+func (r *Response) sniffContentType() string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+
+	br := bufio.NewReaderSize(r.Body, sniffLen)
+	r.Body = &sniffReadCloser{r: br, c: r.Body}
+	b, _ := br.Peek(sniffLen)
+	if len(b) == 0 {
+		return ""
+	}
+
+	if t := bytes.TrimLeft(b, " \t\r\n"); len(t) > 0 && (t[0] == '{' || t[0] == '[') {
+		return jsonContentType
+	}
+	return http.DetectContentType(b)
+}
+
+// matchContentType function reports whether the content type matches the
+// expected one; media type parameters are ignored, and the wildcard
+// subtype, e.g., `text/*`, is supported.
+func matchContentType(expected, ct string) bool {
+	em := parseMediaType(expected)
+	m := parseMediaType(ct)
+	if em == "" || m == "" {
+		return false
+	}
+	if prefix, found := strings.CutSuffix(em, "/*"); found {
+		return strings.HasPrefix(m, prefix+"/")
+	}
+	return em == m
+}
+
+func parseMediaType(v string) string {
+	m, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(v, ";")[0]))
+	}
+	return m
+}
+
+type sniffReadCloser struct {
+	r io.Reader
+	c io.Closer
+}
+
+func (s *sniffReadCloser) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+func (s *sniffReadCloser) Close() error {
+	return s.c.Close()
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createContentTypeTestServer(t *testing.T) string {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set(hdrContentTypeKey, "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"id":"success","message":"login successful"}`))
+		case "/problem":
+			w.Header().Set(hdrContentTypeKey, "application/problem+json")
+			_, _ = w.Write([]byte(`{"title":"bad request"}`))
+		case "/html":
+			w.Header().Set(hdrContentTypeKey, "text/html")
+			_, _ = w.Write([]byte(`<html><body>maintenance</body></html>`))
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/omit-json":
+			// prevent the server from sniffing the content type
+			w.Header()[hdrContentTypeKey] = nil
+			_, _ = w.Write([]byte("  \n" + `{"id":"success","message":"sniffed"}`))
+		case "/omit-html":
+			w.Header()[hdrContentTypeKey] = nil
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>maintenance</body></html>`))
+		}
+	})
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestExpectContentType(t *testing.T) {
+	baseURL := createContentTypeTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	res, err := c.R().
+		SetResult(&AuthSuccess{}).
+		ExpectContentType("application/json").
+		Get("/json")
+	assertNil(t, err)
+	assertEqual(t, "login successful", res.Result().(*AuthSuccess).Message)
+
+	res, err = c.R().
+		SetResult(&AuthSuccess{}).
+		ExpectContentType("application/json").
+		Get("/html")
+	assertErrorIs(t, ErrContentTypeMismatch, err)
+	var ctErr *ContentTypeError
+	assertEqual(t, true, errors.As(err, &ctErr))
+	assertEqual(t, []string{"application/json"}, ctErr.Expected)
+	assertEqual(t, "text/html", ctErr.Actual)
+	assertEqual(t, false, ctErr.Sniffed)
+	assertEqual(t, "resty: content type mismatch: expected application/json, got text/html", err.Error())
+	assertEqual(t, "<html><body>maintenance</body></html>", res.String())
+	assertEqual(t, "", res.Result().(*AuthSuccess).Message)
+
+	// multiple and wildcard
+	_, err = c.R().ExpectContentType("application/json", "application/problem+json").Get("/problem")
+	assertNil(t, err)
+	_, err = c.R().ExpectContentType("TEXT/*").Get("/html")
+	assertNil(t, err)
+	_, err = c.R().ExpectContentType("text/*").Get("/json")
+	assertErrorIs(t, ErrContentTypeMismatch, err)
+
+	// response without the content
+	_, err = c.R().ExpectContentType("application/json").Get("/no-content")
+	assertNil(t, err)
+
+	// not parsed response is verified as well
+	res, err = c.R().
+		SetDoNotParseResponse(true).
+		ExpectContentType("application/json").
+		Get("/html")
+	assertErrorIs(t, ErrContentTypeMismatch, err)
+	assertEqual(t, false, res.IsRead)
+	closeq(res.Body)
+}
+
+func TestExpectContentTypeSaveResponse(t *testing.T) {
+	baseURL := createContentTypeTestServer(t)
+	outputFile := filepath.Join(t.TempDir(), "resty.json")
+
+	_, err := dcnl().R().
+		SetOutputFileName(outputFile).
+		ExpectContentType("application/json").
+		Get(baseURL + "/html")
+	assertErrorIs(t, ErrContentTypeMismatch, err)
+	_, err = os.Stat(outputFile)
+	assertEqual(t, true, os.IsNotExist(err))
+}
+
+func TestContentTypeSniffing(t *testing.T) {
+	baseURL := createContentTypeTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+	assertEqual(t, false, c.IsContentTypeSniffing())
+
+	// header is omitted, and sniffing is disabled
+	_, err := c.R().ExpectContentType("application/json").Get("/omit-json")
+	var ctErr *ContentTypeError
+	assertEqual(t, true, errors.As(err, &ctErr))
+	assertEqual(t, "", ctErr.Actual)
+	assertEqual(t, true, strings.HasSuffix(err.Error(), "got none"))
+
+	c.SetContentTypeSniffing(true)
+	assertEqual(t, true, c.IsContentTypeSniffing())
+
+	// sniffed content type is used for the unmarshalling as well
+	res, err := c.R().
+		SetResult(&AuthSuccess{}).
+		ExpectContentType("application/json").
+		Get("/omit-json")
+	assertNil(t, err)
+	assertEqual(t, "sniffed", res.Result().(*AuthSuccess).Message)
+	assertEqual(t, "", res.Header().Get(hdrContentTypeKey))
+
+	res, err = c.R().ExpectContentType("application/json").Get("/omit-html")
+	assertEqual(t, true, errors.As(err, &ctErr))
+	assertEqual(t, "text/html; charset=utf-8", ctErr.Actual)
+	assertEqual(t, true, ctErr.Sniffed)
+	assertEqual(t, true, strings.HasSuffix(err.Error(), "(sniffed)"))
+	assertEqual(t, `<!DOCTYPE html><html><body>maintenance</body></html>`, res.String())
+
+	// request level overrides the client
+	_, err = c.R().
+		SetContentTypeSniffing(false).
+		ExpectContentType("application/json").
+		Get("/omit-json")
+	assertErrorIs(t, ErrContentTypeMismatch, err)
+}
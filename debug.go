@@ -6,8 +6,16 @@
 package resty
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,7 +25,7 @@ type (
 	DebugLogCallbackFunc func(*DebugLog, *Response)
 
 	// DebugLogFormatterFunc function type is used to implement debug log formatting.
-	// See out of the box [DebugLogStringFormatter], [DebugLogJSONFormatter]
+	// See out of the box [DebugLogFormatter], [DebugLogJSONFormatter]
 	DebugLogFormatterFunc func(*DebugLog) string
 
 	// DebugLog struct is used to collect details from Resty request and response
@@ -25,11 +33,12 @@ type (
 	DebugLog struct {
 		Request   *DebugLogRequest  `json:"request"`
 		Response  *DebugLogResponse `json:"response"`
-		TraceInfo *TraceInfo        `json:"trace_info"`
+		TraceInfo *TraceInfo        `json:"trace_info,omitempty"`
 	}
 
 	// DebugLogRequest type used to capture debug info about the [Request].
 	DebugLogRequest struct {
+		Name         string      `json:"name,omitempty"`
 		Host         string      `json:"host"`
 		URI          string      `json:"uri"`
 		Method       string      `json:"method"`
@@ -38,22 +47,198 @@ type (
 		CurlCmd      string      `json:"curl_cmd"`
 		RetryTraceID string      `json:"retry_trace_id"`
 		Attempt      int         `json:"attempt"`
+		RequestID    string      `json:"request_id,omitempty"`
 		Body         string      `json:"body"`
 	}
 
 	// DebugLogResponse type used to capture debug info about the [Response].
 	DebugLogResponse struct {
-		StatusCode int           `json:"status_code"`
-		Status     string        `json:"status"`
-		Proto      string        `json:"proto"`
-		ReceivedAt time.Time     `json:"received_at"`
-		Duration   time.Duration `json:"duration"`
-		Size       int64         `json:"size"`
-		Header     http.Header   `json:"header"`
-		Body       string        `json:"body"`
+		StatusCode  int           `json:"status_code"`
+		Status      string        `json:"status"`
+		Proto       string        `json:"proto"`
+		ReceivedAt  time.Time     `json:"received_at"`
+		Duration    time.Duration `json:"duration"`
+		Size        int64         `json:"size"`
+		CacheStatus CacheStatus   `json:"cache_status,omitempty"`
+		Header      http.Header   `json:"header"`
+		Body        string        `json:"body"`
 	}
 )
 
+// DebugLevel type is used to control the verbosity of the [DebugSink].
+type DebugLevel uint8
+
+const (
+	// DebugLevelBody level writes the entire debug log, including the request
+	// and response bodies and the curl command. It is the default level.
+	DebugLevelBody DebugLevel = iota
+
+	// DebugLevelHeaders level writes the debug log without the bodies and
+	// the curl command.
+	DebugLevelHeaders
+
+	// DebugLevelBasic level writes the request line, response status, timings,
+	// and trace info only.
+	DebugLevelBasic
+)
+
+// DebugSink struct is used to route the debug log into a writer with an
+// independent level and formatter.
+//
+// See [Client.SetDebugOutput]
+type DebugSink struct {
+	// Writer is the debug log destination. It must not be nil.
+	Writer io.Writer
+
+	// Level is the verbosity of the sink, default is [DebugLevelBody].
+	Level DebugLevel
+
+	// Formatter is the debug log formatter of the sink. If it is nil, the
+	// client debug log formatter is used, see [Client.SetDebugLogFormatter].
+	Formatter DebugLogFormatterFunc
+}
+
+// DebugLogRedaction struct holds the declarative rules to redact sensitive
+// values from the debug log.
+//
+// See [Client.SetDebugLogRedaction]
+type DebugLogRedaction struct {
+	// Headers is the list of request and response header names, whose values
+	// are redacted. Header names are matched case-insensitively.
+	Headers []string
+
+	// QueryParams is the list of request query parameter names, whose values
+	// are redacted.
+	QueryParams []string
+
+	// BodyPatterns is the list of regular expressions applied to the request
+	// and response bodies. If the pattern has capture groups, only the groups
+	// are redacted; otherwise, the entire match is redacted.
+	BodyPatterns []*regexp.Regexp
+
+	// Mask is the replacement value for the redacted values.
+	Mask string
+}
+
+// NewDebugLogRedaction method creates a new [DebugLogRedaction] with default settings.
+//
+// The default settings are:
+//   - Headers: Authorization, Proxy-Authorization, Cookie, Set-Cookie
+//   - Mask: [REDACTED]
+func NewDebugLogRedaction() *DebugLogRedaction {
+	return &DebugLogRedaction{
+		Headers: []string{hdrAuthorizationKey, "Proxy-Authorization", hdrCookieKey, "Set-Cookie"},
+		Mask:    "[REDACTED]",
+	}
+}
+
+// AddHeaders method adds the given header names to the redaction list.
+func (dr *DebugLogRedaction) AddHeaders(headers ...string) *DebugLogRedaction {
+	dr.Headers = append(dr.Headers, headers...)
+	return dr
+}
+
+// AddQueryParams method adds the given query parameter names to the redaction list.
+func (dr *DebugLogRedaction) AddQueryParams(params ...string) *DebugLogRedaction {
+	dr.QueryParams = append(dr.QueryParams, params...)
+	return dr
+}
+
+// AddBodyPatterns method adds the given regular expressions to the body
+// redaction list.
+func (dr *DebugLogRedaction) AddBodyPatterns(patterns ...*regexp.Regexp) *DebugLogRedaction {
+	dr.BodyPatterns = append(dr.BodyPatterns, patterns...)
+	return dr
+}
+
+func (dr *DebugLogRedaction) apply(dl *DebugLog) {
+	req := dl.Request
+	secrets := make([]string, 0)
+	for _, name := range dr.Headers {
+		secrets = append(secrets, dr.redactHeader(req.Header, name)...)
+		if dl.Response != nil {
+			dr.redactHeader(dl.Response.Header, name)
+		}
+	}
+	originalURI := req.URI
+	req.URI = dr.redactQueryParams(req.URI)
+	req.Body = dr.redactBody(req.Body)
+	if dl.Response != nil {
+		dl.Response.Body = dr.redactBody(dl.Response.Body)
+	}
+
+	if len(req.CurlCmd) > 0 {
+		for _, secret := range secrets {
+			req.CurlCmd = strings.ReplaceAll(req.CurlCmd, secret, dr.Mask)
+		}
+		req.CurlCmd = dr.redactBody(req.CurlCmd)
+		if originalURI != req.URI {
+			req.CurlCmd = strings.ReplaceAll(req.CurlCmd, originalURI, req.URI)
+		}
+	}
+}
+
+func (dr *DebugLogRedaction) redactHeader(h http.Header, name string) []string {
+	key := http.CanonicalHeaderKey(name)
+	values, found := h[key]
+	if !found {
+		return nil
+	}
+	secrets := slices.Clone(values)
+	for i := range values {
+		values[i] = dr.Mask
+	}
+	return secrets
+}
+
+func (dr *DebugLogRedaction) redactQueryParams(uri string) string {
+	path, rawQuery, found := strings.Cut(uri, "?")
+	if !found || len(dr.QueryParams) == 0 {
+		return uri
+	}
+
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		name, _, _ := strings.Cut(part, "=")
+		if n, err := url.QueryUnescape(name); err == nil {
+			name = n
+		}
+		if slices.Contains(dr.QueryParams, name) {
+			parts[i] = url.QueryEscape(name) + "=" + url.QueryEscape(dr.Mask)
+		}
+	}
+	return path + "?" + strings.Join(parts, "&")
+}
+
+func (dr *DebugLogRedaction) redactBody(body string) string {
+	for _, re := range dr.BodyPatterns {
+		if re.NumSubexp() == 0 {
+			body = re.ReplaceAllLiteralString(body, dr.Mask)
+			continue
+		}
+
+		matches := re.FindAllStringSubmatchIndex(body, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		var sb strings.Builder
+		last := 0
+		for _, m := range matches {
+			for g := 2; g+1 < len(m); g += 2 {
+				if m[g] < 0 || m[g] < last {
+					continue
+				}
+				sb.WriteString(body[last:m[g]])
+				sb.WriteString(dr.Mask)
+				last = m[g+1]
+			}
+		}
+		sb.WriteString(body[last:])
+		body = sb.String()
+	}
+	return body
+}
+
 // DebugLogFormatter function formats the given debug log info in human readable
 // format.
 //
@@ -68,8 +253,14 @@ func DebugLogFormatter(dl *DebugLog) string {
 	}
 	debugLog += "~~~ REQUEST ~~~\n" +
 		fmt.Sprintf("%s  %s  %s\n", req.Method, req.URI, req.Proto) +
-		fmt.Sprintf("HOST   : %s\n", req.Host) +
-		fmt.Sprintf("HEADERS:\n%s\n", composeHeaders(req.Header)) +
+		fmt.Sprintf("HOST   : %s\n", req.Host)
+	if len(req.Name) > 0 {
+		debugLog += fmt.Sprintf("NAME   : %s\n", req.Name)
+	}
+	if len(req.RequestID) > 0 {
+		debugLog += fmt.Sprintf("REQ ID : %s\n", req.RequestID)
+	}
+	debugLog += fmt.Sprintf("HEADERS:\n%s\n", composeHeaders(req.Header)) +
 		fmt.Sprintf("BODY   :\n%v\n", req.Body) +
 		"------------------------------------------------------------------------------\n"
 	if len(req.RetryTraceID) > 0 {
@@ -83,8 +274,11 @@ func DebugLogFormatter(dl *DebugLog) string {
 		fmt.Sprintf("STATUS       : %s\n", res.Status) +
 		fmt.Sprintf("PROTO        : %s\n", res.Proto) +
 		fmt.Sprintf("RECEIVED AT  : %v\n", res.ReceivedAt.Format(time.RFC3339Nano)) +
-		fmt.Sprintf("DURATION     : %v\n", res.Duration) +
-		"HEADERS      :\n" +
+		fmt.Sprintf("DURATION     : %v\n", res.Duration)
+	if len(res.CacheStatus) > 0 {
+		debugLog += fmt.Sprintf("CACHE STATUS : %s\n", res.CacheStatus)
+	}
+	debugLog += "HEADERS      :\n" +
 		composeHeaders(res.Header) + "\n" +
 		fmt.Sprintf("BODY         :\n%v\n", res.Body)
 	if dl.TraceInfo != nil {
@@ -97,8 +291,32 @@ func DebugLogFormatter(dl *DebugLog) string {
 }
 
 // DebugLogJSONFormatter function formats the given debug log info in JSON format.
+// It emits one single-line JSON object per request and response pair, without
+// HTML escaping; it is suitable for ingesting into log pipelines such as ELK.
+//
+//	client.SetDebugLogFormatter(resty.DebugLogJSONFormatter)
 func DebugLogJSONFormatter(dl *DebugLog) string {
-	return toJSON(dl)
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	_ = encodeJSONEscapeHTML(buf, dl, false)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// isDebugSampled method reports whether the debug mode is enabled for the given
+// request based on the [Client.SetDebugIf] condition and
+// [Client.SetDebugSampleRate] sampling rate.
+func (c *Client) isDebugSampled(r *Request) bool {
+	c.lock.RLock()
+	debugIf, rate, rnd := c.debugIf, c.debugSampleRate, c.rnd
+	c.lock.RUnlock()
+
+	if debugIf == nil && rate >= 1 {
+		return false // not configured
+	}
+	if debugIf != nil && !debugIf(r) {
+		return false
+	}
+	return rate >= 1 || rnd.Float64() < rate
 }
 
 func debugLogger(c *Client, res *Response) {
@@ -108,18 +326,19 @@ func debugLogger(c *Client, res *Response) {
 	}
 
 	rdl := &DebugLogResponse{
-		StatusCode: res.StatusCode(),
-		Status:     res.Status(),
-		Proto:      res.Proto(),
-		ReceivedAt: res.ReceivedAt(),
-		Duration:   res.Duration(),
-		Size:       res.Size(),
-		Header:     res.Header().Clone(),
-		Body:       res.fmtBodyString(res.Request.DebugBodyLimit),
+		StatusCode:  res.StatusCode(),
+		Status:      res.Status(),
+		Proto:       res.Proto(),
+		ReceivedAt:  res.ReceivedAt(),
+		Duration:    res.Duration(),
+		Size:        res.Size(),
+		CacheStatus: res.CacheStatus(),
+		Header:      res.Header().Clone(),
+		Body:        res.fmtBodyString(res.Request.DebugBodyLimit),
 	}
 
 	dl := &DebugLog{
-		Request:  req.values[debugRequestLogKey].(*DebugLogRequest),
+		Request:  req.values[debugRequestLogKey{}].(*DebugLogRequest),
 		Response: rdl,
 	}
 
@@ -128,11 +347,27 @@ func debugLogger(c *Client, res *Response) {
 		dl.TraceInfo = &ti
 	}
 
+	if redaction := c.DebugLogRedaction(); redaction != nil {
+		redaction.apply(dl)
+	}
+
 	dblCallback := c.debugLogCallbackFunc()
 	if dblCallback != nil {
 		dblCallback(dl, res)
 	}
 
+	if sinks := c.debugOutputSinks(); len(sinks) > 0 {
+		for _, sink := range sinks {
+			sink.write(c, dl)
+		}
+		return
+	}
+
+	if sl, ok := req.log.(*slogLogger); ok {
+		sl.debugLog(req.Context(), dl)
+		return
+	}
+
 	formatterFunc := c.debugLogFormatterFunc()
 	if formatterFunc != nil {
 		debugLog := formatterFunc(dl)
@@ -140,7 +375,122 @@ func debugLogger(c *Client, res *Response) {
 	}
 }
 
-const debugRequestLogKey = "__restyDebugRequestLog"
+type debugRequestLogKey struct{}
+
+type debugSink struct {
+	DebugSink
+	lock sync.Mutex
+}
+
+func (ds *debugSink) write(c *Client, dl *DebugLog) {
+	formatterFunc := ds.Formatter
+	if formatterFunc == nil {
+		formatterFunc = c.debugLogFormatterFunc()
+	}
+	if formatterFunc == nil {
+		return
+	}
+
+	debugLog := formatterFunc(dl.withLevel(ds.Level))
+	if !strings.HasSuffix(debugLog, "\n") {
+		debugLog += "\n"
+	}
+
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	_, _ = io.WriteString(ds.Writer, debugLog)
+}
+
+// withLevel method returns the copy of debug log with the details
+// trimmed down to the given level.
+func (dl *DebugLog) withLevel(level DebugLevel) *DebugLog {
+	if level == DebugLevelBody {
+		return dl
+	}
+
+	req := *dl.Request
+	req.CurlCmd = ""
+	req.Body = ""
+	ndl := &DebugLog{Request: &req, TraceInfo: dl.TraceInfo}
+	if dl.Response != nil {
+		res := *dl.Response
+		res.Body = ""
+		ndl.Response = &res
+	}
+	if level == DebugLevelBasic {
+		ndl.Request.Header = http.Header{}
+		if ndl.Response != nil {
+			ndl.Response.Header = http.Header{}
+		}
+	}
+	return ndl
+}
+
+// debugLog method emits the given debug log as structured attributes.
+func (l *slogLogger) debugLog(ctx context.Context, dl *DebugLog) {
+	req := dl.Request
+	reqAttrs := []any{
+		slog.String("method", req.Method),
+		slog.String("host", req.Host),
+		slog.String("uri", req.URI),
+		slog.String("proto", req.Proto),
+		slog.Any("header", req.Header),
+		slog.String("body", req.Body),
+	}
+	if len(req.Name) > 0 {
+		reqAttrs = append(reqAttrs, slog.String("name", req.Name))
+	}
+	if len(req.CurlCmd) > 0 {
+		reqAttrs = append(reqAttrs, slog.String("curl_cmd", req.CurlCmd))
+	}
+	if len(req.RequestID) > 0 {
+		reqAttrs = append(reqAttrs, slog.String("request_id", req.RequestID))
+	}
+	if len(req.RetryTraceID) > 0 {
+		reqAttrs = append(reqAttrs,
+			slog.String("retry_trace_id", req.RetryTraceID),
+			slog.Int("attempt", req.Attempt),
+		)
+	}
+
+	res := dl.Response
+	resAttrs := []any{
+		slog.Int("status_code", res.StatusCode),
+		slog.String("status", res.Status),
+		slog.String("proto", res.Proto),
+		slog.Time("received_at", res.ReceivedAt),
+		slog.Duration("duration", res.Duration),
+		slog.Int64("size", res.Size),
+		slog.Any("header", res.Header),
+		slog.String("body", res.Body),
+	}
+	if len(res.CacheStatus) > 0 {
+		resAttrs = append(resAttrs, slog.String("cache_status", res.CacheStatus.String()))
+	}
+
+	attrs := []slog.Attr{
+		slog.Group("request", reqAttrs...),
+		slog.Group("response", resAttrs...),
+	}
+	if ti := dl.TraceInfo; ti != nil {
+		attrs = append(attrs, slog.Group("trace_info",
+			slog.Duration("dns_lookup_time", ti.DNSLookup),
+			slog.Duration("connection_time", ti.ConnTime),
+			slog.Duration("tcp_connection_time", ti.TCPConnTime),
+			slog.Duration("tls_handshake_time", ti.TLSHandshake),
+			slog.Duration("server_time", ti.ServerTime),
+			slog.Duration("response_time", ti.ResponseTime),
+			slog.Duration("total_time", ti.TotalTime),
+			slog.Bool("is_connection_reused", ti.IsConnReused),
+			slog.Bool("is_connection_was_idle", ti.IsConnWasIdle),
+			slog.Duration("connection_idle_time", ti.ConnIdleTime),
+			slog.Int("request_attempt", ti.RequestAttempt),
+			slog.String("remote_address", ti.RemoteAddr),
+		))
+	}
+
+	l.l.LogAttrs(ctx, slog.LevelDebug, "resty debug log", attrs...)
+}
 
 func prepareRequestDebugInfo(c *Client, r *Request) {
 	if !r.Debug {
@@ -161,12 +511,14 @@ func prepareRequestDebugInfo(c *Client, r *Request) {
 	}
 
 	rdl := &DebugLogRequest{
-		Host:   rr.URL.Host,
-		URI:    rr.URL.RequestURI(),
-		Method: r.Method,
-		Proto:  rr.Proto,
-		Header: rh,
-		Body:   r.fmtBodyString(r.DebugBodyLimit),
+		Name:      r.name,
+		Host:      rr.URL.Host,
+		URI:       rr.URL.RequestURI(),
+		Method:    r.Method,
+		Proto:     rr.Proto,
+		Header:    rh,
+		RequestID: r.requestID,
+		Body:      r.fmtBodyString(r.DebugBodyLimit),
 	}
 	if r.generateCurlCmd && r.debugLogCurlCmd {
 		rdl.CurlCmd = r.resultCurlCmd
@@ -177,5 +529,5 @@ func prepareRequestDebugInfo(c *Client, r *Request) {
 	}
 
 	r.initValuesMap()
-	r.values[debugRequestLogKey] = rdl
+	r.values[debugRequestLogKey{}] = rdl
 }
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Option type is to override the settings of the derived client, see
+// [Client.With]. Any client setter can be used as an option.
+//
+//	debug := func(c *resty.Client) { c.SetDebug(true) }
+type Option func(*Client)
+
+// With method returns the derived client with the given options applied. The
+// derived client is cheap to create; it shares the underlying transport, i.e.,
+// the connection pool, cookie jar, cache, and load balancer with the parent
+// client, while the settings, e.g., headers, auth, timeouts, and middlewares,
+// are isolated, so overriding them does not affect the parent client and vice
+// versa.
+//
+//	tenantClient := client.With(
+//		resty.WithHeader("X-Tenant-ID", tenantID),
+//		resty.WithAuthToken(tenantToken),
+//		resty.WithTimeout(5*time.Second),
+//	)
+//
+// NOTE:
+//   - The transport settings, e.g., [Client.SetTLSClientConfig], are shared
+//     with the parent client; use [Request.SetTransport] to isolate them.
+//   - The [Client.Close] method of the derived client only runs its close hooks;
+//     the shared resources are released by the parent client.
+func (c *Client) With(opts ...Option) *Client {
+	c.lock.RLock()
+	cc := c.Clone(c.ctx)
+	c.lock.RUnlock()
+
+	cc.parent = c
+
+	// own copy of the http client, so the redirect policy and cookie jar
+	// can be overridden, the transport is shared
+	hc := *c.httpClient
+	cc.httpClient = &hc
+
+	// the appended values must not be visible to the parent client
+	cc.retryConditions = slices.Clone(c.retryConditions)
+	cc.retryHooks = slices.Clone(c.retryHooks)
+	cc.errorHooks = slices.Clone(c.errorHooks)
+	cc.invalidHooks = slices.Clone(c.invalidHooks)
+	cc.panicHooks = slices.Clone(c.panicHooks)
+	cc.successHooks = slices.Clone(c.successHooks)
+	cc.connectionHooks = slices.Clone(c.connectionHooks)
+	cc.contentDecompresserKeys = slices.Clone(c.contentDecompresserKeys)
+	cc.closeHooks = nil
+	cc.lock = &sync.RWMutex{}
+
+	for _, opt := range opts {
+		opt(cc)
+	}
+	return cc
+}
+
+// WithHeader function returns the [Option] that sets the header of the derived
+// client, see [Client.SetHeader].
+func WithHeader(header, value string) Option {
+	return func(c *Client) { c.SetHeader(header, value) }
+}
+
+// WithHeaders function returns the [Option] that sets the headers of the derived
+// client, see [Client.SetHeaders].
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) { c.SetHeaders(headers) }
+}
+
+// WithQueryParam function returns the [Option] that sets the query param of the
+// derived client, see [Client.SetQueryParam].
+func WithQueryParam(param, value string) Option {
+	return func(c *Client) { c.SetQueryParam(param, value) }
+}
+
+// WithBaseURL function returns the [Option] that sets the base URL of the
+// derived client, see [Client.SetBaseURL].
+func WithBaseURL(url string) Option {
+	return func(c *Client) { c.SetBaseURL(url) }
+}
+
+// WithAuthToken function returns the [Option] that sets the auth token of the
+// derived client, see [Client.SetAuthToken].
+func WithAuthToken(token string) Option {
+	return func(c *Client) { c.SetAuthToken(token) }
+}
+
+// WithBasicAuth function returns the [Option] that sets the basic auth of the
+// derived client, see [Client.SetBasicAuth].
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) { c.SetBasicAuth(username, password) }
+}
+
+// WithTimeout function returns the [Option] that sets the request timeout of
+// the derived client, see [Client.SetTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.SetTimeout(timeout) }
+}
+
+// WithRequestMiddleware function returns the [Option] that adds the request
+// middleware to the derived client, see [Client.AddRequestMiddleware].
+func WithRequestMiddleware(m RequestMiddleware) Option {
+	return func(c *Client) { c.AddRequestMiddleware(m) }
+}
+
+// WithResponseMiddleware function returns the [Option] that adds the response
+// middleware to the derived client, see [Client.AddResponseMiddleware].
+func WithResponseMiddleware(m ResponseMiddleware) Option {
+	return func(c *Client) { c.AddResponseMiddleware(m) }
+}
+
+// WithCookieJar function returns the [Option] that sets the cookie jar of the
+// derived client, e.g., to isolate the cookies per tenant, see
+// [Client.SetCookieJar].
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) { c.SetCookieJar(jar) }
+}
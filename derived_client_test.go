@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientWith(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s|%s|%s", r.Header.Get("X-Tenant-ID"), r.Header.Get(hdrAuthorizationKey), r.URL.RawQuery)
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL).SetHeader("X-Tenant-ID", "root").SetTimeout(time.Minute)
+	defer c.Close()
+
+	var calls int32
+	dc := c.With(
+		WithHeader("X-Tenant-ID", "acme"),
+		WithAuthToken("acme-token"),
+		WithQueryParam("region", "eu"),
+		WithTimeout(5*time.Second),
+		WithRequestMiddleware(func(*Client, *Request) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		}),
+		func(c *Client) { c.SetDebug(false) },
+	)
+
+	res, err := dc.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "acme|Bearer acme-token|region=eu", res.String())
+	assertEqual(t, 5*time.Second, dc.Timeout())
+	assertEqual(t, int32(1), atomic.LoadInt32(&calls))
+
+	// the parent client is not affected
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "root||", res.String())
+	assertEqual(t, time.Minute, c.Timeout())
+	assertEqual(t, int32(1), atomic.LoadInt32(&calls))
+
+	// the transport and its connection pool are shared
+	assertEqual(t, true, c.Transport() == dc.Transport())
+	assertEqual(t, int32(1), atomic.LoadInt32(&conns))
+
+	// the cookie jar can be isolated
+	jc := dc.With(WithCookieJar(nil))
+	assertNil(t, jc.CookieJar())
+	assertNotNil(t, dc.CookieJar())
+
+	// closing the derived client leaves the shared resources open
+	assertNil(t, dc.Close())
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "root||", res.String())
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// IPFamily type is the address family preference of the client dials, see
+// [DialPreference].
+type IPFamily uint8
+
+// Address family preferences
+const (
+	// IPFamilyAny dials the addresses in the resolver order, the other
+	// address family is the fallback
+	IPFamilyAny IPFamily = iota
+
+	// IPFamilyPreferIPv4 dials the IPv4 addresses first, the IPv6 addresses
+	// are the fallback
+	IPFamilyPreferIPv4
+
+	// IPFamilyPreferIPv6 dials the IPv6 addresses first, the IPv4 addresses
+	// are the fallback
+	IPFamilyPreferIPv6
+
+	// IPFamilyIPv4Only dials only the IPv4 addresses
+	IPFamilyIPv4Only
+
+	// IPFamilyIPv6Only dials only the IPv6 addresses
+	IPFamilyIPv6Only
+)
+
+// DialPreference struct is used to define the dual-stack settings of the
+// client dials, see [Client.SetDialPreference].
+type DialPreference struct {
+	// Family is the address family preference, default value is [IPFamilyAny].
+	Family IPFamily
+
+	// FallbackDelay is the duration to wait for the preferred address family
+	// dial before the fallback address family dial is started in parallel,
+	// aka Happy Eyeballs, see RFC 6555. Default value is `300` milliseconds,
+	// the negative value disables it, so the addresses are dialed one by one.
+	FallbackDelay time.Duration
+}
+
+// DialPreference method returns the dual-stack settings from the client instance.
+func (c *Client) DialPreference() DialPreference {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.dialer == nil {
+		return DialPreference{}
+	}
+	return c.dialer.preference
+}
+
+// SetDialPreference method sets the dual-stack settings for the client dials,
+// e.g., to avoid the long hangs on the broken IPv6 paths.
+//
+//	client.SetDialPreference(resty.DialPreference{
+//		Family:        resty.IPFamilyPreferIPv4,
+//		FallbackDelay: 100 * time.Millisecond,
+//	})
+//
+// NOTE: The host names are resolved via the resolver set by [Client.SetResolver],
+// otherwise, the system resolver, if the family is other than [IPFamilyAny].
+func (c *Client) SetDialPreference(p DialPreference) *Client {
+	return c.updateDialer(func(d *transportDialer) {
+		d.preference = p
+	})
+}
+
+// SetHostDialPreference method sets the dual-stack settings for the given host
+// pattern, it overrides the settings set by [Client.SetDialPreference]. The
+// host patterns are evaluated in the order they are added, the supported
+// patterns are
+//   - Host name, e.g., `api.example.com`
+//   - Domain with its subdomains, e.g., `*.example.com` or `.example.com`
+//
+// For example:
+//
+//	client.SetHostDialPreference("*.legacy.example.com", resty.DialPreference{
+//		Family: resty.IPFamilyIPv4Only,
+//	})
+func (c *Client) SetHostDialPreference(host string, p DialPreference) *Client {
+	pattern, err := parseHostPattern(host)
+	if err != nil {
+		c.Logger().Errorf("%v", err)
+		return c
+	}
+	return c.updateDialer(func(d *transportDialer) {
+		rule := dialPreferenceRule{hostPattern: pattern, preference: p}
+		d.hostPreferences = append(slices.Clip(d.hostPreferences), rule)
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+const defaultFallbackDelay = 300 * time.Millisecond
+
+type dialPreferenceRule struct {
+	hostPattern
+	preference DialPreference
+}
+
+type dialResult struct {
+	conn    net.Conn
+	err     error
+	primary bool
+}
+
+func (d *transportDialer) dialPreference(host string) DialPreference {
+	host = strings.ToLower(host)
+	for _, r := range d.hostPreferences {
+		if r.match(host) {
+			return r.preference
+		}
+	}
+	return d.preference
+}
+
+// partition method filters the addresses by the family preference and the
+// network, then splits them into the preferred and fallback address families.
+func (p DialPreference) partition(network string, ips []net.IPAddr) (primaries, fallbacks []net.IPAddr) {
+	allowIPv4 := p.Family != IPFamilyIPv6Only && !strings.HasSuffix(network, "6")
+	allowIPv6 := p.Family != IPFamilyIPv4Only && !strings.HasSuffix(network, "4")
+	var preferIPv4 bool
+	switch p.Family {
+	case IPFamilyPreferIPv4:
+		preferIPv4 = true
+	case IPFamilyAny:
+		preferIPv4 = len(ips) > 0 && ips[0].IP.To4() != nil
+	}
+
+	for _, ip := range ips {
+		isIPv4 := ip.IP.To4() != nil
+		if (isIPv4 && !allowIPv4) || (!isIPv4 && !allowIPv6) {
+			continue
+		}
+		if isIPv4 == preferIPv4 {
+			primaries = append(primaries, ip)
+		} else {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+	if len(primaries) == 0 {
+		primaries, fallbacks = fallbacks, nil
+	}
+	return
+}
+
+// dialParallel method races the preferred address family dial with the
+// fallback one, which starts after the fallback delay or on the preferred
+// dial failure; the first established connection wins.
+func (d *transportDialer) dialParallel(ctx context.Context, network, port string,
+	primaries, fallbacks []net.IPAddr, delay time.Duration) (net.Conn, error) {
+	if len(fallbacks) == 0 || delay < 0 {
+		return d.dialSerial(ctx, network, port, append(primaries, fallbacks...))
+	}
+	if delay == 0 {
+		delay = defaultFallbackDelay
+	}
+
+	returned := make(chan struct{})
+	defer close(returned)
+	results := make(chan dialResult)
+	race := func(ctx context.Context, primary bool, ips []net.IPAddr) {
+		conn, err := d.dialSerial(ctx, network, port, ips)
+		select {
+		case results <- dialResult{conn: conn, err: err, primary: primary}:
+		case <-returned:
+			if conn != nil {
+				_ = conn.Close()
+			}
+		}
+	}
+
+	primaryCtx, primaryCancel := context.WithCancel(ctx)
+	defer primaryCancel()
+	go race(primaryCtx, true, primaries)
+
+	fallbackTimer := time.NewTimer(delay)
+	defer fallbackTimer.Stop()
+
+	var primaryErr, fallbackErr error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			fallbackCtx, fallbackCancel := context.WithCancel(ctx)
+			defer fallbackCancel()
+			go race(fallbackCtx, false, fallbacks)
+		case res := <-results:
+			if res.err == nil {
+				return res.conn, nil
+			}
+			if res.primary {
+				primaryErr = res.err
+			} else {
+				fallbackErr = res.err
+			}
+			if primaryErr != nil && fallbackErr != nil {
+				return nil, errors.Join(primaryErr, fallbackErr)
+			}
+			// start the fallback now, if it is not started yet
+			if res.primary && fallbackTimer.Stop() {
+				fallbackTimer.Reset(0)
+			}
+		}
+	}
+}
+
+func (d *transportDialer) dialSerial(ctx context.Context, network, port string, ips []net.IPAddr) (net.Conn, error) {
+	var errs []error
+	for _, ip := range ips {
+		conn, err := d.dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
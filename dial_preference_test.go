@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDialer struct simulates the broken IPv6 path, the IPv6 dials hang
+// until the context is done, and the IPv4 dials succeed.
+type testDialer struct {
+	lock  sync.Mutex
+	addrs []string
+}
+
+func (td *testDialer) DialContext(ctx context.Context, _, addr string) (net.Conn, error) {
+	td.lock.Lock()
+	td.addrs = append(td.addrs, addr)
+	td.lock.Unlock()
+	if strings.HasPrefix(addr, "[") {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	c1, c2 := net.Pipe()
+	_ = c2.Close()
+	return c1, nil
+}
+
+func (td *testDialer) dialed() []string {
+	td.lock.Lock()
+	defer td.lock.Unlock()
+	return append([]string{}, td.addrs...)
+}
+
+func newTestDualStackDialer(pref DialPreference) (*transportDialer, *testDialer) {
+	td := &testDialer{}
+	return &transportDialer{
+		dial: td.DialContext,
+		resolver: &testResolver{addrs: map[string][]string{
+			"example.com":    {"2001:db8::1", "192.0.2.1"},
+			"v6.example.com": {"2001:db8::1"},
+		}},
+		preference: pref,
+	}, td
+}
+
+func TestDialPreferenceHappyEyeballs(t *testing.T) {
+	d, td := newTestDualStackDialer(DialPreference{FallbackDelay: 20 * time.Millisecond})
+
+	start := time.Now()
+	conn, err := d.DialContext(context.Background(), "tcp", "example.com:443")
+	assertNil(t, err)
+	_ = conn.Close()
+	elapsed := time.Since(start)
+	assertEqual(t, true, elapsed >= 20*time.Millisecond && elapsed < time.Second)
+	assertEqual(t, []string{"[2001:db8::1]:443", "192.0.2.1:443"}, td.dialed())
+
+	t.Run("fallback disabled", func(t *testing.T) {
+		d, td := newTestDualStackDialer(DialPreference{FallbackDelay: -1})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := d.DialContext(ctx, "tcp", "example.com:443")
+		assertErrorIs(t, context.DeadlineExceeded, err)
+		assertEqual(t, []string{"[2001:db8::1]:443"}, td.dialed())
+	})
+
+	t.Run("fallback on failure", func(t *testing.T) {
+		d, td := newTestDualStackDialer(DialPreference{FallbackDelay: time.Hour})
+		d.dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if strings.HasPrefix(addr, "[") {
+				return nil, errors.New("network is unreachable")
+			}
+			return td.DialContext(ctx, network, addr)
+		}
+		start := time.Now()
+		conn, err := d.DialContext(context.Background(), "tcp", "example.com:443")
+		assertNil(t, err)
+		_ = conn.Close()
+		assertEqual(t, true, time.Since(start) < time.Second)
+		assertEqual(t, []string{"192.0.2.1:443"}, td.dialed())
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		d, _ := newTestDualStackDialer(DialPreference{})
+		d.dial = func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("connection refused")
+		}
+		_, err := d.DialContext(context.Background(), "tcp", "example.com:443")
+		assertEqual(t, "connection refused\nconnection refused", err.Error())
+	})
+}
+
+func TestDialPreferenceFamily(t *testing.T) {
+	tests := []struct {
+		name     string
+		pref     DialPreference
+		network  string
+		host     string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "prefer ipv4",
+			pref:     DialPreference{Family: IPFamilyPreferIPv4},
+			network:  "tcp",
+			host:     "example.com",
+			expected: []string{"192.0.2.1:80"},
+		},
+		{
+			name:     "ipv4 only",
+			pref:     DialPreference{Family: IPFamilyIPv4Only},
+			network:  "tcp",
+			host:     "example.com",
+			expected: []string{"192.0.2.1:80"},
+		},
+		{
+			name:     "tcp4 network",
+			pref:     DialPreference{Family: IPFamilyPreferIPv6},
+			network:  "tcp4",
+			host:     "example.com",
+			expected: []string{"192.0.2.1:80"},
+		},
+		{
+			name:    "no suitable address",
+			pref:    DialPreference{Family: IPFamilyIPv4Only},
+			network: "tcp",
+			host:    "v6.example.com",
+			err:     "dial tcp: address v6.example.com: no suitable address found",
+		},
+		{
+			name:     "ip literal",
+			pref:     DialPreference{Family: IPFamilyIPv4Only},
+			network:  "tcp",
+			host:     "198.51.100.1",
+			expected: []string{"198.51.100.1:80"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, td := newTestDualStackDialer(tt.pref)
+			conn, err := d.DialContext(context.Background(), tt.network, net.JoinHostPort(tt.host, "80"))
+			if len(tt.err) > 0 {
+				assertNotNil(t, err)
+				assertEqual(t, tt.err, err.Error())
+				return
+			}
+			assertNil(t, err)
+			_ = conn.Close()
+			assertEqual(t, tt.expected, td.dialed())
+		})
+	}
+}
+
+func TestClientSetDialPreference(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	r := &testResolver{addrs: map[string][]string{
+		"example.com":        {"::1", "127.0.0.1"},
+		"legacy.example.com": {"::1", "127.0.0.1"},
+	}}
+	c := dcnl().
+		SetResolver(r).
+		SetDialPreference(DialPreference{Family: IPFamilyIPv6Only}).
+		SetHostDialPreference("*.legacy.example.com", DialPreference{Family: IPFamilyIPv4Only})
+	assertEqual(t, DialPreference{Family: IPFamilyIPv6Only}, c.DialPreference())
+
+	// the test server listens on IPv4 only
+	res, err := c.R().Get("http://legacy.example.com:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+
+	_, err = c.R().Get("http://example.com:" + port + "/")
+	assertNotNil(t, err)
+
+	t.Run("invalid host pattern", func(t *testing.T) {
+		var lgr bytes.Buffer
+		c := dcnl().SetLogger(&logger{l: log.New(&lgr, "", 0)})
+		c.SetHostDialPreference("10.0.0.0/33", DialPreference{})
+		assertEqual(t, true, strings.Contains(lgr.String(), "invalid CIDR address"))
+	})
+}
+
+func TestTransportSettingsDialPreference(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	c := NewWithTransportSettings(&TransportSettings{
+		FallbackDelay:    100 * time.Millisecond,
+		ForceAttemptIPv4: true,
+	})
+	assertEqual(t, DialPreference{Family: IPFamilyIPv4Only, FallbackDelay: 100 * time.Millisecond},
+		c.DialPreference())
+
+	res, err := c.R().Get("http://localhost:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+
+	c = NewWithTransportSettings(&TransportSettings{})
+	assertEqual(t, DialPreference{}, c.DialPreference())
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const dnsMessageContentType = "application/dns-message"
+
+// ErrDNSResponse is returned if the DNS server response is invalid or is
+// not successful, see [DNSResolver].
+var ErrDNSResponse = errors.New("resty: invalid dns response")
+
+// DNSResolverOptions struct is used to configure the DNS-over-HTTPS and
+// DNS-over-TLS resolvers, see [NewDoHResolver] and [NewDoTResolver].
+type DNSResolverOptions struct {
+	// Bootstrap is the IP address, with optional port, of the DNS server, so
+	// its host name is not resolved via the system resolver, e.g., `1.1.1.1`.
+	Bootstrap string
+
+	// TLSConfig is used to connect the DNS server; the server name is the
+	// DNS server host name if not set.
+	TLSConfig *tls.Config
+
+	// Timeout is the timeout of the DNS lookup. Default is 5 seconds.
+	Timeout time.Duration
+
+	// DisableCache disables the resolved addresses cache, by default, the
+	// addresses are cached as per the record TTL.
+	DisableCache bool
+}
+
+// DNSResolver struct is the DNS-over-HTTPS (RFC 8484) or DNS-over-TLS (RFC 7858)
+// resolver, it implements the [Resolver] interface.
+//
+//	resolver, err := resty.NewDoHResolver("https://cloudflare-dns.com/dns-query",
+//		&resty.DNSResolverOptions{Bootstrap: "1.1.1.1"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer resolver.Close()
+//
+//	client.SetResolver(resolver)
+type DNSResolver struct {
+	exchange  func(ctx context.Context, query []byte) ([]byte, error)
+	closeFunc func()
+	timeout   time.Duration
+	cache     *DNSCache
+}
+
+var _ Resolver = (*DNSResolver)(nil)
+
+// NewDoHResolver method creates the DNS-over-HTTPS resolver for the given
+// endpoint URL, e.g., `https://dns.google/dns-query`. The queries are sent
+// with the POST method.
+func NewDoHResolver(endpoint string, opts *DNSResolverOptions) (*DNSResolver, error) {
+	if opts == nil {
+		opts = &DNSResolverOptions{}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" || len(u.Host) == 0 {
+		return nil, fmt.Errorf("resty: invalid dns-over-https endpoint: %s", endpoint)
+	}
+
+	transport := createTransport(nil, nil)
+	if opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig.Clone()
+	}
+	if len(opts.Bootstrap) > 0 {
+		d := &transportDialer{
+			transport:   transport,
+			dial:        transport.DialContext,
+			hostMapping: map[string]string{strings.ToLower(u.Hostname()): opts.Bootstrap},
+		}
+		transport.DialContext = d.DialContext
+	}
+	hc := &http.Client{Transport: transport}
+
+	r := newDNSResolver(opts)
+	r.closeFunc = transport.CloseIdleConnections
+	r.exchange = func(ctx context.Context, query []byte) ([]byte, error) {
+		// the values of the dialing request context, e.g., trace, must not
+		// apply to the DNS request
+		ctx, cancel := withoutValues(ctx)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, MethodPost, endpoint, bytes.NewReader(query))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(hdrContentTypeKey, dnsMessageContentType)
+		req.Header.Set(hdrAcceptKey, dnsMessageContentType)
+		res, err := hc.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer closeq(res.Body)
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w: %s", ErrDNSResponse, res.Status)
+		}
+		return io.ReadAll(io.LimitReader(res.Body, 65535))
+	}
+	return r, nil
+}
+
+// NewDoTResolver method creates the DNS-over-TLS resolver for the given
+// server address, e.g., `dns.google` or `dns.google:853`.
+func NewDoTResolver(addr string, opts *DNSResolverOptions) (*DNSResolver, error) {
+	if opts == nil {
+		opts = &DNSResolverOptions{}
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, port = addr, "853"
+	}
+	if len(host) == 0 {
+		return nil, fmt.Errorf("resty: invalid dns-over-tls address: %s", addr)
+	}
+
+	tlsConfig := &tls.Config{}
+	if opts.TLSConfig != nil {
+		tlsConfig = opts.TLSConfig.Clone()
+	}
+	if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = host
+	}
+	dialAddr := net.JoinHostPort(host, port)
+	if b := opts.Bootstrap; len(b) > 0 {
+		if _, _, err := net.SplitHostPort(b); err != nil {
+			b = net.JoinHostPort(strings.Trim(b, "[]"), port)
+		}
+		dialAddr = b
+	}
+	dialer := &tls.Dialer{Config: tlsConfig}
+
+	r := newDNSResolver(opts)
+	r.exchange = func(ctx context.Context, query []byte) ([]byte, error) {
+		conn, err := dialer.DialContext(ctx, "tcp", dialAddr)
+		if err != nil {
+			return nil, err
+		}
+		defer closeq(conn)
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		// the message is prefixed with two bytes length field
+		msg := make([]byte, 2+len(query))
+		binary.BigEndian.PutUint16(msg, uint16(len(query)))
+		copy(msg[2:], query)
+		if _, err = conn.Write(msg); err != nil {
+			return nil, err
+		}
+		if _, err = io.ReadFull(conn, msg[:2]); err != nil {
+			return nil, err
+		}
+		res := make([]byte, binary.BigEndian.Uint16(msg[:2]))
+		if _, err = io.ReadFull(conn, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	return r, nil
+}
+
+// LookupIPAddr method looks up the IPv4 and IPv6 addresses of the given host,
+// the IPv4 addresses are ordered first.
+func (r *DNSResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if r.cache != nil {
+		return r.cache.lookup(ctx, host, r.LookupIPAddrTTL)
+	}
+	addrs, _, err := r.LookupIPAddrTTL(ctx, host)
+	return addrs, err
+}
+
+// LookupIPAddrTTL method looks up the IPv4 and IPv6 addresses of the given
+// host along with the minimum TTL of the records, it implements the [TTLResolver].
+func (r *DNSResolver) LookupIPAddrTTL(ctx context.Context, host string) ([]net.IPAddr, time.Duration, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, 0, nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	types := []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	results := make([]dnsLookupResult, len(types))
+	wg := sync.WaitGroup{}
+	for i, qtype := range types {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.lookup(ctx, host, qtype)
+		}()
+	}
+	wg.Wait()
+
+	var addrs []net.IPAddr
+	var ttl time.Duration
+	var errs []error
+	for _, res := range results {
+		if res.err != nil {
+			errs = append(errs, res.err)
+			continue
+		}
+		if len(res.addrs) > 0 && (ttl == 0 || res.ttl < ttl) {
+			ttl = res.ttl
+		}
+		addrs = append(addrs, res.addrs...)
+	}
+	if len(addrs) == 0 {
+		if len(errs) == len(types) {
+			return nil, 0, &net.DNSError{
+				Err:        errs[0].Error(),
+				Name:       host,
+				IsNotFound: errors.Is(errs[0], errDNSNotFound),
+				UnwrapErr:  errs[0],
+			}
+		}
+		return nil, 0, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, ttl, nil
+}
+
+// Close method closes the idle connections to the DNS server.
+func (r *DNSResolver) Close() error {
+	if r.closeFunc != nil {
+		r.closeFunc()
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+var errDNSNotFound = errors.New("no such host")
+
+type dnsLookupResult struct {
+	addrs []net.IPAddr
+	ttl   time.Duration
+	err   error
+}
+
+func newDNSResolver(opts *DNSResolverOptions) *DNSResolver {
+	r := &DNSResolver{timeout: opts.Timeout}
+	if r.timeout <= 0 {
+		r.timeout = 5 * time.Second
+	}
+	if !opts.DisableCache {
+		r.cache = NewDNSCache().SetNegativeTTL(0).SetStaleTTL(0)
+	}
+	return r
+}
+
+func (r *DNSResolver) lookup(ctx context.Context, host string, qtype dnsmessage.Type) dnsLookupResult {
+	name, err := dnsmessage.NewName(host + ".")
+	if err != nil {
+		return dnsLookupResult{err: err}
+	}
+	var id [2]byte
+	_, _ = rand.Read(id[:])
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: binary.BigEndian.Uint16(id[:]), RecursionDesired: true})
+	_ = b.StartQuestions()
+	_ = b.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET})
+	query, err := b.Finish()
+	if err != nil {
+		return dnsLookupResult{err: err}
+	}
+
+	res, err := r.exchange(ctx, query)
+	if err != nil {
+		return dnsLookupResult{err: err}
+	}
+	return parseDNSResponse(res, binary.BigEndian.Uint16(id[:]), qtype)
+}
+
+// withoutValues function returns the context that carries the deadline and
+// cancellation of the given context, but not its values.
+func withoutValues(ctx context.Context) (context.Context, context.CancelFunc) {
+	nctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		nctx, cancel = context.WithDeadline(nctx, deadline)
+	}
+	nctx, cancelCause := context.WithCancelCause(nctx)
+	stop := context.AfterFunc(ctx, func() { cancelCause(context.Cause(ctx)) })
+	return nctx, func() {
+		stop()
+		cancelCause(context.Canceled)
+		cancel()
+	}
+}
+
+func parseDNSResponse(msg []byte, id uint16, qtype dnsmessage.Type) dnsLookupResult {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil {
+		return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+	}
+	if h.ID != id || !h.Response {
+		return dnsLookupResult{err: fmt.Errorf("%w: id mismatch", ErrDNSResponse)}
+	}
+	switch h.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return dnsLookupResult{err: errDNSNotFound}
+	default:
+		return dnsLookupResult{err: fmt.Errorf("%w: %s", ErrDNSResponse, h.RCode)}
+	}
+	if err = p.SkipAllQuestions(); err != nil {
+		return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+	}
+
+	result := dnsLookupResult{}
+	for {
+		rh, err := p.AnswerHeader()
+		if errors.Is(err, dnsmessage.ErrSectionDone) {
+			break
+		}
+		if err != nil {
+			return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+		}
+		if rh.Type != qtype || rh.Class != dnsmessage.ClassINET {
+			// e.g., CNAME, the recursive resolver includes its target records
+			if err = p.SkipAnswer(); err != nil {
+				return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+			}
+			continue
+		}
+
+		var ip net.IP
+		if qtype == dnsmessage.TypeA {
+			a, err := p.AResource()
+			if err != nil {
+				return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+			}
+			ip = net.IP(a.A[:])
+		} else {
+			aaaa, err := p.AAAAResource()
+			if err != nil {
+				return dnsLookupResult{err: fmt.Errorf("%w: %w", ErrDNSResponse, err)}
+			}
+			ip = net.IP(aaaa.AAAA[:])
+		}
+		ttl := time.Duration(rh.TTL) * time.Second
+		if len(result.addrs) == 0 || ttl < result.ttl {
+			result.ttl = ttl
+		}
+		result.addrs = append(result.addrs, net.IPAddr{IP: ip})
+	}
+	return result
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TTLResolver interface is the [Resolver] that returns the TTL of the resolved
+// addresses, so the [DNSCache] respects the record TTL, e.g., [DNSResolver].
+type TTLResolver interface {
+	Resolver
+	LookupIPAddrTTL(ctx context.Context, host string) ([]net.IPAddr, time.Duration, error)
+}
+
+var _ TTLResolver = (*DNSResolver)(nil)
+
+const dnsCacheSweepInterval = time.Minute
+
+// DNSCacheStats struct is the snapshot of the [DNSCache] statistics.
+type DNSCacheStats struct {
+	// Hits is the count of lookups served from the cache, including
+	// the negative hits
+	Hits int64
+
+	// Misses is the count of lookups sent to the resolver
+	Misses int64
+
+	// NegativeHits is the count of lookups served the cached "not found" error
+	NegativeHits int64
+
+	// StaleHits is the count of misses served the expired addresses on the
+	// resolver error
+	StaleHits int64
+
+	// Entries is the count of hosts in the cache
+	Entries int
+}
+
+// NewDNSCache method creates the new DNS cache instance with default values,
+// see [DNSCache] for the defaults.
+//
+//	cache := resty.NewDNSCache().
+//		SetMaxTTL(time.Minute).
+//		SetStaleTTL(10 * time.Minute)
+//
+//	client.SetDNSCache(cache)
+func NewDNSCache() *DNSCache {
+	return &DNSCache{
+		entries:     make(map[string]*dnsCacheEntry),
+		inflight:    make(map[string]*dnsCacheCall),
+		defaultTTL:  30 * time.Second,
+		maxTTL:      5 * time.Minute,
+		negativeTTL: 5 * time.Second,
+		staleTTL:    time.Hour,
+		maxEntries:  10000,
+	}
+}
+
+// DNSCache struct caches the resolved addresses of the host names, see
+// [Client.SetDNSCache]. The concurrent lookups of the same host name are
+// sent to the resolver once. Defaults are
+//   - Default TTL: 30 seconds, it is used if the resolver does not
+//     implement [TTLResolver], e.g., the system resolver
+//   - Max TTL: 5 minutes, the record TTL is clamped to it
+//   - Negative TTL: 5 seconds, the "not found" error is cached for it
+//   - Stale TTL: 1 hour, the expired addresses are served on the
+//     resolver error until it
+//   - Max entries: 10000, the entries expiring soonest are evicted over it
+//
+// The entries expired beyond the stale TTL are evicted periodically.
+type DNSCache struct {
+	lock        sync.RWMutex
+	entries     map[string]*dnsCacheEntry
+	inflight    map[string]*dnsCacheCall
+	defaultTTL  time.Duration
+	maxTTL      time.Duration
+	negativeTTL time.Duration
+	staleTTL    time.Duration
+	maxEntries  int
+	nextSweep   time.Time
+
+	hits         atomic.Int64
+	misses       atomic.Int64
+	negativeHits atomic.Int64
+	staleHits    atomic.Int64
+}
+
+// SetDefaultTTL method sets the TTL of the addresses resolved by the resolver
+// that does not implement [TTLResolver].
+func (c *DNSCache) SetDefaultTTL(d time.Duration) *DNSCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.defaultTTL = d
+	return c
+}
+
+// SetMaxTTL method sets the max TTL to clamp the record TTL; zero value
+// means no clamp.
+func (c *DNSCache) SetMaxTTL(d time.Duration) *DNSCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxTTL = d
+	return c
+}
+
+// SetNegativeTTL method sets the TTL of the "not found" error; zero value
+// disables the negative caching.
+func (c *DNSCache) SetNegativeTTL(d time.Duration) *DNSCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.negativeTTL = d
+	return c
+}
+
+// SetStaleTTL method sets the duration after the expiry, the expired addresses
+// are served on the resolver error; zero value disables it.
+func (c *DNSCache) SetStaleTTL(d time.Duration) *DNSCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.staleTTL = d
+	return c
+}
+
+// SetMaxEntries method sets the max count of the host names in the cache, the
+// entries expiring soonest are evicted once it is reached; zero value means
+// no limit.
+func (c *DNSCache) SetMaxEntries(n int) *DNSCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxEntries = n
+	return c
+}
+
+// Stats method returns the snapshot of the cache statistics.
+func (c *DNSCache) Stats() DNSCacheStats {
+	c.lock.RLock()
+	n := len(c.entries)
+	c.lock.RUnlock()
+	return DNSCacheStats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		StaleHits:    c.staleHits.Load(),
+		Entries:      n,
+	}
+}
+
+// Flush method removes the given host names from the cache, or all of them
+// if none given.
+//
+//	cache.Flush("api.example.com")
+func (c *DNSCache) Flush(hosts ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(hosts) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, h := range hosts {
+		delete(c.entries, normalizeDNSHost(h))
+	}
+}
+
+// DNSCache method returns the DNS cache from the client instance.
+func (c *Client) DNSCache() *DNSCache {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.dialer == nil {
+		return nil
+	}
+	return c.dialer.cache
+}
+
+// SetDNSCache method sets the DNS cache for the client dials, the host names
+// are resolved via the resolver set by [Client.SetResolver], otherwise, the
+// system resolver. The nil value disables the cache.
+//
+//	client.SetDNSCache(resty.NewDNSCache())
+//
+// NOTE: Set [DNSResolverOptions].DisableCache on the [DNSResolver] to avoid
+// caching twice.
+func (c *Client) SetDNSCache(cache *DNSCache) *Client {
+	return c.updateDialer(func(d *transportDialer) {
+		d.cache = cache
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type dnsLookupTTLFunc func(ctx context.Context, host string) ([]net.IPAddr, time.Duration, error)
+
+type dnsCacheEntry struct {
+	addrs   []net.IPAddr
+	err     error
+	expires time.Time
+}
+
+type dnsCacheCall struct {
+	done  chan struct{}
+	addrs []net.IPAddr
+	err   error
+}
+
+func normalizeDNSHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// lookupTTLFunc function returns the lookup function of the resolver, the
+// default TTL is used if it does not implement [TTLResolver].
+func lookupTTLFunc(r Resolver, defaultTTL time.Duration) dnsLookupTTLFunc {
+	if tr, ok := r.(TTLResolver); ok {
+		return tr.LookupIPAddrTTL
+	}
+	return func(ctx context.Context, host string) ([]net.IPAddr, time.Duration, error) {
+		addrs, err := r.LookupIPAddr(ctx, host)
+		return addrs, defaultTTL, err
+	}
+}
+
+func (c *DNSCache) resolve(ctx context.Context, r Resolver, host string) ([]net.IPAddr, error) {
+	c.lock.RLock()
+	defaultTTL := c.defaultTTL
+	c.lock.RUnlock()
+	return c.lookup(ctx, host, lookupTTLFunc(r, defaultTTL))
+}
+
+func (c *DNSCache) lookup(ctx context.Context, host string, fn dnsLookupTTLFunc) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+	host = normalizeDNSHost(host)
+	now := time.Now()
+
+	c.lock.Lock()
+	if e, found := c.entries[host]; found && now.Before(e.expires) {
+		c.lock.Unlock()
+		c.hits.Add(1)
+		if e.err != nil {
+			c.negativeHits.Add(1)
+		}
+		return e.addrs, e.err
+	}
+	call, found := c.inflight[host]
+	if !found {
+		call = &dnsCacheCall{done: make(chan struct{})}
+		c.inflight[host] = call
+	}
+	c.lock.Unlock()
+
+	if found {
+		select {
+		case <-call.done:
+			c.hits.Add(1)
+			return call.addrs, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.misses.Add(1)
+	// the lookup is shared with the concurrent callers, so it must not be
+	// canceled by the caller, but it is bound by the caller deadline
+	lctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		lctx, cancel = context.WithDeadline(lctx, deadline)
+	}
+	addrs, ttl, err := fn(lctx, host)
+	cancel()
+	call.addrs, call.err = c.store(host, addrs, ttl, err)
+
+	c.lock.Lock()
+	delete(c.inflight, host)
+	c.lock.Unlock()
+	close(call.done)
+	return call.addrs, call.err
+}
+
+// store method caches the lookup result, it returns the stale addresses on
+// the resolver error, if available.
+func (c *DNSCache) store(host string, addrs []net.IPAddr, ttl time.Duration, err error) ([]net.IPAddr, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			if c.negativeTTL > 0 {
+				c.put(host, &dnsCacheEntry{err: err, expires: now.Add(c.negativeTTL)}, now)
+			} else {
+				delete(c.entries, host)
+			}
+			return nil, err
+		}
+		if e, found := c.entries[host]; found && e.err == nil &&
+			c.staleTTL > 0 && now.Before(e.expires.Add(c.staleTTL)) {
+			c.staleHits.Add(1)
+			return e.addrs, nil
+		}
+		return nil, err
+	}
+
+	if c.maxTTL > 0 && ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	if ttl > 0 {
+		c.put(host, &dnsCacheEntry{addrs: addrs, expires: now.Add(ttl)}, now)
+	}
+	return addrs, nil
+}
+
+// put method must be called with the lock held, it caches the entry and
+// evicts the expired entries periodically, and the entries expiring soonest
+// over the max entries.
+func (c *DNSCache) put(host string, entry *dnsCacheEntry, now time.Time) {
+	if now.After(c.nextSweep) {
+		c.nextSweep = now.Add(dnsCacheSweepInterval)
+		for h, e := range c.entries {
+			if c.isEvictable(e, now) {
+				delete(c.entries, h)
+			}
+		}
+	}
+
+	c.entries[host] = entry
+	for c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		soonest := ""
+		for h, e := range c.entries {
+			if h != host && (soonest == "" || e.expires.Before(c.entries[soonest].expires)) {
+				soonest = h
+			}
+		}
+		if soonest == "" {
+			break
+		}
+		delete(c.entries, soonest)
+	}
+}
+
+// isEvictable method returns true if the entry is expired, and it is not
+// served on the resolver error anymore, see [DNSCache.SetStaleTTL].
+func (c *DNSCache) isEvictable(e *dnsCacheEntry, now time.Time) bool {
+	expires := e.expires
+	if e.err == nil {
+		expires = expires.Add(c.staleTTL)
+	}
+	return !now.Before(expires)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testTTLResolver struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	err     error
+	delay   time.Duration
+	lookups atomic.Int32
+}
+
+func (tr *testTTLResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, _, err := tr.LookupIPAddrTTL(ctx, host)
+	return addrs, err
+}
+
+func (tr *testTTLResolver) LookupIPAddrTTL(_ context.Context, _ string) ([]net.IPAddr, time.Duration, error) {
+	tr.lookups.Add(1)
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	time.Sleep(tr.delay)
+	if tr.err != nil {
+		return nil, 0, tr.err
+	}
+	return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, tr.ttl, nil
+}
+
+func (tr *testTTLResolver) set(ttl time.Duration, err error) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.ttl, tr.err = ttl, err
+}
+
+func TestDNSCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewDNSCache().SetMaxTTL(time.Minute)
+
+	// record TTL is clamped
+	tr := &testTTLResolver{ttl: time.Hour}
+	_, err := cache.resolve(ctx, tr, "Example.com.")
+	assertNil(t, err)
+	e := cache.entries["example.com"]
+	assertEqual(t, true, time.Until(e.expires) <= time.Minute)
+	_, err = cache.resolve(ctx, tr, "example.com")
+	assertNil(t, err)
+	assertEqual(t, int32(1), tr.lookups.Load())
+
+	// default TTL for the resolver without TTL
+	r := &testResolver{addrs: map[string][]string{"www.example.com": {"127.0.0.1"}}}
+	cache.SetDefaultTTL(10 * time.Second)
+	_, err = cache.resolve(ctx, r, "www.example.com")
+	assertNil(t, err)
+	e = cache.entries["www.example.com"]
+	assertEqual(t, true, time.Until(e.expires) <= 10*time.Second)
+
+	// zero TTL is not cached
+	tr.set(0, nil)
+	_, err = cache.resolve(ctx, tr, "api.example.com")
+	assertNil(t, err)
+	_, err = cache.resolve(ctx, tr, "api.example.com")
+	assertNil(t, err)
+	assertEqual(t, int32(3), tr.lookups.Load())
+
+	// IP address
+	addrs, err := cache.resolve(ctx, tr, "10.1.2.3")
+	assertNil(t, err)
+	assertEqual(t, "10.1.2.3", addrs[0].String())
+
+	assertEqual(t, DNSCacheStats{Hits: 1, Misses: 4, Entries: 2}, cache.Stats())
+
+	cache.Flush("EXAMPLE.com")
+	assertEqual(t, 1, cache.Stats().Entries)
+	cache.Flush()
+	assertEqual(t, 0, cache.Stats().Entries)
+}
+
+func TestDNSCacheNegative(t *testing.T) {
+	ctx := context.Background()
+	r := &testResolver{addrs: map[string][]string{}}
+	cache := NewDNSCache()
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.resolve(ctx, r, "unknown.example.com")
+		var dnsErr *net.DNSError
+		assertEqual(t, true, errors.As(err, &dnsErr))
+		assertEqual(t, true, dnsErr.IsNotFound)
+	}
+	assertEqual(t, int32(1), r.lookups.Load())
+	assertEqual(t, DNSCacheStats{Hits: 2, Misses: 1, NegativeHits: 2, Entries: 1}, cache.Stats())
+
+	// disabled
+	cache = NewDNSCache().SetNegativeTTL(0)
+	for i := 0; i < 2; i++ {
+		_, err := cache.resolve(ctx, r, "unknown.example.com")
+		assertNotNil(t, err)
+	}
+	assertEqual(t, int32(3), r.lookups.Load())
+}
+
+func TestDNSCacheStaleOnError(t *testing.T) {
+	ctx := context.Background()
+	tr := &testTTLResolver{ttl: time.Millisecond}
+	cache := NewDNSCache()
+
+	_, err := cache.resolve(ctx, tr, "example.com")
+	assertNil(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	// resolver error, not the "not found"
+	tr.set(0, &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true})
+	addrs, err := cache.resolve(ctx, tr, "example.com")
+	assertNil(t, err)
+	assertEqual(t, "127.0.0.1", addrs[0].String())
+	assertEqual(t, int64(1), cache.Stats().StaleHits)
+
+	// disabled
+	cache.SetStaleTTL(0)
+	_, err = cache.resolve(ctx, tr, "example.com")
+	assertNotNil(t, err)
+	assertEqual(t, int64(1), cache.Stats().StaleHits)
+}
+
+func TestDNSCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	tr := &testTTLResolver{}
+	cache := NewDNSCache().SetStaleTTL(0).SetMaxEntries(2)
+
+	for i, host := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		tr.set(time.Duration(i+1)*time.Minute, nil)
+		_, err := cache.resolve(ctx, tr, host)
+		assertNil(t, err)
+	}
+	assertEqual(t, 2, cache.Stats().Entries)
+	_, found := cache.entries["a.example.com"]
+	assertEqual(t, false, found) // expires soonest
+
+	// expired entries are evicted on the sweep
+	tr.set(time.Millisecond, nil)
+	cache.SetMaxEntries(0)
+	_, err := cache.resolve(ctx, tr, "d.example.com")
+	assertNil(t, err)
+	time.Sleep(5 * time.Millisecond)
+	cache.nextSweep = time.Time{}
+	tr.set(time.Minute, nil)
+	_, err = cache.resolve(ctx, tr, "e.example.com")
+	assertNil(t, err)
+	_, found = cache.entries["d.example.com"]
+	assertEqual(t, false, found)
+	assertEqual(t, 3, cache.Stats().Entries)
+}
+
+func TestDNSCacheConcurrentLookups(t *testing.T) {
+	tr := &testTTLResolver{ttl: time.Minute, delay: 50 * time.Millisecond}
+	cache := NewDNSCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addrs, err := cache.resolve(context.Background(), tr, "example.com")
+			assertNil(t, err)
+			assertEqual(t, 1, len(addrs))
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, int32(1), tr.lookups.Load())
+	assertEqual(t, int64(9), cache.Stats().Hits)
+
+	// caller is canceled while waiting
+	cache.Flush()
+	go func() { _, _ = cache.resolve(context.Background(), tr, "example.com") }()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cache.resolve(ctx, tr, "example.com")
+	assertErrorIs(t, context.Canceled, err)
+}
+
+func TestClientSetDNSCache(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	r := &testResolver{addrs: map[string][]string{"example.com": {"127.0.0.1"}}}
+	cache := NewDNSCache()
+
+	// system resolver is used without the custom resolver
+	c := dcnl().SetDNSCache(cache)
+	assertEqual(t, cache, c.DNSCache())
+	_, err := c.R().Get("http://localhost:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, int64(1), cache.Stats().Misses)
+
+	c.SetResolver(r).SetCloseConnection(true)
+	for i := 0; i < 3; i++ {
+		res, err := c.R().Get("http://example.com:" + port + "/")
+		assertNil(t, err)
+		assertEqual(t, "TestGet: text response", res.String())
+	}
+	assertEqual(t, int32(1), r.lookups.Load())
+	assertEqual(t, DNSCacheStats{Hits: 2, Misses: 2, Entries: 2}, cache.Stats())
+
+	c.SetDNSCache(nil)
+	assertNil(t, c.DNSCache())
+}
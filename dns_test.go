@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var dnsTestRecords = map[string][]string{
+	"example.com.":      {"127.0.0.1", "::1"},
+	"www.example.com.":  {"127.0.0.1"},
+	"fail.example.com.": {"servfail"},
+}
+
+// dnsTestAnswer function answers the query from the test records.
+func dnsTestAnswer(t *testing.T, query []byte) []byte {
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	q, err := p.Question()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+
+	rh := dnsmessage.Header{ID: h.ID, Response: true, RecursionAvailable: true}
+	records, found := dnsTestRecords[q.Name.String()]
+	switch {
+	case !found:
+		rh.RCode = dnsmessage.RCodeNameError
+	case records[0] == "servfail":
+		rh.RCode = dnsmessage.RCodeServerFailure
+	}
+
+	b := dnsmessage.NewBuilder(nil, rh)
+	_ = b.StartQuestions()
+	_ = b.Question(q)
+	_ = b.StartAnswers()
+	if rh.RCode == dnsmessage.RCodeSuccess {
+		alias, _ := dnsmessage.NewName("alias." + q.Name.String())
+		_ = b.CNAMEResource(dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 10},
+			dnsmessage.CNAMEResource{CNAME: alias})
+		for _, r := range records {
+			ip := net.ParseIP(r)
+			hdr := dnsmessage.ResourceHeader{Name: alias, Class: dnsmessage.ClassINET, TTL: 60}
+			if ip4 := ip.To4(); ip4 != nil && q.Type == dnsmessage.TypeA {
+				_ = b.AResource(hdr, dnsmessage.AResource{A: [4]byte(ip4)})
+			} else if ip4 == nil && q.Type == dnsmessage.TypeAAAA {
+				_ = b.AAAAResource(hdr, dnsmessage.AAAAResource{AAAA: [16]byte(ip)})
+			}
+		}
+	}
+	res, err := b.Finish()
+	if err != nil {
+		t.Error(err)
+	}
+	return res
+}
+
+func createDoHTestServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	queries := &atomic.Int32{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != MethodPost || r.Header.Get(hdrContentTypeKey) != dnsMessageContentType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		queries.Add(1)
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set(hdrContentTypeKey, dnsMessageContentType)
+		_, _ = w.Write(dnsTestAnswer(t, b))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, queries
+}
+
+func TestDoHResolver(t *testing.T) {
+	ts, queries := createDoHTestServer(t)
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	// DNS server host name is not resolvable, bootstrap address is dialed
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	r, err := NewDoHResolver("https://dns.example.com:"+port+"/dns-query", &DNSResolverOptions{
+		Bootstrap: "127.0.0.1",
+		TLSConfig: &tls.Config{RootCAs: tlsConfig.RootCAs, ServerName: "example.com"},
+	})
+	assertNil(t, err)
+	defer r.Close()
+
+	addrs, err := r.LookupIPAddr(context.Background(), "Example.com.")
+	assertNil(t, err)
+	assertEqual(t, []net.IPAddr{{IP: net.ParseIP("127.0.0.1").To4()}, {IP: net.ParseIP("::1")}}, addrs)
+	assertEqual(t, int32(2), queries.Load())
+
+	// cached
+	_, err = r.LookupIPAddr(context.Background(), "example.com")
+	assertNil(t, err)
+	assertEqual(t, int32(2), queries.Load())
+
+	// IP address
+	addrs, err = r.LookupIPAddr(context.Background(), "10.1.2.3")
+	assertNil(t, err)
+	assertEqual(t, "10.1.2.3", addrs[0].String())
+	assertEqual(t, int32(2), queries.Load())
+
+	_, err = r.LookupIPAddr(context.Background(), "unknown.example.com")
+	var dnsErr *net.DNSError
+	assertEqual(t, true, errors.As(err, &dnsErr))
+	assertEqual(t, true, dnsErr.IsNotFound)
+	assertEqual(t, "unknown.example.com", dnsErr.Name)
+
+	_, err = r.LookupIPAddr(context.Background(), "fail.example.com")
+	assertErrorIs(t, ErrDNSResponse, err)
+	assertEqual(t, true, strings.Contains(err.Error(), "RCodeServerFailure"))
+
+	// client dials
+	echo := createGetServer(t)
+	defer echo.Close()
+	_, echoPort, _ := net.SplitHostPort(echo.Listener.Addr().String())
+	c := dcnl().SetResolver(r)
+	res, err := c.R().Get("http://www.example.com:" + echoPort + "/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+
+	_, err = NewDoHResolver("http://dns.example.com/dns-query", nil)
+	assertNotNil(t, err)
+}
+
+func TestDoTResolver(t *testing.T) {
+	ts, _ := createDoHTestServer(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: ts.TLS.Certificates})
+	assertNil(t, err)
+	defer ln.Close()
+
+	var conns atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer conn.Close()
+				var l [2]byte
+				if _, err := io.ReadFull(conn, l[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(l[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				res := dnsTestAnswer(t, query)
+				binary.BigEndian.PutUint16(l[:], uint16(len(res)))
+				_, _ = conn.Write(append(l[:], res...))
+			}()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	r, err := NewDoTResolver("example.com:"+port, &DNSResolverOptions{
+		Bootstrap:    "127.0.0.1",
+		TLSConfig:    &tls.Config{RootCAs: tlsConfig.RootCAs},
+		DisableCache: true,
+	})
+	assertNil(t, err)
+	defer r.Close()
+
+	for i := 0; i < 2; i++ {
+		addrs, err := r.LookupIPAddr(context.Background(), "www.example.com")
+		assertNil(t, err)
+		assertEqual(t, []net.IPAddr{{IP: net.ParseIP("127.0.0.1").To4()}}, addrs)
+	}
+	// not cached
+	assertEqual(t, int32(4), conns.Load())
+
+	// default port
+	r, err = NewDoTResolver("dns.example.com", &DNSResolverOptions{Bootstrap: "127.0.0.1", Timeout: 100 * time.Millisecond})
+	assertNil(t, err)
+	_, err = r.LookupIPAddr(context.Background(), "www.example.com")
+	assertNotNil(t, err)
+	assertEqual(t, true, strings.Contains(err.Error(), "127.0.0.1:853"))
+
+	_, err = NewDoTResolver(":853", nil)
+	assertNotNil(t, err)
+}
+
+func TestParseDNSResponse(t *testing.T) {
+	res := parseDNSResponse([]byte{1, 2}, 1, dnsmessage.TypeA)
+	assertErrorIs(t, ErrDNSResponse, res.err)
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 2, Response: true})
+	msg, _ := b.Finish()
+	res = parseDNSResponse(msg, 1, dnsmessage.TypeA)
+	assertErrorIs(t, ErrDNSResponse, res.err)
+	assertEqual(t, true, strings.Contains(res.err.Error(), "id mismatch"))
+}
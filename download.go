@@ -0,0 +1,415 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDownloadParallelism = 4
+	defaultDownloadChunkSize   = 8 << 20 // 8 MiB
+
+	downloadPartSuffix  = ".part"
+	downloadStateSuffix = ".resty-download"
+)
+
+var (
+	hdrAcceptRangesKey = http.CanonicalHeaderKey("Accept-Ranges")
+	hdrIfRangeKey      = http.CanonicalHeaderKey("If-Range")
+
+	// ErrDownloadResourceChanged is returned when the remote resource has been
+	// changed during the ranged download, i.e., the `If-Range` validation failed.
+	ErrDownloadResourceChanged = errors.New("resty: download: remote resource has been changed")
+)
+
+// DownloadOptions struct is used to configure the download,
+// see [Client.Download].
+type DownloadOptions struct {
+	// Parallelism is the count of the chunks downloaded concurrently;
+	// default is 4.
+	Parallelism int
+
+	// ChunkSize is the size of the chunk in bytes; default is 8 MiB.
+	ChunkSize int64
+
+	// Resume is used to resume the interrupted download from the completed
+	// chunks, if the remote resource has not been changed.
+	Resume bool
+
+	// MaxChunkRetries is the count of the retries of a failed chunk,
+	// independent of the other chunks; default is 3, and a negative value
+	// disables the retries.
+	MaxChunkRetries int
+
+	// RetryWaitTime and RetryMaxWaitTime are used to compute the capped
+	// exponential backoff with jitter between the chunk retries.
+	RetryWaitTime    time.Duration
+	RetryMaxWaitTime time.Duration
+
+	// Header is the additional headers of every download request.
+	Header http.Header
+
+	// Context is the context of the download requests; default is the client
+	// context.
+	Context context.Context
+
+	// OnProgress is called with the live download progress of all the chunks,
+	// see [DownloadProgress]. The bytes of a failed chunk are rewound on retry.
+	OnProgress func(DownloadProgress)
+}
+
+// Download method downloads the given URL into the destination file. If the
+// server supports the range requests, i.e., `Accept-Ranges: bytes`, the chunks
+// are downloaded concurrently with per-chunk retries; otherwise, the content is
+// downloaded in a single stream.
+//
+//	err := client.Download("https://example.com/large.iso", "/tmp/large.iso", resty.DownloadOptions{
+//		Parallelism: 8,
+//		ChunkSize:   16 << 20,
+//		Resume:      true,
+//	})
+//
+// The content is written into the `<dest>.part` file and renamed to the destination
+// on completion. The progress is tracked in the `<dest>.resty-download` file; so the
+// interrupted download resumes from the completed chunks with [DownloadOptions].Resume.
+// The `If-Range` header with the `ETag` or `Last-Modified` validator ensures the
+// chunks belong to the same resource version; otherwise, [ErrDownloadResourceChanged]
+// is returned, and the download starts afresh on the next attempt.
+//
+// The relative destination is resolved against the [Client.OutputDirectory],
+// if set; see [Client.SetOutputDirectory].
+func (c *Client) Download(url, dest string, opts DownloadOptions) error {
+	if dir := c.OutputDirectory(); len(dir) > 0 && !filepath.IsAbs(dest) {
+		dest = filepath.Join(dir, dest)
+	}
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	d := &downloader{c: c, url: url, dest: dest, opts: opts}
+	if d.opts.Parallelism <= 0 {
+		d.opts.Parallelism = defaultDownloadParallelism
+	}
+	if d.opts.ChunkSize <= 0 {
+		d.opts.ChunkSize = defaultDownloadChunkSize
+	}
+	if d.opts.MaxChunkRetries == 0 {
+		d.opts.MaxChunkRetries = 3
+	}
+	return d.download()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// downloadState struct is persisted to resume the interrupted download.
+type downloadState struct {
+	URL       string `json:"url"`
+	Validator string `json:"validator"`
+	Size      int64  `json:"size"`
+	ChunkSize int64  `json:"chunk_size"`
+	Done      []bool `json:"done"`
+}
+
+type downloader struct {
+	c    *Client
+	url  string
+	dest string
+	opts DownloadOptions
+
+	lock     sync.Mutex
+	state    downloadState
+	progress *progressTracker
+}
+
+func (d *downloader) newRequest() *Request {
+	r := d.c.R()
+	if d.opts.Context != nil {
+		r.SetContext(d.opts.Context)
+	}
+	for k, v := range d.opts.Header {
+		r.Header[k] = v
+	}
+	// the byte ranges apply to the identity content
+	r.SetHeader(hdrAcceptEncodingKey, "identity")
+	return r
+}
+
+func (d *downloader) context() context.Context {
+	if d.opts.Context != nil {
+		return d.opts.Context
+	}
+	if ctx := d.c.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func (d *downloader) download() error {
+	if dir := filepath.Dir(d.dest); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	res, err := d.newRequest().Head(d.url)
+	if err != nil {
+		return err
+	}
+	size := res.RawResponse.ContentLength
+	if !res.IsSuccess() || size <= 0 ||
+		!strings.EqualFold(res.Header().Get(hdrAcceptRangesKey), "bytes") {
+		return d.downloadStream()
+	}
+
+	validator := res.Header().Get(hdrETagKey)
+	if strings.HasPrefix(validator, "W/") {
+		validator = "" // weak validator is not allowed in If-Range
+	}
+	if len(validator) == 0 {
+		validator = res.Header().Get(hdrLastModifiedKey)
+	}
+	return d.downloadRanges(size, validator)
+}
+
+// downloadStream method downloads the content in a single stream, it is used
+// when the server does not support the range requests.
+func (d *downloader) downloadStream() error {
+	partFile := d.dest + downloadPartSuffix
+	var res *Response
+	var err error
+	backoff := d.c.newBackoffWithJitter(d.opts.RetryWaitTime, d.opts.RetryMaxWaitTime)
+	for attempt := 0; ; attempt++ {
+		res, err = d.newRequest().
+			SetOutputFileName(partFile).
+			SetDownloadProgressCallback(d.opts.OnProgress).
+			Get(d.url)
+		if err == nil && !res.IsSuccess() {
+			err = fmt.Errorf("resty: download: unexpected status %s", res.Status())
+		}
+		if err == nil || attempt >= d.opts.MaxChunkRetries {
+			break
+		}
+		if err = d.wait(backoff, res, err, attempt); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		_ = os.Remove(partFile)
+		return err
+	}
+	return os.Rename(partFile, d.dest)
+}
+
+func (d *downloader) downloadRanges(size int64, validator string) error {
+	partFile := d.dest + downloadPartSuffix
+	stateFile := d.dest + downloadStateSuffix
+
+	chunks := int((size + d.opts.ChunkSize - 1) / d.opts.ChunkSize)
+	d.state = downloadState{
+		URL:       d.url,
+		Validator: validator,
+		Size:      size,
+		ChunkSize: d.opts.ChunkSize,
+		Done:      make([]bool, chunks),
+	}
+
+	flag := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if d.opts.Resume && d.loadState(stateFile) {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(partFile, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	if err = f.Truncate(size); err != nil {
+		closeq(f)
+		return err
+	}
+
+	if d.opts.OnProgress != nil {
+		var written int64
+		for i, done := range d.state.Done {
+			if done {
+				written += d.chunkSize(i)
+			}
+		}
+		d.progress = newProgressTracker(d.opts.OnProgress, written, size)
+	}
+
+	ctx, cancel := context.WithCancel(d.context())
+	defer cancel()
+
+	jobs := make(chan int)
+	errOnce := sync.Once{}
+	var firstErr error
+	wg := sync.WaitGroup{}
+	for range min(d.opts.Parallelism, chunks) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := d.downloadChunkWithRetry(ctx, f, i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for i := range chunks {
+		if d.state.Done[i] {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	closeErr := f.Close()
+	if firstErr != nil {
+		if errors.Is(firstErr, ErrDownloadResourceChanged) {
+			// start afresh on the next attempt
+			_ = os.Remove(stateFile)
+		}
+		return firstErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if err = os.Rename(partFile, d.dest); err != nil {
+		return err
+	}
+	_ = os.Remove(stateFile)
+	return nil
+}
+
+// loadState method loads the persisted state of the interrupted download,
+// it reports true if the state is resumable.
+func (d *downloader) loadState(stateFile string) bool {
+	b, err := os.ReadFile(stateFile)
+	if err != nil {
+		return false
+	}
+	st := downloadState{}
+	if err = json.Unmarshal(b, &st); err != nil {
+		return false
+	}
+	if st.URL != d.state.URL || st.Validator != d.state.Validator || len(st.Validator) == 0 ||
+		st.Size != d.state.Size || st.ChunkSize != d.state.ChunkSize || len(st.Done) != len(d.state.Done) {
+		return false
+	}
+	if _, err = os.Stat(d.dest + downloadPartSuffix); err != nil {
+		return false
+	}
+	d.state.Done = st.Done
+	return true
+}
+
+func (d *downloader) markDone(i int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.state.Done[i] = true
+	if b, err := json.Marshal(d.state); err == nil {
+		_ = os.WriteFile(d.dest+downloadStateSuffix, b, 0o644)
+	}
+}
+
+func (d *downloader) downloadChunkWithRetry(ctx context.Context, f *os.File, i int) error {
+	backoff := d.c.newBackoffWithJitter(d.opts.RetryWaitTime, d.opts.RetryMaxWaitTime)
+	for attempt := 0; ; attempt++ {
+		res, err := d.downloadChunk(ctx, f, i)
+		if err == nil {
+			d.markDone(i)
+			return nil
+		}
+		if errors.Is(err, ErrDownloadResourceChanged) || ctx.Err() != nil ||
+			attempt >= d.opts.MaxChunkRetries {
+			return err
+		}
+		if err = d.wait(backoff, res, err, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *downloader) wait(backoff *backoffWithJitter, res *Response, err error, attempt int) error {
+	waitTime, werr := backoff.NextWaitDuration(d.c, res, err, attempt)
+	if werr != nil {
+		return werr
+	}
+	select {
+	case <-time.After(waitTime):
+		return nil
+	case <-d.context().Done():
+		return d.context().Err()
+	}
+}
+
+func (d *downloader) downloadChunk(ctx context.Context, f *os.File, i int) (*Response, error) {
+	start := int64(i) * d.opts.ChunkSize
+	end := start + d.chunkSize(i) - 1
+
+	r := d.newRequest().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader(hdrRangeKey, fmt.Sprintf("bytes=%d-%d", start, end))
+	if len(d.state.Validator) > 0 {
+		r.SetHeader(hdrIfRangeKey, d.state.Validator)
+	}
+	res, err := r.Get(d.url)
+	if err != nil {
+		return res, err
+	}
+	defer closeq(res.Body)
+
+	switch res.StatusCode() {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// If-Range validation failed, the full content is sent
+		return res, ErrDownloadResourceChanged
+	default:
+		return res, fmt.Errorf("resty: download chunk %d-%d: unexpected status %s", start, end, res.Status())
+	}
+
+	body := io.Reader(res.Body)
+	if d.progress != nil {
+		body = &countReadCloser{r: res.Body, f: d.progress.add}
+	}
+	n, err := io.Copy(io.NewOffsetWriter(f, start), io.LimitReader(body, end-start+1))
+	if err == nil && n != end-start+1 {
+		err = fmt.Errorf("resty: download chunk %d-%d: %w", start, end, io.ErrUnexpectedEOF)
+	}
+	if err != nil && d.progress != nil && n > 0 {
+		d.progress.add(-n) // rewind, the chunk is downloaded again
+	}
+	return res, err
+}
+
+func (d *downloader) chunkSize(i int) int64 {
+	start := int64(i) * d.opts.ChunkSize
+	return min(start+d.opts.ChunkSize, d.state.Size) - start
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rangeDownloadServer serves the content with range support; it fails the given
+// byte ranges once.
+func rangeDownloadServer(t *testing.T, content []byte, etag string, failOnce map[string]bool) (*sync.Map, string) {
+	var lock sync.Mutex
+	ranges := &sync.Map{}
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		rng := r.Header.Get(hdrRangeKey)
+		if len(rng) > 0 {
+			ranges.Store(rng, r.Header.Get(hdrIfRangeKey))
+		}
+		lock.Lock()
+		fail := failOnce[rng]
+		delete(failOnce, rng)
+		lock.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if len(etag) > 0 {
+			w.Header().Set(hdrETagKey, etag)
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	})
+	t.Cleanup(ts.Close)
+	return ranges, ts.URL
+}
+
+func TestClientDownloadParallel(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 105)) // 1050 bytes
+	ranges, url := rangeDownloadServer(t, content, `"v1"`, map[string]bool{"bytes=200-299": true})
+
+	dest := filepath.Join(t.TempDir(), "sub", "file.bin")
+	var lock sync.Mutex
+	var progress []DownloadProgress
+	c := dcnl()
+	err := c.Download(url, dest, DownloadOptions{
+		Parallelism:   3,
+		ChunkSize:     100,
+		RetryWaitTime: time.Millisecond,
+		OnProgress: func(p DownloadProgress) {
+			lock.Lock()
+			progress = append(progress, p)
+			lock.Unlock()
+		},
+	})
+	assertNil(t, err)
+
+	last := progress[len(progress)-1]
+	assertEqual(t, int64(1050), last.Written)
+	assertEqual(t, int64(1050), last.Total)
+	assertEqual(t, float64(100), last.Percent)
+
+	b, err := os.ReadFile(dest)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+
+	count := 0
+	ranges.Range(func(k, v any) bool {
+		count++
+		assertEqual(t, `"v1"`, v)
+		return true
+	})
+	assertEqual(t, 11, count)
+	assertEqual(t, true, os.IsNotExist(statErr(dest+downloadPartSuffix)))
+	assertEqual(t, true, os.IsNotExist(statErr(dest+downloadStateSuffix)))
+}
+
+func TestClientDownloadResume(t *testing.T) {
+	content := []byte(strings.Repeat("abcdefghij", 30)) // 300 bytes
+	ranges, url := rangeDownloadServer(t, content, `"v2"`, map[string]bool{})
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	// interrupted download, the first chunk is completed
+	part := make([]byte, len(content))
+	copy(part, content[:100])
+	assertNil(t, os.WriteFile(dest+downloadPartSuffix, part, 0o644))
+	st, _ := json.Marshal(downloadState{
+		URL:       url,
+		Validator: `"v2"`,
+		Size:      300,
+		ChunkSize: 100,
+		Done:      []bool{true, false, false},
+	})
+	assertNil(t, os.WriteFile(dest+downloadStateSuffix, st, 0o644))
+
+	var first DownloadProgress
+	c := dcnl()
+	err := c.Download(url, dest, DownloadOptions{
+		ChunkSize: 100,
+		Resume:    true,
+		OnProgress: func(p DownloadProgress) {
+			if first.Written == 0 {
+				first = p
+			}
+		},
+	})
+	assertNil(t, err)
+	assertEqual(t, true, first.Written > 100) // includes the resumed chunk
+
+	b, err := os.ReadFile(dest)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+
+	_, found := ranges.Load("bytes=0-99")
+	assertEqual(t, false, found)
+	_, found = ranges.Load("bytes=100-199")
+	assertEqual(t, true, found)
+}
+
+func TestClientDownloadResourceChanged(t *testing.T) {
+	content := []byte(strings.Repeat("x", 200))
+	etag := `"v1"`
+	var lock sync.Mutex
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		w.Header().Set(hdrETagKey, etag)
+		if r.Method == MethodHead {
+			etag = `"v2"` // changed after the probe
+		}
+		lock.Unlock()
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	})
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	c := dcnl()
+	err := c.Download(ts.URL, dest, DownloadOptions{ChunkSize: 100, Resume: true})
+	assertErrorIs(t, ErrDownloadResourceChanged, err)
+	assertEqual(t, true, os.IsNotExist(statErr(dest)))
+	assertEqual(t, true, os.IsNotExist(statErr(dest+downloadStateSuffix)))
+}
+
+func TestClientDownloadWithoutRanges(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, "", r.Header.Get(hdrRangeKey))
+		_, _ = w.Write([]byte("no ranges here"))
+	})
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.txt")
+	c := dcnl()
+	assertNil(t, c.Download(ts.URL, dest, DownloadOptions{}))
+
+	b, err := os.ReadFile(dest)
+	assertNil(t, err)
+	assertEqual(t, "no ranges here", string(b))
+}
+
+func TestClientDownloadOutputDirectory(t *testing.T) {
+	content := []byte(strings.Repeat("r", 100))
+	_, rangesURL := rangeDownloadServer(t, content, `"v1"`, nil)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	})
+	defer ts.Close()
+
+	dir := t.TempDir()
+	c := dcnl().SetOutputDirectory(dir)
+	assertNil(t, c.Download(ts.URL, "stream/file.txt", DownloadOptions{}))
+	assertNil(t, c.Download(rangesURL, "ranges/file.txt", DownloadOptions{ChunkSize: 30}))
+
+	for _, name := range []string{"stream/file.txt", "ranges/file.txt"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assertNil(t, err)
+		assertEqual(t, content, b)
+	}
+}
+
+func TestClientDownloadChunkRetriesExhausted(t *testing.T) {
+	content := []byte(strings.Repeat("z", 200))
+	_, url := rangeDownloadServer(t, content, `"v1"`, map[string]bool{"bytes=100-199": true})
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	c := dcnl()
+	err := c.Download(url, dest, DownloadOptions{
+		Parallelism:     1,
+		ChunkSize:       100,
+		MaxChunkRetries: -1,
+	})
+	assertEqual(t, "resty: download chunk 100-199: unexpected status 503 Service Unavailable", err.Error())
+
+	// state is kept to resume
+	b, err := os.ReadFile(dest + downloadStateSuffix)
+	assertNil(t, err)
+	st := downloadState{}
+	assertNil(t, json.Unmarshal(b, &st))
+	assertEqual(t, []bool{true, false}, st.Done)
+
+	assertNil(t, c.Download(url, dest, DownloadOptions{ChunkSize: 100, Resume: true}))
+	b, err = os.ReadFile(dest)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+}
+
+func statErr(name string) error {
+	_, err := os.Stat(name)
+	return err
+}
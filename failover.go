@@ -0,0 +1,251 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"sync"
+	"time"
+)
+
+// FailoverFunc type provides feedback on the failover and fail-back of the
+// [Failover] load balancer
+type FailoverFunc func(from, to string)
+
+// NewFailover method creates the new primary/standby request load balancer
+// instance with given primary and standby base URLs in the order of preference.
+//
+//	fo, err := resty.NewFailover("https://api.example.com", "https://api-dr.example.com")
+//	if err != nil {
+//		return err
+//	}
+//	client.SetLoadBalancer(fo)
+func NewFailover(primary string, standbys ...string) (*Failover, error) {
+	fo := &Failover{
+		lock:        new(sync.Mutex),
+		maxFailures: 3, // default value is 3
+		healthCheck: (&HealthCheck{}).withDefaults(),
+	}
+	for _, u := range append([]string{primary}, standbys...) {
+		baseURL, err := extractBaseURL(u)
+		if err != nil {
+			return fo, err
+		}
+		fo.baseURLs = append(fo.baseURLs, baseURL)
+	}
+	return fo, nil
+}
+
+var _ StatsLoadBalancer = (*Failover)(nil)
+
+// Failover struct used to implement the primary/standby request load balancer,
+// it is a simpler alternative to the full load balancer. The requests are sent
+// to the primary Base URL; after the consecutive failures, they are shifted to
+// the next standby. The preceding Base URLs are probed periodically, and
+// the requests are shifted back once any of them is healthy again.
+//
+// See [Failover.SetMaxFailures], [Failover.SetHealthCheck]
+type Failover struct {
+	lock          *sync.Mutex
+	baseURLs      []string
+	active        int
+	failures      int
+	maxFailures   int
+	healthCheck   *HealthCheck
+	probeStop     chan struct{}
+	onFailover    FailoverFunc
+	onStateChange HostStateChangeFunc
+	stats         lbStats
+}
+
+// Next method returns the active Base URL
+func (fo *Failover) Next() (string, error) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	fo.stats.begin(fo.baseURLs[fo.active])
+	return fo.baseURLs[fo.active], nil
+}
+
+// Feedback method process the request feedback of the active Base URL, it
+// shifts the requests to the next standby after the consecutive failures.
+func (fo *Failover) Feedback(f *RequestFeedback) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	fo.stats.end(f)
+
+	if f.BaseURL != fo.baseURLs[fo.active] {
+		return
+	}
+	if f.Success {
+		fo.failures = 0
+		return
+	}
+	fo.failures++
+	if fo.failures >= fo.maxFailures && fo.active < len(fo.baseURLs)-1 {
+		fo.switchTo(fo.active + 1)
+	}
+}
+
+// Close method does the cleanup by stopping the probes on the
+// primary/standby request load balancer
+func (fo *Failover) Close() error {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	fo.stopProbe()
+	return nil
+}
+
+// Active method returns the active Base URL and whether it is the primary
+func (fo *Failover) Active() (string, bool) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	return fo.baseURLs[fo.active], fo.active == 0
+}
+
+// Stats method returns the statistics of the Base URLs, the Base URLs preceding
+// the active one are [HostStateInActive].
+func (fo *Failover) Stats() []TargetStats {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	result := make([]TargetStats, 0, len(fo.baseURLs))
+	for idx, baseURL := range fo.baseURLs {
+		result = append(result, fo.stats.snapshot(baseURL, fo.state(idx)))
+	}
+	return result
+}
+
+// SetMaxFailures method sets the number of the consecutive failures to shift
+// the requests to the next standby, default value is `3`.
+func (fo *Failover) SetMaxFailures(n int) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	if n > 0 {
+		fo.maxFailures = n
+	}
+}
+
+// SetHealthCheck method sets the health check to probe the preceding Base URLs
+// for the fail-back, see [HealthCheck] for the default values. The requests are
+// shifted back once the Base URL passes the [HealthCheck].HealthyThreshold
+// consecutive checks.
+//
+//	fo.SetHealthCheck(&resty.HealthCheck{
+//		Path:     "/healthz",
+//		Interval: 30 * time.Second,
+//	})
+func (fo *Failover) SetHealthCheck(hc *HealthCheck) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	if hc == nil {
+		hc = &HealthCheck{}
+	}
+	fo.healthCheck = hc.withDefaults()
+	if fo.probeStop != nil {
+		fo.startProbe()
+	}
+}
+
+// SetOnFailover method used to set a callback for the failover and fail-back
+func (fo *Failover) SetOnFailover(fn FailoverFunc) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	fo.onFailover = fn
+}
+
+// SetOnStateChange method used to set a callback for the Base URL transition state,
+// i.e., it is [HostStateInActive] on the failover and [HostStateActive] on the fail-back.
+func (fo *Failover) SetOnStateChange(fn HostStateChangeFunc) {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	fo.onStateChange = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// switchTo method shifts the requests to the Base URL of the given index, and
+// probes the preceding ones if any. The caller must hold the lock.
+func (fo *Failover) switchTo(idx int) {
+	prev := fo.active
+	fo.active, fo.failures = idx, 0
+	if idx == 0 {
+		fo.stopProbe()
+	} else {
+		fo.startProbe()
+	}
+	if fo.onFailover != nil {
+		fo.onFailover(fo.baseURLs[prev], fo.baseURLs[idx])
+	}
+	if fo.onStateChange != nil {
+		for i := min(prev, idx); i < max(prev, idx); i++ {
+			if idx > prev {
+				fo.onStateChange(fo.baseURLs[i], HostStateActive, HostStateInActive)
+			} else {
+				fo.onStateChange(fo.baseURLs[i], HostStateInActive, HostStateActive)
+			}
+		}
+	}
+}
+
+// state method returns the state of the Base URL of the given index. The
+// caller must hold the lock.
+func (fo *Failover) state(idx int) HostState {
+	if idx < fo.active {
+		return HostStateInActive
+	}
+	return HostStateActive
+}
+
+// startProbe method starts the probe of the Base URLs preceding the active
+// one after stopping the running one. The caller must hold the lock.
+func (fo *Failover) startProbe() {
+	fo.stopProbe()
+	stop := make(chan struct{})
+	fo.probeStop = stop
+	go fo.probe(stop, fo.healthCheck, fo.baseURLs[:fo.active])
+}
+
+// stopProbe method stops the running probe. The caller must hold the lock.
+func (fo *Failover) stopProbe() {
+	if fo.probeStop != nil {
+		close(fo.probeStop)
+		fo.probeStop = nil
+	}
+}
+
+// probe method probes the given Base URLs at the interval until the stop
+// channel is closed; it shifts the requests back to the first healthy one.
+func (fo *Failover) probe(stop <-chan struct{}, hc *HealthCheck, baseURLs []string) {
+	health := make([]hostHealth, len(baseURLs))
+	for i := range health {
+		health[i].unhealthy = true
+	}
+
+	ticker := time.NewTicker(hc.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		for i, baseURL := range baseURLs {
+			if !health[i].record(hc, hc.probe(stop, baseURL)) {
+				continue
+			}
+
+			fo.lock.Lock()
+			select {
+			case <-stop:
+			default:
+				fo.switchTo(i)
+			}
+			fo.lock.Unlock()
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailover(t *testing.T) {
+	var primaryDown atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "primary")
+	}))
+	defer primary.Close()
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "standby")
+	}))
+	defer standby.Close()
+
+	fo, err := NewFailover(primary.URL, standby.URL+"/")
+	assertNil(t, err)
+	fo.SetMaxFailures(2)
+	fo.SetHealthCheck(&HealthCheck{Interval: 20 * time.Millisecond, HealthyThreshold: 2})
+
+	var lock sync.Mutex
+	var switches []string
+	fo.SetOnFailover(func(from, to string) {
+		lock.Lock()
+		defer lock.Unlock()
+		switches = append(switches, from+" -> "+to)
+	})
+
+	c := dcnl().SetLoadBalancer(fo)
+	defer c.Close()
+
+	res, err := c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "primary", res.String())
+
+	// consecutive failures shift the requests to the standby
+	primaryDown.Store(true)
+	for i := 0; i < 2; i++ {
+		res, err = c.R().Get("/")
+		assertNil(t, err)
+		assertEqual(t, http.StatusServiceUnavailable, res.StatusCode())
+	}
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "standby", res.String())
+	baseURL, isPrimary := fo.Active()
+	assertEqual(t, standby.URL, baseURL)
+	assertEqual(t, false, isPrimary)
+
+	// fail-back once the primary is healthy again
+	time.Sleep(50 * time.Millisecond)
+	primaryDown.Store(false)
+	waitForCondition(t, func() bool {
+		_, isPrimary := fo.Active()
+		return isPrimary
+	})
+	res, err = c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "primary", res.String())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assertEqual(t, []string{primary.URL + " -> " + standby.URL, standby.URL + " -> " + primary.URL}, switches)
+
+	t.Run("last standby", func(t *testing.T) {
+		fo, err := NewFailover("https://api.example.com", "https://api-dr.example.com")
+		assertNil(t, err)
+		defer fo.Close()
+		for i := 0; i < 10; i++ {
+			baseURL, _ := fo.Next()
+			fo.Feedback(&RequestFeedback{BaseURL: baseURL, Success: false})
+		}
+		baseURL, _ := fo.Next()
+		assertEqual(t, "https://api-dr.example.com", baseURL)
+
+		// the success resets the failures
+		fo, _ = NewFailover("https://api.example.com", "https://api-dr.example.com")
+		defer fo.Close()
+		for i := 0; i < 10; i++ {
+			fo.Feedback(&RequestFeedback{BaseURL: "https://api.example.com", Success: i%2 == 0})
+		}
+		fo.Feedback(&RequestFeedback{BaseURL: "https://api-dr.example.com", Success: false})
+		baseURL, _ = fo.Next()
+		assertEqual(t, "https://api.example.com", baseURL)
+	})
+
+	t.Run("invalid base url", func(t *testing.T) {
+		_, err := NewFailover("https://api.example.com", "://api-dr.example.com")
+		assertNotNil(t, err)
+	})
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrFaultInjected error is wrapped by the errors injected by the [FaultInjection].
+var ErrFaultInjected = errors.New("resty: fault injected")
+
+// FaultInjection struct holds the settings to inject the faults into the client
+// requests for the chaos testing, e.g., to verify the retry and circuit breaker
+// settings against the realistic failure modes. The probabilities are between
+// `0` and `1`, and the faults are injected on each request attempt.
+//
+// See [Client.SetFaultInjection], [Client.SetHostFaultInjection]
+type FaultInjection struct {
+	// Latency is the delay added before the request is sent
+	Latency time.Duration
+
+	// LatencyProbability is the probability to add the latency
+	LatencyProbability float64
+
+	// ResetProbability is the probability to fail the request with the
+	// connection reset error, i.e., [syscall.ECONNRESET]
+	ResetProbability float64
+
+	// StatusCodes is the list of status codes, one of them is picked at random
+	// to respond instead of sending the request
+	StatusCodes []int
+
+	// StatusProbability is the probability to respond with the status code
+	StatusProbability float64
+
+	// TruncateProbability is the probability to truncate the response body at
+	// a random point, reading it fails with [io.ErrUnexpectedEOF]
+	TruncateProbability float64
+}
+
+// SetFaultInjection method sets the fault injection for the client requests,
+// the nil value disables it.
+//
+//	client.SetFaultInjection(&resty.FaultInjection{
+//		Latency:             2 * time.Second,
+//		LatencyProbability:  0.1,
+//		ResetProbability:    0.05,
+//		StatusCodes:         []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+//		StatusProbability:   0.1,
+//		TruncateProbability: 0.05,
+//	})
+//
+// NOTE: It is meant for the tests, never enable it in production.
+func (c *Client) SetFaultInjection(fi *FaultInjection) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.faultInjection = fi
+	return c
+}
+
+// SetHostFaultInjection method sets the fault injection for the requests of the
+// given host pattern, it overrides the settings set by [Client.SetFaultInjection].
+// The host patterns are evaluated in the order they are added, the supported
+// patterns are
+//   - Host name, e.g., `api.example.com`
+//   - Domain with its subdomains, e.g., `*.example.com` or `.example.com`
+//   - IP address, e.g., `10.1.2.3`
+//   - CIDR, e.g., `10.0.0.0/8`
+//
+// For example:
+//
+//	client.SetHostFaultInjection("payments.example.com", &resty.FaultInjection{
+//		StatusCodes:       []int{http.StatusServiceUnavailable},
+//		StatusProbability: 0.5,
+//	})
+func (c *Client) SetHostFaultInjection(host string, fi *FaultInjection) *Client {
+	pattern, err := parseHostPattern(host)
+	if err != nil {
+		c.Logger().Errorf("%v", err)
+		return c
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	rule := faultInjectionRule{hostPattern: pattern, faultInjection: fi}
+	c.hostFaultInjections = append(slices.Clip(c.hostFaultInjections), rule)
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type faultInjectionRule struct {
+	hostPattern
+	faultInjection *FaultInjection
+}
+
+// activeFaultInjection method returns the fault injection for the given host
+func (c *Client) activeFaultInjection(host string) *FaultInjection {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	host = strings.ToLower(host)
+	for _, r := range c.hostFaultInjections {
+		if r.match(host) {
+			return r.faultInjection
+		}
+	}
+	return c.faultInjection
+}
+
+// do method sends the request via the given function and injects the faults
+func (fi *FaultInjection) do(rnd *lockedRand, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if fi.Latency > 0 && faultHit(rnd, fi.LatencyProbability) {
+		timer := time.NewTimer(fi.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, &url.Error{Op: urlErrorOp(req.Method), URL: req.URL.String(), Err: req.Context().Err()}
+		case <-timer.C:
+		}
+	}
+
+	if faultHit(rnd, fi.ResetProbability) {
+		return nil, &url.Error{
+			Op:  urlErrorOp(req.Method),
+			URL: req.URL.String(),
+			Err: &net.OpError{
+				Op:  "read",
+				Net: "tcp",
+				Err: os.NewSyscallError("read", &faultError{err: syscall.ECONNRESET}),
+			},
+		}
+	}
+
+	if len(fi.StatusCodes) > 0 && faultHit(rnd, fi.StatusProbability) {
+		statusCode := fi.StatusCodes[rnd.IntN(len(fi.StatusCodes))]
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode: statusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := send(req)
+	if err != nil || !faultHit(rnd, fi.TruncateProbability) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &truncatedBody{r: bytes.NewReader(body[:rnd.IntN(len(body)+1)])}
+	return resp, nil
+}
+
+// truncatedBody type reads the partial body, and fails with [io.ErrUnexpectedEOF]
+type truncatedBody struct {
+	r io.Reader
+}
+
+func (tb *truncatedBody) Read(p []byte) (int, error) {
+	n, err := tb.r.Read(p)
+	if err == io.EOF {
+		err = &faultError{err: io.ErrUnexpectedEOF}
+	}
+	return n, err
+}
+
+func (tb *truncatedBody) Close() error {
+	return nil
+}
+
+// faultError type is the injected error, it wraps the [ErrFaultInjected] and
+// the underlying error
+type faultError struct {
+	err error
+}
+
+func (e *faultError) Error() string {
+	return ErrFaultInjected.Error() + ": " + e.err.Error()
+}
+
+func (e *faultError) Unwrap() []error {
+	return []error{ErrFaultInjected, e.err}
+}
+
+// faultHit function reports whether the event of the given probability happens
+func faultHit(rnd *lockedRand, probability float64) bool {
+	return probability > 0 && rnd.Float64() < probability
+}
+
+// urlErrorOp function returns the [url.Error] operation of the given method,
+// same as the [http.Client]
+func urlErrorOp(method string) string {
+	if method == "" {
+		return "Get"
+	}
+	return method[:1] + strings.ToLower(method[1:])
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFaultInjection(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = io.WriteString(w, strings.Repeat("resty", 100))
+	}))
+	defer ts.Close()
+
+	t.Run("connection reset", func(t *testing.T) {
+		hits.Store(0)
+		c := dcnl().
+			SetRetryCount(2).
+			SetRetryWaitTime(time.Millisecond).
+			AddRetryConditions(func(_ *Response, err error) bool {
+				return errors.Is(err, syscall.ECONNRESET)
+			}).
+			SetFaultInjection(&FaultInjection{ResetProbability: 1})
+		defer c.Close()
+
+		res, err := c.R().Get(ts.URL)
+		assertErrorIs(t, syscall.ECONNRESET, err)
+		assertErrorIs(t, ErrFaultInjected, err)
+		assertEqual(t, 3, res.Request.Attempt)
+		assertEqual(t, int32(0), hits.Load())
+	})
+
+	t.Run("status code", func(t *testing.T) {
+		hits.Store(0)
+		c := dcnl().SetFaultInjection(&FaultInjection{
+			StatusCodes:       []int{http.StatusServiceUnavailable},
+			StatusProbability: 1,
+		})
+		defer c.Close()
+
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, http.StatusServiceUnavailable, res.StatusCode())
+		assertEqual(t, "503 Service Unavailable", res.Status())
+		assertEqual(t, int32(0), hits.Load())
+	})
+
+	t.Run("with response cache", func(t *testing.T) {
+		hits.Store(0)
+		c := dcnl().
+			EnableFeature(FeatureResponseCache).
+			SetFaultInjection(&FaultInjection{
+				StatusCodes:       []int{http.StatusServiceUnavailable},
+				StatusProbability: 1,
+			})
+		defer c.Close()
+
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, http.StatusServiceUnavailable, res.StatusCode())
+		assertEqual(t, int32(0), hits.Load())
+	})
+
+	t.Run("truncated body", func(t *testing.T) {
+		c := dcnl().SetFaultInjection(&FaultInjection{TruncateProbability: 1})
+		defer c.Close()
+
+		_, err := c.R().Get(ts.URL)
+		assertErrorIs(t, io.ErrUnexpectedEOF, err)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		c := dcnl().SetFaultInjection(&FaultInjection{Latency: time.Second, LatencyProbability: 1})
+		defer c.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := c.R().SetContext(ctx).Get(ts.URL)
+		assertEqual(t, true, errors.Is(err, context.DeadlineExceeded))
+
+		c.SetFaultInjection(&FaultInjection{Latency: 20 * time.Millisecond, LatencyProbability: 1})
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, true, res.Duration() >= 20*time.Millisecond)
+	})
+
+	t.Run("per host", func(t *testing.T) {
+		hits.Store(0)
+		c := dcnl().
+			SetFaultInjection(&FaultInjection{ResetProbability: 1}).
+			SetHostFaultInjection("*.example.com", &FaultInjection{ResetProbability: 1}).
+			SetHostFaultInjection("127.0.0.0/8", &FaultInjection{})
+		defer c.Close()
+
+		res, err := c.R().Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+		assertEqual(t, int32(1), hits.Load())
+
+		// nil disables the client fault injection
+		c.SetFaultInjection(nil)
+		assertNil(t, c.activeFaultInjection("api.other.com"))
+		assertNotNil(t, c.activeFaultInjection("api.example.com"))
+	})
+}
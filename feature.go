@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+// Feature type represents an opt-in Resty behavior that is not enabled by
+// default. Features are rolled out gradually; once a feature is considered
+// stable, it becomes the default behavior and its flag turns into a no-op.
+//
+// See [Client.EnableFeature], [Client.DisableFeature], [Client.Features]
+type Feature string
+
+const (
+	// FeatureHTTP3 allows Resty to use the HTTP/3 (QUIC) transport, when one is
+	// configured on the client, see the `http3` module. It is enabled by
+	// `http3.Enable`; while it is disabled, the requests are sent using the
+	// fallback transport.
+	FeatureHTTP3 Feature = "http3"
+
+	// FeatureAdaptiveRetry makes the retry backoff honor the server-sent
+	// `Retry-After` header on every retryable response, not only on
+	// 429 Too Many Requests and 503 Service Unavailable.
+	FeatureAdaptiveRetry Feature = "adaptive-retry"
+
+	// FeatureResponseCache enables the HTTP response cache on the client.
+	FeatureResponseCache Feature = "response-cache"
+)
+
+// knownFeatures holds the features that are recognized by Resty.
+var knownFeatures = map[Feature]struct{}{
+	FeatureHTTP3:         {},
+	FeatureAdaptiveRetry: {},
+	FeatureResponseCache: {},
+}
+
+// String method returns the string value of the feature.
+func (f Feature) String() string {
+	return string(f)
+}
+
+func isKnownFeature(f Feature) bool {
+	_, found := knownFeatures[f]
+	return found
+}
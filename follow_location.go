@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultFollowPollInterval = time.Second
+
+var (
+	// ErrLocationHeaderMissing error is returned by [Response.FollowLocation]
+	// when the response has no `Location` header.
+	ErrLocationHeaderMissing = errors.New("resty: location header is missing")
+
+	// ErrFollowLocationPending error is returned by [Response.FollowLocation]
+	// when the resource is still processing, i.e., `202 Accepted`, after the
+	// max poll attempts, see [Request.SetFollowCreatedPolling]. The last
+	// response is returned along with the error.
+	ErrFollowLocationPending = errors.New("resty: follow location: resource is still pending")
+)
+
+// SetFollowCreated method instructs Resty to follow the `Location` header of
+// the `201 Created` and `202 Accepted` responses of the current request, i.e.,
+// to issue the follow-up GET request and decode the final resource into the
+// result, see [Request.SetResult]. The final response is returned by the
+// request execution; see [Response.FollowLocation] for details.
+//
+//	res, err := client.R().
+//		SetBody(order).
+//		SetResult(&Order{}).
+//		SetFollowCreated(true).
+//		Post("/orders")
+//
+//	order := res.Result().(*Order) // from the GET request of the created order
+func (r *Request) SetFollowCreated(follow bool) *Request {
+	r.followCreated = follow
+	return r
+}
+
+// SetFollowCreatedPolling method enables the polling of the `Location` header
+// for the long-running operations; while the follow-up GET request returns
+// `202 Accepted`, it waits for the interval and polls again, up to the max
+// attempts. The `Retry-After` header of the response takes precedence over
+// the interval.
+//
+//	res, err := client.R().
+//		SetBody(exportRequest).
+//		SetResult(&Export{}).
+//		SetFollowCreated(true).
+//		SetFollowCreatedPolling(2*time.Second, 30).
+//		Post("/exports")
+//
+// If the resource is still pending after the max attempts, the last response is
+// returned along with the error [ErrFollowLocationPending].
+func (r *Request) SetFollowCreatedPolling(interval time.Duration, maxAttempts int) *Request {
+	r.followPollInterval = interval
+	r.followPollMaxAttempts = maxAttempts
+	return r
+}
+
+// FollowLocation method issues the follow-up GET request to the URL of the
+// response `Location` header, it is resolved against the request URL. The final
+// resource is decoded into the result and error values of the request, see
+// [Request.SetResult] and [Request.SetError]. It polls while the resource is
+// pending, if enabled, see [Request.SetFollowCreatedPolling].
+//
+//	res, err := client.R().SetBody(job).Post("/jobs") // 202 Accepted
+//	if err == nil && res.StatusCode() == http.StatusAccepted {
+//		res, err = res.FollowLocation()
+//	}
+//
+// NOTE: The headers and credentials of the request are sent only if the
+// `Location` has the same host as the request, like the redirect policy of the
+// standard HTTP client; otherwise, only the client-level ones are sent.
+func (r *Response) FollowLocation() (*Response, error) {
+	if r.Request == nil {
+		return nil, ErrLocationHeaderMissing
+	}
+
+	loc, err := r.locationURL()
+	if err != nil {
+		return nil, err
+	}
+
+	req := r.Request
+	attempts := max(req.followPollMaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		res, err := req.newFollowLocationRequest(loc).Get(loc.String())
+		if err != nil || res.StatusCode() != http.StatusAccepted || req.followPollMaxAttempts <= 0 {
+			return res, err
+		}
+		if attempt >= attempts {
+			return res, fmt.Errorf("%w: %s", ErrFollowLocationPending, loc)
+		}
+
+		// the status monitor may point to the next location
+		if next, err := res.locationURL(); err == nil {
+			loc = next
+		}
+
+		wait := req.followPollInterval
+		if wait <= 0 {
+			wait = defaultFollowPollInterval
+		}
+		if delay, ok := res.RetryAfter(); ok {
+			wait = delay
+		}
+		drainBody(res)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return res, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// followCreatedIfRequired method follows the `Location` header of the created
+// or accepted response, if enabled, see [Request.SetFollowCreated].
+func (r *Request) followCreatedIfRequired(res *Response, err error) (*Response, error) {
+	if !r.followCreated || err != nil || res == nil ||
+		(res.StatusCode() != http.StatusCreated && res.StatusCode() != http.StatusAccepted) ||
+		len(res.Header().Get(hdrLocationKey)) == 0 {
+		return res, err
+	}
+	drainBody(res)
+	return res.FollowLocation()
+}
+
+func (r *Response) locationURL() (*url.URL, error) {
+	loc := r.Header().Get(hdrLocationKey)
+	if len(loc) == 0 {
+		return nil, ErrLocationHeaderMissing
+	}
+	u, err := url.Parse(loc)
+	if err != nil {
+		return nil, fmt.Errorf("resty: follow location: %w", err)
+	}
+	if r.RawResponse != nil && r.RawResponse.Request != nil {
+		u = r.RawResponse.Request.URL.ResolveReference(u)
+	}
+	return u, nil
+}
+
+func (r *Request) newFollowLocationRequest(loc *url.URL) *Request {
+	fr := r.client.R().
+		SetContext(r.Context()).
+		SetLogger(r.log).
+		SetDebug(r.Debug)
+	fr.Result = r.Result
+	fr.Error = r.Error
+	fr.followPollMaxAttempts = r.followPollMaxAttempts
+	fr.followPollInterval = r.followPollInterval
+
+	if r.RawRequest != nil && r.RawRequest.URL.Host == loc.Host {
+		fr.Header = r.Header.Clone()
+		fr.Header.Del(hdrContentTypeKey)
+		fr.Header.Del(hdrContentLengthKey)
+		fr.credentials = r.credentials
+		fr.AuthToken = r.AuthToken
+		fr.AuthScheme = r.AuthScheme
+	}
+	return fr
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type followOrder struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Auth   string `json:"auth"`
+}
+
+func TestRequestFollowCreated(t *testing.T) {
+	other := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		_, _ = w.Write([]byte(`{"id":"2","status":"external","auth":"` + r.Header.Get(hdrAuthorizationKey) + `"}`))
+	})
+	defer other.Close()
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/orders":
+			w.Header().Set(hdrLocationKey, "/orders/1")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"1","status":"new"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/external":
+			w.Header().Set(hdrLocationKey, other.URL+"/orders/2")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/orders/1":
+			_, _ = w.Write([]byte(`{"id":"1","status":"confirmed","auth":"` + r.Header.Get(hdrAuthorizationKey) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+
+	res, err := c.R().
+		SetAuthToken("token").
+		SetBody(map[string]string{"item": "book"}).
+		SetResult(&followOrder{}).
+		SetFollowCreated(true).
+		Post("/orders")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, http.MethodGet, res.Request.Method)
+	assertEqual(t, followOrder{ID: "1", Status: "confirmed", Auth: "Bearer token"}, *res.Result().(*followOrder))
+
+	t.Run("not enabled", func(t *testing.T) {
+		res, err := c.R().SetResult(&followOrder{}).Post("/orders")
+		assertError(t, err)
+		assertEqual(t, http.StatusCreated, res.StatusCode())
+		assertEqual(t, "new", res.Result().(*followOrder).Status)
+
+		res, err = res.FollowLocation()
+		assertError(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+		assertEqual(t, "confirmed", res.Result().(*followOrder).Status)
+	})
+
+	t.Run("credentials not sent to other host", func(t *testing.T) {
+		res, err := c.R().
+			SetAuthToken("token").
+			SetResult(&followOrder{}).
+			SetFollowCreated(true).
+			Post("/external")
+		assertError(t, err)
+		assertEqual(t, followOrder{ID: "2", Status: "external"}, *res.Result().(*followOrder))
+	})
+
+	t.Run("location header missing", func(t *testing.T) {
+		res, err := c.R().Get("/orders/1")
+		assertError(t, err)
+		_, err = res.FollowLocation()
+		assertErrorIs(t, ErrLocationHeaderMissing, err)
+	})
+}
+
+func TestRequestFollowCreatedPolling(t *testing.T) {
+	var polls atomic.Int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/exports":
+			w.Header().Set(hdrLocationKey, "/exports/1/status")
+			w.WriteHeader(http.StatusAccepted)
+		case "/exports/1/status":
+			if polls.Add(1) < 3 {
+				w.Header().Set(hdrRetryAfterKey, "0")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Header().Set(hdrContentTypeKey, "application/json")
+			_, _ = w.Write([]byte(`{"id":"1","status":"done"}`))
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+
+	res, err := c.R().
+		SetResult(&followOrder{}).
+		SetFollowCreated(true).
+		SetFollowCreatedPolling(time.Hour, 5).
+		Post("/exports")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "done", res.Result().(*followOrder).Status)
+	assertEqual(t, int32(3), polls.Load())
+
+	polls.Store(0)
+	res, err = c.R().
+		SetFollowCreated(true).
+		SetFollowCreatedPolling(time.Millisecond, 2).
+		Post("/exports")
+	assertErrorIs(t, ErrFollowLocationPending, err)
+	assertEqual(t, http.StatusAccepted, res.StatusCode())
+	assertEqual(t, int32(2), polls.Load())
+}
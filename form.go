@@ -0,0 +1,133 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// FormBracketScheme type is to define the key scheme of the slice and array
+// values in the nested form data encoding, see [Request.SetFormDataNested].
+type FormBracketScheme uint8
+
+const (
+	// FormBracketsEmpty scheme encodes the slice values with the empty brackets,
+	// i.e., `list[]=x&list[]=y` (Rails/PHP style). It is the default scheme.
+	FormBracketsEmpty FormBracketScheme = iota
+
+	// FormBracketsIndexed scheme encodes the slice values with the indexed brackets,
+	// i.e., `list[0]=x&list[1]=y`.
+	FormBracketsIndexed
+
+	// FormBracketsNone scheme encodes the slice values with the repeated keys,
+	// i.e., `list=x&list=y`.
+	FormBracketsNone
+)
+
+// ErrUnsupportedFormDataKind error is returned when the nested form data is
+// neither a struct nor a map.
+var ErrUnsupportedFormDataKind = errors.New("resty: unsupported form data kind, it must be struct or map")
+
+var (
+	formTagNames  = []string{"form", "json"}
+	queryTagNames = []string{"query", "json"}
+)
+
+// formEncoder type flattens the given struct or map into the values, the
+// nested values are encoded with the brackets, i.e., `a[b]=1`.
+//
+// The struct field name is taken from the first non-empty tag of the tag names,
+// falling back to the field name.
+type formEncoder struct {
+	scheme     FormBracketScheme
+	timeLayout string
+	tagNames   []string
+}
+
+func (e formEncoder) encode(values url.Values, v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if k := rv.Kind(); (k != reflect.Struct && k != reflect.Map) || rv.Type() == timeType {
+		return ErrUnsupportedFormDataKind
+	}
+	return e.encodeValue(values, "", rv)
+}
+
+func (e formEncoder) encodeValue(values url.Values, key string, v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		values.Add(key, formatTime(v.Interface().(time.Time), e.timeLayout))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range structFields(v.Type(), e.tagNames...) {
+			fv := v.FieldByIndex(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if err := e.encodeValue(values, formKey(key, f.name), fv); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		items := make(map[string]reflect.Value, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, k)
+			items[k] = iter.Value()
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := e.encodeValue(values, formKey(key, k), items[k]); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(key, string(v.Bytes()))
+			return nil
+		}
+		for i := range v.Len() {
+			ek := key
+			switch e.scheme {
+			case FormBracketsEmpty:
+				ek += "[]"
+			case FormBracketsIndexed:
+				ek += "[" + strconv.Itoa(i) + "]"
+			}
+			if err := e.encodeValue(values, ek, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Func, reflect.Chan, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return fmt.Errorf("resty: unsupported form data value type %s", v.Type())
+	default:
+		values.Add(key, fmt.Sprint(v.Interface()))
+	}
+	return nil
+}
+
+func formKey(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type formTestAddress struct {
+	City string `form:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type formTestUser struct {
+	Name      string            `form:"name"`
+	Roles     []string          `form:"roles"`
+	Address   *formTestAddress  `form:"address"`
+	Meta      map[string]int    `form:"meta"`
+	Others    []formTestAddress `form:"others"`
+	Joined    time.Time         `form:"joined"`
+	Secret    string            `form:"-"`
+	Nickname  string            `form:"nickname,omitempty"`
+	IsEnabled bool
+}
+
+func TestEncodeFormValues(t *testing.T) {
+	user := &formTestUser{
+		Name:      "resty",
+		Roles:     []string{"admin", "dev"},
+		Address:   &formTestAddress{City: "Chennai"},
+		Meta:      map[string]int{"b": 2, "a": 1},
+		Others:    []formTestAddress{{City: "Paris", Zip: "75001"}},
+		Joined:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Secret:    "secret",
+		IsEnabled: true,
+	}
+
+	tests := []struct {
+		scheme   FormBracketScheme
+		expected url.Values
+	}{
+		{
+			scheme: FormBracketsEmpty,
+			expected: url.Values{
+				"name":           {"resty"},
+				"roles[]":        {"admin", "dev"},
+				"address[city]":  {"Chennai"},
+				"meta[a]":        {"1"},
+				"meta[b]":        {"2"},
+				"others[][city]": {"Paris"},
+				"others[][zip]":  {"75001"},
+				"joined":         {"2024-01-02T03:04:05Z"},
+				"IsEnabled":      {"true"},
+			},
+		},
+		{
+			scheme: FormBracketsIndexed,
+			expected: url.Values{
+				"name":            {"resty"},
+				"roles[0]":        {"admin"},
+				"roles[1]":        {"dev"},
+				"address[city]":   {"Chennai"},
+				"meta[a]":         {"1"},
+				"meta[b]":         {"2"},
+				"others[0][city]": {"Paris"},
+				"others[0][zip]":  {"75001"},
+				"joined":          {"2024-01-02T03:04:05Z"},
+				"IsEnabled":       {"true"},
+			},
+		},
+		{
+			scheme: FormBracketsNone,
+			expected: url.Values{
+				"name":          {"resty"},
+				"roles":         {"admin", "dev"},
+				"address[city]": {"Chennai"},
+				"meta[a]":       {"1"},
+				"meta[b]":       {"2"},
+				"others[city]":  {"Paris"},
+				"others[zip]":   {"75001"},
+				"joined":        {"2024-01-02T03:04:05Z"},
+				"IsEnabled":     {"true"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		values := url.Values{}
+		assertNil(t, formEncoder{scheme: tc.scheme, tagNames: formTagNames}.encode(values, user))
+		assertEqual(t, tc.expected, values)
+	}
+
+	assertErrorIs(t, ErrUnsupportedFormDataKind, formEncoder{tagNames: formTagNames}.encode(url.Values{}, "text"))
+	assertErrorIs(t, ErrUnsupportedFormDataKind, formEncoder{tagNames: formTagNames}.encode(url.Values{}, time.Now()))
+
+	err := formEncoder{tagNames: formTagNames}.encode(url.Values{}, map[string]any{"fn": func() {}})
+	assertEqual(t, "resty: unsupported form data value type func()", err.Error())
+}
+
+func TestRequestSetFormDataNested(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set(hdrContentTypeKey, r.Header.Get(hdrContentTypeKey))
+		_, _ = w.Write(b)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	assertEqual(t, FormBracketsEmpty, c.FormBracketScheme())
+
+	res, err := c.R().
+		SetFormData(map[string]string{"token": "abc"}).
+		SetFormDataNested(map[string]any{
+			"user": map[string]any{
+				"name":  "resty",
+				"roles": []string{"admin", "dev"},
+			},
+		}).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, formContentType, res.Header().Get(hdrContentTypeKey))
+	assertEqual(t, "token=abc&user%5Bname%5D=resty&user%5Broles%5D%5B%5D=admin&user%5Broles%5D%5B%5D=dev", res.String())
+
+	c.SetFormBracketScheme(FormBracketsIndexed)
+	assertEqual(t, FormBracketsIndexed, c.FormBracketScheme())
+	res, err = c.R().
+		SetFormDataNested(map[string]any{"list": []string{"x", "y"}}).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "list%5B0%5D=x&list%5B1%5D=y", res.String())
+
+	// request level overrides the client
+	res, err = c.R().
+		SetFormBracketScheme(FormBracketsNone).
+		SetFormDataNested(map[string]any{"list": []string{"x", "y"}}).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "list=x&list=y", res.String())
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+)
+
+// Get function sends the HTTP GET request and returns the successful response
+// decoded into the type T, i.e., no [Request.SetResult] and type assertion.
+//
+//	user, res, err := resty.Get[User](ctx, client, "https://example.com/users/{id}",
+//		func(r *resty.Request) *resty.Request {
+//			return r.SetPathParam("id", "1001")
+//		},
+//	)
+//
+// The options are applied to the request in the given order before sending it.
+//
+// NOTE: The value is the zero value of T, if the response is not successful.
+//
+// See [Execute]
+func Get[T any](ctx context.Context, c *Client, url string, opts ...RequestFunc) (T, *Response, error) {
+	return doTyped[T](ctx, c, MethodGet, url, nil, opts)
+}
+
+// Post function sends the HTTP POST request with the given body and returns the
+// successful response decoded into the type T.
+//
+//	user, res, err := resty.Post[User](ctx, client, "https://example.com/users", &User{Name: "resty"})
+//
+// See [Get], [Execute]
+func Post[T any](ctx context.Context, c *Client, url string, body any, opts ...RequestFunc) (T, *Response, error) {
+	return doTyped[T](ctx, c, MethodPost, url, body, opts)
+}
+
+// Put function sends the HTTP PUT request with the given body and returns the
+// successful response decoded into the type T.
+//
+// See [Get], [Execute]
+func Put[T any](ctx context.Context, c *Client, url string, body any, opts ...RequestFunc) (T, *Response, error) {
+	return doTyped[T](ctx, c, MethodPut, url, body, opts)
+}
+
+// Patch function sends the HTTP PATCH request with the given body and returns the
+// successful response decoded into the type T.
+//
+// See [Get], [Execute]
+func Patch[T any](ctx context.Context, c *Client, url string, body any, opts ...RequestFunc) (T, *Response, error) {
+	return doTyped[T](ctx, c, MethodPatch, url, body, opts)
+}
+
+// Delete function sends the HTTP DELETE request and returns the successful
+// response decoded into the type T.
+//
+// See [Get], [Execute]
+func Delete[T any](ctx context.Context, c *Client, url string, opts ...RequestFunc) (T, *Response, error) {
+	return doTyped[T](ctx, c, MethodDelete, url, nil, opts)
+}
+
+// Execute function sends the HTTP request with the given method and returns the
+// successful response decoded into the type T and the error response, i.e.,
+// HTTP status code > 399, decoded into the type E.
+//
+//	user, apiErr, res, err := resty.Execute[User, APIError](ctx, client, resty.MethodPost,
+//		"https://example.com/users",
+//		func(r *resty.Request) *resty.Request {
+//			return r.SetBody(&User{Name: "resty"})
+//		},
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	if res.IsError() {
+//		fmt.Println(apiErr.Message)
+//	}
+//
+// It overrides the error type set at the client instance level, see [Client.SetError].
+func Execute[T, E any](ctx context.Context, c *Client, method, url string, opts ...RequestFunc) (T, E, *Response, error) {
+	var result T
+	var errResult E
+	r := newTypedRequest(ctx, c, nil, opts).
+		SetResult(&result).
+		SetError(&errResult)
+	res, err := r.Execute(method, url)
+	return result, errResult, res, err
+}
+
+func doTyped[T any](ctx context.Context, c *Client, method, url string, body any, opts []RequestFunc) (T, *Response, error) {
+	var result T
+	res, err := newTypedRequest(ctx, c, body, opts).
+		SetResult(&result).
+		Execute(method, url)
+	return result, res, err
+}
+
+func newTypedRequest(ctx context.Context, c *Client, body any, opts []RequestFunc) *Request {
+	r := c.R()
+	if ctx != nil {
+		r.SetContext(ctx)
+	}
+	if body != nil {
+		r.SetBody(body)
+	}
+	for _, opt := range opts {
+		r = opt(r)
+	}
+	return r
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type genericTestUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type genericTestError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func createGenericTestServer(t *testing.T) string {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		switch {
+		case r.URL.Path == "/users/1001":
+			_, _ = w.Write([]byte(`{"id":"1001","name":"resty"}`))
+		case r.URL.Path == "/users" && r.Method == MethodGet:
+			_, _ = w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+		case r.URL.Path == "/users":
+			u := genericTestUser{}
+			_ = json.NewDecoder(r.Body).Decode(&u)
+			u.ID = r.Method
+			_ = json.NewEncoder(w).Encode(u)
+		case r.URL.Path == "/echo-header":
+			_, _ = io.WriteString(w, `{"name":"`+r.Header.Get("X-Name")+`"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	})
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestGenericHelpers(t *testing.T) {
+	baseURL := createGenericTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+	ctx := context.Background()
+
+	user, res, err := Get[genericTestUser](ctx, c, "/users/{id}", func(r *Request) *Request {
+		return r.SetPathParam("id", "1001")
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, genericTestUser{ID: "1001", Name: "resty"}, user)
+
+	users, _, err := Get[[]genericTestUser](ctx, c, "/users")
+	assertNil(t, err)
+	assertEqual(t, 2, len(users))
+
+	pu, _, err := Get[*genericTestUser](nil, c, "/echo-header", func(r *Request) *Request {
+		return r.SetHeader("X-Name", "header")
+	})
+	assertNil(t, err)
+	assertEqual(t, "header", pu.Name)
+
+	for method, fn := range map[string]func(context.Context, *Client, string, any, ...RequestFunc) (genericTestUser, *Response, error){
+		MethodPost:  Post[genericTestUser],
+		MethodPut:   Put[genericTestUser],
+		MethodPatch: Patch[genericTestUser],
+	} {
+		user, _, err = fn(ctx, c, "/users", &genericTestUser{Name: "new"})
+		assertNil(t, err)
+		assertEqual(t, genericTestUser{ID: method, Name: "new"}, user)
+	}
+
+	// not successful, zero value
+	user, res, err = Delete[genericTestUser](ctx, c, "/users/1002")
+	assertNil(t, err)
+	assertEqual(t, http.StatusNotFound, res.StatusCode())
+	assertEqual(t, genericTestUser{}, user)
+}
+
+func TestGenericExecute(t *testing.T) {
+	baseURL := createGenericTestServer(t)
+	c := dcnl().SetBaseURL(baseURL).SetError(&AuthError{})
+	ctx := context.Background()
+
+	user, apiErr, res, err := Execute[genericTestUser, genericTestError](ctx, c, MethodGet, "/users/1001")
+	assertNil(t, err)
+	assertEqual(t, true, res.IsSuccess())
+	assertEqual(t, "resty", user.Name)
+	assertEqual(t, genericTestError{}, apiErr)
+
+	user, apiErr, res, err = Execute[genericTestUser, genericTestError](ctx, c, MethodGet, "/unknown")
+	assertNil(t, err)
+	assertEqual(t, true, res.IsError())
+	assertEqual(t, genericTestUser{}, user)
+	assertEqual(t, genericTestError{Code: 404, Message: "not found"}, apiErr)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, _, err = Execute[genericTestUser, genericTestError](cctx, c, MethodGet, "/users/1001")
+	assertErrorIs(t, context.Canceled, err)
+}
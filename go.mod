@@ -2,4 +2,8 @@ module github.com/rockcookies/go-resty
 
 go 1.23.0
 
-require golang.org/x/net v0.43.0
+require (
+	github.com/klauspost/compress v1.18.0
+	golang.org/x/net v0.43.0
+	golang.org/x/text v0.28.0
+)
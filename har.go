@@ -0,0 +1,316 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// EnableHARRecording method enables recording of the client traffic in the
+// HAR (HTTP Archive) 1.2 format. The archive is written into the given writer
+// when the recording is disabled or the client is closed; so it can be inspected
+// in the browser devtools or shared with the API vendors for debugging.
+//
+//	f, _ := os.Create("session.har")
+//	defer f.Close()
+//
+//	client.EnableHARRecording(f)
+//	defer client.Close() // writes the archive
+//
+// NOTE:
+//   - It enables the client trace to record the timings, see [Client.EnableTrace];
+//     the trace setting is restored on [Client.DisableHARRecording].
+//   - Request and response bodies are held in memory until the archive is written.
+//
+// See [Client.DisableHARRecording]
+func (c *Client) EnableHARRecording(w io.Writer) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	prevTrace := c.isTrace
+	if c.harRecorder != nil {
+		prevTrace = c.harRecorder.prevTrace
+	}
+	c.harRecorder = &harRecorder{lock: &sync.Mutex{}, w: w, prevTrace: prevTrace}
+	c.isTrace = true
+	return c
+}
+
+// DisableHARRecording method stops the HAR recording and writes the recorded
+// archive into the writer given at [Client.EnableHARRecording].
+func (c *Client) DisableHARRecording() error {
+	c.lock.Lock()
+	hr := c.harRecorder
+	c.harRecorder = nil
+	if hr != nil {
+		c.isTrace = hr.prevTrace
+	}
+	c.lock.Unlock()
+
+	if hr == nil {
+		return nil
+	}
+	return hr.write()
+}
+
+// IsHARRecording method returns true if the HAR recording is enabled;
+// otherwise, it is false.
+func (c *Client) IsHARRecording() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.harRecorder != nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type harRecorder struct {
+	lock      *sync.Mutex
+	w         io.Writer
+	entries   []*harEntry
+	prevTrace bool // the trace setting before the recording
+}
+
+func (hr *harRecorder) record(res *Response) {
+	e := newHAREntry(res)
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+	hr.entries = append(hr.entries, e)
+}
+
+func (hr *harRecorder) write() error {
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+
+	entries := hr.entries
+	if entries == nil {
+		entries = make([]*harEntry, 0)
+	}
+	doc := &harDocument{
+		Log: &harLog{
+			Version: "1.2",
+			Creator: &harCreator{Name: "resty", Version: Version},
+			Entries: entries,
+		},
+	}
+	return encodeJSONEscapeHTMLIndent(hr.w, doc, false, "  ")
+}
+
+func (c *Client) harRecording() *harRecorder {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.harRecorder
+}
+
+type harDocument struct {
+	Log *harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator *harCreator `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *harRequest  `json:"request"`
+	Response        *harResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *harTimings  `json:"timings"`
+	ServerIPAddress string       `json:"serverIPAddress,omitempty"`
+}
+
+type harRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*harCookie    `json:"cookies"`
+	Headers     []*harNameValue `json:"headers"`
+	QueryString []*harNameValue `json:"queryString"`
+	PostData    *harPostData    `json:"postData,omitempty"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int64           `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*harCookie    `json:"cookies"`
+	Headers     []*harNameValue `json:"headers"`
+	Content     *harContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int64           `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+func newHAREntry(res *Response) *harEntry {
+	req := res.Request
+	rawReq := req.RawRequest
+
+	hreq := &harRequest{
+		Method:      rawReq.Method,
+		URL:         rawReq.URL.String(),
+		HTTPVersion: rawReq.Proto,
+		Cookies:     harCookies(rawReq.Cookies()),
+		Headers:     harHeaders(rawReq.Header),
+		QueryString: make([]*harNameValue, 0),
+		HeadersSize: -1,
+		BodySize:    rawReq.ContentLength,
+	}
+	for name, values := range rawReq.URL.Query() {
+		for _, v := range values {
+			hreq.QueryString = append(hreq.QueryString, &harNameValue{Name: name, Value: v})
+		}
+	}
+	if rawReq.GetBody != nil {
+		if body, err := rawReq.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			closeq(body)
+			hreq.PostData = &harPostData{
+				MimeType: rawReq.Header.Get(hdrContentTypeKey),
+				Text:     string(b),
+			}
+			hreq.BodySize = int64(len(b))
+		}
+	}
+
+	hres := &harResponse{
+		Status:      res.StatusCode(),
+		StatusText:  http.StatusText(res.StatusCode()),
+		HTTPVersion: res.Proto(),
+		Cookies:     harCookies(res.Cookies()),
+		Headers:     harHeaders(res.Header()),
+		Content: &harContent{
+			Size:     int64(len(res.bodyBytes)),
+			MimeType: res.Header().Get(hdrContentTypeKey),
+		},
+		RedirectURL: res.Header().Get(hdrLocationKey),
+		HeadersSize: -1,
+		BodySize:    int64(len(res.bodyBytes)),
+	}
+	if utf8.Valid(res.bodyBytes) {
+		hres.Content.Text = string(res.bodyBytes)
+	} else {
+		hres.Content.Text = base64.StdEncoding.EncodeToString(res.bodyBytes)
+		hres.Content.Encoding = "base64"
+	}
+
+	e := &harEntry{
+		StartedDateTime: req.Time.Format(time.RFC3339Nano),
+		Time:            harMillis(res.Duration()),
+		Request:         hreq,
+		Response:        hres,
+		Timings: &harTimings{
+			Blocked: -1,
+			DNS:     -1,
+			Connect: -1,
+			SSL:     -1,
+			Wait:    harMillis(res.Duration()),
+		},
+	}
+
+	if req.IsTrace {
+		ti := req.TraceInfo()
+		if !ti.IsConnReused {
+			e.Timings.DNS = harMillis(ti.DNSLookup)
+			e.Timings.Connect = harMillis(ti.TCPConnTime + ti.TLSHandshake)
+			if ti.TLSHandshake > 0 {
+				e.Timings.SSL = harMillis(ti.TLSHandshake)
+			}
+		}
+		e.Timings.Wait = harMillis(ti.ServerTime)
+		e.Timings.Receive = harMillis(ti.ResponseTime)
+		if host, _, err := net.SplitHostPort(ti.RemoteAddr); err == nil {
+			e.ServerIPAddress = host
+		}
+	}
+
+	return e
+}
+
+func harHeaders(h http.Header) []*harNameValue {
+	result := make([]*harNameValue, 0, len(h))
+	for _, name := range sortHeaderKeys(h) {
+		for _, v := range h[name] {
+			result = append(result, &harNameValue{Name: name, Value: v})
+		}
+	}
+	return result
+}
+
+func harCookies(cookies []*http.Cookie) []*harCookie {
+	result := make([]*harCookie, 0, len(cookies))
+	for _, c := range cookies {
+		hc := &harCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			HTTPOnly: c.HttpOnly,
+			Secure:   c.Secure,
+		}
+		if !c.Expires.IsZero() {
+			hc.Expires = c.Expires.Format(time.RFC3339)
+		}
+		result = append(result, hc)
+	}
+	return result
+}
+
+func harMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
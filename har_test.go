@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestClientHARRecording(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/binary":
+			w.Header().Set(hdrContentTypeKey, "application/octet-stream")
+			_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", HttpOnly: true})
+			w.Header().Set(hdrContentTypeKey, jsonContentType)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}
+	})
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := dcnl().EnableHARRecording(&buf)
+	assertEqual(t, true, c.IsHARRecording())
+	assertEqual(t, true, c.IsTrace())
+
+	_, err := c.R().
+		SetQueryParam("page", "2").
+		SetHeader(hdrContentTypeKey, jsonContentType).
+		SetBody(map[string]string{"name": "resty"}).
+		Post(ts.URL + "/users")
+	assertNil(t, err)
+
+	res, err := c.R().Get(ts.URL + "/binary")
+	assertNil(t, err)
+	assertEqual(t, []byte{0xff, 0xfe, 0x00}, res.Bytes())
+	assertEqual(t, 0, buf.Len())
+
+	assertNil(t, c.DisableHARRecording())
+	assertEqual(t, false, c.IsHARRecording())
+	assertEqual(t, false, c.IsTrace()) // restored
+
+	har := &harDocument{}
+	assertNil(t, json.Unmarshal(buf.Bytes(), har))
+	assertEqual(t, "1.2", har.Log.Version)
+	assertEqual(t, "resty", har.Log.Creator.Name)
+	assertEqual(t, 2, len(har.Log.Entries))
+
+	e := har.Log.Entries[0]
+	assertEqual(t, MethodPost, e.Request.Method)
+	assertEqual(t, ts.URL+"/users?page=2", e.Request.URL)
+	assertEqual(t, "HTTP/1.1", e.Request.HTTPVersion)
+	assertEqual(t, []*harNameValue{{Name: "page", Value: "2"}}, e.Request.QueryString)
+	assertEqual(t, jsonContentType, e.Request.PostData.MimeType)
+	assertEqual(t, `{"name":"resty"}`+"\n", e.Request.PostData.Text)
+	assertEqual(t, http.StatusOK, e.Response.Status)
+	assertEqual(t, "OK", e.Response.StatusText)
+	assertEqual(t, `{"id":1}`, e.Response.Content.Text)
+	assertEqual(t, int64(8), e.Response.Content.Size)
+	assertEqual(t, "session", e.Response.Cookies[0].Name)
+	assertEqual(t, true, e.Response.Cookies[0].HTTPOnly)
+	assertEqual(t, "127.0.0.1", e.ServerIPAddress)
+	assertEqual(t, true, e.Time > 0)
+	assertEqual(t, true, e.Timings.Connect >= 0)
+	assertEqual(t, float64(-1), e.Timings.SSL)
+
+	e = har.Log.Entries[1]
+	assertEqual(t, MethodGet, e.Request.Method)
+	assertNil(t, e.Request.PostData)
+	assertEqual(t, "base64", e.Response.Content.Encoding)
+	assertEqual(t, base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00}), e.Response.Content.Text)
+
+	// not recording anymore
+	_, err = c.R().Get(ts.URL + "/users")
+	assertNil(t, err)
+	assertNil(t, c.DisableHARRecording())
+}
+
+func TestClientHARRecordingRestoresTrace(t *testing.T) {
+	var buf bytes.Buffer
+	c := dcnl().EnableTrace()
+	c.EnableHARRecording(&buf).EnableHARRecording(&buf)
+	assertEqual(t, true, c.IsTrace())
+	assertNil(t, c.DisableHARRecording())
+	assertEqual(t, true, c.IsTrace())
+
+	c.DisableTrace().EnableHARRecording(&buf).EnableHARRecording(&buf)
+	assertEqual(t, true, c.IsTrace())
+	assertNil(t, c.DisableHARRecording())
+	assertEqual(t, false, c.IsTrace())
+}
+
+func TestClientHARRecordingOnClose(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := dcnl().EnableHARRecording(&buf)
+	_, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertNil(t, c.Close())
+
+	har := &harDocument{}
+	assertNil(t, json.Unmarshal(buf.Bytes(), har))
+	assertEqual(t, 1, len(har.Log.Entries))
+	assertEqual(t, "hello", har.Log.Entries[0].Response.Content.Text)
+}
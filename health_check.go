@@ -0,0 +1,163 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HealthCheck struct is used to define the active health check of the load
+// balancer hosts; the unhealthy hosts are ejected from the load balancer and
+// re-admitted once they are healthy again. Please refer to individual struct
+// fields to know the default values.
+//
+// See [WeightedRoundRobin.SetHealthCheck] and [Host].HealthCheck
+type HealthCheck struct {
+	// Path is the request path of the health check, appended to
+	// the host base URL, default value is `/`.
+	Path string
+
+	// Interval is the duration between the checks, default value
+	// is `10` seconds.
+	Interval time.Duration
+
+	// Timeout is the timeout of the check, default value is `5` seconds.
+	Timeout time.Duration
+
+	// HealthyThreshold is the number of consecutive successful checks
+	// to mark the unhealthy host healthy, default value is `2`.
+	HealthyThreshold int
+
+	// UnhealthyThreshold is the number of consecutive failed checks
+	// to mark the healthy host unhealthy, default value is `3`.
+	UnhealthyThreshold int
+
+	// IsHealthy is the function to determine the check result; by default,
+	// the check is successful on the status code `2xx` or `3xx`.
+	IsHealthy func(res *http.Response, err error) bool
+
+	// Client is the HTTP client used to send the checks, e.g., [Client.Client]
+	// to share the TLS configuration; a new [http.Client] is used if nil.
+	Client *http.Client
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+var defaultHealthCheckClient = &http.Client{}
+
+// withDefaults method returns the copy of the health check with the default
+// values applied.
+func (hc *HealthCheck) withDefaults() *HealthCheck {
+	c := *hc
+	if c.Path == "" {
+		c.Path = "/"
+	} else if c.Path[0] != '/' {
+		c.Path = "/" + c.Path
+	}
+	if c.Interval <= 0 {
+		c.Interval = 10 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.HealthyThreshold <= 0 {
+		c.HealthyThreshold = 2
+	}
+	if c.UnhealthyThreshold <= 0 {
+		c.UnhealthyThreshold = 3
+	}
+	if c.IsHealthy == nil {
+		c.IsHealthy = isHealthyResponse
+	}
+	if c.Client == nil {
+		c.Client = defaultHealthCheckClient
+	}
+	return &c
+}
+
+func isHealthyResponse(res *http.Response, err error) bool {
+	return err == nil && res.StatusCode >= 200 && res.StatusCode < 400
+}
+
+// probe method sends the health check to the given base URL.
+func (hc *HealthCheck) probe(stop <-chan struct{}, baseURL string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), hc.Timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+hc.Path, nil)
+	if err != nil {
+		return false
+	}
+	res, err := hc.Client.Do(req)
+	if res != nil {
+		defer closeq(res.Body)
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+	}
+	return hc.IsHealthy(res, err)
+}
+
+// hostHealth struct holds the active health check state of the host.
+type hostHealth struct {
+	unhealthy bool
+	successes int
+	failures  int
+}
+
+// record method records the check result, it returns true if the host
+// health state is changed.
+func (h *hostHealth) record(hc *HealthCheck, healthy bool) bool {
+	if healthy {
+		h.failures = 0
+		h.successes++
+		if h.unhealthy && h.successes >= hc.HealthyThreshold {
+			h.unhealthy = false
+			return true
+		}
+		return false
+	}
+
+	h.successes = 0
+	h.failures++
+	if !h.unhealthy && h.failures >= hc.UnhealthyThreshold {
+		h.unhealthy = true
+		return true
+	}
+	return false
+}
+
+// runHealthCheck function runs the health check of the base URL at the
+// interval until the stop channel is closed; the result is sent to the fn.
+func runHealthCheck(stop <-chan struct{}, hc *HealthCheck, baseURL string, fn func(healthy bool)) {
+	ticker := time.NewTicker(hc.Interval)
+	defer ticker.Stop()
+	for {
+		healthy := hc.probe(stop, baseURL)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		fn(healthy)
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
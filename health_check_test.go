@@ -0,0 +1,163 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func createHealthTestServer(t *testing.T, healthy *atomic.Bool) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" && !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func waitForCondition(t *testing.T, fn func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWeightedRoundRobinHealthCheck(t *testing.T) {
+	var healthy1, healthy2 atomic.Bool
+	healthy1.Store(true)
+	healthy2.Store(true)
+	ts1 := createHealthTestServer(t, &healthy1)
+	ts2 := createHealthTestServer(t, &healthy2)
+
+	wrr, err := NewWeightedRoundRobin(
+		time.Hour,
+		&Host{BaseURL: ts1.URL, Weight: 1},
+		&Host{BaseURL: ts2.URL, Weight: 1},
+	)
+	assertNil(t, err)
+	defer wrr.Close()
+
+	var lock sync.Mutex
+	var transitions []HostState
+	wrr.SetOnStateChange(func(baseURL string, from, to HostState) {
+		lock.Lock()
+		defer lock.Unlock()
+		if baseURL == ts2.URL {
+			transitions = append(transitions, from, to)
+		}
+	})
+	stateChanges := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(transitions) / 2
+	}
+
+	healthy2.Store(false)
+	wrr.SetHealthCheck(&HealthCheck{
+		Path:               "healthz",
+		Interval:           10 * time.Millisecond,
+		HealthyThreshold:   2,
+		UnhealthyThreshold: 2,
+	})
+
+	// unhealthy host is ejected
+	waitForCondition(t, func() bool { return stateChanges() == 1 })
+	for i := 0; i < 4; i++ {
+		baseURL, err := wrr.Next()
+		assertNil(t, err)
+		assertEqual(t, ts1.URL, baseURL)
+	}
+
+	// the recovery does not re-admit the unhealthy host
+	wrr.SetRecoveryDuration(5 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	baseURL, _ := wrr.Next()
+	assertEqual(t, ts1.URL, baseURL)
+
+	// healthy host is re-admitted
+	healthy2.Store(true)
+	waitForCondition(t, func() bool { return stateChanges() == 2 })
+	result := map[string]int{}
+	for i := 0; i < 4; i++ {
+		baseURL, err := wrr.Next()
+		assertNil(t, err)
+		result[baseURL]++
+	}
+	assertEqual(t, map[string]int{ts1.URL: 2, ts2.URL: 2}, result)
+	assertEqual(t, []HostState{HostStateActive, HostStateInActive, HostStateInActive, HostStateActive}, transitions)
+
+	// all hosts are unhealthy
+	healthy1.Store(false)
+	healthy2.Store(false)
+	waitForCondition(t, func() bool {
+		_, err := wrr.Next()
+		return err == ErrNoActiveHost
+	})
+
+	// disabled
+	wrr.SetHealthCheck(nil)
+	_, err = wrr.Next()
+	assertNil(t, err)
+}
+
+func TestHostHealthCheck(t *testing.T) {
+	var healthy atomic.Bool
+	ts := createHealthTestServer(t, &healthy)
+
+	var checks atomic.Int32
+	wrr, err := NewWeightedRoundRobin(
+		time.Hour,
+		&Host{BaseURL: "http://127.0.0.1:1", Weight: 1},
+		&Host{BaseURL: ts.URL, Weight: 1, HealthCheck: &HealthCheck{
+			Path:               "/healthz",
+			Interval:           10 * time.Millisecond,
+			UnhealthyThreshold: 1,
+			IsHealthy: func(res *http.Response, err error) bool {
+				checks.Add(1)
+				return err == nil && res.StatusCode == http.StatusOK
+			},
+		}},
+	)
+	assertNil(t, err)
+
+	waitForCondition(t, func() bool { return checks.Load() > 0 })
+	for i := 0; i < 3; i++ {
+		baseURL, err := wrr.Next()
+		assertNil(t, err)
+		assertEqual(t, "http://127.0.0.1:1", baseURL)
+	}
+
+	// checks are stopped on close
+	assertNil(t, wrr.Close())
+	time.Sleep(20 * time.Millisecond)
+	n := checks.Load()
+	time.Sleep(30 * time.Millisecond)
+	assertEqual(t, n, checks.Load())
+}
+
+func TestHealthCheckDefaults(t *testing.T) {
+	hc := (&HealthCheck{}).withDefaults()
+	assertEqual(t, "/", hc.Path)
+	assertEqual(t, 10*time.Second, hc.Interval)
+	assertEqual(t, 5*time.Second, hc.Timeout)
+	assertEqual(t, 2, hc.HealthyThreshold)
+	assertEqual(t, 3, hc.UnhealthyThreshold)
+	assertEqual(t, true, hc.IsHealthy(&http.Response{StatusCode: http.StatusFound}, nil))
+	assertEqual(t, false, hc.IsHealthy(&http.Response{StatusCode: http.StatusNotFound}, nil))
+	assertEqual(t, false, hc.IsHealthy(nil, http.ErrHandlerTimeout))
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// SetHeaderForHost method sets a single header field and its value for the
+// requests of the given host scope, so the shared client talking to multiple
+// APIs does not leak the headers across them. The host scope can be
+//   - Host name, e.g., `api.example.com`
+//   - Domain with its subdomains, e.g., `*.example.com` or `.example.com`
+//   - IP address, e.g., `10.1.2.3`, or CIDR, e.g., `10.0.0.0/8`
+//   - URL prefix, e.g., `https://api.example.com/v2/`; the scheme and host,
+//     including the port, must be equal, and the path matches on the segment
+//     boundary, i.e., `/v2` matches `/v2/users`, but not `/v20`
+//
+// For example:
+//
+//	client.
+//		SetHeaderForHost("api.foo.com", "X-Api-Version", "2").
+//		SetHeaderForHost("https://api.bar.com/v1/", "X-Api-Key", "bar-key")
+//
+// The header set at the request level takes precedence over the host scope,
+// and the host scope takes precedence over the client level, see
+// [Client.SetHeader]. The host scopes are evaluated in the order they are added.
+func (c *Client) SetHeaderForHost(host, header, value string) *Client {
+	return c.updateHostDefaults(host, func(d *hostDefaults) {
+		d.header.Set(header, value)
+	})
+}
+
+// SetHeadersForHost method sets multiple header fields and their values for the
+// requests of the given host scope, see [Client.SetHeaderForHost].
+//
+//	client.SetHeadersForHost("*.foo.com", map[string]string{
+//		"X-Api-Version": "2",
+//		"X-Tenant":      "acme",
+//	})
+func (c *Client) SetHeadersForHost(host string, headers map[string]string) *Client {
+	return c.updateHostDefaults(host, func(d *hostDefaults) {
+		for h, v := range headers {
+			d.header.Set(h, v)
+		}
+	})
+}
+
+// SetQueryParamForHost method sets a single query parameter and its value for
+// the requests of the given host scope, see [Client.SetHeaderForHost] for the
+// supported host scopes.
+//
+//	client.SetQueryParamForHost("maps.foo.com", "key", "maps-api-key")
+//
+// The query parameter set at the request level takes precedence over the host
+// scope, and the host scope takes precedence over the client level, see
+// [Client.SetQueryParam].
+func (c *Client) SetQueryParamForHost(host, param, value string) *Client {
+	return c.updateHostDefaults(host, func(d *hostDefaults) {
+		d.queryParams.Set(param, value)
+	})
+}
+
+// SetQueryParamsForHost method sets multiple query parameters and their values
+// for the requests of the given host scope, see [Client.SetQueryParamForHost].
+func (c *Client) SetQueryParamsForHost(host string, params map[string]string) *Client {
+	return c.updateHostDefaults(host, func(d *hostDefaults) {
+		for p, v := range params {
+			d.queryParams.Set(p, v)
+		}
+	})
+}
+
+// RemoveHostDefaults method removes the headers and query parameters set for
+// the given host scopes, see [Client.SetHeaderForHost].
+func (c *Client) RemoveHostDefaults(hosts ...string) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.hostDefaults = slices.DeleteFunc(slices.Clone(c.hostDefaults), func(d *hostDefaults) bool {
+		return slices.ContainsFunc(hosts, func(h string) bool { return normalizeHostScope(h) == d.scope })
+	})
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type hostDefaults struct {
+	hostPattern
+	scope       string
+	urlPrefix   *url.URL
+	header      http.Header
+	queryParams url.Values
+}
+
+func (d *hostDefaults) match(u *url.URL) bool {
+	if d.urlPrefix != nil {
+		return matchURLPrefix(d.urlPrefix, u)
+	}
+	return d.hostPattern.match(strings.ToLower(u.Hostname()))
+}
+
+// matchURLPrefix function returns true if the given URL has the same scheme
+// and host, including the port, as the prefix, and its path is the prefix path
+// or below it on the segment boundary, i.e., `/v1` matches `/v1/users`, but not
+// `/v10`.
+func matchURLPrefix(prefix, u *url.URL) bool {
+	if !strings.EqualFold(prefix.Scheme, u.Scheme) || !strings.EqualFold(prefix.Host, u.Host) {
+		return false
+	}
+	p := strings.TrimSuffix(prefix.Path, "/")
+	if len(p) == 0 {
+		return true
+	}
+	return u.Path == p || strings.HasPrefix(u.Path, p+"/")
+}
+
+func parseURLPrefix(scope string) (*url.URL, error) {
+	u, err := url.Parse(scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Scheme) == 0 || len(u.Host) == 0 || u.User != nil {
+		return nil, fmt.Errorf("resty: invalid host scope URL prefix '%s'", scope)
+	}
+	return u, nil
+}
+
+func normalizeHostScope(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		return host
+	}
+	return strings.ToLower(host)
+}
+
+// updateHostDefaults method updates the copy of the host scope defaults, so
+// the requests in-flight are not affected.
+func (c *Client) updateHostDefaults(host string, fn func(*hostDefaults)) *Client {
+	scope := normalizeHostScope(host)
+	d := &hostDefaults{scope: scope}
+	if strings.Contains(scope, "://") {
+		prefix, err := parseURLPrefix(scope)
+		if err != nil {
+			c.Logger().Errorf("%v", err)
+			return c
+		}
+		d.urlPrefix = prefix
+	} else {
+		pattern, err := parseHostPattern(scope)
+		if err != nil {
+			c.Logger().Errorf("%v", err)
+			return c
+		}
+		d.hostPattern = pattern
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defaults := slices.Clone(c.hostDefaults)
+	idx := slices.IndexFunc(defaults, func(e *hostDefaults) bool { return e.scope == scope })
+	if idx == -1 {
+		d.header, d.queryParams = http.Header{}, url.Values{}
+		defaults = append(defaults, d)
+		idx = len(defaults) - 1
+	} else {
+		e := *defaults[idx]
+		e.header, e.queryParams = e.header.Clone(), cloneURLValues(e.queryParams)
+		defaults[idx] = &e
+	}
+	fn(defaults[idx])
+	c.hostDefaults = defaults
+	return c
+}
+
+// matchingHostDefaults method returns the host scope defaults for the given
+// request URL, in the order they are added.
+func (c *Client) matchingHostDefaults(u *url.URL) []*hostDefaults {
+	c.lock.RLock()
+	defaults := c.hostDefaults
+	c.lock.RUnlock()
+
+	var matched []*hostDefaults
+	for _, d := range defaults {
+		if d.match(u) {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClientHostDefaults(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Api-Version") + "|" + r.Header.Get("X-Api-Key") + "|" + r.URL.RawQuery))
+	})
+	defer ts.Close()
+
+	c, lb := dcldb()
+	c.SetHeader("X-Api-Version", "1").
+		SetQueryParam("key", "client").
+		SetHeaderForHost("127.0.0.1", "X-Api-Version", "2").
+		SetHeadersForHost(ts.URL+"/v2/", map[string]string{"X-Api-Key": "v2-key", "X-Api-Version": "3"}).
+		SetQueryParamForHost("127.0.0.0/8", "key", "host").
+		SetQueryParamsForHost("*.example.com", map[string]string{"key": "example"})
+	defer c.Close()
+
+	tests := []struct {
+		name   string
+		req    *Request
+		url    string
+		expect string
+	}{
+		{"host", c.R(), ts.URL + "/v1/users", "2||key=host"},
+		{"url prefix and host in order", c.R(), ts.URL + "/v2/users", "2|v2-key|key=host"},
+		{"request precedence", c.R().SetHeader("X-Api-Version", "9").SetQueryParam("key", "req"),
+			ts.URL + "/v2/users", "9|v2-key|key=req"},
+		{"not matched", c.R(), strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/v2/users", "1||key=client"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.req.Get(tc.url)
+			assertError(t, err)
+			assertEqual(t, tc.expect, res.String())
+		})
+	}
+
+	cc := c.Clone(context.Background()).
+		SetHeaderForHost("127.0.0.1", "X-Api-Version", "cloned").
+		RemoveHostDefaults(ts.URL+"/v2/", "127.0.0.0/8")
+	res, err := cc.R().Get(ts.URL + "/v2/users")
+	assertError(t, err)
+	assertEqual(t, "cloned||key=client", res.String())
+
+	// the original client is not affected
+	res, err = c.R().Get(ts.URL + "/v2/users")
+	assertError(t, err)
+	assertEqual(t, "2|v2-key|key=host", res.String())
+
+	c.SetHeaderForHost("10.0.0.0/99", "X-Api-Version", "4")
+	assertEqual(t, true, strings.Contains(lb.String(), "invalid CIDR address"))
+}
+
+func TestClientHostDefaultsQueryParamOnRetry(t *testing.T) {
+	ts1 := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer ts1.Close()
+
+	ts2 := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RawQuery))
+	})
+	defer ts2.Close()
+
+	rr, err := NewRoundRobin(ts1.URL, ts2.URL)
+	assertError(t, err)
+
+	c := dcnl().
+		SetLoadBalancer(rr).
+		SetRetryCount(1).
+		SetQueryParamForHost(ts1.URL, "key", "ts1")
+	defer c.Close()
+
+	res, err := c.R().SetQueryParam("page", "1").Get("/")
+	assertError(t, err)
+	assertEqual(t, 2, res.Request.Attempt)
+	assertEqual(t, "page=1", res.String())
+	assertEqual(t, "", res.Request.QueryParams.Get("key"))
+}
+
+func TestClientHostDefaultsURLPrefix(t *testing.T) {
+	c, lb := dcldb()
+	c.SetHeaderForHost("https://api.foo.com", "X-Api-Key", "foo-key").
+		SetHeaderForHost("https://api.bar.com:8443/v1", "X-Api-Key", "bar-key")
+	defer c.Close()
+
+	tests := []struct {
+		url    string
+		expect bool
+	}{
+		{"https://api.foo.com", true},
+		{"https://API.foo.com/users", true},
+		{"https://api.foo.com.evil.net/users", false},
+		{"https://api.foo.com@attacker.net/users", false},
+		{"https://api.foo.com:8443/users", false},
+		{"http://api.foo.com/users", false},
+		{"https://api.bar.com:8443/v1", true},
+		{"https://api.bar.com:8443/v1/users", true},
+		{"https://api.bar.com:8443/v10", false},
+		{"https://api.bar.com:8443/v1x/users", false},
+		{"https://api.bar.com/v1/users", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			assertError(t, err)
+			assertEqual(t, tc.expect, len(c.matchingHostDefaults(u)) > 0)
+		})
+	}
+
+	c.SetHeaderForHost("https://user@api.baz.com", "X-Api-Key", "baz-key")
+	assertEqual(t, true, strings.Contains(lb.String(), "invalid host scope URL prefix"))
+}
@@ -0,0 +1,24 @@
+module github.com/rockcookies/go-resty/http3
+
+go 1.23.0
+
+require (
+	github.com/quic-go/quic-go v0.54.1
+	github.com/rockcookies/go-resty v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/quic-go/qpack v0.5.1 // indirect
+	go.uber.org/mock v0.5.0 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/mod v0.26.0 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	golang.org/x/tools v0.35.0 // indirect
+)
+
+// the root module is not tagged yet; it is resolved from the repository
+replace github.com/rockcookies/go-resty => ../
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+// Package http3 provides the HTTP/3 (QUIC) transport for the Resty client
+// using [quic-go]. It is a separate module, so the Resty module does not
+// depend on the QUIC implementation.
+//
+//	client := resty.New()
+//	defer client.Close()
+//
+//	http3.Enable(client, nil)
+//
+//	res, err := client.R().Get("https://example.com")
+//	fmt.Println(res.Proto()) // HTTP/3.0, once the origin advertises it
+//
+// By default, the origin is upgraded to HTTP/3 once it is advertised through
+// the `Alt-Svc` response header ([RFC 7838]); until then, and on the HTTP/3
+// failure, the requests are sent using the client's existing transport,
+// i.e., HTTP/2 or HTTP/1.1.
+//
+// [quic-go]: https://github.com/quic-go/quic-go
+// [RFC 7838]: https://datatracker.ietf.org/doc/html/rfc7838
+package http3
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/rockcookies/go-resty"
+)
+
+// Options struct is used to configure the HTTP/3 transport, see [Enable].
+type Options struct {
+	// ForceHTTP3 sends the HTTPS requests over HTTP/3 without waiting for the
+	// `Alt-Svc` advertisement; it falls back on failure.
+	ForceHTTP3 bool
+
+	// BrokenDuration is the duration the origin is not tried over HTTP/3
+	// after the failure. Default value is `5` minutes.
+	BrokenDuration time.Duration
+
+	// QUICConfig is the QUIC configuration; quic-go defaults are used if nil.
+	QUICConfig *quic.Config
+
+	// AltSvcCache is the cache of the alternative services advertised by the
+	// origins; a new one is created if nil. [Enable] uses the client's cache,
+	// see [resty.Client.SetAltSvcCache].
+	AltSvcCache *resty.AltSvcCache
+}
+
+// New function creates a new Resty client with the HTTP/3 transport enabled.
+//
+//	client := http3.New(&http3.Options{ForceHTTP3: true})
+func New(opts *Options) *resty.Client {
+	c := resty.New()
+	Enable(c, opts)
+	return c
+}
+
+// Enable function wires the HTTP/3 transport into the given client and returns
+// it; the client's existing transport is used as the fallback, and the HTTP/3
+// transport is closed on [resty.Client.Close].
+//
+//	transport := http3.Enable(client, &http3.Options{
+//		BrokenDuration: time.Minute,
+//	})
+//
+// The TLS client configuration, the alternative services cache, the trace,
+// i.e., [resty.Request.EnableTrace], and the HTTP/2 fallback are shared with
+// the client; the requests through the
+// proxy are sent using the fallback transport, since HTTP/3 is not proxied.
+//
+// It enables [resty.FeatureHTTP3] on the client; disable the feature to send
+// the requests using the fallback transport, e.g., to roll back HTTP/3 at
+// runtime without re-creating the client.
+//
+//	client.DisableFeature(resty.FeatureHTTP3)
+//
+// NOTE: Configure the client's transport, e.g., [resty.Client.SetProxy], before
+// enabling HTTP/3; the TLS client configuration can be changed later.
+func Enable(c *resty.Client, opts *Options) *Transport {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.AltSvcCache == nil {
+		o := *opts
+		o.AltSvcCache = c.AltSvcCache()
+		opts = &o
+	}
+	t := NewTransport(c.Transport(), opts)
+	t.client = c
+	c.EnableFeature(resty.FeatureHTTP3)
+	c.SetTransport(t)
+	c.OnClose(func() { _ = t.Close() })
+	return t
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package http3
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	qhttp3 "github.com/quic-go/quic-go/http3"
+	"github.com/rockcookies/go-resty"
+)
+
+// createTestServers function starts the HTTPS server and, if altSvc is true,
+// the HTTP/3 server advertised by it.
+func createTestServers(t *testing.T, altSvc bool) (*httptest.Server, *x509.CertPool) {
+	var h3Port int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h3Port > 0 {
+			w.Header().Set("Alt-Svc", `h3=":`+strconv.Itoa(h3Port)+`"; ma=60`)
+		}
+		b, _ := io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, r.Proto+":"+string(b))
+	})
+
+	ts := httptest.NewUnstartedServer(handler)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	if altSvc {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		h3Port = conn.LocalAddr().(*net.UDPAddr).Port
+		srv := &qhttp3.Server{
+			Handler: handler,
+			TLSConfig: qhttp3.ConfigureTLSConfig(&tls.Config{
+				Certificates: ts.TLS.Certificates,
+			}),
+		}
+		go func() { _ = srv.Serve(conn) }()
+		t.Cleanup(func() {
+			_ = srv.Close()
+			_ = conn.Close()
+		})
+	}
+	return ts, pool
+}
+
+func TestEnableAltSvcUpgrade(t *testing.T) {
+	ts, pool := createTestServers(t, true)
+	c := resty.New().SetTLSClientConfig(&tls.Config{RootCAs: pool})
+	defer c.Close()
+
+	tr := Enable(c, nil)
+	assertEqual(t, tr, c.Transport())
+	assertEqual(t, pool, c.TLSClientConfig().RootCAs)
+	assertEqual(t, c.AltSvcCache(), tr.altSvc)
+
+	// not advertised yet
+	res, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "HTTP/2.0:", res.String())
+
+	res, err = c.R().EnableTrace().SetBody("resty").Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, 3, res.RawResponse.ProtoMajor)
+	assertEqual(t, "HTTP/3.0:resty", res.String())
+	assertEqual(t, true, strings.HasPrefix(res.Request.TraceInfo().RemoteAddr, "127.0.0.1:"))
+
+	// feature flag rolls back to the fallback transport
+	assertEqual(t, true, c.IsFeatureEnabled(resty.FeatureHTTP3))
+	c.DisableFeature(resty.FeatureHTTP3)
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "HTTP/2.0:", res.String())
+	c.EnableFeature(resty.FeatureHTTP3)
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "HTTP/3.0:", res.String())
+
+	// TLS client config is shared with HTTP/3
+	c.SetTLSClientConfig(&tls.Config{})
+	tr.CloseIdleConnections()
+	_, err = c.R().Get(ts.URL)
+	assertNotNil(t, err)
+}
+
+func TestForceHTTP3Fallback(t *testing.T) {
+	ts, pool := createTestServers(t, false)
+	c := New(&Options{
+		ForceHTTP3:     true,
+		BrokenDuration: time.Minute,
+		QUICConfig:     &quic.Config{HandshakeIdleTimeout: 300 * time.Millisecond},
+	}).SetTLSClientConfig(&tls.Config{RootCAs: pool})
+	defer c.Close()
+
+	// HTTP/3 is not reachable
+	res, err := c.R().SetBody("resty").Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "HTTP/2.0:resty", res.String())
+
+	tr := c.Transport().(*Transport)
+	assertEqual(t, 1, len(tr.broken))
+
+	// origin is marked broken, so not tried over HTTP/3
+	start := time.Now()
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "HTTP/2.0:", res.String())
+	assertEqual(t, true, time.Since(start) < 300*time.Millisecond)
+
+	// plain HTTP is not upgraded
+	assertEqual(t, false, tr.useHTTP3(&http.Request{URL: mustParseURL("http://127.0.0.1")}, ""))
+}
+
+func TestProcessAltSvc(t *testing.T) {
+	tr := NewTransport(nil, nil)
+	origin := "example.com:443"
+	req := &http.Request{URL: mustParseURL("https://example.com"), Header: http.Header{}}
+	assertEqual(t, origin, originAddr(req))
+	assertEqual(t, false, tr.useHTTP3(req, origin))
+
+	tr.processAltSvc(origin, &http.Response{Header: http.Header{"Alt-Svc": {`h2=":443", h3=":8443"`}}})
+	addr, _, _ := tr.altSvc.Lookup(origin, qhttp3.NextProtoH3)
+	assertEqual(t, "example.com:8443", addr)
+	assertEqual(t, true, tr.useHTTP3(req, origin))
+
+	tr.processAltSvc(origin, &http.Response{Header: http.Header{"Alt-Svc": {"clear"}}})
+	assertEqual(t, false, tr.useHTTP3(req, origin))
+
+	tr.processAltSvc(origin, &http.Response{Header: http.Header{"Alt-Svc": {`h3=":8443"`}}})
+	tr.processAltSvc(origin, &http.Response{Header: http.Header{"Alt-Svc": {`h3=":8443"; ma=0`}}})
+	assertEqual(t, false, tr.useHTTP3(req, origin))
+
+	// HTTP/3 failure removes the alternative
+	tr.processAltSvc(origin, &http.Response{Header: http.Header{"Alt-Svc": {`h3=":8443"`}}})
+	tr.markBroken(origin)
+	_, _, found := tr.altSvc.Lookup(origin, qhttp3.NextProtoH3)
+	assertEqual(t, false, found)
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func assertNil(t *testing.T, v any) {
+	t.Helper()
+	if !isNil(v) {
+		t.Errorf("[%v] was expected to be nil", v)
+	}
+}
+
+func assertNotNil(t *testing.T, v any) {
+	t.Helper()
+	if isNil(v) {
+		t.Errorf("[%v] was expected to be non-nil", v)
+	}
+}
+
+func assertEqual(t *testing.T, e, g any) {
+	t.Helper()
+	if !reflect.DeepEqual(e, g) {
+		t.Errorf("Expected [%v], got [%v]", e, g)
+	}
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package http3
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	qhttp3 "github.com/quic-go/quic-go/http3"
+	"github.com/rockcookies/go-resty"
+)
+
+var _ resty.TLSClientConfiger = (*Transport)(nil)
+
+// Transport is the [http.RoundTripper] that sends the HTTPS requests over
+// HTTP/3 to the origins that advertise it, or all of them with
+// [Options.ForceHTTP3], and the rest using the fallback transport.
+type Transport struct {
+	fallback       http.RoundTripper
+	h3             *qhttp3.Transport
+	client         *resty.Client
+	force          bool
+	brokenDuration time.Duration
+
+	lock      sync.RWMutex
+	tlsConfig *tls.Config
+	altSvc    *resty.AltSvcCache
+	broken    map[string]time.Time
+}
+
+// NewTransport function creates the HTTP/3 transport with the given fallback
+// transport; [http.DefaultTransport] is used if the fallback is nil.
+func NewTransport(fallback http.RoundTripper, opts *Options) *Transport {
+	if fallback == nil {
+		fallback = http.DefaultTransport
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	t := &Transport{
+		fallback:       fallback,
+		force:          opts.ForceHTTP3,
+		brokenDuration: opts.BrokenDuration,
+		altSvc:         opts.AltSvcCache,
+		broken:         make(map[string]time.Time),
+	}
+	if t.brokenDuration <= 0 {
+		t.brokenDuration = 5 * time.Minute
+	}
+	if t.altSvc == nil {
+		t.altSvc = resty.NewAltSvcCache()
+	}
+	if _, ok := fallback.(*http.Transport); !ok {
+		if _, ok := fallback.(resty.TLSClientConfiger); !ok {
+			t.tlsConfig = &tls.Config{}
+		}
+	}
+	t.h3 = &qhttp3.Transport{
+		QUICConfig: opts.QUICConfig,
+		Dial:       t.dial,
+	}
+	return t
+}
+
+// Fallback method returns the fallback transport.
+func (t *Transport) Fallback() http.RoundTripper {
+	return t.fallback
+}
+
+// TLSClientConfig method returns the TLS client configuration shared with
+// the fallback transport.
+func (t *Transport) TLSClientConfig() *tls.Config {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	switch ft := t.fallback.(type) {
+	case *http.Transport:
+		return ft.TLSClientConfig
+	case resty.TLSClientConfiger:
+		return ft.TLSClientConfig()
+	}
+	return t.tlsConfig
+}
+
+// SetTLSClientConfig method sets the TLS client configuration for both HTTP/3
+// and the fallback transport; it applies to the new connections.
+func (t *Transport) SetTLSClientConfig(tlsConfig *tls.Config) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	switch ft := t.fallback.(type) {
+	case *http.Transport:
+		ft.TLSClientConfig = tlsConfig
+		return nil
+	case resty.TLSClientConfiger:
+		return ft.SetTLSClientConfig(tlsConfig)
+	}
+	t.tlsConfig = tlsConfig
+	return nil
+}
+
+// RoundTrip method implements the [http.RoundTripper] interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	origin := originAddr(req)
+	if t.useHTTP3(req, origin) {
+		res, err := t.h3.RoundTrip(req)
+		if err == nil {
+			t.processAltSvc(origin, res)
+			return res, nil
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+
+		// HTTP/3 is not reachable, e.g., UDP is blocked; fall back
+		t.markBroken(origin)
+		rr, ok := rewindRequest(req)
+		if !ok {
+			return nil, err
+		}
+		req = rr
+	}
+
+	res, err := t.fallback.RoundTrip(req)
+	if err == nil && origin != "" {
+		t.processAltSvc(origin, res)
+	}
+	return res, err
+}
+
+// CloseIdleConnections method closes the idle connections of both HTTP/3 and
+// the fallback transport.
+func (t *Transport) CloseIdleConnections() {
+	t.h3.CloseIdleConnections()
+	if ci, ok := t.fallback.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+// Close method closes the HTTP/3 connections and the idle connections of
+// the fallback transport.
+func (t *Transport) Close() error {
+	if ci, ok := t.fallback.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+	return t.h3.Close()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//_______________________________________________________________________
+
+func (t *Transport) useHTTP3(req *http.Request, origin string) bool {
+	if origin == "" || t.isProxied(req) {
+		return false
+	}
+	if t.client != nil && !t.client.IsFeatureEnabled(resty.FeatureHTTP3) {
+		return false
+	}
+
+	now := time.Now()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if until, found := t.broken[origin]; found && now.Before(until) {
+		return false
+	}
+	if _, _, found := t.altSvc.Lookup(origin, qhttp3.NextProtoH3); found {
+		return true
+	}
+	return t.force
+}
+
+func (t *Transport) isProxied(req *http.Request) bool {
+	ft, ok := t.fallback.(*http.Transport)
+	if !ok || ft.Proxy == nil {
+		return false
+	}
+	proxyURL, err := ft.Proxy(req)
+	return err != nil || proxyURL != nil
+}
+
+func (t *Transport) markBroken(origin string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.broken[origin] = time.Now().Add(t.brokenDuration)
+	t.altSvc.Remove(origin, qhttp3.NextProtoH3)
+}
+
+func (t *Transport) processAltSvc(origin string, res *http.Response) {
+	t.altSvc.Update(origin, res.Header.Values("Alt-Svc")...)
+}
+
+// dial method dials the QUIC connection to the alternative service of the
+// origin, if advertised; the TLS server name remains the origin host.
+func (t *Transport) dial(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+	if alt, _, found := t.altSvc.Lookup(addr, qhttp3.NextProtoH3); found {
+		addr = alt
+	}
+
+	tc := t.TLSClientConfig()
+	if tc == nil {
+		tc = &tls.Config{}
+	} else {
+		tc = tc.Clone()
+	}
+	if tc.ServerName == "" {
+		tc.ServerName = tlsCfg.ServerName
+	}
+	tc.NextProtos = tlsCfg.NextProtos
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if trace != nil && trace.DNSDone != nil {
+		trace.DNSDone(httptrace.DNSDoneInfo{Addrs: ips, Err: err})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raddr := net.JoinHostPort(ips[0].String(), port)
+	if trace != nil && trace.ConnectStart != nil {
+		trace.ConnectStart("udp", raddr)
+	}
+	if trace != nil && trace.TLSHandshakeStart != nil {
+		trace.TLSHandshakeStart()
+	}
+	conn, err := quic.DialAddrEarly(ctx, raddr, tc, cfg)
+	if trace != nil && trace.TLSHandshakeDone != nil {
+		var state tls.ConnectionState
+		if conn != nil {
+			state = conn.ConnectionState().TLS
+		}
+		trace.TLSHandshakeDone(state, err)
+	}
+	if trace != nil && trace.ConnectDone != nil {
+		trace.ConnectDone("udp", raddr, err)
+	}
+	return conn, err
+}
+
+// originAddr function returns the origin host:port of the HTTPS request;
+// otherwise, it is empty.
+func originAddr(req *http.Request) string {
+	if !strings.EqualFold(req.URL.Scheme, "https") {
+		return ""
+	}
+	host, port := req.URL.Hostname(), req.URL.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// rewindRequest function returns the request with the body rewound for the
+// fallback; it is false if the body cannot be rewound, e.g., stream.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	rr := req.Clone(req.Context())
+	rr.Body = body
+	return rr, true
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"reflect"
+)
+
+// The status code ranges, i.e., classes, to register the error type with
+// [Client.SetErrorFor] and [Request.SetErrorFor].
+const (
+	StatusRange4xx = 4
+	StatusRange5xx = 5
+)
+
+// HTTPError is the error of the HTTP error response, i.e., the status code
+// is greater than 399; it holds the decoded error response body.
+//
+//	var httpErr *resty.HTTPError
+//	if errors.As(err, &httpErr) {
+//		fmt.Println(httpErr.StatusCode, httpErr.Body.(*APIError).Message)
+//	}
+//
+// If the decoded body implements the `error` interface, it is unwrapped, i.e.,
+// [errors.As] works with the body type as well.
+//
+// See [Request.SetErrorFor], [Client.SetReturnErrorOnHTTPError]
+type HTTPError struct {
+	StatusCode int
+	Status     string
+
+	// Body is the decoded error response body, i.e., [Response.Error]
+	Body any
+}
+
+func (e *HTTPError) Error() string {
+	if err, ok := e.Body.(error); ok {
+		return "resty: " + e.Status + ": " + err.Error()
+	}
+	return "resty: " + e.Status
+}
+
+func (e *HTTPError) Unwrap() error {
+	if err, ok := e.Body.(error); ok {
+		return err
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func newHTTPError(res *Response) *ResponseError {
+	return &ResponseError{
+		Response: res,
+		Err: &HTTPError{
+			StatusCode: res.StatusCode(),
+			Status:     res.Status(),
+			Body:       res.Error(),
+		},
+	}
+}
+
+func isValidErrorStatus(status int) bool {
+	return status == StatusRange4xx || status == StatusRange5xx ||
+		(status > 399 && status < 600)
+}
+
+// errorTypeFor method returns the registered error type of the status code;
+// the exact status code takes precedence over the status code range, and the
+// request level takes precedence over the client level.
+func (r *Request) errorTypeFor(code int) (reflect.Type, bool) {
+	cts := r.client.errorTypesFor()
+	for _, k := range []int{code, code / 100} {
+		if t, found := r.errorTypes[k]; found {
+			return t, true
+		}
+		if t, found := cts[k]; found {
+			return t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type httpErrorTestNotFound struct {
+	Resource string `json:"resource"`
+}
+
+func (e *httpErrorTestNotFound) Error() string {
+	return e.Resource + " not found"
+}
+
+type httpErrorTestAPIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func createHTTPErrorTestServer(t *testing.T) string {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		w.WriteHeader(code)
+		switch {
+		case code == http.StatusNotFound:
+			_, _ = w.Write([]byte(`{"resource":"user"}`))
+		case code >= 400:
+			_, _ = w.Write([]byte(`{"code":"E` + strconv.Itoa(code) + `","message":"failed"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestSetErrorFor(t *testing.T) {
+	baseURL := createHTTPErrorTestServer(t)
+	c := dcnl().
+		SetBaseURL(baseURL).
+		SetError(&AuthError{}).
+		SetErrorFor(StatusRange4xx, &httpErrorTestAPIError{})
+
+	// exact status code at the request level
+	res, err := c.R().
+		SetErrorFor(http.StatusNotFound, httpErrorTestNotFound{}).
+		Get("/404")
+	var notFound *httpErrorTestNotFound
+	assertEqual(t, true, errors.As(err, &notFound))
+	assertEqual(t, "user", notFound.Resource)
+	assertEqual(t, "resty: 404 Not Found: user not found", err.Error())
+	assertEqual(t, notFound, res.Error())
+
+	var resErr *ResponseError
+	assertEqual(t, true, errors.As(err, &resErr))
+	assertEqual(t, res, resErr.Response)
+
+	// range at the client level
+	res, err = c.R().Get("/409")
+	var httpErr *HTTPError
+	assertEqual(t, true, errors.As(err, &httpErr))
+	assertEqual(t, http.StatusConflict, httpErr.StatusCode)
+	assertEqual(t, "resty: 409 Conflict", httpErr.Error())
+	apiErr := httpErr.Body.(*httpErrorTestAPIError)
+	assertEqual(t, "E409", apiErr.Code)
+	assertEqual(t, apiErr, res.Error())
+
+	// no match, falls back to the SetError
+	res, err = c.R().Get("/500")
+	assertNil(t, err)
+	assertEqual(t, true, res.IsError())
+	_, ok := res.Error().(*AuthError)
+	assertEqual(t, true, ok)
+
+	c.SetErrorFor(StatusRange5xx, &httpErrorTestAPIError{})
+	_, err = c.R().Get("/503")
+	assertEqual(t, true, errors.As(err, &httpErr))
+	assertEqual(t, "E503", httpErr.Body.(*httpErrorTestAPIError).Code)
+
+	// success is not affected
+	res, err = c.R().Get("/200")
+	assertNil(t, err)
+	assertEqual(t, nil, res.Error())
+}
+
+func TestSetErrorForInvalidStatus(t *testing.T) {
+	c, lb := dcldb()
+	c.SetErrorFor(200, &httpErrorTestAPIError{})
+	c.R().SetErrorFor(3, &httpErrorTestAPIError{})
+	assertEqual(t, 0, len(c.errorTypesFor()))
+	assertEqual(t, true, strings.Contains(lb.String(), "invalid error status 200"))
+	assertEqual(t, true, strings.Contains(lb.String(), "invalid error status 3"))
+}
+
+func TestSetErrorForErrorHooks(t *testing.T) {
+	baseURL := createHTTPErrorTestServer(t)
+	var hookErr error
+	c := dcnl().
+		SetBaseURL(baseURL).
+		SetErrorFor(http.StatusBadRequest, &httpErrorTestAPIError{}).
+		OnError(func(r *Request, err error) { hookErr = err })
+
+	_, err := c.R().Get("/400")
+	assertNotNil(t, err)
+
+	// not wrapped twice
+	resErr, ok := hookErr.(*ResponseError)
+	assertEqual(t, true, ok)
+	_, ok = resErr.Err.(*HTTPError)
+	assertEqual(t, true, ok)
+}
+
+func TestSetReturnErrorOnHTTPError(t *testing.T) {
+	baseURL := createHTTPErrorTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+	assertEqual(t, false, c.IsReturnErrorOnHTTPError())
+
+	// disabled, by default
+	res, err := c.R().Get("/400")
+	assertNil(t, err)
+	assertEqual(t, true, res.IsError())
+
+	c.SetReturnErrorOnHTTPError(true).SetError(&httpErrorTestAPIError{})
+	assertEqual(t, true, c.IsReturnErrorOnHTTPError())
+
+	res, err = c.R().Get("/400")
+	var httpErr *HTTPError
+	assertEqual(t, true, errors.As(err, &httpErr))
+	assertEqual(t, http.StatusBadRequest, httpErr.StatusCode)
+	assertEqual(t, "400 Bad Request", httpErr.Status)
+	assertEqual(t, "E400", httpErr.Body.(*httpErrorTestAPIError).Code)
+	assertEqual(t, res, err.(*ResponseError).Response)
+
+	// not decoded body
+	_, err = c.R().SetDoNotParseResponse(true).Get("/502")
+	assertEqual(t, true, errors.As(err, &httpErr))
+	assertEqual(t, http.StatusBadGateway, httpErr.StatusCode)
+	assertNil(t, httpErr.Body)
+
+	res, err = c.R().Get("/201")
+	assertNil(t, err)
+	assertEqual(t, http.StatusCreated, res.StatusCode())
+
+	// request level overrides the client
+	res, err = c.R().SetReturnErrorOnHTTPError(false).Get("/404")
+	assertNil(t, err)
+	assertEqual(t, http.StatusNotFound, res.StatusCode())
+}
@@ -6,10 +6,12 @@
 package resty
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -23,12 +25,96 @@ type LoadBalancer interface {
 	Close() error
 }
 
+// RequestLoadBalancer is the interface that extends the [LoadBalancer] to select
+// the Base URL based on the request, e.g., [ConsistentHash]; the "NextRequest"
+// is used instead of the "Next" for the requests.
+type RequestLoadBalancer interface {
+	LoadBalancer
+	NextRequest(*Request) (string, error)
+}
+
+// StatsLoadBalancer is the interface that extends the [LoadBalancer] to expose
+// the per-target statistics, e.g., for the metrics and the introspection
+// endpoints; all the Resty load balancers implement it.
+//
+//	if lb, ok := client.LoadBalancer().(resty.StatsLoadBalancer); ok {
+//		for _, s := range lb.Stats() {
+//			fmt.Println(s.BaseURL, s.State, s.Requests, s.Errors, s.InFlight, s.LastError)
+//		}
+//	}
+type StatsLoadBalancer interface {
+	LoadBalancer
+	Stats() []TargetStats
+}
+
+// TargetStats struct holds the statistics of the load balancer target, the
+// counters are of the request attempts. The state transitions are sent to the
+// [HostStateChangeFunc] callback of the load balancer, e.g.,
+// [WeightedRoundRobin.SetOnStateChange], to alert on them.
+type TargetStats struct {
+	BaseURL string
+	State   HostState
+
+	// Requests is the count of the completed requests
+	Requests int64
+
+	// Errors is the count of the failed requests
+	Errors int64
+
+	// InFlight is the count of the requests in progress, i.e., the open connections
+	InFlight int64
+
+	// LastError is the error of the latest failed request, and LastErrorAt
+	// is its time
+	LastError   error
+	LastErrorAt time.Time
+}
+
 // RequestFeedback struct is used to send the request feedback to load balancing
-// algorithm
+// algorithm, it is sent for every request attempt
 type RequestFeedback struct {
 	BaseURL string
 	Success bool
 	Attempt int
+
+	// Latency is the duration of the request attempt until
+	// the response is received, it is zero if not known.
+	Latency time.Duration
+
+	// Err is the error of the request attempt, if any; it is
+	// the [HTTPError] for the server error response.
+	Err error
+}
+
+// SetHost method pins the request to the given target Base URL, the load
+// balancer is skipped and it does not receive the request feedback. It is
+// useful to debug or warm up the specific backend.
+//
+//	client.R().SetHost("https://api2.example.com").Get("/v1/status")
+//
+// NOTE: It is applicable to the relative request URL only, and it is not the
+// `Host` header of the request.
+func (r *Request) SetHost(target string) *Request {
+	r.pinnedBaseURL = target
+	return r
+}
+
+// SetLoadBalancerKey method sets the load balancing key of the request, e.g.,
+// the tenant ID. It influences the selection of the load balancers that
+// support it, such as [ConsistentHash]; the others ignore it.
+//
+//	client.R().SetLoadBalancerKey(tenantID).Get("/v1/orders")
+//
+// See [RequestLoadBalancer]
+func (r *Request) SetLoadBalancerKey(k string) *Request {
+	r.loadBalancerKey = k
+	return r
+}
+
+// LoadBalancerKey method returns the load balancing key of the request,
+// see [Request.SetLoadBalancerKey].
+func (r *Request) LoadBalancerKey() string {
+	return r.loadBalancerKey
 }
 
 // NewRoundRobin method creates the new Round-Robin(RR) request load balancer
@@ -41,7 +127,7 @@ func NewRoundRobin(baseURLs ...string) (*RoundRobin, error) {
 	return rr, nil
 }
 
-var _ LoadBalancer = (*RoundRobin)(nil)
+var _ StatsLoadBalancer = (*RoundRobin)(nil)
 
 // RoundRobin struct used to implement the Round-Robin(RR) request
 // load balancer algorithm
@@ -49,6 +135,7 @@ type RoundRobin struct {
 	lock     *sync.Mutex
 	baseURLs []string
 	current  int
+	stats    lbStats
 }
 
 // Next method returns the next Base URL based on the Round-Robin(RR) algorithm
@@ -56,13 +143,20 @@ func (rr *RoundRobin) Next() (string, error) {
 	rr.lock.Lock()
 	defer rr.lock.Unlock()
 
+	if len(rr.baseURLs) == 0 {
+		return "", ErrNoActiveHost
+	}
+	rr.current %= len(rr.baseURLs) // the Base URLs may have been removed
 	baseURL := rr.baseURLs[rr.current]
 	rr.current = (rr.current + 1) % len(rr.baseURLs)
+	rr.stats.begin(baseURL)
 	return baseURL, nil
 }
 
-// Feedback method does nothing in Round-Robin(RR) request load balancer
-func (rr *RoundRobin) Feedback(_ *RequestFeedback) {}
+// Feedback method records the request statistics in Round-Robin(RR) request load balancer
+func (rr *RoundRobin) Feedback(f *RequestFeedback) {
+	rr.stats.end(f)
+}
 
 // Close method does nothing in Round-Robin(RR) request load balancer
 func (rr *RoundRobin) Close() error { return nil }
@@ -82,9 +176,52 @@ func (rr *RoundRobin) Refresh(baseURLs ...string) error {
 
 	// after processing, assign the updates
 	rr.baseURLs = result
+	rr.stats.retain(result...)
 	return nil
 }
 
+// AddTarget method adds the given Base URL, if not exists; the in-flight
+// requests are not affected. It is safe for concurrent use, e.g., to apply
+// the endpoint changes from the service discovery.
+func (rr *RoundRobin) AddTarget(baseURL string) error {
+	baseURL, err := extractBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	if !slices.Contains(rr.baseURLs, baseURL) {
+		rr.baseURLs = append(slices.Clip(rr.baseURLs), baseURL)
+	}
+	return nil
+}
+
+// RemoveTarget method removes the given Base URL, if exists; the in-flight
+// requests to it are not affected.
+func (rr *RoundRobin) RemoveTarget(baseURL string) {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	rr.baseURLs = removeTarget(rr.baseURLs, baseURL)
+	rr.stats.retain(rr.baseURLs...)
+}
+
+// SetTargets method replaces the Base URLs with the given ones, the statistics
+// of the existing Base URLs are retained. See [RoundRobin.AddTarget]
+func (rr *RoundRobin) SetTargets(baseURLs ...string) error {
+	return rr.Refresh(baseURLs...)
+}
+
+// Stats method returns the statistics of the Base URLs
+func (rr *RoundRobin) Stats() []TargetStats {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	result := make([]TargetStats, 0, len(rr.baseURLs))
+	for _, baseURL := range rr.baseURLs {
+		result = append(result, rr.stats.snapshot(baseURL, HostStateActive))
+	}
+	return result
+}
+
 // Host struct used to represent the host information and its weight
 // to load balance the requests
 type Host struct {
@@ -96,14 +233,31 @@ type Host struct {
 	// the percentage of requests to send
 	Weight int
 
+	// Priority represents the host priority, the lower value is preferred;
+	// the requests are sent to the active hosts with the lowest priority
+	// value, the others are used only when all of them are inactive.
+	//	Default value is 0
+	Priority int
+
+	// Zone and Region represent the host locality, e.g., `us-east-1a`
+	// and `us-east-1`. See [WeightedRoundRobin.SetLocality]
+	Zone   string
+	Region string
+
 	// MaxFailures represents the value to mark the host as
 	// not usable until it reaches the Recovery duration
 	//	Default value is 5
 	MaxFailures int
 
+	// HealthCheck represents the active health check of the host,
+	// it overrides the load balancer health check, if any.
+	// See [WeightedRoundRobin.SetHealthCheck]
+	HealthCheck *HealthCheck
+
 	state          HostState
 	currentWeight  int
 	failedRequests int
+	health         hostHealth
 }
 
 func (h *Host) addWeight() {
@@ -148,7 +302,7 @@ func NewWeightedRoundRobin(recovery time.Duration, hosts ...*Host) (*WeightedRou
 	return wrr, err
 }
 
-var _ LoadBalancer = (*WeightedRoundRobin)(nil)
+var _ StatsLoadBalancer = (*WeightedRoundRobin)(nil)
 
 // WeightedRoundRobin struct used to represent the host details for
 // Weighted Round-Robin(WRR) algorithm implementation
@@ -158,6 +312,10 @@ type WeightedRoundRobin struct {
 	totalWeight   int
 	tick          *time.Ticker
 	onStateChange HostStateChangeFunc
+	healthCheck   *HealthCheck
+	healthStop    chan struct{}
+	locality      *Locality
+	stats         lbStats
 
 	// Recovery duration is used to set the timer to put
 	// the host back in the pool for the next turn and
@@ -170,10 +328,17 @@ func (wrr *WeightedRoundRobin) Next() (string, error) {
 	wrr.lock.Lock()
 	defer wrr.lock.Unlock()
 
+	priority, found := wrr.activePriority()
+	if !found {
+		return "", ErrNoActiveHost
+	}
+
+	local := wrr.localityFilter(priority)
+
 	var best *Host
 	total := 0
 	for _, h := range wrr.hosts {
-		if h.state == HostStateInActive {
+		if h.state == HostStateInActive || h.Priority != priority || !local(h) {
 			continue
 		}
 
@@ -185,11 +350,8 @@ func (wrr *WeightedRoundRobin) Next() (string, error) {
 		}
 	}
 
-	if best == nil {
-		return "", ErrNoActiveHost
-	}
-
 	best.resetWeight(total)
+	wrr.stats.begin(best.BaseURL)
 	return best.BaseURL, nil
 }
 
@@ -198,6 +360,7 @@ func (wrr *WeightedRoundRobin) Next() (string, error) {
 func (wrr *WeightedRoundRobin) Feedback(f *RequestFeedback) {
 	wrr.lock.Lock()
 	defer wrr.lock.Unlock()
+	wrr.stats.end(f)
 
 	for _, host := range wrr.hosts {
 		if host.BaseURL == f.BaseURL {
@@ -221,17 +384,141 @@ func (wrr *WeightedRoundRobin) Close() error {
 	wrr.lock.Lock()
 	defer wrr.lock.Unlock()
 	wrr.tick.Stop()
+	wrr.stopHealthChecks()
 	return nil
 }
 
 // Refresh method reset the existing values with the given [Host] slice to refresh it
 func (wrr *WeightedRoundRobin) Refresh(hosts ...*Host) error {
-	if hosts == nil {
-		return nil
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	return wrr.refresh(hosts, false)
+}
+
+// AddTarget method adds the given host, it replaces the existing host of the
+// same Base URL retaining its state. The in-flight requests are not affected.
+// It is safe for concurrent use, e.g., to apply the endpoint changes from the
+// service discovery.
+func (wrr *WeightedRoundRobin) AddTarget(h *Host) error {
+	baseURL, err := extractBaseURL(h.BaseURL)
+	if err != nil {
+		return err
+	}
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	hosts := slices.DeleteFunc(slices.Clone(wrr.hosts), func(eh *Host) bool {
+		return eh.BaseURL == baseURL
+	})
+	return wrr.refresh(append(hosts, h), true)
+}
+
+// RemoveTarget method removes the host of the given Base URL, if exists; the
+// in-flight requests to it are not affected.
+func (wrr *WeightedRoundRobin) RemoveTarget(baseURL string) {
+	if u, err := extractBaseURL(baseURL); err == nil {
+		baseURL = u
+	}
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	hosts := slices.DeleteFunc(slices.Clone(wrr.hosts), func(h *Host) bool {
+		return h.BaseURL == baseURL
+	})
+	_ = wrr.refresh(hosts, true)
+}
+
+// SetTargets method replaces the hosts with the given ones, unlike the
+// [WeightedRoundRobin.Refresh], the state of the existing hosts of the same
+// Base URL is retained. See [WeightedRoundRobin.AddTarget]
+func (wrr *WeightedRoundRobin) SetTargets(hosts ...*Host) error {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	return wrr.refresh(hosts, true)
+}
+
+// Stats method returns the statistics of the hosts
+func (wrr *WeightedRoundRobin) Stats() []TargetStats {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	result := make([]TargetStats, 0, len(wrr.hosts))
+	for _, h := range wrr.hosts {
+		result = append(result, wrr.stats.snapshot(h.BaseURL, h.state))
+	}
+	return result
+}
+
+// SetOnStateChange method used to set a callback for the host transition state
+func (wrr *WeightedRoundRobin) SetOnStateChange(fn HostStateChangeFunc) {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	wrr.onStateChange = fn
+}
+
+// SetRecoveryDuration method is used to change the existing recovery duration for the host
+func (wrr *WeightedRoundRobin) SetRecoveryDuration(d time.Duration) {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	wrr.recovery = d
+	wrr.tick.Reset(d)
+}
+
+// SetHealthCheck method sets the active health check for the hosts, the [Host].HealthCheck
+// overrides it. The unhealthy hosts are marked [HostStateInActive] and are not
+// re-admitted by the recovery until they pass the health check; the state
+// transitions are sent to the callback set by [WeightedRoundRobin.SetOnStateChange].
+//
+//	wrr.SetHealthCheck(&resty.HealthCheck{
+//		Path:               "/healthz",
+//		Interval:           5 * time.Second,
+//		HealthyThreshold:   2,
+//		UnhealthyThreshold: 2,
+//	})
+//
+// The nil value disables it.
+func (wrr *WeightedRoundRobin) SetHealthCheck(hc *HealthCheck) {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	wrr.healthCheck = hc
+	for _, h := range wrr.hosts {
+		if h.health.unhealthy {
+			h.state = HostStateActive
+		}
+		h.health = hostHealth{}
 	}
+	wrr.startHealthChecks()
+}
 
+// SetLocality method sets the locality of the client to prefer the hosts of the same
+// zone, then the same region, see [Host].Zone and [Host].Region. It keeps the
+// traffic of the multi-region deployments local as long as enough local hosts are active.
+//
+//	wrr.SetLocality(&resty.Locality{
+//		Zone:               "us-east-1a",
+//		Region:             "us-east-1",
+//		SpilloverThreshold: 0.7,
+//	})
+//
+// The nil value disables it.
+func (wrr *WeightedRoundRobin) SetLocality(l *Locality) {
 	wrr.lock.Lock()
 	defer wrr.lock.Unlock()
+	wrr.locality = l
+}
+
+// refresh method replaces the hosts with the given ones; the state of the
+// existing hosts, matched by the base URL, is carried over if keepState is true.
+// The caller must hold the lock.
+func (wrr *WeightedRoundRobin) refresh(hosts []*Host, keepState bool) error {
+	if hosts == nil {
+		return nil
+	}
+
+	existing := make(map[string]*Host)
+	if keepState {
+		for _, h := range wrr.hosts {
+			existing[h.BaseURL] = h
+		}
+	}
+
 	newTotalWeight := 0
 	for _, h := range hosts {
 		baseURL, err := extractBaseURL(h.BaseURL)
@@ -240,7 +527,17 @@ func (wrr *WeightedRoundRobin) Refresh(hosts ...*Host) error {
 		}
 
 		h.BaseURL = baseURL
-		h.state = HostStateActive
+		if eh, found := existing[baseURL]; found {
+			if eh != h {
+				h.state = eh.state
+				h.currentWeight = eh.currentWeight
+				h.failedRequests = eh.failedRequests
+				h.health = eh.health
+			}
+		} else {
+			h.state = HostStateActive
+			h.health = hostHealth{}
+		}
 		newTotalWeight += h.Weight
 
 		// assign defaults if not provided
@@ -252,29 +549,67 @@ func (wrr *WeightedRoundRobin) Refresh(hosts ...*Host) error {
 	// after processing, assign the updates
 	wrr.hosts = hosts
 	wrr.totalWeight = newTotalWeight
+	baseURLs := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		baseURLs = append(baseURLs, h.BaseURL)
+	}
+	wrr.stats.retain(baseURLs...)
+	wrr.startHealthChecks()
 	return nil
 }
 
-// SetOnStateChange method used to set a callback for the host transition state
-func (wrr *WeightedRoundRobin) SetOnStateChange(fn HostStateChangeFunc) {
-	wrr.lock.Lock()
-	defer wrr.lock.Unlock()
-	wrr.onStateChange = fn
+// activePriority method returns the lowest priority value of the active hosts.
+// The caller must hold the lock.
+func (wrr *WeightedRoundRobin) activePriority() (int, bool) {
+	priority, found := 0, false
+	for _, h := range wrr.hosts {
+		if h.state == HostStateActive && (!found || h.Priority < priority) {
+			priority, found = h.Priority, true
+		}
+	}
+	return priority, found
 }
 
-// SetRecoveryDuration method is used to change the existing recovery duration for the host
-func (wrr *WeightedRoundRobin) SetRecoveryDuration(d time.Duration) {
-	wrr.lock.Lock()
-	defer wrr.lock.Unlock()
-	wrr.recovery = d
-	wrr.tick.Reset(d)
+// localityFilter method returns the host filter of the preferred locality
+// among the hosts of the given priority, see [Locality]. The caller must
+// hold the lock.
+func (wrr *WeightedRoundRobin) localityFilter(priority int) func(*Host) bool {
+	l := wrr.locality
+	if l == nil {
+		return func(*Host) bool { return true }
+	}
+
+	threshold := l.SpilloverThreshold
+	if threshold <= 0 {
+		threshold = 0.5
+	}
+	tiers := []func(*Host) bool{
+		func(h *Host) bool { return len(l.Zone) > 0 && h.Zone == l.Zone },
+		func(h *Host) bool { return len(l.Region) > 0 && h.Region == l.Region },
+	}
+	for _, tier := range tiers {
+		total, active := 0, 0
+		for _, h := range wrr.hosts {
+			if h.Priority != priority || !tier(h) {
+				continue
+			}
+			total++
+			if h.state == HostStateActive {
+				active++
+			}
+		}
+		if active > 0 && float64(active) >= threshold*float64(total) {
+			return tier
+		}
+	}
+	return func(*Host) bool { return true }
 }
 
 func (wrr *WeightedRoundRobin) ticker() {
 	for range wrr.tick.C {
 		wrr.lock.Lock()
 		for _, host := range wrr.hosts {
-			if host.state == HostStateInActive {
+			if host.state == HostStateInActive && !host.health.unhealthy {
 				host.state = HostStateActive
 				host.failedRequests = 0
 
@@ -287,8 +622,85 @@ func (wrr *WeightedRoundRobin) ticker() {
 	}
 }
 
+// startHealthChecks method starts the health checks of the hosts after stopping
+// the running ones. The caller must hold the lock.
+func (wrr *WeightedRoundRobin) startHealthChecks() {
+	wrr.stopHealthChecks()
+	stop := make(chan struct{})
+	for _, h := range wrr.hosts {
+		hc := h.HealthCheck
+		if hc == nil {
+			hc = wrr.healthCheck
+		}
+		if hc == nil {
+			continue
+		}
+		hc = hc.withDefaults()
+		wrr.healthStop = stop
+		go runHealthCheck(stop, hc, h.BaseURL, func(healthy bool) {
+			wrr.recordHealth(stop, h, hc, healthy)
+		})
+	}
+}
+
+// stopHealthChecks method stops the running health checks. The caller must
+// hold the lock.
+func (wrr *WeightedRoundRobin) stopHealthChecks() {
+	if wrr.healthStop != nil {
+		close(wrr.healthStop)
+		wrr.healthStop = nil
+	}
+}
+
+func (wrr *WeightedRoundRobin) recordHealth(stop <-chan struct{}, h *Host, hc *HealthCheck, healthy bool) {
+	wrr.lock.Lock()
+	defer wrr.lock.Unlock()
+	select {
+	case <-stop:
+		return // hosts are refreshed or the load balancer is closed
+	default:
+	}
+	if !h.health.record(hc, healthy) {
+		return
+	}
+
+	from := h.state
+	if h.health.unhealthy {
+		h.state = HostStateInActive
+	} else {
+		h.state = HostStateActive
+		h.failedRequests = 0
+	}
+	if from != h.state && wrr.onStateChange != nil {
+		wrr.onStateChange(h.BaseURL, from, h.state)
+	}
+}
+
+// Locality struct is used to define the locality of the client for the
+// zone-aware routing of the load balancer; the requests are sent to the
+// hosts in the same zone first, then to the hosts in the same region, and
+// then to all the hosts. See [WeightedRoundRobin.SetLocality]
+type Locality struct {
+	// Zone of the client, e.g., `us-east-1a`
+	Zone string
+
+	// Region of the client, e.g., `us-east-1`
+	Region string
+
+	// SpilloverThreshold is the minimum ratio of the active hosts in the
+	// zone or region to keep the traffic in it; otherwise, the traffic spills
+	// over to the next one. Default value is `0.5`, i.e., the traffic spills
+	// over when more than half of the local hosts are inactive.
+	SpilloverThreshold float64
+}
+
 // NewSRVWeightedRoundRobin method creates a new Weighted Round-Robin(WRR) load balancer instance
-// with given SRV values
+// with given SRV values, e.g., the Consul service or Kubernetes headless service.
+// The `_service._proto.domainName` SRV records are resolved periodically, see
+// [SRVWeightedRoundRobin.SetRefreshDuration]; the records priority and weight are
+// honored, see [Host].Priority.
+//
+//	lb, err := resty.NewSRVWeightedRoundRobin("api", "tcp", "service.consul", "https")
 func NewSRVWeightedRoundRobin(service, proto, domainName, httpScheme string) (*SRVWeightedRoundRobin, error) {
 	if isStringEmpty(proto) {
 		proto = "tcp"
@@ -306,11 +718,8 @@ func NewSRVWeightedRoundRobin(service, proto, domainName, httpScheme string) (*S
 		wrr:        wrr,
 		tick:       time.NewTicker(180 * time.Second), // default is 180 seconds
 		lock:       new(sync.Mutex),
-		lookupSRV: func() ([]*net.SRV, error) {
-			_, addrs, err := net.LookupSRV(service, proto, domainName)
-			return addrs, err
-		},
 	}
+	swrr.lookupSRV = swrr.lookupSRVWith(net.DefaultResolver)
 
 	err := swrr.Refresh()
 
@@ -319,7 +728,13 @@ func NewSRVWeightedRoundRobin(service, proto, domainName, httpScheme string) (*S
 	return swrr, err
 }
 
-var _ LoadBalancer = (*SRVWeightedRoundRobin)(nil)
+var _ StatsLoadBalancer = (*SRVWeightedRoundRobin)(nil)
+
+// SRVResolver is the interface that wraps the DNS SRV records lookup,
+// it is implemented by [net.Resolver].
+type SRVResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
 
 // SRVWeightedRoundRobin struct used to implement SRV Weighted Round-Robin(RR) algorithm
 type SRVWeightedRoundRobin struct {
@@ -339,8 +754,8 @@ func (swrr *SRVWeightedRoundRobin) Next() (string, error) {
 	return swrr.wrr.Next()
 }
 
-// Feedback method does nothing in SRV Base URL based on Weighted Round-Robin(WRR)
-// request load balancer
+// Feedback method process the request feedback for SRV Base URL based on
+// Weighted Round-Robin(WRR) request load balancer
 func (swrr *SRVWeightedRoundRobin) Feedback(f *RequestFeedback) {
 	swrr.wrr.Feedback(f)
 }
@@ -355,7 +770,14 @@ func (swrr *SRVWeightedRoundRobin) Close() error {
 	return nil
 }
 
-// Refresh method reset the values based [net.LookupSRV] values to refresh it
+// Refresh method reset the values based [net.LookupSRV] values to refresh it.
+// The state of the existing hosts is retained, and the existing hosts are
+// retained on the lookup error.
+//
+// NOTE: The zero weight record is given the weight `1`, it has a very small
+// chance to be selected among the weighted records, see [RFC 2782].
+//
+// [RFC 2782]: https://datatracker.ietf.org/doc/html/rfc2782
 func (swrr *SRVWeightedRoundRobin) Refresh() error {
 	swrr.lock.Lock()
 	defer swrr.lock.Unlock()
@@ -364,14 +786,40 @@ func (swrr *SRVWeightedRoundRobin) Refresh() error {
 		return err
 	}
 
-	hosts := make([]*Host, len(addrs))
-	for idx, addr := range addrs {
+	hosts := make([]*Host, 0, len(addrs))
+	for _, addr := range addrs {
 		domain := strings.TrimRight(addr.Target, ".")
+		if domain == "" {
+			continue // the target "." denotes the service is not available
+		}
 		baseURL := fmt.Sprintf("%s://%s:%d", swrr.HttpScheme, domain, addr.Port)
-		hosts[idx] = &Host{BaseURL: baseURL, Weight: int(addr.Weight)}
+		hosts = append(hosts, &Host{
+			BaseURL:  baseURL,
+			Weight:   max(int(addr.Weight), 1),
+			Priority: int(addr.Priority),
+		})
 	}
 
-	return swrr.wrr.Refresh(hosts...)
+	return swrr.wrr.SetTargets(hosts...)
+}
+
+// SetResolver method sets the resolver to lookup the SRV records, default is
+// [net.DefaultResolver]; it refreshes the hosts with the given resolver.
+//
+//	lb.SetResolver(&net.Resolver{
+//		PreferGo: true,
+//		Dial:     dialConsulDNS,
+//	})
+func (swrr *SRVWeightedRoundRobin) SetResolver(r SRVResolver) error {
+	swrr.lock.Lock()
+	swrr.lookupSRV = swrr.lookupSRVWith(r)
+	swrr.lock.Unlock()
+	return swrr.Refresh()
+}
+
+// Stats method returns the statistics of the SRV hosts
+func (swrr *SRVWeightedRoundRobin) Stats() []TargetStats {
+	return swrr.wrr.Stats()
 }
 
 // SetRefreshDuration method assists in changing the default (180 seconds) refresh duration
@@ -391,12 +839,100 @@ func (swrr *SRVWeightedRoundRobin) SetRecoveryDuration(d time.Duration) {
 	swrr.wrr.SetRecoveryDuration(d)
 }
 
+// SetHealthCheck method sets the active health check for the SRV hosts,
+// see [WeightedRoundRobin.SetHealthCheck]
+func (swrr *SRVWeightedRoundRobin) SetHealthCheck(hc *HealthCheck) {
+	swrr.wrr.SetHealthCheck(hc)
+}
+
+func (swrr *SRVWeightedRoundRobin) lookupSRVWith(r SRVResolver) func() ([]*net.SRV, error) {
+	return func() ([]*net.SRV, error) {
+		_, addrs, err := r.LookupSRV(context.Background(), swrr.Service, swrr.Proto, swrr.DomainName)
+		return addrs, err
+	}
+}
+
 func (swrr *SRVWeightedRoundRobin) ticker() {
 	for range swrr.tick.C {
 		swrr.Refresh()
 	}
 }
 
+// removeTarget function returns the Base URLs without the given one.
+func removeTarget(baseURLs []string, baseURL string) []string {
+	if u, err := extractBaseURL(baseURL); err == nil {
+		baseURL = u
+	}
+	return slices.DeleteFunc(slices.Clone(baseURLs), func(u string) bool {
+		return u == baseURL
+	})
+}
+
+// lbStats struct tracks the per-target statistics of the load balancer,
+// the zero value is ready to use.
+type lbStats struct {
+	lock    sync.Mutex
+	targets map[string]*TargetStats
+}
+
+func (s *lbStats) target(baseURL string) *TargetStats {
+	if s.targets == nil {
+		s.targets = make(map[string]*TargetStats)
+	}
+	t, found := s.targets[baseURL]
+	if !found {
+		t = &TargetStats{BaseURL: baseURL}
+		s.targets[baseURL] = t
+	}
+	return t
+}
+
+// begin method records the request start of the target selected by the
+// load balancer.
+func (s *lbStats) begin(baseURL string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.target(baseURL).InFlight++
+}
+
+// end method records the request completion from the request feedback.
+func (s *lbStats) end(f *RequestFeedback) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, found := s.targets[f.BaseURL]
+	if !found {
+		return // the target is removed
+	}
+	if t.InFlight > 0 {
+		t.InFlight--
+	}
+	t.Requests++
+	if !f.Success {
+		t.Errors++
+		t.LastError, t.LastErrorAt = f.Err, time.Now()
+	}
+}
+
+// snapshot method returns the statistics of the given target.
+func (s *lbStats) snapshot(baseURL string, state HostState) TargetStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t := *s.target(baseURL)
+	t.State = state
+	return t
+}
+
+// retain method drops the statistics of the targets other than the given ones.
+func (s *lbStats) retain(baseURLs ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for baseURL := range s.targets {
+		if !slices.Contains(baseURLs, baseURL) {
+			delete(s.targets, baseURL)
+		}
+	}
+}
+
 func extractBaseURL(u string) (string, error) {
 	baseURL, err := url.Parse(u)
 	if err != nil {
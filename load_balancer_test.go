@@ -6,10 +6,15 @@
 package resty
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -210,6 +215,67 @@ func TestWeightedRoundRobin(t *testing.T) {
 	})
 }
 
+func TestWeightedRoundRobinLocality(t *testing.T) {
+	newWRR := func(l *Locality) *WeightedRoundRobin {
+		wrr, err := NewWeightedRoundRobin(0,
+			&Host{BaseURL: "https://a1.example.com", Weight: 50, Zone: "us-east-1a", Region: "us-east-1"},
+			&Host{BaseURL: "https://a2.example.com", Weight: 50, Zone: "us-east-1a", Region: "us-east-1"},
+			&Host{BaseURL: "https://b1.example.com", Weight: 50, Zone: "us-east-1b", Region: "us-east-1"},
+			&Host{BaseURL: "https://c1.example.com", Weight: 50, Zone: "eu-west-1a", Region: "eu-west-1"},
+		)
+		assertNil(t, err)
+		wrr.SetLocality(l)
+		return wrr
+	}
+	next := func(wrr *WeightedRoundRobin, n int) map[string]bool {
+		result := make(map[string]bool)
+		for i := 0; i < n; i++ {
+			baseURL, err := wrr.Next()
+			assertNil(t, err)
+			result[baseURL] = true
+		}
+		return result
+	}
+	fail := func(wrr *WeightedRoundRobin, baseURL string) {
+		for i := 0; i < 5; i++ {
+			wrr.Feedback(&RequestFeedback{BaseURL: baseURL, Success: false})
+		}
+	}
+
+	wrr := newWRR(&Locality{Zone: "us-east-1a", Region: "us-east-1"})
+	defer wrr.Close()
+	assertEqual(t, map[string]bool{"https://a1.example.com": true, "https://a2.example.com": true}, next(wrr, 4))
+
+	// half of the zone is active, the traffic stays in the zone
+	fail(wrr, "https://a1.example.com")
+	assertEqual(t, map[string]bool{"https://a2.example.com": true}, next(wrr, 4))
+
+	// the region has one of three active, it spills over to all
+	fail(wrr, "https://a2.example.com")
+	assertEqual(t, map[string]bool{"https://b1.example.com": true, "https://c1.example.com": true}, next(wrr, 4))
+	fail(wrr, "https://b1.example.com")
+	assertEqual(t, map[string]bool{"https://c1.example.com": true}, next(wrr, 4))
+
+	t.Run("spillover threshold", func(t *testing.T) {
+		wrr := newWRR(&Locality{Zone: "us-east-1a", Region: "us-east-1", SpilloverThreshold: 0.6})
+		defer wrr.Close()
+		fail(wrr, "https://a1.example.com")
+		assertEqual(t, map[string]bool{"https://a2.example.com": true, "https://b1.example.com": true}, next(wrr, 4))
+	})
+
+	t.Run("unknown zone and disabled", func(t *testing.T) {
+		wrr := newWRR(&Locality{Zone: "ap-south-1a"})
+		defer wrr.Close()
+		assertEqual(t, 4, len(next(wrr, 8)))
+
+		wrr.SetLocality(&Locality{Region: "eu-west-1"})
+		assertEqual(t, map[string]bool{"https://c1.example.com": true}, next(wrr, 4))
+
+		wrr.SetLocality(nil)
+		assertEqual(t, 4, len(next(wrr, 8)))
+	})
+}
+
 func TestSRVWeightedRoundRobin(t *testing.T) {
 	t.Run("3 records with weight {50,30,20}", func(t *testing.T) {
 		srv, err := NewSRVWeightedRoundRobin("_sample-server", "", "example.com", "")
@@ -222,8 +288,8 @@ func TestSRVWeightedRoundRobin(t *testing.T) {
 		srv.lookupSRV = func() ([]*net.SRV, error) {
 			return []*net.SRV{
 				{Target: "service1.example.com.", Port: 443, Priority: 10, Weight: 50},
-				{Target: "service2.example.com.", Port: 443, Priority: 20, Weight: 30},
-				{Target: "service3.example.com.", Port: 443, Priority: 20, Weight: 20},
+				{Target: "service2.example.com.", Port: 443, Priority: 10, Weight: 30},
+				{Target: "service3.example.com.", Port: 443, Priority: 10, Weight: 20},
 			}, nil
 		}
 		err = srv.Refresh()
@@ -261,7 +327,7 @@ func TestSRVWeightedRoundRobin(t *testing.T) {
 		srv.lookupSRV = func() ([]*net.SRV, error) {
 			return []*net.SRV{
 				{Target: "service1.example.com.", Port: 443, Priority: 10, Weight: 50},
-				{Target: "service2.example.com.", Port: 443, Priority: 20, Weight: 50},
+				{Target: "service2.example.com.", Port: 443, Priority: 10, Weight: 50},
 			}, nil
 		}
 		err = srv.Refresh()
@@ -299,8 +365,8 @@ func TestSRVWeightedRoundRobin(t *testing.T) {
 		srv.lookupSRV = func() ([]*net.SRV, error) {
 			return []*net.SRV{
 				{Target: "service1.example.com.", Port: 443, Priority: 10, Weight: 60},
-				{Target: "service2.example.com.", Port: 443, Priority: 20, Weight: 20},
-				{Target: "service3.example.com.", Port: 443, Priority: 20, Weight: 20},
+				{Target: "service2.example.com.", Port: 443, Priority: 10, Weight: 20},
+				{Target: "service3.example.com.", Port: 443, Priority: 10, Weight: 20},
 			}, nil
 		}
 		err = srv.Refresh()
@@ -353,7 +419,7 @@ func TestSRVWeightedRoundRobin(t *testing.T) {
 		srv.lookupSRV = func() ([]*net.SRV, error) {
 			return []*net.SRV{
 				{Target: "service1.example.com.", Port: 443, Priority: 10, Weight: 50},
-				{Target: "service2.example.com.", Port: 443, Priority: 20, Weight: 50},
+				{Target: "service2.example.com.", Port: 443, Priority: 10, Weight: 50},
 			}, nil
 		}
 		err = srv.Refresh()
@@ -395,6 +461,82 @@ func TestSRVWeightedRoundRobin(t *testing.T) {
 
 	})
 
+	t.Run("srv records with priority", func(t *testing.T) {
+		srv, _ := NewSRVWeightedRoundRobin("_sample-server", "", "example.com", "")
+		defer srv.Close()
+		records := []*net.SRV{
+			{Target: "service1.example.com.", Port: 443, Priority: 10, Weight: 0},
+			{Target: "service2.example.com.", Port: 443, Priority: 20, Weight: 50},
+			{Target: "service3.example.com.", Port: 443, Priority: 20, Weight: 50},
+			{Target: ".", Port: 443, Priority: 5, Weight: 10},
+		}
+		srv.lookupSRV = func() ([]*net.SRV, error) { return records, nil }
+		assertNil(t, srv.Refresh())
+
+		for i := 0; i < 3; i++ {
+			baseURL, err := srv.Next()
+			assertNil(t, err)
+			assertEqual(t, "https://service1.example.com:443", baseURL)
+		}
+
+		// the lower priority hosts are used once the preferred host is inactive
+		for i := 0; i < 5; i++ {
+			srv.Feedback(&RequestFeedback{BaseURL: "https://service1.example.com:443", Success: false})
+		}
+		var result []string
+		for i := 0; i < 4; i++ {
+			baseURL, err := srv.Next()
+			assertNil(t, err)
+			result = append(result, baseURL)
+		}
+		assertEqual(t, []string{
+			"https://service2.example.com:443", "https://service3.example.com:443",
+			"https://service2.example.com:443", "https://service3.example.com:443",
+		}, result)
+
+		// the host state is retained on refresh
+		assertNil(t, srv.Refresh())
+		baseURL, _ := srv.Next()
+		assertEqual(t, "https://service2.example.com:443", baseURL)
+
+		// the removed records are dropped, and the lookup error retains the hosts
+		records = records[:1]
+		assertNil(t, srv.Refresh())
+		_, err := srv.Next()
+		assertErrorIs(t, ErrNoActiveHost, err)
+
+		srv.lookupSRV = func() ([]*net.SRV, error) { return nil, errors.New("network error") }
+		assertNotNil(t, srv.Refresh())
+		srv.SetRecoveryDuration(10 * time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		baseURL, err = srv.Next()
+		assertNil(t, err)
+		assertEqual(t, "https://service1.example.com:443", baseURL)
+	})
+
+	t.Run("srv records with custom resolver", func(t *testing.T) {
+		srv, _ := NewSRVWeightedRoundRobin("_sample-server", "", "example.com", "http")
+		defer srv.Close()
+		r := &testSRVResolver{addrs: []*net.SRV{
+			{Target: "service1.example.com.", Port: 8080, Priority: 10, Weight: 10},
+		}}
+		assertNil(t, srv.SetResolver(r))
+		assertEqual(t, "_sample-server tcp example.com", r.query)
+
+		baseURL, err := srv.Next()
+		assertNil(t, err)
+		assertEqual(t, "http://service1.example.com:8080", baseURL)
+	})
+}
+
+type testSRVResolver struct {
+	addrs []*net.SRV
+	query string
+}
+
+func (r *testSRVResolver) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.query = strings.Join([]string{service, proto, name}, " ")
+	return "", r.addrs, nil
 }
 
 func TestLoadBalancerRequest(t *testing.T) {
@@ -426,6 +568,60 @@ func TestLoadBalancerRequest(t *testing.T) {
 	assertEqual(t, ts1URL, ts2URL)
 }
 
+type testFeedbackLoadBalancer struct {
+	*RoundRobin
+	feedbacks []*RequestFeedback
+}
+
+func (lb *testFeedbackLoadBalancer) Feedback(f *RequestFeedback) {
+	lb.feedbacks = append(lb.feedbacks, f)
+}
+
+func TestLoadBalancerRequestSetHost(t *testing.T) {
+	ts1 := createGetServer(t)
+	defer ts1.Close()
+
+	ts2 := createGetServer(t)
+	defer ts2.Close()
+
+	rr, err := NewRoundRobin(ts1.URL)
+	assertNil(t, err)
+	lb := &testFeedbackLoadBalancer{RoundRobin: rr}
+
+	c := dcnl().SetLoadBalancer(lb)
+	defer c.Close()
+
+	res, err := c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, ts1.URL, res.BaseURL())
+	assertEqual(t, 1, len(lb.feedbacks))
+	assertEqual(t, true, lb.feedbacks[0].Latency > 0)
+
+	res, err = c.R().SetHost(ts2.URL + "/ignored/path").Get("/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+	assertEqual(t, ts2.URL, res.BaseURL())
+	assertEqual(t, 1, len(lb.feedbacks))
+
+	res, err = c.R().Get(ts2.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, "", res.BaseURL())
+
+	_, err = c.R().SetHost("://example.com").Get("/")
+	assertType(t, url.Error{}, err)
+
+	t.Run("without load balancer", func(t *testing.T) {
+		c := dcnl().SetBaseURL(ts1.URL)
+		res, err := c.R().Get("/")
+		assertNil(t, err)
+		assertEqual(t, ts1.URL, res.BaseURL())
+
+		res, err = c.R().SetHost(ts2.URL).Get("/")
+		assertNil(t, err)
+		assertEqual(t, ts2.URL, res.BaseURL())
+	})
+}
+
 func TestLoadBalancerRequestFlowError(t *testing.T) {
 
 	t.Run("obtain next url error", func(t *testing.T) {
@@ -545,6 +741,7 @@ func TestLoadBalancerCoverage(t *testing.T) {
 		c.SetLoadBalancer(wrr)
 
 		req := c.R()
+		req.loadBalanced = true
 
 		netOpErr := &net.OpError{Op: "mock", Net: "mock", Err: &mockTimeoutErr{}}
 		req.sendLoadBalancerFeedback(&Response{}, netOpErr)
@@ -554,3 +751,236 @@ func TestLoadBalancerCoverage(t *testing.T) {
 		}}, nil)
 	})
 }
+
+func TestLoadBalancerStats(t *testing.T) {
+	ts1 := createGetServer(t)
+	defer ts1.Close()
+	ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts2.Close()
+
+	rr, err := NewRoundRobin(ts1.URL, ts2.URL)
+	assertNil(t, err)
+
+	c := dcnl().SetLoadBalancer(rr)
+	defer c.Close()
+
+	for i := 0; i < 4; i++ {
+		_, _ = c.R().Get("/")
+	}
+
+	stats := rr.Stats()
+	assertEqual(t, 2, len(stats))
+	assertEqual(t, TargetStats{BaseURL: ts1.URL, State: HostStateActive, Requests: 2}, stats[0])
+	assertEqual(t, ts2.URL, stats[1].BaseURL)
+	assertEqual(t, int64(2), stats[1].Requests)
+	assertEqual(t, int64(2), stats[1].Errors)
+	assertEqual(t, int64(0), stats[1].InFlight)
+	assertEqual(t, "resty: 502 Bad Gateway", stats[1].LastError.Error())
+	assertEqual(t, false, stats[1].LastErrorAt.IsZero())
+
+	// the in-flight requests, and the stats of the removed target are dropped
+	_, _ = rr.Next()
+	assertEqual(t, int64(1), rr.Stats()[0].InFlight)
+	assertNil(t, rr.Refresh(ts2.URL))
+	assertNil(t, rr.Refresh(ts1.URL, ts2.URL))
+	stats = rr.Stats()
+	assertEqual(t, TargetStats{BaseURL: ts1.URL, State: HostStateActive}, stats[0])
+	assertEqual(t, int64(2), stats[1].Requests)
+
+	t.Run("feedback per attempt", func(t *testing.T) {
+		rr, _ := NewRoundRobin(ts2.URL, ts1.URL)
+		lb := &testFeedbackLoadBalancer{RoundRobin: rr}
+		c := dcnl().SetLoadBalancer(lb).
+			SetRetryCount(2).
+			SetRetryWaitTime(time.Millisecond).
+			SetRetryMaxWaitTime(time.Millisecond)
+		defer c.Close()
+
+		res, err := c.R().Get("/")
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+		assertEqual(t, 2, len(lb.feedbacks))
+		assertEqual(t, RequestFeedback{BaseURL: ts2.URL, Success: false, Attempt: 1,
+			Latency: lb.feedbacks[0].Latency, Err: lb.feedbacks[0].Err}, *lb.feedbacks[0])
+		assertEqual(t, true, lb.feedbacks[1].Success)
+		assertEqual(t, 2, lb.feedbacks[1].Attempt)
+	})
+
+	t.Run("all load balancers", func(t *testing.T) {
+		wrr, _ := NewWeightedRoundRobin(0, &Host{BaseURL: ts1.URL, Weight: 1})
+		defer wrr.Close()
+		ch, _ := NewConsistentHash(nil, ts1.URL)
+		p2c, _ := NewPowerOfTwoChoices(ts1.URL)
+		fo, _ := NewFailover(ts1.URL)
+		defer fo.Close()
+
+		for _, lb := range []StatsLoadBalancer{wrr, ch, p2c, fo} {
+			baseURL, err := lb.Next()
+			assertNil(t, err)
+			lb.Feedback(&RequestFeedback{BaseURL: baseURL, Success: false, Err: errMockNetwork})
+			stats := lb.Stats()
+			assertEqual(t, 1, len(stats))
+			assertEqual(t, TargetStats{BaseURL: ts1.URL, State: HostStateActive, Requests: 1, Errors: 1,
+				LastError: errMockNetwork, LastErrorAt: stats[0].LastErrorAt}, stats[0])
+		}
+	})
+
+	t.Run("failover state change", func(t *testing.T) {
+		fo, _ := NewFailover("https://api.example.com", "https://api-dr.example.com")
+		defer fo.Close()
+		var transitions []string
+		fo.SetOnStateChange(func(baseURL string, from, to HostState) {
+			transitions = append(transitions, fmt.Sprintf("%s %d->%d", baseURL, from, to))
+		})
+		for i := 0; i < 3; i++ {
+			fo.Feedback(&RequestFeedback{BaseURL: "https://api.example.com", Success: false})
+		}
+		stats := fo.Stats()
+		assertEqual(t, HostStateInActive, stats[0].State)
+		assertEqual(t, HostStateActive, stats[1].State)
+		assertEqual(t, []string{"https://api.example.com 1->0"}, transitions)
+
+		fo.lock.Lock()
+		fo.switchTo(0)
+		fo.lock.Unlock()
+		assertEqual(t, []string{"https://api.example.com 1->0", "https://api.example.com 0->1"}, transitions)
+	})
+}
+
+var errMockNetwork = errors.New("network error")
+
+func TestLoadBalancerTargets(t *testing.T) {
+	t.Run("round-robin", func(t *testing.T) {
+		rr, _ := NewRoundRobin("https://api1.example.com", "https://api2.example.com", "https://api3.example.com")
+		for i := 0; i < 2; i++ {
+			_, _ = rr.Next()
+		}
+		rr.RemoveTarget("https://api2.example.com/")
+		rr.RemoveTarget("https://api3.example.com")
+		baseURL, err := rr.Next()
+		assertNil(t, err)
+		assertEqual(t, "https://api1.example.com", baseURL)
+
+		assertNil(t, rr.AddTarget("https://api4.example.com/v1"))
+		assertNil(t, rr.AddTarget("https://api4.example.com"))
+		assertNotNil(t, rr.AddTarget("://api5.example.com"))
+		assertEqual(t, []string{"https://api1.example.com", "https://api4.example.com"}, rr.baseURLs)
+
+		assertNil(t, rr.SetTargets())
+		_, err = rr.Next()
+		assertErrorIs(t, ErrNoActiveHost, err)
+	})
+
+	t.Run("weighted round-robin", func(t *testing.T) {
+		wrr, _ := NewWeightedRoundRobin(time.Hour,
+			&Host{BaseURL: "https://api1.example.com", Weight: 1, MaxFailures: 1},
+			&Host{BaseURL: "https://api2.example.com", Weight: 1},
+		)
+		defer wrr.Close()
+		wrr.Feedback(&RequestFeedback{BaseURL: "https://api1.example.com", Success: false})
+
+		// the state of the existing host is retained
+		assertNil(t, wrr.AddTarget(&Host{BaseURL: "https://api1.example.com/", Weight: 5}))
+		wrr.RemoveTarget("https://api2.example.com")
+		_, err := wrr.Next()
+		assertErrorIs(t, ErrNoActiveHost, err)
+
+		assertNil(t, wrr.SetTargets(
+			&Host{BaseURL: "https://api1.example.com", Weight: 5},
+			&Host{BaseURL: "https://api3.example.com", Weight: 5},
+		))
+		stats := wrr.Stats()
+		assertEqual(t, 2, len(stats))
+		assertEqual(t, HostStateInActive, stats[0].State)
+		assertEqual(t, HostStateActive, stats[1].State)
+		baseURL, _ := wrr.Next()
+		assertEqual(t, "https://api3.example.com", baseURL)
+
+		assertNotNil(t, wrr.AddTarget(&Host{BaseURL: "://api4.example.com"}))
+	})
+
+	t.Run("consistent hash and p2c", func(t *testing.T) {
+		ch, _ := NewConsistentHash(nil, "https://api1.example.com")
+		p2c, _ := NewPowerOfTwoChoices("https://api1.example.com")
+		type targetLoadBalancer interface {
+			StatsLoadBalancer
+			AddTarget(string) error
+			RemoveTarget(string)
+			SetTargets(...string) error
+		}
+		for _, lb := range []targetLoadBalancer{ch, p2c} {
+			assertNil(t, lb.AddTarget("https://api2.example.com"))
+			assertNil(t, lb.AddTarget("https://api2.example.com"))
+			assertNotNil(t, lb.AddTarget("://api3.example.com"))
+			lb.RemoveTarget("https://api1.example.com")
+			baseURL, err := lb.Next()
+			assertNil(t, err)
+			assertEqual(t, "https://api2.example.com", baseURL)
+			assertEqual(t, 1, len(lb.Stats()))
+
+			assertNil(t, lb.SetTargets())
+			_, err = lb.Next()
+			assertErrorIs(t, ErrNoActiveHost, err)
+		}
+	})
+
+	t.Run("concurrent updates", func(t *testing.T) {
+		rr, _ := NewRoundRobin("https://api0.example.com")
+		wrr, _ := NewWeightedRoundRobin(0, &Host{BaseURL: "https://api0.example.com", Weight: 1})
+		defer wrr.Close()
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				baseURL := fmt.Sprintf("https://api%d.example.com", i)
+				_ = rr.AddTarget(baseURL)
+				_ = wrr.AddTarget(&Host{BaseURL: baseURL, Weight: 1})
+				rr.RemoveTarget(baseURL)
+				wrr.RemoveTarget(baseURL)
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					baseURL, err := rr.Next()
+					assertNil(t, err)
+					rr.Feedback(&RequestFeedback{BaseURL: baseURL, Success: true})
+					_, err = wrr.Next()
+					assertNil(t, err)
+				}
+			}()
+		}
+		wg.Wait()
+		assertEqual(t, []string{"https://api0.example.com"}, rr.baseURLs)
+		assertEqual(t, 1, len(wrr.Stats()))
+	})
+
+	t.Run("in-flight request", func(t *testing.T) {
+		release := make(chan struct{})
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			_, _ = w.Write([]byte("done"))
+		}))
+		defer ts.Close()
+
+		rr, _ := NewRoundRobin(ts.URL)
+		c := dcnl().SetLoadBalancer(rr)
+		defer c.Close()
+
+		done := make(chan *Response)
+		go func() {
+			res, _ := c.R().Get("/")
+			done <- res
+		}()
+		waitForCondition(t, func() bool { return rr.Stats()[0].InFlight == 1 })
+		rr.RemoveTarget(ts.URL)
+		close(release)
+
+		res := <-done
+		assertEqual(t, "done", res.String())
+		assertEqual(t, 0, len(rr.Stats()))
+	})
+}
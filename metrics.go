@@ -0,0 +1,433 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsCollector interface is for collecting the Resty client request metrics.
+// The client calls it once per request execution, after all the retry attempts
+// are completed.
+//
+// See [Client.SetMetricsCollector], [PrometheusMetrics]
+type MetricsCollector interface {
+	CollectRequestMetrics(m *RequestMetrics)
+}
+
+// RequestMetrics struct holds the metrics of the completed request execution.
+type RequestMetrics struct {
+	// Name is the logical operation name of the request, see [Request.SetName].
+	Name string
+
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Host is the request URL host.
+	Host string
+
+	// Path is the request URL path before the path parameters are applied,
+	// e.g., `/users/{userId}`, to keep the metrics cardinality low.
+	Path string
+
+	// StatusCode is the response status code; it is zero if there is no response.
+	StatusCode int
+
+	// StatusClass is the response status class, e.g., `2xx`, `4xx`; it is
+	// `error` if there is no response.
+	StatusClass string
+
+	// Duration is the end-to-end duration of the request execution, including
+	// the retry attempts.
+	Duration time.Duration
+
+	// Retries is the number of retry attempts made.
+	Retries int
+
+	// CircuitBreakerState is the client circuit breaker state, i.e., `closed`,
+	// `open`, or `half-open`; it is empty if no circuit breaker is set.
+	CircuitBreakerState string
+
+	// DNSLookup, TCPConnTime, and TLSHandshake timings are taken from
+	// [TraceInfo] of the last attempt, available when the trace is enabled.
+	DNSLookup    time.Duration
+	TCPConnTime  time.Duration
+	TLSHandshake time.Duration
+
+	// Err is the request execution error, if any.
+	Err error
+}
+
+// MetricsLabel type represents the label name used by [PrometheusMetrics].
+type MetricsLabel string
+
+// Metrics labels
+const (
+	MetricsLabelMethod      MetricsLabel = "method"
+	MetricsLabelHost        MetricsLabel = "host"
+	MetricsLabelPath        MetricsLabel = "path"
+	MetricsLabelStatusCode  MetricsLabel = "status_code"
+	MetricsLabelStatusClass MetricsLabel = "status_class"
+	MetricsLabelName        MetricsLabel = "name"
+)
+
+// defaultMetricsBuckets is the same as Prometheus client default buckets
+var defaultMetricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics struct is a ready-made [MetricsCollector] implementation
+// that exposes the metrics in the Prometheus text exposition format, without
+// any third-party dependency. It implements [http.Handler], so it can be
+// served directly as the scrape endpoint.
+//
+//	pm := resty.NewPrometheusMetrics().
+//		SetLabels(resty.MetricsLabelMethod, resty.MetricsLabelStatusClass)
+//	client.SetMetricsCollector(pm)
+//	http.Handle("/metrics", pm)
+//
+// The exposed metrics are:
+//   - <namespace>_http_client_requests_total
+//   - <namespace>_http_client_retries_total
+//   - <namespace>_http_client_request_duration_seconds
+//   - <namespace>_http_client_dns_duration_seconds
+//   - <namespace>_http_client_connect_duration_seconds
+//   - <namespace>_http_client_tls_handshake_duration_seconds
+//   - <namespace>_http_client_circuit_breaker_state (0 closed, 1 open, 2 half-open)
+type PrometheusMetrics struct {
+	lock                *sync.Mutex
+	namespace           string
+	labels              []MetricsLabel
+	buckets             []float64
+	series              map[string]*prometheusSeries
+	circuitBreakerState string
+}
+
+// NewPrometheusMetrics method creates a new [PrometheusMetrics] with default settings.
+//
+// The default settings are:
+//   - Namespace: resty
+//   - Labels: method, host, status_class
+//   - Buckets: .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		lock:      &sync.Mutex{},
+		namespace: "resty",
+		labels:    []MetricsLabel{MetricsLabelMethod, MetricsLabelHost, MetricsLabelStatusClass},
+		buckets:   defaultMetricsBuckets,
+		series:    make(map[string]*prometheusSeries),
+	}
+}
+
+// SetNamespace method sets the metric name prefix.
+func (pm *PrometheusMetrics) SetNamespace(ns string) *PrometheusMetrics {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.namespace = ns
+	return pm
+}
+
+// SetLabels method sets the labels added to the metrics. Choose the labels
+// carefully to avoid the cardinality explosion, e.g., [MetricsLabelPath] and
+// [MetricsLabelStatusCode] produce more series. The [MetricsLabelName] is the
+// logical operation name, see [Request.SetName].
+//
+// NOTE: It resets the metrics collected so far.
+func (pm *PrometheusMetrics) SetLabels(labels ...MetricsLabel) *PrometheusMetrics {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.labels = labels
+	pm.series = make(map[string]*prometheusSeries)
+	return pm
+}
+
+// SetBuckets method sets the histogram buckets in seconds.
+//
+// NOTE: It resets the metrics collected so far.
+func (pm *PrometheusMetrics) SetBuckets(buckets ...float64) *PrometheusMetrics {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.buckets = slices.Sorted(slices.Values(buckets))
+	pm.series = make(map[string]*prometheusSeries)
+	return pm
+}
+
+// CollectRequestMetrics method records the given request metrics.
+func (pm *PrometheusMetrics) CollectRequestMetrics(m *RequestMetrics) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+
+	values := make([]string, len(pm.labels))
+	for i, l := range pm.labels {
+		values[i] = m.labelValue(l)
+	}
+	key := strings.Join(values, "\xff")
+	s, found := pm.series[key]
+	if !found {
+		s = &prometheusSeries{
+			labelValues:  values,
+			duration:     newPrometheusHistogram(pm.buckets),
+			dnsLookup:    newPrometheusHistogram(pm.buckets),
+			connect:      newPrometheusHistogram(pm.buckets),
+			tlsHandshake: newPrometheusHistogram(pm.buckets),
+		}
+		pm.series[key] = s
+	}
+
+	s.requests++
+	s.retries += uint64(m.Retries)
+	s.duration.observe(m.Duration)
+	if m.DNSLookup > 0 {
+		s.dnsLookup.observe(m.DNSLookup)
+	}
+	if m.TCPConnTime > 0 {
+		s.connect.observe(m.TCPConnTime)
+	}
+	if m.TLSHandshake > 0 {
+		s.tlsHandshake.observe(m.TLSHandshake)
+	}
+	if len(m.CircuitBreakerState) > 0 {
+		pm.circuitBreakerState = m.CircuitBreakerState
+	}
+}
+
+// WriteTo method writes the metrics in the Prometheus text exposition format
+// into the given writer.
+func (pm *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+
+	keys := make([]string, 0, len(pm.series))
+	for k := range pm.series {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	prefix := pm.namespace + "_http_client_"
+	if len(pm.namespace) == 0 {
+		prefix = "http_client_"
+	}
+
+	pm.writeHeader(bw, prefix+"requests_total", "counter", "Total number of HTTP client requests.")
+	for _, k := range keys {
+		s := pm.series[k]
+		fmt.Fprintf(bw, "%srequests_total%s %d\n", prefix, pm.formatLabels(s.labelValues, ""), s.requests)
+	}
+
+	pm.writeHeader(bw, prefix+"retries_total", "counter", "Total number of HTTP client request retries.")
+	for _, k := range keys {
+		s := pm.series[k]
+		fmt.Fprintf(bw, "%sretries_total%s %d\n", prefix, pm.formatLabels(s.labelValues, ""), s.retries)
+	}
+
+	histograms := []struct {
+		name string
+		help string
+		get  func(*prometheusSeries) *prometheusHistogram
+	}{
+		{"request_duration_seconds", "HTTP client request duration in seconds, including retries.",
+			func(s *prometheusSeries) *prometheusHistogram { return s.duration }},
+		{"dns_duration_seconds", "HTTP client DNS lookup duration in seconds.",
+			func(s *prometheusSeries) *prometheusHistogram { return s.dnsLookup }},
+		{"connect_duration_seconds", "HTTP client TCP connect duration in seconds.",
+			func(s *prometheusSeries) *prometheusHistogram { return s.connect }},
+		{"tls_handshake_duration_seconds", "HTTP client TLS handshake duration in seconds.",
+			func(s *prometheusSeries) *prometheusHistogram { return s.tlsHandshake }},
+	}
+	for _, h := range histograms {
+		name := prefix + h.name
+		pm.writeHeader(bw, name, "histogram", h.help)
+		for _, k := range keys {
+			s := pm.series[k]
+			pm.writeHistogram(bw, name, s.labelValues, h.get(s))
+		}
+	}
+
+	if len(pm.circuitBreakerState) > 0 {
+		state := 0
+		switch pm.circuitBreakerState {
+		case circuitBreakerStateOpen.String():
+			state = 1
+		case circuitBreakerStateHalfOpen.String():
+			state = 2
+		}
+		pm.writeHeader(bw, prefix+"circuit_breaker_state", "gauge",
+			"HTTP client circuit breaker state, 0 closed, 1 open, 2 half-open.")
+		fmt.Fprintf(bw, "%scircuit_breaker_state %d\n", prefix, state)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP method serves the metrics in the Prometheus text exposition format.
+func (pm *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(hdrContentTypeKey, "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = pm.WriteTo(w)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//_______________________________________________________________________
+
+type prometheusSeries struct {
+	labelValues  []string
+	requests     uint64
+	retries      uint64
+	duration     *prometheusHistogram
+	dnsLookup    *prometheusHistogram
+	connect      *prometheusHistogram
+	tlsHandshake *prometheusHistogram
+}
+
+type prometheusHistogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newPrometheusHistogram(buckets []float64) *prometheusHistogram {
+	return &prometheusHistogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *prometheusHistogram) observe(d time.Duration) {
+	v := d.Seconds()
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+func (pm *PrometheusMetrics) writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (pm *PrometheusMetrics) writeHistogram(w io.Writer, name string, values []string, h *prometheusHistogram) {
+	for i, b := range h.buckets {
+		le := strconv.FormatFloat(b, 'g', -1, 64)
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, pm.formatLabels(values, le), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, pm.formatLabels(values, "+Inf"), h.count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, pm.formatLabels(values, ""), strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, pm.formatLabels(values, ""), h.count)
+}
+
+func (pm *PrometheusMetrics) formatLabels(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, string(pm.labels[i])+`="`+escapePrometheusLabel(v)+`"`)
+	}
+	if len(le) > 0 {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapePrometheusLabel(v string) string {
+	return prometheusLabelReplacer.Replace(v)
+}
+
+func (m *RequestMetrics) labelValue(l MetricsLabel) string {
+	switch l {
+	case MetricsLabelMethod:
+		return m.Method
+	case MetricsLabelHost:
+		return m.Host
+	case MetricsLabelPath:
+		return m.Path
+	case MetricsLabelStatusCode:
+		if m.StatusCode == 0 {
+			return ""
+		}
+		return strconv.Itoa(m.StatusCode)
+	case MetricsLabelStatusClass:
+		return m.StatusClass
+	case MetricsLabelName:
+		return m.Name
+	}
+	return ""
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// collectMetrics method sends the request execution metrics to the metrics
+// collector, if one is set on the client.
+func (c *Client) collectMetrics(r *Request, res *Response, err error, urlTemplate string, startedAt time.Time) {
+	mc := c.MetricsCollector()
+	if mc == nil {
+		return
+	}
+
+	m := &RequestMetrics{
+		Name:        r.name,
+		Method:      r.Method,
+		Path:        metricsURLPath(urlTemplate),
+		StatusClass: "error",
+		Duration:    time.Since(startedAt),
+		Retries:     max(0, r.Attempt-1),
+		Err:         err,
+	}
+	if r.RawRequest != nil {
+		m.Host = r.RawRequest.URL.Host
+	}
+	if res != nil && res.RawResponse != nil {
+		m.StatusCode = res.StatusCode()
+		m.StatusClass = strconv.Itoa(m.StatusCode/100) + "xx"
+	}
+	if cb := c.circuitBreaker; cb != nil {
+		m.CircuitBreakerState = cb.getState().String()
+	}
+	if r.IsTrace {
+		ti := r.TraceInfo()
+		m.DNSLookup = ti.DNSLookup
+		m.TCPConnTime = ti.TCPConnTime
+		m.TLSHandshake = ti.TLSHandshake
+	}
+
+	mc.CollectRequestMetrics(m)
+}
+
+// metricsURLPath method returns the path of the given request URL template,
+// without scheme, host, and query string.
+func metricsURLPath(u string) string {
+	u, _, _ = strings.Cut(u, "?")
+	if _, rest, found := strings.Cut(u, "://"); found {
+		if idx := strings.IndexByte(rest, '/'); idx >= 0 {
+			return rest[idx:]
+		}
+		return "/"
+	}
+	if !strings.HasPrefix(u, "/") {
+		u = "/" + u
+	}
+	return u
+}
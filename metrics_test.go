@@ -0,0 +1,186 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetricsCollector struct {
+	lock    sync.Mutex
+	metrics []*RequestMetrics
+}
+
+func (tc *testMetricsCollector) CollectRequestMetrics(m *RequestMetrics) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.metrics = append(tc.metrics, m)
+}
+
+func TestClientMetricsCollector(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	tc := &testMetricsCollector{}
+	c := dcnl().
+		SetMetricsCollector(tc).
+		SetCircuitBreaker(NewCircuitBreaker().SetFailureThreshold(10)).
+		EnableTrace()
+	assertEqual(t, tc, c.MetricsCollector())
+
+	_, err := c.R().
+		SetPathParam("userId", "sample@sample.com").
+		Get(ts.URL + "/users/{userId}?q=1")
+	assertNil(t, err)
+
+	_, err = c.R().
+		SetRetryCount(2).
+		SetRetryWaitTime(time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Millisecond).
+		Get(ts.URL + "/fail")
+	assertNil(t, err)
+
+	assertEqual(t, 2, len(tc.metrics))
+
+	m := tc.metrics[0]
+	assertEqual(t, MethodGet, m.Method)
+	assertEqual(t, strings.TrimPrefix(ts.URL, "http://"), m.Host)
+	assertEqual(t, "/users/{userId}", m.Path)
+	assertEqual(t, http.StatusOK, m.StatusCode)
+	assertEqual(t, "2xx", m.StatusClass)
+	assertEqual(t, 0, m.Retries)
+	assertEqual(t, "closed", m.CircuitBreakerState)
+	assertEqual(t, true, m.Duration > 0)
+	assertEqual(t, true, m.TCPConnTime > 0)
+
+	m = tc.metrics[1]
+	assertEqual(t, "5xx", m.StatusClass)
+	assertEqual(t, 2, m.Retries)
+}
+
+func TestClientMetricsCollectorError(t *testing.T) {
+	tc := &testMetricsCollector{}
+	c := dcnl().SetMetricsCollector(tc)
+
+	_, err := c.R().Get("http://127.0.0.1:1/unreachable")
+	assertNotNil(t, err)
+	assertEqual(t, 1, len(tc.metrics))
+	assertEqual(t, "error", tc.metrics[0].StatusClass)
+	assertEqual(t, 0, tc.metrics[0].StatusCode)
+	assertNotNil(t, tc.metrics[0].Err)
+
+	// invalid requests are not collected
+	_, err = c.R().SetBody(make(chan int)).Post("http://127.0.0.1:1/invalid")
+	assertNotNil(t, err)
+	assertEqual(t, 1, len(tc.metrics))
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	pm := NewPrometheusMetrics().
+		SetNamespace("app").
+		SetLabels(MetricsLabelMethod, MetricsLabelPath, MetricsLabelStatusCode).
+		SetBuckets(1, 0.1)
+
+	pm.CollectRequestMetrics(&RequestMetrics{
+		Method:              MethodGet,
+		Path:                `/say/"hi"`,
+		StatusCode:          200,
+		Duration:            50 * time.Millisecond,
+		TCPConnTime:         2 * time.Second,
+		CircuitBreakerState: "open",
+	})
+	pm.CollectRequestMetrics(&RequestMetrics{
+		Method:   MethodGet,
+		Path:     `/say/"hi"`,
+		Retries:  2,
+		Duration: 500 * time.Millisecond,
+		Err:      errors.New("test"),
+	})
+
+	buf := &bytes.Buffer{}
+	n, err := pm.WriteTo(buf)
+	assertNil(t, err)
+	assertEqual(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	for _, expected := range []string{
+		"# TYPE app_http_client_requests_total counter\n",
+		`app_http_client_requests_total{method="GET",path="/say/\"hi\"",status_code="200"} 1` + "\n",
+		`app_http_client_requests_total{method="GET",path="/say/\"hi\"",status_code=""} 1` + "\n",
+		`app_http_client_retries_total{method="GET",path="/say/\"hi\"",status_code=""} 2` + "\n",
+		"# TYPE app_http_client_request_duration_seconds histogram\n",
+		`app_http_client_request_duration_seconds_bucket{method="GET",path="/say/\"hi\"",status_code="200",le="0.1"} 1` + "\n",
+		`app_http_client_request_duration_seconds_bucket{method="GET",path="/say/\"hi\"",status_code="",le="0.1"} 0` + "\n",
+		`app_http_client_request_duration_seconds_bucket{method="GET",path="/say/\"hi\"",status_code="",le="1"} 1` + "\n",
+		`app_http_client_request_duration_seconds_sum{method="GET",path="/say/\"hi\"",status_code=""} 0.5` + "\n",
+		`app_http_client_connect_duration_seconds_bucket{method="GET",path="/say/\"hi\"",status_code="200",le="+Inf"} 1` + "\n",
+		`app_http_client_connect_duration_seconds_count{method="GET",path="/say/\"hi\"",status_code="200"} 1` + "\n",
+		"app_http_client_circuit_breaker_state 1\n",
+	} {
+		assertEqual(t, true, strings.Contains(out, expected))
+	}
+
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(MethodGet, "/metrics", nil))
+	assertEqual(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get(hdrContentTypeKey))
+	assertEqual(t, out, rec.Body.String())
+}
+
+func TestMetricsURLPath(t *testing.T) {
+	assertEqual(t, "/users/{id}", metricsURLPath("https://example.com/users/{id}?a=b"))
+	assertEqual(t, "/", metricsURLPath("https://example.com"))
+	assertEqual(t, "/users", metricsURLPath("users"))
+	assertEqual(t, "/users", metricsURLPath("/users?x=1"))
+}
+
+func TestRequestName(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	tc := &testMetricsCollector{}
+	pm := NewPrometheusMetrics().SetLabels(MetricsLabelName, MetricsLabelMethod)
+	var dl *DebugLog
+	c, lb := dcldb()
+	c.SetMetricsCollector(tc).
+		EnableTrace().
+		OnDebugLog(func(d *DebugLog, _ *Response) { dl = d })
+	defer c.Close()
+
+	req := c.R().SetDebug(true).SetName("GetUser").SetPathParam("id", "1")
+	assertEqual(t, "GetUser", req.Name())
+	_, err := req.Get(ts.URL + "/users/{id}")
+	assertNil(t, err)
+
+	assertEqual(t, "GetUser", tc.metrics[0].Name)
+	pm.CollectRequestMetrics(tc.metrics[0])
+	buf := &bytes.Buffer{}
+	_, _ = pm.WriteTo(buf)
+	assertEqual(t, true, strings.Contains(buf.String(), `resty_http_client_requests_total{name="GetUser",method="GET"} 1`))
+
+	assertEqual(t, "GetUser", dl.Request.Name)
+	assertEqual(t, true, strings.Contains(lb.String(), "NAME   : GetUser"))
+	assertEqual(t, true, strings.Contains(DebugLogJSONFormatter(dl), `"name":"GetUser"`))
+
+	assertEqual(t, "GetUser", req.TraceInfo().Name)
+	for _, ti := range req.TraceInfos() {
+		assertEqual(t, "GetUser", ti.Name)
+	}
+}
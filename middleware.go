@@ -7,6 +7,7 @@ package resty
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime"
@@ -17,10 +18,139 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// Names of the Resty built-in middlewares, which can be referenced by
+// [Before] and [After], see [Client.AddRequestMiddlewareNamed] and
+// [Client.AddResponseMiddlewareNamed].
+const (
+	PrepareRequestMiddlewareName     = "prepare"
+	AutoParseResponseMiddlewareName  = "auto-parse"
+	SaveToFileResponseMiddlewareName = "save-to-file"
+)
+
+// MiddlewarePosition type represents the position of the named middleware in
+// the middleware chain, see [Before], [After], [First], and [Last].
+//
+// The zero value is the default position, i.e., the same as
+// [Client.AddRequestMiddleware] and [Client.AddResponseMiddleware].
+type MiddlewarePosition struct {
+	kind middlewarePositionKind
+	name string
+}
+
+type middlewarePositionKind uint8
+
+const (
+	middlewarePositionDefault middlewarePositionKind = iota
+	middlewarePositionFirst
+	middlewarePositionLast
+	middlewarePositionBefore
+	middlewarePositionAfter
+)
+
+// Before function returns the position just before the named middleware.
+//
+//	client.AddRequestMiddlewareNamed("auth", resty.Before(resty.PrepareRequestMiddlewareName), authMiddleware)
+func Before(name string) MiddlewarePosition {
+	return MiddlewarePosition{kind: middlewarePositionBefore, name: name}
+}
+
+// After function returns the position just after the named middleware.
+//
+//	client.AddResponseMiddlewareNamed("audit", resty.After(resty.AutoParseResponseMiddlewareName), auditMiddleware)
+func After(name string) MiddlewarePosition {
+	return MiddlewarePosition{kind: middlewarePositionAfter, name: name}
+}
+
+// First function returns the position at the beginning of the middleware chain.
+func First() MiddlewarePosition {
+	return MiddlewarePosition{kind: middlewarePositionFirst}
+}
+
+// Last function returns the position at the end of the middleware chain.
+//
+// NOTE: The request middleware added at the end of the request chain is
+// executed after [PrepareRequestMiddleware].
+func Last() MiddlewarePosition {
+	return MiddlewarePosition{kind: middlewarePositionLast}
+}
+
+type namedMiddleware[T any] struct {
+	name string
+	fn   T
+}
+
+var builtinMiddlewareNames = map[string]string{
+	functionName(PrepareRequestMiddleware):     PrepareRequestMiddlewareName,
+	functionName(AutoParseResponseMiddleware):  AutoParseResponseMiddlewareName,
+	functionName(SaveToFileResponseMiddleware): SaveToFileResponseMiddlewareName,
+}
+
+// newNamedMiddlewares wraps the given middlewares, the Resty built-in
+// middlewares get their names.
+func newNamedMiddlewares[T any](middlewares []T) []namedMiddleware[T] {
+	list := make([]namedMiddleware[T], 0, len(middlewares))
+	for _, m := range middlewares {
+		list = append(list, namedMiddleware[T]{name: builtinMiddlewareNames[functionName(m)], fn: m})
+	}
+	return list
+}
+
+// insertMiddleware returns the new middleware list with m inserted at the
+// given position; the existing middleware of the same name is replaced. It
+// returns `false` if the referenced middleware is not found, then m is
+// inserted at the default index.
+//
+// The given list is not modified, so the middleware chain in execution is
+// not affected.
+func insertMiddleware[T any](list []namedMiddleware[T], m namedMiddleware[T], pos MiddlewarePosition, defaultIdx func(list []namedMiddleware[T]) int) ([]namedMiddleware[T], bool) {
+	list = slices.Clone(list)
+	if len(m.name) > 0 {
+		list = slices.DeleteFunc(list, func(e namedMiddleware[T]) bool { return e.name == m.name })
+	}
+
+	idx, found := -1, true
+	switch pos.kind {
+	case middlewarePositionFirst:
+		idx = 0
+	case middlewarePositionLast:
+		idx = len(list)
+	case middlewarePositionBefore, middlewarePositionAfter:
+		idx = indexOfMiddleware(list, pos.name)
+		if idx == -1 {
+			found = false
+		} else if pos.kind == middlewarePositionAfter {
+			idx++
+		}
+	}
+	if idx == -1 {
+		idx = defaultIdx(list)
+	}
+	return slices.Insert(list, idx, m), found
+}
+
+func indexOfMiddleware[T any](list []namedMiddleware[T], name string) int {
+	return slices.IndexFunc(list, func(e namedMiddleware[T]) bool { return e.name == name })
+}
+
+func removeMiddlewares[T any](list []namedMiddleware[T], names []string) []namedMiddleware[T] {
+	return slices.DeleteFunc(slices.Clone(list), func(e namedMiddleware[T]) bool {
+		return len(e.name) > 0 && slices.Contains(names, e.name)
+	})
+}
+
+func middlewareNames[T any](list []namedMiddleware[T]) []string {
+	names := make([]string, 0, len(list))
+	for _, e := range list {
+		names = append(names, e.name)
+	}
+	return names
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Request Middleware(s)
 //_______________________________________________________________________
@@ -28,6 +158,10 @@ import (
 // PrepareRequestMiddleware method is used to prepare HTTP requests from
 // user provides request values. Request preparation fails if any error occurs
 func PrepareRequestMiddleware(c *Client, r *Request) (err error) {
+	if r.expectDigestErr != nil {
+		return &invalidRequestError{Err: r.expectDigestErr}
+	}
+
 	if err = parseRequestURL(c, r); err != nil {
 		return err
 	}
@@ -50,8 +184,34 @@ func PrepareRequestMiddleware(c *Client, r *Request) (err error) {
 	return nil
 }
 
+// RequestIDMiddleware method is used to generate or propagate the request ID
+// header, see [Client.SetRequestIDHeader]. If the request already has the
+// header, its value is propagated; otherwise, a new ID is generated using
+// [Client.SetRequestIDGenerator]. The same ID is kept across the retry attempts.
+//
+//	client.AddRequestMiddleware(resty.RequestIDMiddleware)
+//
+// The request ID is included in the debug log and is available via
+// [Request.RequestID] and [Response.RequestID], e.g., in the error hooks.
+func RequestIDMiddleware(c *Client, r *Request) error {
+	hdr := c.RequestIDHeader()
+	id := r.Header.Get(hdr)
+	if len(id) == 0 {
+		id = c.newRequestID()
+		r.Header.Set(hdr, id)
+	}
+	r.requestID = id
+	return nil
+}
+
 func parseRequestURL(c *Client, r *Request) error {
-	if len(c.PathParams())+len(r.PathParams) > 0 {
+	if len(r.uriTemplate) > 0 && (len(r.URL) == 0 || r.URL == r.uriTemplate) {
+		u, err := ExpandURITemplate(r.uriTemplate, r.uriTemplateVars)
+		if err != nil {
+			return &invalidRequestError{Err: err}
+		}
+		r.URL = u
+	} else if len(c.PathParams())+len(r.PathParams) > 0 {
 		// GitHub #103 Path Params, #663 Raw Path Params
 		for p, v := range c.PathParams() {
 			if _, ok := r.PathParams[p]; ok {
@@ -60,50 +220,7 @@ func parseRequestURL(c *Client, r *Request) error {
 			r.PathParams[p] = v
 		}
 
-		var prev int
-		buf := acquireBuffer()
-		defer releaseBuffer(buf)
-		// search for the next or first opened curly bracket
-		for curr := strings.Index(r.URL, "{"); curr == 0 || curr > prev; curr = prev + strings.Index(r.URL[prev:], "{") {
-			// write everything from the previous position up to the current
-			if curr > prev {
-				buf.WriteString(r.URL[prev:curr])
-			}
-			// search for the closed curly bracket from current position
-			next := curr + strings.Index(r.URL[curr:], "}")
-			// if not found, then write the remainder and exit
-			if next < curr {
-				buf.WriteString(r.URL[curr:])
-				prev = len(r.URL)
-				break
-			}
-			// special case for {}, without parameter's name
-			if next == curr+1 {
-				buf.WriteString("{}")
-			} else {
-				// check for the replacement
-				key := r.URL[curr+1 : next]
-				value, ok := r.PathParams[key]
-				// keep the original string if the replacement not found
-				if !ok {
-					value = r.URL[curr : next+1]
-				}
-				buf.WriteString(value)
-			}
-
-			// set the previous position after the closed curly bracket
-			prev = next + 1
-			if prev >= len(r.URL) {
-				break
-			}
-		}
-		if buf.Len() > 0 {
-			// write remainder
-			if prev < len(r.URL) {
-				buf.WriteString(r.URL[prev:])
-			}
-			r.URL = buf.String()
-		}
+		r.URL = expandPathParams(r.URL, r.PathParams)
 	}
 
 	// Parsing request URL
@@ -114,26 +231,38 @@ func parseRequestURL(c *Client, r *Request) error {
 
 	// If [Request.URL] is a relative path, then the following
 	// gets evaluated in the order
-	//	1. [Client.LoadBalancer] is used to obtain the base URL if not nil
-	//	2. [Client.BaseURL] is used to obtain the base URL
-	//	3. Otherwise [Request.URL] is used as-is
+	//	1. [Request.SetHost] is used as the base URL if set
+	//	2. [Client.LoadBalancer] is used to obtain the base URL if not nil
+	//	3. [Client.BaseURL] is used to obtain the base URL
+	//	4. Otherwise [Request.URL] is used as-is
 	if !reqURL.IsAbs() {
 		r.URL = reqURL.String()
 		if len(r.URL) > 0 && r.URL[0] != '/' {
 			r.URL = "/" + r.URL
 		}
 
-		if r.client.LoadBalancer() != nil {
-			r.baseURL, err = r.client.LoadBalancer().Next()
-			if err != nil {
-				return &invalidRequestError{Err: err}
+		lb := r.client.LoadBalancer()
+		switch {
+		case len(r.pinnedBaseURL) > 0:
+			r.baseURL, err = extractBaseURL(r.pinnedBaseURL)
+		case lb != nil:
+			if rlb, ok := lb.(RequestLoadBalancer); ok {
+				r.baseURL, err = rlb.NextRequest(r)
+			} else {
+				r.baseURL, err = lb.Next()
 			}
+			r.loadBalanced = err == nil
+		}
+		if err != nil {
+			return &invalidRequestError{Err: err}
 		}
 
 		reqURL, err = url.Parse(r.baseURL + r.URL)
 		if err != nil {
 			return &invalidRequestError{Err: err}
 		}
+	} else {
+		r.baseURL = ""
 	}
 
 	// GH #407 && #318
@@ -141,23 +270,57 @@ func parseRequestURL(c *Client, r *Request) error {
 		reqURL.Scheme = c.Scheme()
 	}
 
+	// Adding host scope Query Param, see [Client.SetQueryParamForHost]; it is
+	// not merged into the request, the retry attempt may go to another host
+	hostQueryParams := url.Values{}
+	for _, d := range c.matchingHostDefaults(reqURL) {
+		for k, v := range d.queryParams {
+			if _, ok := r.QueryParams[k]; ok {
+				continue
+			}
+			if _, ok := hostQueryParams[k]; ok {
+				continue
+			}
+			hostQueryParams[k] = v[:]
+		}
+	}
+
+	// Raw query replaces the URL query string as-is, see [Request.SetRawQuery]
+	if len(r.rawQuery) > 0 {
+		reqURL.RawQuery = r.rawQuery
+	}
+
 	// Adding Query Param
-	if len(c.QueryParams())+len(r.QueryParams) > 0 {
+	if len(c.QueryParams())+len(r.QueryParams)+len(hostQueryParams)+len(r.orderedQueryParams) > 0 {
 		for k, v := range c.QueryParams() {
 			if _, ok := r.QueryParams[k]; ok {
 				continue
 			}
+			if _, ok := hostQueryParams[k]; ok {
+				continue
+			}
 			r.QueryParams[k] = v[:]
 		}
 
+		queryParams := r.QueryParams
+		if len(hostQueryParams) > 0 {
+			queryParams = make(url.Values, len(r.QueryParams)+len(hostQueryParams))
+			for k, v := range r.QueryParams {
+				queryParams[k] = v
+			}
+			for k, v := range hostQueryParams {
+				queryParams[k] = v
+			}
+		}
+
 		// GitHub #123 Preserve query string order partially.
 		// Since not feasible in `SetQuery*` resty methods, because
 		// standard package `url.Encode(...)` sorts the query params
-		// alphabetically
+		// alphabetically; use [Request.AddQueryParam] for the exact order
 		if isStringEmpty(reqURL.RawQuery) {
-			reqURL.RawQuery = r.QueryParams.Encode()
+			reqURL.RawQuery = r.encodeQuery(queryParams)
 		} else {
-			reqURL.RawQuery = reqURL.RawQuery + "&" + r.QueryParams.Encode()
+			reqURL.RawQuery = reqURL.RawQuery + "&" + r.encodeQuery(queryParams)
 		}
 	}
 
@@ -176,6 +339,18 @@ func parseRequestURL(c *Client, r *Request) error {
 }
 
 func parseRequestHeader(c *Client, r *Request) error {
+	// host scope headers, see [Client.SetHeaderForHost]
+	if reqURL, err := url.Parse(r.URL); err == nil {
+		for _, d := range c.matchingHostDefaults(reqURL) {
+			for k, v := range d.header {
+				if _, ok := r.Header[k]; ok {
+					continue
+				}
+				r.Header[k] = v[:]
+			}
+		}
+	}
+
 	for k, v := range c.Header() {
 		if _, ok := r.Header[k]; ok {
 			continue
@@ -191,6 +366,10 @@ func parseRequestHeader(c *Client, r *Request) error {
 		r.Header.Set(hdrAcceptEncodingKey, r.client.ContentDecompresserKeys())
 	}
 
+	if !r.isHeaderExists(hdrAcceptKey) && isProtoMessage(r.Result) {
+		r.Header.Set(hdrAcceptKey, protobufContentType)
+	}
+
 	return nil
 }
 
@@ -202,6 +381,10 @@ func parseRequestBody(c *Client, r *Request) error {
 
 	if r.isPayloadSupported() {
 		switch {
+		case r.soap != nil: // Handling SOAP envelope
+			if err := handleSOAP(c, r); err != nil {
+				return &invalidRequestError{Err: err}
+			}
 		case r.isMultiPart: // Handling Multipart
 			if err := handleMultipart(c, r); err != nil {
 				return &invalidRequestError{Err: err}
@@ -213,19 +396,25 @@ func parseRequestBody(c *Client, r *Request) error {
 				return &invalidRequestError{Err: err}
 			}
 		}
+
+		if err := handleContentEncoding(c, r); err != nil {
+			return &invalidRequestError{Err: err}
+		}
 	} else {
 		r.Body = nil // if the payload is not supported by HTTP verb, set explicit nil
 	}
 
 	// by default resty won't set content length, but user can opt-in
 	if r.setContentLength {
-		cntLen := 0
+		cntLen := int64(0)
 		if r.bodyBuf != nil {
-			cntLen = r.bodyBuf.Len()
+			cntLen = int64(r.bodyBuf.Len())
 		} else if b, ok := r.Body.(*bytes.Reader); ok {
-			cntLen = b.Len()
+			cntLen = int64(b.Len())
+		} else if r.multipartLength > 0 {
+			cntLen = r.multipartLength
 		}
-		r.Header.Set(hdrContentLengthKey, strconv.Itoa(cntLen))
+		r.Header.Set(hdrContentLengthKey, strconv.FormatInt(cntLen, 10))
 	}
 
 	return nil
@@ -235,23 +424,28 @@ func createRawRequest(c *Client, r *Request) (err error) {
 	// init client trace if enabled
 	r.initTraceIfEnabled()
 
+	// the trace hooks are bound to the raw request of the current attempt
+	// only; so they do not pile up on the request context across the retries
+	r.connInfo = nil
+	ctx := r.withTraceContext(r.Context())
+	ctx = context.WithValue(ctx, requestContextKey{}, r)
 	if r.bodyBuf == nil {
 		if reader, ok := r.Body.(io.Reader); ok {
-			r.RawRequest, err = http.NewRequestWithContext(r.Context(), r.Method, r.URL, reader)
+			r.RawRequest, err = http.NewRequestWithContext(ctx, r.Method, r.URL, reader)
+			if err == nil && r.multipartLength > 0 {
+				r.RawRequest.ContentLength = r.multipartLength
+			}
 		} else {
-			r.RawRequest, err = http.NewRequestWithContext(r.Context(), r.Method, r.URL, nil)
+			r.RawRequest, err = http.NewRequestWithContext(ctx, r.Method, r.URL, nil)
 		}
 	} else {
-		r.RawRequest, err = http.NewRequestWithContext(r.Context(), r.Method, r.URL, r.bodyBuf)
+		r.RawRequest, err = http.NewRequestWithContext(ctx, r.Method, r.URL, r.bodyBuf)
 	}
 
 	if err != nil {
 		return &invalidRequestError{Err: err}
 	}
 
-	// get the context reference back from underlying RawRequest
-	r.ctx = r.RawRequest.Context()
-
 	// Assign close connection option
 	r.RawRequest.Close = r.CloseConnection
 
@@ -307,9 +501,9 @@ func handleMultipart(c *Client, r *Request) error {
 		r.bodyBuf = acquireBuffer()
 		mw := multipart.NewWriter(r.bodyBuf)
 
-		// set boundary if it is provided by the user
-		if !isStringEmpty(r.multipartBoundary) {
-			if err := mw.SetBoundary(r.multipartBoundary); err != nil {
+		// set boundary if it is provided by the user or the random source
+		if boundary := c.multipartBoundary(r.multipartBoundary); !isStringEmpty(boundary) {
+			if err := mw.SetBoundary(boundary); err != nil {
 				return err
 			}
 		}
@@ -318,7 +512,7 @@ func handleMultipart(c *Client, r *Request) error {
 			return err
 		}
 
-		r.Header.Set(hdrContentTypeKey, mw.FormDataContentType())
+		r.Header.Set(hdrContentTypeKey, multipartContentType(r.multipartContentType, mw))
 		closeq(mw)
 
 		return nil
@@ -330,13 +524,21 @@ func handleMultipart(c *Client, r *Request) error {
 	r.Body = bodyReader
 	r.multipartErrChan = make(chan error, 1)
 
-	// set boundary if it is provided by the user
-	if !isStringEmpty(r.multipartBoundary) {
-		if err := mw.SetBoundary(r.multipartBoundary); err != nil {
+	// set boundary if it is provided by the user or the random source
+	if boundary := c.multipartBoundary(r.multipartBoundary); !isStringEmpty(boundary) {
+		if err := mw.SetBoundary(boundary); err != nil {
 			return err
 		}
 	}
 
+	// stream with the known length if possible, otherwise chunked encoding
+	r.multipartLength = 0
+	if isStringEmpty(r.contentEncoding) {
+		if n, ok := multipartLength(mw.Boundary(), r); ok {
+			r.multipartLength = n
+		}
+	}
+
 	go func() {
 		defer close(r.multipartErrChan)
 		if err := createMultipart(mw, r); err != nil {
@@ -346,7 +548,7 @@ func handleMultipart(c *Client, r *Request) error {
 		closeq(bodyWriter)
 	}()
 
-	r.Header.Set(hdrContentTypeKey, mw.FormDataContentType())
+	r.Header.Set(hdrContentTypeKey, multipartContentType(r.multipartContentType, mw))
 	return nil
 }
 
@@ -358,11 +560,33 @@ func createMultipart(w *multipart.Writer, r *Request) error {
 	if err := r.writeFormData(w); err != nil {
 		return err
 	}
+	return writeMultipartFields(w, r.multipartFields, r.multipartProgressFn, r.client.randSource())
+}
+
+func writeMultipartFields(w *multipart.Writer, fields []*MultipartField, progressFn func(string, int64, int64), rnd *lockedRand) error {
+	for _, mf := range fields {
+		if len(mf.Parts) > 0 {
+			if err := writeNestedMultipart(w, mf, progressFn, rnd); err != nil {
+				return err
+			}
+			continue
+		}
 
-	for _, mf := range r.multipartFields {
 		if len(mf.Values) > 0 {
 			for _, v := range mf.Values {
-				w.WriteField(mf.Name, v)
+				if len(mf.Header) == 0 && isStringEmpty(mf.ContentType) {
+					if err := w.WriteField(mf.Name, v); err != nil {
+						return err
+					}
+					continue
+				}
+				partWriter, err := mpCreatePart(w, mf.createHeader())
+				if err != nil {
+					return err
+				}
+				if _, err = io.WriteString(partWriter, v); err != nil {
+					return err
+				}
 			}
 			continue
 		}
@@ -387,6 +611,7 @@ func createMultipart(w *multipart.Writer, r *Request) error {
 		}
 
 		partWriter = mf.wrapProgressCallbackIfPresent(partWriter)
+		partWriter = mf.wrapPartProgressIfPresent(partWriter, progressFn)
 		partWriter.Write(p[:size])
 
 		if _, err = ioCopy(partWriter, mf.Reader); err != nil {
@@ -397,6 +622,41 @@ func createMultipart(w *multipart.Writer, r *Request) error {
 	return nil
 }
 
+// writeNestedMultipart function writes the nested multipart content of the
+// given field, e.g., `multipart/related`, `multipart/mixed`.
+func writeNestedMultipart(w *multipart.Writer, mf *MultipartField, progressFn func(string, int64, int64), rnd *lockedRand) error {
+	ct := mf.ContentType
+	if isStringEmpty(ct) {
+		ct = "multipart/mixed"
+	}
+
+	// boundary is required in the part header, so it's buffered
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	nw := multipart.NewWriter(buf)
+	if rnd != nil {
+		if err := nw.SetBoundary(rnd.hex(30)); err != nil {
+			return err
+		}
+	}
+	if err := writeMultipartFields(nw, mf.Parts, progressFn, rnd); err != nil {
+		return err
+	}
+	if err := nw.Close(); err != nil {
+		return err
+	}
+
+	h := mf.createHeader()
+	h.Set(hdrContentTypeKey, multipartContentType(ct, nw))
+	partWriter, err := mpCreatePart(w, h)
+	if err != nil {
+		return err
+	}
+	partWriter = mf.wrapProgressCallbackIfPresent(partWriter)
+	_, err = partWriter.Write(buf.Bytes())
+	return err
+}
+
 func handleFormData(c *Client, r *Request) {
 	for k, v := range c.FormData() {
 		if _, ok := r.FormData[k]; ok {
@@ -451,7 +711,7 @@ func handleRequestBody(c *Client, r *Request) error {
 	default:
 		encKey := inferContentTypeMapKey(contentType)
 		if jsonKey == encKey {
-			if !r.jsonEscapeHTML {
+			if !r.jsonEscapeHTML && c.jsonMarshaler() == nil {
 				return encodeJSONEscapeHTML(r.bodyBuf, r.Body, r.jsonEscapeHTML)
 			}
 		} else if xmlKey == encKey {
@@ -479,6 +739,58 @@ func handleRequestBody(c *Client, r *Request) error {
 	return nil
 }
 
+func handleContentEncoding(c *Client, r *Request) error {
+	if len(r.contentEncoding) == 0 {
+		return nil
+	}
+	compresser, found := c.ContentCompressers()[r.contentEncoding]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrContentCompresserNotFound, r.contentEncoding)
+	}
+
+	switch body := r.Body.(type) {
+	default:
+		if r.bodyBuf == nil {
+			return nil // no content
+		}
+	case io.Reader:
+		if r.bodyBuf != nil {
+			break
+		}
+		if r.isMultiPart {
+			// compress the multipart streaming body on the fly
+			pr, pw := io.Pipe()
+			go func() {
+				err := compressTo(pw, body, compresser)
+				closeq(body)
+				_ = pw.CloseWithError(err)
+			}()
+			r.Body = pr
+			r.Header.Set(hdrContentEncodingKey, r.contentEncoding)
+			return nil
+		}
+		r.bodyBuf = acquireBuffer()
+		if err := compressTo(r.bodyBuf, body, compresser); err != nil {
+			releaseBuffer(r.bodyBuf)
+			r.bodyBuf = nil
+			return err
+		}
+		r.Header.Set(hdrContentEncodingKey, r.contentEncoding)
+		return nil
+	}
+
+	buf := acquireBuffer()
+	if err := compressTo(buf, r.bodyBuf, compresser); err != nil {
+		releaseBuffer(buf)
+		return err
+	}
+	releaseBuffer(r.bodyBuf)
+	r.bodyBuf = buf
+
+	r.Header.Set(hdrContentEncodingKey, r.contentEncoding)
+	return nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Response Middleware(s)
 //_______________________________________________________________________
@@ -487,10 +799,14 @@ func handleRequestBody(c *Client, r *Request) error {
 // based on registered HTTP response `Content-Type` decoder, see [Client.AddContentTypeDecoder];
 // if [Request.SetResult], [Request.SetError], or [Client.SetError] is used
 func AutoParseResponseMiddleware(c *Client, res *Response) (err error) {
-	if res.Err != nil || res.Request.DoNotParseResponse {
+	if res.Err != nil {
 		return // move on
 	}
 
+	if err = res.verifyContentType(); err != nil || res.Request.DoNotParseResponse {
+		return
+	}
+
 	if res.StatusCode() == http.StatusNoContent {
 		res.Request.Error = nil
 		return
@@ -500,8 +816,13 @@ func AutoParseResponseMiddleware(c *Client, res *Response) (err error) {
 		res.Request.ForceResponseContentType,
 		res.Header().Get(hdrContentTypeKey),
 		res.Request.ExpectResponseContentType,
+		res.sniffedContentType,
 	)
 	decKey := inferContentTypeMapKey(rct)
+	if res.Request.soap != nil && decKey == xmlKey {
+		return res.parseSOAPEnvelope()
+	}
+
 	decFunc, found := c.inferContentTypeDecoder(rct, decKey)
 	if !found {
 		// the Content-Type decoder is not found; just read all the body bytes
@@ -509,17 +830,41 @@ func AutoParseResponseMiddleware(c *Client, res *Response) (err error) {
 		return
 	}
 
+	// HTTP status code > 199 and < 300, JSON array decoded incrementally
+	if res.IsSuccess() && res.Request.jsonArrayElementFn != nil && decKey == jsonKey {
+		res.Request.Error = nil
+		defer closeq(res.Body)
+		err = decodeJSONArray(res.Body, res.Request.jsonArrayElement, res.Request.jsonArrayElementFn)
+		err = res.drainIfRequired(err)
+		res.IsRead = true
+		return
+	}
+
 	// HTTP status code > 199 and < 300, considered as Result
 	if res.IsSuccess() && res.Request.Result != nil {
 		res.Request.Error = nil
 		defer closeq(res.Body)
 		err = decFunc(res.Body, res.Request.Result)
+		err = res.drainIfRequired(err)
 		res.IsRead = true
 		return
 	}
 
 	// HTTP status code > 399, considered as Error
 	if res.IsError() {
+		// error type registered for the status code
+		if et, found := res.Request.errorTypeFor(res.StatusCode()); found {
+			res.Request.Error = reflect.New(et).Interface()
+			defer closeq(res.Body)
+			err = decFunc(res.Body, res.Request.Error)
+			err = res.drainIfRequired(err)
+			res.IsRead = true
+			if err == nil {
+				err = newHTTPError(res)
+			}
+			return
+		}
+
 		// global error type registered at client-instance
 		if res.Request.Error == nil {
 			res.Request.Error = c.newErrorInterface()
@@ -528,11 +873,17 @@ func AutoParseResponseMiddleware(c *Client, res *Response) (err error) {
 		if res.Request.Error != nil {
 			defer closeq(res.Body)
 			err = decFunc(res.Body, res.Request.Error)
+			err = res.drainIfRequired(err)
 			res.IsRead = true
 			return
 		}
 	}
 
+	if res.readToEOF && !res.Request.IsSaveResponse {
+		// the body is not decoded nor saved; read it till the end
+		err = res.readAll()
+	}
+
 	return
 }
 
@@ -575,18 +926,17 @@ func SaveToFileResponseMiddleware(c *Client, res *Response) error {
 		return err
 	}
 
-	outFile, err := createFile(file)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		closeq(outFile)
-		closeq(res.Body)
-	}()
+	defer closeq(res.Body)
 
-	// io.Copy reads maximum 32kb size, it is perfect for large file download too
-	res.size, err = ioCopy(outFile, res.Body)
+	body := io.Reader(res.Body)
+	if fn := res.Request.downloadProgressFn; fn != nil {
+		pt := newProgressTracker(fn, 0, res.RawResponse.ContentLength)
+		body = &countReadCloser{r: res.Body, f: pt.add}
+	}
 
+	// the body is written into the temp file and renamed on success; the
+	// corrupted file, e.g., digest mismatch, never reaches the destination
+	var err error
+	res.size, err = writeFileAtomic(file, body, res.Request.outputFileSync)
 	return err
 }
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_parseRequestURL(t *testing.T) {
@@ -868,12 +869,12 @@ func TestMiddlewareSaveToFileErrorCases(t *testing.T) {
 		return errors.New(errDirMsg)
 	}
 	errFileMsg := "test file error"
-	createFile = func(_ string) (*os.File, error) {
+	createTempFile = func(_, _ string) (*os.File, error) {
 		return nil, errors.New(errFileMsg)
 	}
 	t.Cleanup(func() {
 		mkdirAll = os.MkdirAll
-		createFile = os.Create
+		createTempFile = os.CreateTemp
 	})
 
 	// dir create error
@@ -908,6 +909,75 @@ func TestMiddlewareSaveToFileCopyError(t *testing.T) {
 	assertEqual(t, errCopyMsg, err1.Error())
 }
 
+func TestMiddlewareSaveToFileAtomic(t *testing.T) {
+	c := dcnl()
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "sample.txt")
+	assertNil(t, os.WriteFile(file, []byte("previous content"), 0o600))
+
+	ioCopy = func(dst io.Writer, src io.Reader) (written int64, err error) {
+		n, _ := io.CopyN(dst, src, 4)
+		return n, io.ErrUnexpectedEOF
+	}
+	t.Cleanup(func() {
+		ioCopy = io.Copy
+	})
+
+	// interrupted write, the existing file is untouched and no temp file is left
+	req := c.R().SetOutputFileName(file)
+	err := SaveToFileResponseMiddleware(c, &Response{Request: req, Body: io.NopCloser(bytes.NewBufferString("Test context"))})
+	assertErrorIs(t, io.ErrUnexpectedEOF, err)
+
+	b, _ := os.ReadFile(file)
+	assertEqual(t, "previous content", string(b))
+	entries, _ := os.ReadDir(tempDir)
+	assertEqual(t, 1, len(entries))
+
+	// successful write replaces the file and keeps its mode
+	ioCopy = io.Copy
+	req = c.R().SetOutputFileName(file).SetOutputFileSync(true)
+	err = SaveToFileResponseMiddleware(c, &Response{Request: req, Body: io.NopCloser(bytes.NewBufferString("Test context"))})
+	assertNil(t, err)
+
+	b, _ = os.ReadFile(file)
+	assertEqual(t, "Test context", string(b))
+	fi, _ := os.Stat(file)
+	assertEqual(t, os.FileMode(0o600), fi.Mode().Perm())
+	entries, _ = os.ReadDir(tempDir)
+	assertEqual(t, 1, len(entries))
+}
+
+func TestResponseSaveToFile(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c := dcnl().SetOutputFileSync(true)
+	tempDir := t.TempDir()
+
+	t.Run("read body", func(t *testing.T) {
+		res, err := c.R().Get(ts.URL + "/")
+		assertNil(t, err)
+
+		file := filepath.Join(tempDir, "read", "body.txt")
+		assertNil(t, res.SaveToFile(file))
+		b, _ := os.ReadFile(file)
+		assertEqual(t, "TestGet: text response", string(b))
+
+		// body is still available
+		assertEqual(t, "TestGet: text response", res.String())
+	})
+
+	t.Run("stream body", func(t *testing.T) {
+		res, err := c.R().SetDoNotParseResponse(true).Get(ts.URL + "/")
+		assertNil(t, err)
+
+		file := filepath.Join(tempDir, "stream.txt")
+		assertNil(t, res.SaveToFile(file))
+		b, _ := os.ReadFile(file)
+		assertEqual(t, "TestGet: text response", string(b))
+	})
+}
+
 func TestRequestURL_GH797(t *testing.T) {
 	ts := createGetServer(t)
 	defer ts.Close()
@@ -936,3 +1006,158 @@ func TestMiddlewareCoverage(t *testing.T) {
 	err1 := createRawRequest(c, req1)
 	assertEqual(t, true, strings.Contains(err1.Error(), "invalid character"))
 }
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen []string
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-Correlation-Id"))
+		if len(seen) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	c, logBuf := dcldb()
+	c.AddRequestMiddleware(RequestIDMiddleware).
+		SetRequestIDHeader("X-Correlation-ID").
+		SetRequestIDGenerator(func() string { return "generated-id" }).
+		SetRetryCount(1).
+		SetRetryWaitTime(time.Millisecond)
+	assertEqual(t, "X-Correlation-Id", c.RequestIDHeader())
+
+	res, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, []string{"generated-id", "generated-id"}, seen)
+	assertEqual(t, "generated-id", res.Request.RequestID())
+	assertEqual(t, "generated-id", res.RequestID())
+	assertEqual(t, true, strings.Contains(logBuf.String(), "REQ ID : generated-id"))
+
+	// propagate the existing ID
+	seen = seen[:1]
+	res, err = c.R().SetHeader("X-Correlation-ID", "upstream-id").Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "upstream-id", seen[1])
+	assertEqual(t, "upstream-id", res.RequestID())
+}
+
+func TestResponseRequestIDFromHeader(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrRequestIDKey, "server-id")
+	})
+	defer ts.Close()
+
+	res, err := dcnl().R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, "", res.Request.RequestID())
+	assertEqual(t, "server-id", res.RequestID())
+
+	res = &Response{RawResponse: &http.Response{Header: http.Header{hdrRequestIDKey: {"server-id"}}}}
+	assertEqual(t, "", res.RequestID())
+}
+
+func TestRequestSetContentEncoding(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var body io.ReadCloser = r.Body
+		switch r.Header.Get(hdrContentEncodingKey) {
+		case "gzip":
+			body, _ = decompressGzip(r.Body)
+		case "deflate":
+			body, _ = decompressDeflate(r.Body)
+		case "zstd":
+			body, _ = decompressZstd(r.Body)
+		}
+		defer closeq(body)
+		b, _ := io.ReadAll(body)
+		w.Header().Set("X-Content-Encoding", r.Header.Get(hdrContentEncodingKey))
+		_, _ = w.Write(b)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+
+	t.Run("gzip json body", func(t *testing.T) {
+		res, err := c.R().
+			SetContentEncoding("gzip").
+			SetBody(map[string]string{"name": "resty"}).
+			Post(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "gzip", res.Header().Get("X-Content-Encoding"))
+		assertEqual(t, `{"name":"resty"}`, res.String())
+	})
+
+	t.Run("deflate reader body with retry", func(t *testing.T) {
+		attempts := 0
+		res, err := c.R().
+			SetContentEncoding("deflate").
+			SetBody(strings.NewReader(strings.Repeat("resty ", 50))).
+			SetRetryCount(1).
+			SetRetryWaitTime(time.Millisecond).
+			AddRetryConditions(func(*Response, error) bool {
+				attempts++
+				return attempts == 1
+			}).
+			Put(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, 2, res.Request.Attempt)
+		assertEqual(t, "deflate", res.Header().Get("X-Content-Encoding"))
+		assertEqual(t, strings.Repeat("resty ", 50), string(res.Bytes()))
+	})
+
+	t.Run("gzip form data", func(t *testing.T) {
+		res, err := c.R().
+			SetContentEncoding("gzip").
+			SetFormData(map[string]string{"a": "b"}).
+			Post(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "a=b", res.String())
+	})
+
+	t.Run("gzip multipart streaming", func(t *testing.T) {
+		res, err := c.R().
+			SetContentEncoding("gzip").
+			SetMultipartField("file", "hello.txt", plainTextType, strings.NewReader("hello resty")).
+			Post(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "gzip", res.Header().Get("X-Content-Encoding"))
+		assertEqual(t, true, strings.Contains(res.String(), "hello resty"))
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		res, err := c.R().SetContentEncoding("gzip").Get(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "", res.Header().Get("X-Content-Encoding"))
+	})
+
+	t.Run("custom compresser", func(t *testing.T) {
+		c := dcnl().AddContentCompresser("identity", func(w io.Writer) (io.WriteCloser, error) {
+			return nopWriteCloser{w}, nil
+		})
+		res, err := c.R().SetContentEncoding("identity").SetBody("plain").Post(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "identity", res.Header().Get("X-Content-Encoding"))
+		assertEqual(t, "plain", res.String())
+	})
+
+	t.Run("zstd json body", func(t *testing.T) {
+		res, err := c.R().
+			SetContentEncoding("zstd").
+			SetBody(map[string]string{"name": "resty"}).
+			Post(ts.URL)
+		assertNil(t, err)
+		assertEqual(t, "zstd", res.Header().Get("X-Content-Encoding"))
+		assertEqual(t, `{"name":"resty"}`, res.String())
+	})
+
+	t.Run("compresser not found", func(t *testing.T) {
+		_, err := c.R().SetContentEncoding("br").SetBody("data").Post(ts.URL)
+		assertErrorIs(t, ErrContentCompresserNotFound, err)
+	})
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,412 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMockNotMatched error returned by the [MockTransport] when no mock route
+// matches the request.
+var ErrMockNotMatched = errors.New("resty: no mock route matched")
+
+// NewMockTransport function creates the new mock transport to stub the HTTP
+// responses in the unit tests, without the test server. It is set on the client
+// via [Client.SetTransport].
+//
+//	mt := resty.NewMockTransport()
+//	mt.On(http.MethodGet, "https://api.example.com/users/{id}").
+//		MatchHeader("Authorization", "Bearer token").
+//		RespondJSON(http.StatusOK, map[string]any{"id": 1, "name": "Jeeva"})
+//
+//	client := resty.New().SetTransport(mt)
+//	defer client.Close()
+//
+//	// ... test code ...
+//
+//	mt.AssertExpectations(t)
+func NewMockTransport() *MockTransport {
+	return &MockTransport{lock: new(sync.Mutex)}
+}
+
+var _ http.RoundTripper = (*MockTransport)(nil)
+
+// MockTransport struct is the [http.RoundTripper] that serves the responses of
+// the matching mock routes in the order of the registration. The request
+// not matched by any route fails with [ErrMockNotMatched], or panics, see
+// [MockTransport.SetPanicOnUnmatched].
+type MockTransport struct {
+	lock             *sync.Mutex
+	routes           []*MockRoute
+	calls            []*MockCall
+	panicOnUnmatched bool
+}
+
+// MockCall struct holds the request served by the [MockTransport], the request
+// body is read into the Body. The Route is nil if the request is not matched.
+type MockCall struct {
+	Request *http.Request
+	Body    []byte
+	Route   *MockRoute
+}
+
+// MockT is the interface that wraps the methods of the [testing.T] used by
+// the [MockTransport] assertions.
+type MockT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// On method registers the new mock route for the given method and URL pattern,
+// the empty method or `*` matches any method. The pattern matches the URL
+// without the query, e.g., `https://api.example.com/users/{id}`; or only its
+// path if the pattern does not have the scheme, e.g., `/users/*`. The `{name}`
+// matches a path segment, and the `*` matches any characters.
+func (mt *MockTransport) On(method, urlPattern string) *MockRoute {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+	route := &MockRoute{
+		method:     strings.ToUpper(method),
+		urlPattern: urlPattern,
+		urlRegexp:  compileMockPattern(urlPattern),
+		lock:       mt.lock,
+	}
+	mt.routes = append(mt.routes, route)
+	return route
+}
+
+// SetPanicOnUnmatched method makes the transport panic on the request not
+// matched by any route, instead of returning the [ErrMockNotMatched] error; it
+// surfaces the unexpected requests even if the code under test ignores the errors.
+func (mt *MockTransport) SetPanicOnUnmatched(p bool) *MockTransport {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+	mt.panicOnUnmatched = p
+	return mt
+}
+
+// Calls method returns the requests served by the transport so far, in the order.
+func (mt *MockTransport) Calls() []*MockCall {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+	return append([]*MockCall{}, mt.calls...)
+}
+
+// Reset method removes the routes and the calls from the transport.
+func (mt *MockTransport) Reset() {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+	mt.routes, mt.calls = nil, nil
+}
+
+// AssertExpectations method asserts that all the routes are called as expected,
+// i.e., exactly the [MockRoute.Times], otherwise at least once, and no request
+// is unmatched. It returns true if all the expectations are met.
+func (mt *MockTransport) AssertExpectations(t MockT) bool {
+	t.Helper()
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+
+	ok := true
+	for _, route := range mt.routes {
+		switch {
+		case route.times > 0 && route.calls != route.times:
+			t.Errorf("resty: mock route %s expected %d call(s), got %d", route, route.times, route.calls)
+			ok = false
+		case route.times == 0 && route.calls == 0:
+			t.Errorf("resty: mock route %s expected to be called", route)
+			ok = false
+		}
+	}
+	for _, call := range mt.calls {
+		if call.Route == nil {
+			t.Errorf("resty: unmatched request %s %s", call.Request.Method, call.Request.URL)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// RoundTrip method implements the [http.RoundTripper] interface.
+func (mt *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := bufferRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	mt.lock.Lock()
+	call := &MockCall{Request: req, Body: body}
+	mt.calls = append(mt.calls, call)
+	var res *MockResponse
+	var responder func(*http.Request) (*http.Response, error)
+	for _, route := range mt.routes {
+		if route.match(req, body) {
+			call.Route = route
+			res, responder = route.next()
+			break
+		}
+	}
+	panicOnUnmatched := mt.panicOnUnmatched
+	mt.lock.Unlock()
+
+	if call.Route == nil {
+		err := fmt.Errorf("%w: %s %s", ErrMockNotMatched, req.Method, req.URL)
+		if panicOnUnmatched {
+			panic(err)
+		}
+		return nil, err
+	}
+	if responder != nil {
+		return responder(req)
+	}
+	return res.response(req)
+}
+
+// MockRoute struct is the mock route of the [MockTransport], it is created via
+// [MockTransport.On]. The responses are served in the order, and the last one
+// is repeated, e.g., to stub the failures before the success.
+//
+//	mt.On(http.MethodPost, "/orders").
+//		MatchBodyString(`{"sku":"A1"}`).
+//		Respond(&resty.MockResponse{StatusCode: http.StatusServiceUnavailable}).
+//		RespondString(http.StatusCreated, `{"id":"o-1"}`)
+type MockRoute struct {
+	method     string
+	urlPattern string
+	urlRegexp  *regexp.Regexp
+	headers    http.Header
+	query      map[string]string
+	bodyFn     func([]byte) bool
+	responses  []*MockResponse
+	responder  func(*http.Request) (*http.Response, error)
+	times      int
+	calls      int
+	lock       *sync.Mutex
+}
+
+// MockResponse struct is the response of the [MockRoute].
+type MockResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Delay is the duration to wait before the response, it honors
+	// the request context
+	Delay time.Duration
+
+	// Err is the error returned instead of the response, e.g., to
+	// simulate the connection failure
+	Err error
+}
+
+// MatchHeader method matches the request header value.
+func (r *MockRoute) MatchHeader(key, value string) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.headers == nil {
+		r.headers = http.Header{}
+	}
+	r.headers.Add(key, value)
+	return r
+}
+
+// MatchQuery method matches the request query parameter value.
+func (r *MockRoute) MatchQuery(key, value string) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.query == nil {
+		r.query = make(map[string]string)
+	}
+	r.query[key] = value
+	return r
+}
+
+// MatchBody method matches the request body with the given function.
+func (r *MockRoute) MatchBody(fn func(body []byte) bool) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.bodyFn = fn
+	return r
+}
+
+// MatchBodyString method matches the request body exactly.
+func (r *MockRoute) MatchBodyString(body string) *MockRoute {
+	return r.MatchBody(func(b []byte) bool { return string(b) == body })
+}
+
+// Respond method adds the given responses to the response sequence of the route.
+func (r *MockRoute) Respond(responses ...*MockResponse) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.responses = append(r.responses, responses...)
+	return r
+}
+
+// RespondString method adds the response of the given status code and body
+// to the response sequence of the route.
+func (r *MockRoute) RespondString(statusCode int, body string) *MockRoute {
+	return r.Respond(&MockResponse{
+		StatusCode: statusCode,
+		Header:     http.Header{hdrContentTypeKey: []string{plainTextType}},
+		Body:       []byte(body),
+	})
+}
+
+// RespondJSON method adds the response of the given status code and the
+// JSON-encoded value to the response sequence of the route. It panics if the
+// value cannot be encoded.
+func (r *MockRoute) RespondJSON(statusCode int, v any) *MockRoute {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("resty: mock response: %v", err))
+	}
+	return r.Respond(&MockResponse{
+		StatusCode: statusCode,
+		Header:     http.Header{hdrContentTypeKey: []string{jsonContentType}},
+		Body:       body,
+	})
+}
+
+// RespondWith method sets the function to respond the requests of the route,
+// it takes precedence over the response sequence.
+func (r *MockRoute) RespondWith(fn func(*http.Request) (*http.Response, error)) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.responder = fn
+	return r
+}
+
+// Times method sets the expected number of the calls, the route does not match
+// after it is called the given times. See [MockTransport.AssertExpectations]
+func (r *MockRoute) Times(n int) *MockRoute {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.times = n
+	return r
+}
+
+// Calls method returns the number of the requests matched by the route.
+func (r *MockRoute) Calls() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.calls
+}
+
+func (r *MockRoute) String() string {
+	method := r.method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + r.urlPattern
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+var mockPatternParamRe = regexp.MustCompile(`\\\{[^/]+?\\\}`)
+
+// compileMockPattern function compiles the URL pattern into the anchored regexp.
+func compileMockPattern(pattern string) *regexp.Regexp {
+	p := regexp.QuoteMeta(pattern)
+	p = mockPatternParamRe.ReplaceAllString(p, `[^/]+`)
+	p = strings.ReplaceAll(p, `\*`, `.*`)
+	return regexp.MustCompile("^" + p + "$")
+}
+
+// match method reports whether the route matches the request. The caller
+// must hold the lock.
+func (r *MockRoute) match(req *http.Request, body []byte) bool {
+	if r.times > 0 && r.calls >= r.times {
+		return false
+	}
+	if r.method != "" && r.method != "*" && r.method != req.Method {
+		return false
+	}
+
+	u := *req.URL
+	u.RawQuery, u.Fragment = "", ""
+	target := u.String()
+	if !strings.Contains(r.urlPattern, "://") {
+		target = u.EscapedPath()
+	}
+	if !r.urlRegexp.MatchString(target) {
+		return false
+	}
+
+	for k, values := range r.headers {
+		for _, v := range values {
+			if !slices.Contains(req.Header.Values(k), v) {
+				return false
+			}
+		}
+	}
+	query := req.URL.Query()
+	for k, v := range r.query {
+		if !slices.Contains(query[k], v) {
+			return false
+		}
+	}
+	return r.bodyFn == nil || r.bodyFn(body)
+}
+
+// next method records the call and returns the next response of the
+// sequence. The caller must hold the lock.
+func (r *MockRoute) next() (*MockResponse, func(*http.Request) (*http.Response, error)) {
+	r.calls++
+	if r.responder != nil {
+		return nil, r.responder
+	}
+	if len(r.responses) == 0 {
+		return &MockResponse{StatusCode: http.StatusOK}, nil
+	}
+	return r.responses[min(r.calls, len(r.responses))-1], nil
+}
+
+func (res *MockResponse) response(req *http.Request) (*http.Response, error) {
+	if res.Delay > 0 {
+		timer := time.NewTimer(res.Delay)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	statusCode := res.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	header := res.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}, nil
+}
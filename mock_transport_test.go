@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testMockT struct {
+	errors []string
+}
+
+func (t *testMockT) Helper() {}
+
+func (t *testMockT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockTransport(t *testing.T) {
+	mt := NewMockTransport()
+	mt.On(http.MethodGet, "https://api.example.com/users/{id}").
+		MatchHeader("Authorization", "Bearer token").
+		RespondJSON(http.StatusOK, map[string]any{"id": 1, "name": "Jeeva"})
+	mt.On(http.MethodGet, "/users/{id}").RespondString(http.StatusUnauthorized, "unauthorized")
+	mt.On("*", "/search").MatchQuery("q", "resty").RespondString(http.StatusOK, "found")
+	mt.On(http.MethodPost, "/orders").
+		MatchBodyString(`{"sku":"A1"}`).
+		Respond(&MockResponse{StatusCode: http.StatusServiceUnavailable}).
+		RespondString(http.StatusCreated, `{"id":"o-1"}`).
+		Times(3)
+
+	c := dcnl().SetTransport(mt).SetBaseURL("https://api.example.com")
+	defer c.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	res, err := c.R().SetAuthToken("token").SetResult(&user{}).Get("/users/1")
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, &user{ID: 1, Name: "Jeeva"}, res.Result())
+
+	res, err = c.R().Get("/users/1")
+	assertNil(t, err)
+	assertEqual(t, http.StatusUnauthorized, res.StatusCode())
+	assertEqual(t, "unauthorized", res.String())
+
+	res, err = c.R().SetQueryParam("q", "resty").Put("/search")
+	assertNil(t, err)
+	assertEqual(t, "found", res.String())
+
+	// the responses in the sequence, the last one repeats
+	var statusCodes []int
+	for i := 0; i < 3; i++ {
+		res, err = c.R().SetBody(`{"sku":"A1"}`).Post("/orders")
+		assertNil(t, err)
+		statusCodes = append(statusCodes, res.StatusCode())
+	}
+	assertEqual(t, []int{http.StatusServiceUnavailable, http.StatusCreated, http.StatusCreated}, statusCodes)
+
+	calls := mt.Calls()
+	assertEqual(t, 6, len(calls))
+	assertEqual(t, `{"sku":"A1"}`, string(calls[5].Body))
+	assertEqual(t, "POST /orders", calls[5].Route.String())
+	assertEqual(t, 3, calls[5].Route.Calls())
+	assertEqual(t, true, mt.AssertExpectations(t))
+
+	// the route does not match after the times
+	_, err = c.R().SetBody(`{"sku":"A1"}`).Post("/orders")
+	assertErrorIs(t, ErrMockNotMatched, err)
+
+	mockT := &testMockT{}
+	assertEqual(t, false, mt.AssertExpectations(mockT))
+	assertEqual(t, []string{"resty: unmatched request POST https://api.example.com/orders"}, mockT.errors)
+
+	mt.Reset()
+	assertEqual(t, 0, len(mt.Calls()))
+}
+
+func TestMockTransportResponders(t *testing.T) {
+	mt := NewMockTransport()
+	mt.On(http.MethodGet, "/slow").Respond(&MockResponse{Delay: time.Second})
+	mt.On(http.MethodGet, "/down").Respond(&MockResponse{Err: errMockNetwork})
+	mt.On(http.MethodGet, "/files/*").RespondWith(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(r.URL.Path)),
+			Request:    r,
+		}, nil
+	})
+	mt.On(http.MethodDelete, "/never")
+
+	c := dcnl().SetTransport(mt).SetBaseURL("https://api.example.com")
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.R().SetContext(ctx).Get("/slow")
+	assertEqual(t, true, errors.Is(err, context.DeadlineExceeded))
+
+	_, err = c.R().Get("/down")
+	assertErrorIs(t, errMockNetwork, err)
+
+	res, err := c.R().Get("/files/docs/readme.md")
+	assertNil(t, err)
+	assertEqual(t, "/files/docs/readme.md", res.String())
+
+	mockT := &testMockT{}
+	assertEqual(t, false, mt.AssertExpectations(mockT))
+	assertEqual(t, []string{"resty: mock route DELETE /never expected to be called"}, mockT.errors)
+
+	t.Run("panic on unmatched", func(t *testing.T) {
+		mt.SetPanicOnUnmatched(true)
+		defer func() {
+			r := recover()
+			assertNotNil(t, r)
+			assertErrorIs(t, ErrMockNotMatched, r.(error))
+		}()
+		_, _ = mt.RoundTrip(httptestRequest(t, http.MethodGet, "https://api.example.com/unknown"))
+	})
+}
+
+func httptestRequest(t *testing.T, method, url string) *http.Request {
+	req, err := http.NewRequest(method, url, nil)
+	assertNil(t, err)
+	return req
+}
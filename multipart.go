@@ -6,8 +6,11 @@
 package resty
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net/http"
 	"net/textproto"
 	"os"
 	"path/filepath"
@@ -58,12 +61,32 @@ type MultipartField struct {
 	//
 	// It is primarily added for ordered multipart form-data field use cases
 	Values []string
+
+	// Header is used to set the custom part headers, such as `Content-ID`,
+	// `Content-Transfer-Encoding`, etc. It overrides the headers composed by
+	// Resty, e.g., `Content-Disposition`, `Content-Type`. (Optional)
+	//
+	// NOTE: Resty does not encode the part content as per `Content-Transfer-Encoding`;
+	// the content is expected to be encoded already.
+	Header http.Header
+
+	// Parts is used to compose the nested multipart content in this part, such
+	// as `multipart/related`, `multipart/mixed`. The ContentType value is used as the
+	// nested multipart type; default is `multipart/mixed`. (Optional)
+	Parts []*MultipartField
 }
 
 // Clone method returns the deep copy of m except [io.Reader].
 func (mf *MultipartField) Clone() *MultipartField {
 	mf2 := new(MultipartField)
 	*mf2 = *mf
+	mf2.Header = mf.Header.Clone()
+	if mf.Parts != nil {
+		mf2.Parts = make([]*MultipartField, len(mf.Parts))
+		for i, p := range mf.Parts {
+			mf2.Parts[i] = p.Clone()
+		}
+	}
 	return mf2
 }
 
@@ -77,24 +100,147 @@ func (mf *MultipartField) resetReader() error {
 
 func (mf *MultipartField) close() {
 	closeq(mf.Reader)
+	for _, p := range mf.Parts {
+		p.close()
+	}
 }
 
 func (mf *MultipartField) createHeader() textproto.MIMEHeader {
 	h := make(textproto.MIMEHeader)
-	if isStringEmpty(mf.FileName) {
-		h.Set(hdrContentDisposition,
-			fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(mf.Name)))
-	} else {
+	switch {
+	case !isStringEmpty(mf.FileName):
 		h.Set(hdrContentDisposition,
 			fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
 				escapeQuotes(mf.Name), escapeQuotes(mf.FileName)))
+	case !isStringEmpty(mf.Name):
+		h.Set(hdrContentDisposition,
+			fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(mf.Name)))
 	}
 	if !isStringEmpty(mf.ContentType) {
 		h.Set(hdrContentTypeKey, mf.ContentType)
 	}
+	for k, v := range mf.Header {
+		h[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
 	return h
 }
 
+// size method returns the remaining content size of the reader; it reports
+// false if unknown. The content type is auto-detected upfront if empty, using
+// the seekable reader, so the part header size is known.
+func (mf *MultipartField) size() (int64, bool) {
+	if err := mf.openFileIfRequired(); err != nil {
+		return 0, false
+	}
+
+	var size int64
+	switch rd := mf.Reader.(type) {
+	case *bytes.Reader:
+		size = int64(rd.Len())
+	case *strings.Reader:
+		size = int64(rd.Len())
+	case *bytes.Buffer:
+		size = int64(rd.Len())
+	case *os.File:
+		fi, err := rd.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := rd.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		size = fi.Size() - offset
+	default:
+		return 0, false
+	}
+
+	if isStringEmpty(mf.ContentType) {
+		rs, ok := mf.Reader.(io.ReadSeeker)
+		if !ok {
+			return 0, false
+		}
+		offset, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		p := make([]byte, 512)
+		n, err := rs.Read(p)
+		if err != nil && err != io.EOF {
+			return 0, false
+		}
+		if _, err = rs.Seek(offset, io.SeekStart); err != nil {
+			return 0, false
+		}
+		mf.ContentType = http.DetectContentType(p[:n])
+	}
+
+	return size, true
+}
+
+// multipartLength function computes the content length of the streaming
+// multipart body with the given boundary; it reports false if any of the
+// part sizes is unknown, then the body is sent with chunked encoding.
+func multipartLength(boundary string, r *Request) (int64, bool) {
+	cw := &countWriter{w: io.Discard}
+	w := multipart.NewWriter(cw)
+	if err := w.SetBoundary(boundary); err != nil {
+		return 0, false
+	}
+	if err := r.writeFormData(w); err != nil {
+		return 0, false
+	}
+
+	for _, mf := range r.multipartFields {
+		if len(mf.Parts) > 0 {
+			// nested boundary is generated on write
+			return 0, false
+		}
+
+		if len(mf.Values) > 0 {
+			for _, v := range mf.Values {
+				if len(mf.Header) == 0 && isStringEmpty(mf.ContentType) {
+					_ = w.WriteField(mf.Name, v)
+					continue
+				}
+				if _, err := w.CreatePart(mf.createHeader()); err != nil {
+					return 0, false
+				}
+				cw.n += int64(len(v))
+			}
+			continue
+		}
+
+		size, ok := mf.size()
+		if !ok {
+			return 0, false
+		}
+		if _, err := w.CreatePart(mf.createHeader()); err != nil {
+			return 0, false
+		}
+		cw.n += size
+	}
+
+	if err := w.Close(); err != nil {
+		return 0, false
+	}
+	return cw.n, true
+}
+
+// multipartContentType function returns the Content-Type value of the multipart
+// content with the writer boundary, the default type is `multipart/form-data`.
+func multipartContentType(ct string, w *multipart.Writer) string {
+	if isStringEmpty(ct) {
+		return w.FormDataContentType()
+	}
+	b := w.Boundary()
+	// RFC 2045 tspecials, quote the boundary if present
+	if strings.ContainsAny(b, `()<>@,;:\"/[]?= `) {
+		b = `"` + b + `"`
+	}
+	return ct + "; boundary=" + b
+}
+
 func (mf *MultipartField) openFileIfRequired() error {
 	if isStringEmpty(mf.FilePath) || mf.Reader != nil {
 		return nil
@@ -136,6 +282,21 @@ func (mf *MultipartField) wrapProgressCallbackIfPresent(pw io.Writer) io.Writer
 	}
 }
 
+func (mf *MultipartField) wrapPartProgressIfPresent(pw io.Writer, fn func(string, int64, int64)) io.Writer {
+	if fn == nil {
+		return pw
+	}
+
+	name := mf.Name
+	if isStringEmpty(name) {
+		name = mf.FileName
+	}
+	return &multipartProgressWriter{
+		w: pw,
+		f: func(pb int64) { fn(name, pb, mf.FileSize) },
+	}
+}
+
 // MultipartFieldCallbackFunc function used to transmit live multipart upload
 // progress in bytes count
 type MultipartFieldCallbackFunc func(MultipartFieldProgress)
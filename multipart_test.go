@@ -11,6 +11,7 @@ import (
 	"errors"
 	"io"
 	"io/fs"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
@@ -668,3 +669,196 @@ func TestMultipartCornerCoverage(t *testing.T) {
 	assertNil(t, err)
 	assertEqual(t, 0, n)
 }
+
+func TestMultipartPartHeadersAndRelated(t *testing.T) {
+	type part struct {
+		header textproto.MIMEHeader
+		body   string
+	}
+	var readParts func(mr *multipart.Reader) []part
+	readParts = func(mr *multipart.Reader) []part {
+		var parts []part
+		for {
+			p, err := mr.NextRawPart()
+			if err != nil {
+				return parts
+			}
+			b, _ := io.ReadAll(p)
+			parts = append(parts, part{header: p.Header, body: string(b)})
+		}
+	}
+
+	var contentType string
+	var parts []part
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get(hdrContentTypeKey)
+		_, params, _ := mime.ParseMediaType(contentType)
+		parts = readParts(multipart.NewReader(r.Body, params["boundary"]))
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	_, err := c.R().
+		SetMultipartBoundary("outer-boundary").
+		SetMultipartContentType(`multipart/related; type="application/xop+xml"; start="<root>"`).
+		SetMultipartFields(
+			&MultipartField{
+				ContentType: `application/xop+xml; type="text/xml"`,
+				Header:      http.Header{"content-id": []string{"<root>"}},
+				Reader:      strings.NewReader("<Envelope/>"),
+			},
+			&MultipartField{
+				Name:        "metadata",
+				Values:      []string{"e30="},
+				ContentType: "application/json",
+				Header: http.Header{
+					"Content-Transfer-Encoding": []string{"base64"},
+				},
+			},
+			&MultipartField{
+				ContentType: "multipart/alternative",
+				Header:      http.Header{"Content-ID": []string{"<nested>"}},
+				Parts: []*MultipartField{
+					{ContentType: "text/plain", Reader: strings.NewReader("plain")},
+					{ContentType: "text/html", Reader: strings.NewReader("<p>html</p>")},
+				},
+			},
+		).
+		Post(ts.URL)
+	assertNil(t, err)
+
+	assertEqual(t, `multipart/related; type="application/xop+xml"; start="<root>"; boundary=outer-boundary`, contentType)
+	assertEqual(t, 3, len(parts))
+
+	// custom headers, no form-data disposition without name
+	assertEqual(t, "<root>", parts[0].header.Get("Content-Id"))
+	assertEqual(t, "", parts[0].header.Get(hdrContentDisposition))
+	assertEqual(t, "<Envelope/>", parts[0].body)
+
+	assertEqual(t, `form-data; name="metadata"`, parts[1].header.Get(hdrContentDisposition))
+	assertEqual(t, "application/json", parts[1].header.Get(hdrContentTypeKey))
+	assertEqual(t, "base64", parts[1].header.Get("Content-Transfer-Encoding"))
+	assertEqual(t, "e30=", parts[1].body)
+
+	// nested multipart
+	mediaType, params, err := mime.ParseMediaType(parts[2].header.Get(hdrContentTypeKey))
+	assertNil(t, err)
+	assertEqual(t, "multipart/alternative", mediaType)
+	nested := readParts(multipart.NewReader(strings.NewReader(parts[2].body), params["boundary"]))
+	assertEqual(t, 2, len(nested))
+	assertEqual(t, "text/plain", nested[0].header.Get(hdrContentTypeKey))
+	assertEqual(t, "plain", nested[0].body)
+	assertEqual(t, "<p>html</p>", nested[1].body)
+}
+
+func TestMultipartFieldCloneDeep(t *testing.T) {
+	mf := &MultipartField{
+		Name:   "root",
+		Header: http.Header{"Content-Id": []string{"<root>"}},
+		Parts:  []*MultipartField{{Name: "child"}},
+	}
+	mf2 := mf.Clone()
+	mf2.Header.Set("Content-Id", "<changed>")
+	mf2.Parts[0].Name = "changed"
+	assertEqual(t, "<root>", mf.Header.Get("Content-Id"))
+	assertEqual(t, "child", mf.Parts[0].Name)
+}
+
+func TestMultipartProgressCallbacks(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+	defer ts.Close()
+
+	content := strings.Repeat("resty", 20000) // 100000 bytes
+	type progress struct {
+		part        string
+		sent, total int64
+	}
+	var partProgress []progress
+	var bodyProgress []progress
+
+	c := dcnl()
+	_, err := c.R().
+		SetMultipartFields(
+			&MultipartField{
+				Name:     "file",
+				FileName: "file.txt",
+				FileSize: int64(len(content)),
+				Reader:   strings.NewReader(content),
+			},
+			&MultipartField{
+				FileName: "notes.txt",
+				Reader:   strings.NewReader("notes"),
+			},
+		).
+		SetMultipartProgressCallback(func(part string, sent, total int64) {
+			partProgress = append(partProgress, progress{part, sent, total})
+		}).
+		SetUploadProgressCallback(func(sent, total int64) {
+			bodyProgress = append(bodyProgress, progress{"", sent, total})
+		}).
+		Post(ts.URL)
+	assertNil(t, err)
+
+	assertEqual(t, true, len(partProgress) > 2)
+	last := partProgress[len(partProgress)-2]
+	assertEqual(t, progress{"file", int64(len(content)), int64(len(content))}, last)
+	assertEqual(t, progress{"notes.txt", 5, 0}, partProgress[len(partProgress)-1])
+
+	assertEqual(t, true, len(bodyProgress) > 1)
+	lastBody := bodyProgress[len(bodyProgress)-1]
+	assertEqual(t, true, lastBody.sent > int64(len(content)))
+	assertEqual(t, lastBody.sent, lastBody.total)
+
+	// known content length
+	bodyProgress = nil
+	_, err = c.R().
+		SetBody([]byte(content)).
+		SetUploadProgressCallback(func(sent, total int64) {
+			bodyProgress = append(bodyProgress, progress{"", sent, total})
+		}).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, progress{"", int64(len(content)), int64(len(content))}, bodyProgress[len(bodyProgress)-1])
+}
+
+func TestMultipartStreamingContentLength(t *testing.T) {
+	var contentLength int64
+	var transferEncoding []string
+	var received int64
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		transferEncoding = r.TransferEncoding
+		received, _ = io.Copy(io.Discard, r.Body)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+
+	// known part sizes
+	res, err := c.R().
+		SetMultipartBoundary("resty-boundary").
+		SetMultipartFormData(map[string]string{"name": "resty"}).
+		SetMultipartOrderedFormData("tags", []string{"go", "http"}).
+		SetFile("profile_img", filepath.Join(getTestDataPath(), "test-img.png")).
+		SetFileReader("notes", "notes.txt", strings.NewReader("some notes")).
+		SetContentLength(true).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, true, contentLength > 0)
+	assertEqual(t, received, contentLength)
+	assertEqual(t, 0, len(transferEncoding))
+	assertEqual(t, strconv.FormatInt(contentLength, 10), res.Request.Header.Get(hdrContentLengthKey))
+
+	// unknown part size, chunked
+	res, err = c.R().
+		SetFileReader("notes", "notes.txt", io.MultiReader(strings.NewReader("some notes"))).
+		Post(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, int64(-1), contentLength)
+	assertEqual(t, []string{"chunked"}, transferEncoding)
+	assertEqual(t, true, received > 0)
+}
@@ -0,0 +1,780 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrOpenAPIViolation error is wrapped by the [OpenAPIValidationError].
+var ErrOpenAPIViolation = errors.New("resty: openapi contract violation")
+
+// OpenAPIViolationFunc type is for the callback of the OpenAPI contract violations,
+// see [OpenAPIValidator.SetOnViolation].
+type OpenAPIViolationFunc func(*Response, []*OpenAPIViolation)
+
+// OpenAPIViolation struct holds the OpenAPI contract violation of the request or
+// the response.
+type OpenAPIViolation struct {
+	// In is either `request` or `response`
+	In string
+
+	// Location is the location of the violation, e.g., `path`, `query.limit`,
+	// `header.X-Request-Id`, `body.items[0].name`, `status`
+	Location string
+
+	// Message is the description of the violation
+	Message string
+}
+
+// String method returns the string value of the violation.
+func (v *OpenAPIViolation) String() string {
+	return v.In + " " + v.Location + ": " + v.Message
+}
+
+// OpenAPIValidationError struct is the error returned when the request or the
+// response violates the OpenAPI contract, it wraps the [ErrOpenAPIViolation].
+type OpenAPIValidationError struct {
+	Method     string
+	URL        string
+	Violations []*OpenAPIViolation
+}
+
+// Error method returns the error message.
+func (e *OpenAPIValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(ErrOpenAPIViolation.Error())
+	sb.WriteString(" on " + e.Method + " " + e.URL)
+	for _, v := range e.Violations {
+		sb.WriteString("; " + v.String())
+	}
+	return sb.String()
+}
+
+// Unwrap method returns the [ErrOpenAPIViolation].
+func (e *OpenAPIValidationError) Unwrap() error {
+	return ErrOpenAPIViolation
+}
+
+// NewOpenAPIValidator function creates the new OpenAPI contract validator from
+// the given OpenAPI 3 document in the JSON format. It validates the requests and
+// the responses against the paths, the parameters and the schemas of the
+// document. It is set on the client via [Client.SetOpenAPIValidator].
+//
+//	spec, _ := os.ReadFile("testdata/openapi.json")
+//	validator, err := resty.NewOpenAPIValidator(spec)
+//	if err != nil {
+//		return err
+//	}
+//	client.SetOpenAPIValidator(validator)
+//
+// NOTE:
+//   - The YAML document can be converted into JSON via the YAML library.
+//   - The local references, i.e., `#/components/...`, are supported.
+//   - The `servers` URL paths are stripped from the request path, e.g., `/v1`.
+func NewOpenAPIValidator(spec []byte) (*OpenAPIValidator, error) {
+	doc := &openAPIDocument{}
+	if err := json.Unmarshal(spec, doc); err != nil {
+		return nil, fmt.Errorf("resty: openapi: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("resty: openapi: unsupported version %q", doc.OpenAPI)
+	}
+
+	v := &OpenAPIValidator{lock: new(sync.RWMutex), doc: doc}
+	for _, s := range doc.Servers {
+		if u, err := url.Parse(s.URL); err == nil {
+			if p := strings.TrimRight(u.Path, "/"); p != "" && !slices.Contains(v.basePaths, p) {
+				v.basePaths = append(v.basePaths, p)
+			}
+		}
+	}
+	for pattern, item := range doc.Paths {
+		v.paths = append(v.paths, newOpenAPIPath(pattern, item))
+	}
+	// the literal paths take precedence over the templated ones
+	sort.SliceStable(v.paths, func(i, j int) bool {
+		if v.paths[i].params != v.paths[j].params {
+			return v.paths[i].params < v.paths[j].params
+		}
+		return v.paths[i].pattern < v.paths[j].pattern
+	})
+	return v, nil
+}
+
+// OpenAPIValidator struct is the OpenAPI contract validator, see [NewOpenAPIValidator].
+type OpenAPIValidator struct {
+	lock        *sync.RWMutex
+	doc         *openAPIDocument
+	basePaths   []string
+	paths       []*openAPIPath
+	onViolation OpenAPIViolationFunc
+}
+
+// SetOnViolation method sets the callback for the violations; otherwise, the
+// violations are returned as the [OpenAPIValidationError].
+//
+//	validator.SetOnViolation(func(res *resty.Response, violations []*resty.OpenAPIViolation) {
+//		for _, v := range violations {
+//			t.Errorf("%s %s: %s", res.Request.Method, res.Request.URL, v)
+//		}
+//	})
+func (v *OpenAPIValidator) SetOnViolation(fn OpenAPIViolationFunc) *OpenAPIValidator {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.onViolation = fn
+	return v
+}
+
+// ValidateRequest method validates the given request and its body against the
+// OpenAPI document, it returns the violations if any.
+func (v *OpenAPIValidator) ValidateRequest(req *http.Request, body []byte) []*OpenAPIViolation {
+	op, pathParams, violations := v.findOperation(req)
+	if op == nil {
+		return violations
+	}
+	return v.validateRequest(op, pathParams, req, body)
+}
+
+// ValidateResponse method validates the given response and its body against
+// the OpenAPI document, it returns the violations if any. The response of the
+// undocumented operation is not validated.
+func (v *OpenAPIValidator) ValidateResponse(res *http.Response, body []byte) []*OpenAPIViolation {
+	op, _, _ := v.findOperation(res.Request)
+	if op == nil {
+		return nil
+	}
+	return v.validateResponse(op, res, body)
+}
+
+// OpenAPIValidator method returns the OpenAPI contract validator from the client
+// instance. Otherwise returns nil.
+func (c *Client) OpenAPIValidator() *OpenAPIValidator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.openAPIValidator
+}
+
+// SetOpenAPIValidator method sets the OpenAPI contract validator into the client,
+// the requests and the responses are validated once the response is received;
+// so the client drift is caught in the integration tests. The nil value
+// disables it.
+//
+//	client.SetOpenAPIValidator(validator)
+//
+// NOTE: The response body is read into memory for the validation, see
+// [Request.SetResponseBodyUnlimitedReads].
+func (c *Client) SetOpenAPIValidator(v *OpenAPIValidator) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.openAPIValidator = v
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type openAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Servers    []*openAPIServer            `json:"servers"`
+	Paths      map[string]*openAPIPathItem `json:"paths"`
+	Components *openAPIComponents          `json:"components"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIComponents struct {
+	Schemas       map[string]*openAPISchema      `json:"schemas"`
+	Parameters    map[string]*openAPIParameter   `json:"parameters"`
+	RequestBodies map[string]*openAPIRequestBody `json:"requestBodies"`
+	Responses     map[string]*openAPIResponse    `json:"responses"`
+}
+
+type openAPIPathItem struct {
+	Parameters []*openAPIParameter `json:"parameters"`
+	Get        *openAPIOperation   `json:"get"`
+	Put        *openAPIOperation   `json:"put"`
+	Post       *openAPIOperation   `json:"post"`
+	Delete     *openAPIOperation   `json:"delete"`
+	Options    *openAPIOperation   `json:"options"`
+	Head       *openAPIOperation   `json:"head"`
+	Patch      *openAPIOperation   `json:"patch"`
+	Trace      *openAPIOperation   `json:"trace"`
+}
+
+type openAPIOperation struct {
+	Parameters  []*openAPIParameter         `json:"parameters"`
+	RequestBody *openAPIRequestBody         `json:"requestBody"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref      string         `json:"$ref"`
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Ref      string                       `json:"$ref"`
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Ref     string                       `json:"$ref"`
+	Content map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 openAPISchemaType         `json:"type"`
+	Format               string                    `json:"format"`
+	Nullable             bool                      `json:"nullable"`
+	Enum                 []any                     `json:"enum"`
+	Required             []string                  `json:"required"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	AdditionalProperties json.RawMessage           `json:"additionalProperties"`
+	Items                *openAPISchema            `json:"items"`
+	AllOf                []*openAPISchema          `json:"allOf"`
+	AnyOf                []*openAPISchema          `json:"anyOf"`
+	OneOf                []*openAPISchema          `json:"oneOf"`
+	Minimum              *float64                  `json:"minimum"`
+	Maximum              *float64                  `json:"maximum"`
+	MinLength            *int                      `json:"minLength"`
+	MaxLength            *int                      `json:"maxLength"`
+	MinItems             *int                      `json:"minItems"`
+	MaxItems             *int                      `json:"maxItems"`
+	Pattern              string                    `json:"pattern"`
+}
+
+// openAPISchemaType type is the schema type, it is the string in OpenAPI 3.0
+// and the string or the list of strings in OpenAPI 3.1
+type openAPISchemaType []string
+
+func (t *openAPISchemaType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = openAPISchemaType{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	*t = l
+	return nil
+}
+
+type openAPIPath struct {
+	pattern  string
+	segments []string
+	params   int
+	item     *openAPIPathItem
+}
+
+func newOpenAPIPath(pattern string, item *openAPIPathItem) *openAPIPath {
+	p := &openAPIPath{
+		pattern:  pattern,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		item:     item,
+	}
+	for _, s := range p.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			p.params++
+		}
+	}
+	return p
+}
+
+func (p *openAPIPath) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(p.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range p.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			params[s[1:len(s)-1]] = value
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (item *openAPIPathItem) operation(method string) *openAPIOperation {
+	switch method {
+	case MethodGet:
+		return item.Get
+	case MethodPut:
+		return item.Put
+	case MethodPost:
+		return item.Post
+	case MethodDelete:
+		return item.Delete
+	case MethodOptions:
+		return item.Options
+	case MethodHead:
+		return item.Head
+	case MethodPatch:
+		return item.Patch
+	case MethodTrace:
+		return item.Trace
+	}
+	return nil
+}
+
+type openAPIOperationRef struct {
+	*openAPIOperation
+	item *openAPIPathItem
+}
+
+func (v *OpenAPIValidator) validate(res *Response) {
+	req := res.Request
+	rawReq := req.RawRequest
+	var reqBody []byte
+	if rawReq.GetBody != nil {
+		if body, err := rawReq.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			closeq(body)
+		}
+	}
+
+	op, pathParams, violations := v.findOperation(rawReq)
+	if op != nil {
+		violations = v.validateRequest(op, pathParams, rawReq, reqBody)
+		if !req.DoNotParseResponse && !req.responseBodyStream {
+			violations = append(violations, v.validateResponse(op, res.RawResponse, res.bodyBytes)...)
+		}
+	}
+	if len(violations) == 0 {
+		return
+	}
+
+	v.lock.RLock()
+	onViolation := v.onViolation
+	v.lock.RUnlock()
+	if onViolation != nil {
+		onViolation(res, violations)
+		return
+	}
+	res.Err = wrapErrors(&OpenAPIValidationError{
+		Method:     rawReq.Method,
+		URL:        rawReq.URL.String(),
+		Violations: violations,
+	}, res.Err)
+}
+
+func (v *OpenAPIValidator) findOperation(req *http.Request) (*openAPIOperationRef, map[string]string, []*OpenAPIViolation) {
+	p := req.URL.EscapedPath()
+	for _, bp := range v.basePaths {
+		if p == bp || strings.HasPrefix(p, bp+"/") {
+			p = p[len(bp):]
+			break
+		}
+	}
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+
+	for _, path := range v.paths {
+		params, ok := path.match(segments)
+		if !ok {
+			continue
+		}
+		op := path.item.operation(req.Method)
+		if op == nil {
+			return nil, nil, []*OpenAPIViolation{{
+				In: "request", Location: "method",
+				Message: fmt.Sprintf("operation %s %s is not defined", req.Method, path.pattern),
+			}}
+		}
+		return &openAPIOperationRef{openAPIOperation: op, item: path.item}, params, nil
+	}
+	return nil, nil, []*OpenAPIViolation{{
+		In: "request", Location: "path",
+		Message: fmt.Sprintf("path %s is not defined", p),
+	}}
+}
+
+func (v *OpenAPIValidator) validateRequest(op *openAPIOperationRef, pathParams map[string]string, req *http.Request, body []byte) []*OpenAPIViolation {
+	var violations []*OpenAPIViolation
+	addViolation := func(location, message string) {
+		violations = append(violations, &OpenAPIViolation{In: "request", Location: location, Message: message})
+	}
+
+	query := req.URL.Query()
+	for _, param := range v.parameters(op) {
+		var values []string
+		switch param.In {
+		case "path":
+			if value, found := pathParams[param.Name]; found {
+				values = []string{value}
+			}
+		case "query":
+			values = query[param.Name]
+		case "header":
+			values = req.Header.Values(param.Name)
+		case "cookie":
+			if cookie, err := req.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		default:
+			continue
+		}
+
+		location := param.In + "." + param.Name
+		if len(values) == 0 {
+			if param.Required || param.In == "path" {
+				addViolation(location, "required parameter is missing")
+			}
+			continue
+		}
+		if param.Schema == nil {
+			continue
+		}
+		schema := v.resolveSchema(param.Schema)
+		if schema.hasType("array") && schema.Items != nil {
+			if param.In != "query" {
+				values = strings.Split(values[0], ",")
+			}
+			items := make([]any, 0, len(values))
+			for _, value := range values {
+				items = append(items, coerceOpenAPIValue(value, v.resolveSchema(schema.Items)))
+			}
+			violations = append(violations, v.validateValue("request", location, items, schema)...)
+			continue
+		}
+		violations = append(violations, v.validateValue("request", location, coerceOpenAPIValue(values[0], schema), schema)...)
+	}
+
+	rb := op.RequestBody
+	if rb == nil {
+		return violations
+	}
+	rb = v.resolveRequestBody(rb)
+	if len(body) == 0 {
+		if rb.Required {
+			addViolation("body", "required request body is missing")
+		}
+		return violations
+	}
+	return append(violations, v.validateContent("request", rb.Content, req.Header.Get(hdrContentTypeKey), body)...)
+}
+
+func (v *OpenAPIValidator) validateResponse(op *openAPIOperationRef, res *http.Response, body []byte) []*OpenAPIViolation {
+	statusCode := strconv.Itoa(res.StatusCode)
+	r, found := op.Responses[statusCode]
+	if !found {
+		r, found = op.Responses[statusCode[:1]+"XX"]
+	}
+	if !found {
+		r, found = op.Responses["default"]
+	}
+	if !found {
+		return []*OpenAPIViolation{{
+			In: "response", Location: "status",
+			Message: fmt.Sprintf("status code %d is not documented", res.StatusCode),
+		}}
+	}
+
+	r = v.resolveResponse(r)
+	if len(r.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+	return v.validateContent("response", r.Content, res.Header.Get(hdrContentTypeKey), body)
+}
+
+func (v *OpenAPIValidator) validateContent(in string, content map[string]*openAPIMediaType, contentType string, body []byte) []*OpenAPIViolation {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	mt, found := content[mediaType]
+	if !found {
+		if major, _, ok := strings.Cut(mediaType, "/"); ok {
+			mt, found = content[major+"/*"]
+		}
+	}
+	if !found {
+		mt, found = content["*/*"]
+	}
+	if !found {
+		return []*OpenAPIViolation{{
+			In: in, Location: "header.Content-Type",
+			Message: fmt.Sprintf("content type %q is not documented", contentType),
+		}}
+	}
+
+	if mt == nil || mt.Schema == nil || !isJSONContentType(mediaType) {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []*OpenAPIViolation{{In: in, Location: "body", Message: "invalid JSON: " + err.Error()}}
+	}
+	return v.validateValue(in, "body", value, mt.Schema)
+}
+
+// parameters method returns the path item parameters, overridden by the
+// operation parameters of the same name and location.
+func (v *OpenAPIValidator) parameters(op *openAPIOperationRef) []*openAPIParameter {
+	var result []*openAPIParameter
+	for _, params := range [][]*openAPIParameter{op.item.Parameters, op.Parameters} {
+		for _, param := range params {
+			param = v.resolveParameter(param)
+			result = slices.DeleteFunc(result, func(p *openAPIParameter) bool {
+				return p.Name == param.Name && p.In == param.In
+			})
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+// validateValue method validates the decoded JSON value against the schema
+func (v *OpenAPIValidator) validateValue(in, location string, value any, schema *openAPISchema) []*OpenAPIViolation {
+	var violations []*OpenAPIViolation
+	v.validateSchema(value, schema, location, func(location, message string) {
+		violations = append(violations, &OpenAPIViolation{In: in, Location: location, Message: message})
+	}, 0)
+	return violations
+}
+
+// maxOpenAPISchemaDepth is the limit of the nested schemas, e.g., the recursive references
+const maxOpenAPISchemaDepth = 64
+
+func (v *OpenAPIValidator) validateSchema(value any, schema *openAPISchema, location string, addViolation func(string, string), depth int) {
+	if schema == nil || depth > maxOpenAPISchemaDepth {
+		return
+	}
+	schema = v.resolveSchema(schema)
+
+	if value == nil {
+		if !schema.Nullable && len(schema.Type) > 0 && !schema.hasType("null") {
+			addViolation(location, "must not be null")
+		}
+		return
+	}
+
+	if len(schema.Type) > 0 {
+		typeName := openAPITypeOf(value)
+		if !schema.hasType(typeName) && (typeName != "integer" || !schema.hasType("number")) {
+			addViolation(location, fmt.Sprintf("expected type %s, got %s", strings.Join(schema.Type, " or "), typeName))
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		addViolation(location, fmt.Sprintf("value %v is not one of %v", value, schema.Enum))
+	}
+
+	for _, s := range schema.AllOf {
+		v.validateSchema(value, s, location, addViolation, depth+1)
+	}
+	if len(schema.AnyOf) > 0 && v.countMatches(value, schema.AnyOf, depth) == 0 {
+		addViolation(location, "does not match any of the schemas")
+	}
+	if len(schema.OneOf) > 0 {
+		if n := v.countMatches(value, schema.OneOf, depth); n != 1 {
+			addViolation(location, fmt.Sprintf("must match exactly one of the schemas, matched %d", n))
+		}
+	}
+
+	switch val := value.(type) {
+	case string:
+		if schema.MinLength != nil && len([]rune(val)) < *schema.MinLength {
+			addViolation(location, fmt.Sprintf("length must be at least %d", *schema.MinLength))
+		}
+		if schema.MaxLength != nil && len([]rune(val)) > *schema.MaxLength {
+			addViolation(location, fmt.Sprintf("length must be at most %d", *schema.MaxLength))
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(val) {
+				addViolation(location, fmt.Sprintf("must match the pattern %s", schema.Pattern))
+			}
+		}
+		if !validOpenAPIFormat(schema.Format, val) {
+			addViolation(location, fmt.Sprintf("must be a valid %s", schema.Format))
+		}
+	case float64:
+		if schema.Minimum != nil && val < *schema.Minimum {
+			addViolation(location, fmt.Sprintf("must be at least %v", *schema.Minimum))
+		}
+		if schema.Maximum != nil && val > *schema.Maximum {
+			addViolation(location, fmt.Sprintf("must be at most %v", *schema.Maximum))
+		}
+	case []any:
+		if schema.MinItems != nil && len(val) < *schema.MinItems {
+			addViolation(location, fmt.Sprintf("must have at least %d items", *schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(val) > *schema.MaxItems {
+			addViolation(location, fmt.Sprintf("must have at most %d items", *schema.MaxItems))
+		}
+		for i, item := range val {
+			v.validateSchema(item, schema.Items, fmt.Sprintf("%s[%d]", location, i), addViolation, depth+1)
+		}
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, found := val[name]; !found {
+				addViolation(location+"."+name, "required property is missing")
+			}
+		}
+		var additional *openAPISchema
+		noAdditional := string(schema.AdditionalProperties) == "false"
+		if len(schema.AdditionalProperties) > 0 && schema.AdditionalProperties[0] == '{' {
+			additional = &openAPISchema{}
+			_ = json.Unmarshal(schema.AdditionalProperties, additional)
+		}
+		for _, name := range sortedKeys(val) {
+			if s, found := schema.Properties[name]; found {
+				v.validateSchema(val[name], s, location+"."+name, addViolation, depth+1)
+				continue
+			}
+			if noAdditional {
+				addViolation(location+"."+name, "additional property is not allowed")
+			} else if additional != nil {
+				v.validateSchema(val[name], additional, location+"."+name, addViolation, depth+1)
+			}
+		}
+	}
+}
+
+func (v *OpenAPIValidator) countMatches(value any, schemas []*openAPISchema, depth int) int {
+	n := 0
+	for _, s := range schemas {
+		matched := true
+		v.validateSchema(value, s, "", func(string, string) { matched = false }, depth+1)
+		if matched {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *OpenAPIValidator) resolveSchema(s *openAPISchema) *openAPISchema {
+	for i := 0; s.Ref != "" && i < maxOpenAPISchemaDepth; i++ {
+		name, found := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !found || v.doc.Components == nil || v.doc.Components.Schemas[name] == nil {
+			return &openAPISchema{}
+		}
+		s = v.doc.Components.Schemas[name]
+	}
+	return s
+}
+
+func (v *OpenAPIValidator) resolveParameter(p *openAPIParameter) *openAPIParameter {
+	if name, found := strings.CutPrefix(p.Ref, "#/components/parameters/"); found && v.doc.Components != nil {
+		if rp := v.doc.Components.Parameters[name]; rp != nil {
+			return rp
+		}
+	}
+	return p
+}
+
+func (v *OpenAPIValidator) resolveRequestBody(rb *openAPIRequestBody) *openAPIRequestBody {
+	if name, found := strings.CutPrefix(rb.Ref, "#/components/requestBodies/"); found && v.doc.Components != nil {
+		if r := v.doc.Components.RequestBodies[name]; r != nil {
+			return r
+		}
+	}
+	return rb
+}
+
+func (v *OpenAPIValidator) resolveResponse(r *openAPIResponse) *openAPIResponse {
+	if name, found := strings.CutPrefix(r.Ref, "#/components/responses/"); found && v.doc.Components != nil {
+		if rr := v.doc.Components.Responses[name]; rr != nil {
+			return rr
+		}
+	}
+	return r
+}
+
+func (s *openAPISchema) hasType(t string) bool {
+	return slices.Contains(s.Type, t)
+}
+
+// openAPITypeOf function returns the schema type of the decoded JSON value
+func openAPITypeOf(value any) string {
+	switch val := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+// coerceOpenAPIValue function converts the parameter value into the schema type
+func coerceOpenAPIValue(value string, schema *openAPISchema) any {
+	switch {
+	case schema.hasType("integer"), schema.hasType("number"):
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case schema.hasType("boolean"):
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+var openAPIUUIDRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validOpenAPIFormat(format, value string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "uuid":
+		return openAPIUUIDRe.MatchString(value)
+	}
+	return err == nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
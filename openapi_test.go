@@ -0,0 +1,223 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `{
+  "openapi": "3.0.3",
+  "servers": [{"url": "https://api.example.com/v1"}],
+  "paths": {
+    "/users": {
+      "get": {
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 100}},
+          {"name": "status", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["active", "blocked"]}}}
+        ],
+        "responses": {
+          "200": {
+            "description": "users",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}}}
+          }
+        }
+      },
+      "post": {
+        "parameters": [{"$ref": "#/components/parameters/RequestID"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        },
+        "responses": {
+          "201": {"description": "created"},
+          "4XX": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/users/me": {
+      "get": {"responses": {"200": {"description": "me"}}}
+    },
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "responses": {
+          "200": {
+            "description": "user",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "RequestID": {"name": "X-Request-Id", "in": "header", "required": true, "schema": {"type": "string", "format": "uuid"}}
+    },
+    "responses": {
+      "Error": {
+        "description": "error",
+        "content": {"application/json": {"schema": {"type": "object", "required": ["message"], "properties": {"message": {"type": "string"}}}}}
+      }
+    },
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["id", "name"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "integer"},
+          "name": {"type": "string", "minLength": 1},
+          "email": {"type": "string", "nullable": true, "pattern": "^[^@]+@[^@]+$"},
+          "createdAt": {"type": "string", "format": "date-time"},
+          "manager": {"$ref": "#/components/schemas/User"},
+          "tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+          "role": {"oneOf": [{"type": "string", "enum": ["admin"]}, {"type": "integer"}]}
+        }
+      }
+    }
+  }
+}`
+
+func TestOpenAPIValidator(t *testing.T) {
+	validator, err := NewOpenAPIValidator([]byte(testOpenAPISpec))
+	assertNil(t, err)
+
+	jsonResponse := func(statusCode int, body string) *MockResponse {
+		return &MockResponse{
+			StatusCode: statusCode,
+			Header:     http.Header{hdrContentTypeKey: []string{jsonContentType}},
+			Body:       []byte(body),
+		}
+	}
+	mt := NewMockTransport()
+	mt.On(http.MethodGet, "/v1/users").Respond(jsonResponse(http.StatusOK, `[{"id": 1, "name": "Jeeva"}]`))
+	mt.On(http.MethodGet, "/v1/users/me").RespondString(http.StatusOK, "me")
+	mt.On(http.MethodGet, "/v1/users/1").RespondJSON(http.StatusOK, map[string]any{
+		"id": 1, "name": "", "email": nil, "createdAt": "yesterday", "role": true,
+		"manager": map[string]any{"id": "2", "name": "Jeeva", "age": 42},
+		"tags":    []string{"a", "b", "c"},
+	})
+	mt.On(http.MethodGet, "/v1/users/2").Respond(jsonResponse(http.StatusNotFound, `{"error": "not found"}`))
+	mt.On(http.MethodPost, "/v1/users").RespondString(http.StatusCreated, "")
+	mt.On("*", "/v1/*").Respond(&MockResponse{StatusCode: http.StatusNoContent})
+
+	c := dcnl().
+		SetTransport(mt).
+		SetBaseURL("https://api.example.com/v1").
+		SetOpenAPIValidator(validator)
+	defer c.Close()
+	assertEqual(t, validator, c.OpenAPIValidator())
+
+	res, err := c.R().SetQueryParam("limit", "10").SetQueryParamsFromValues(map[string][]string{
+		"status": {"active", "blocked"},
+	}).Get("/users")
+	assertNil(t, err)
+	assertEqual(t, `[{"id": 1, "name": "Jeeva"}]`, res.String())
+
+	_, err = c.R().Get("/users/me")
+	assertNil(t, err)
+
+	_, err = c.R().
+		SetHeader("X-Request-Id", "0b4c0f5c-9a43-4b6e-8f3e-1e2f3a4b5c6d").
+		SetBody(map[string]any{"id": 3, "name": "resty"}).
+		Post("/users")
+	assertNil(t, err)
+
+	assertViolations := func(err error, expected ...string) {
+		t.Helper()
+		assertErrorIs(t, ErrOpenAPIViolation, err)
+		var result []string
+		for _, v := range err.(*OpenAPIValidationError).Violations {
+			result = append(result, v.String())
+		}
+		assertEqual(t, expected, result)
+	}
+
+	_, err = c.R().SetQueryParam("limit", "0").SetQueryParam("status", "deleted").Get("/users")
+	assertViolations(err,
+		"request query.limit: must be at least 1",
+		"request query.status[0]: value deleted is not one of [active blocked]",
+	)
+
+	res, err = c.R().Get("/users/1")
+	assertViolations(err,
+		"response body.createdAt: must be a valid date-time",
+		"response body.manager.age: additional property is not allowed",
+		"response body.manager.id: expected type integer, got string",
+		"response body.name: length must be at least 1",
+		"response body.role: must match exactly one of the schemas, matched 0",
+		"response body.tags: must have at most 2 items",
+	)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, true, strings.HasPrefix(err.Error(),
+		"resty: openapi contract violation on GET https://api.example.com/v1/users/1; response body.createdAt"))
+
+	_, err = c.R().Get("/users/2")
+	assertViolations(err, "response body.message: required property is missing")
+
+	_, err = c.R().Get("/users/abc")
+	assertViolations(err, "request path.id: expected type integer, got string")
+
+	_, err = c.R().SetHeader("X-Request-Id", "1").SetBody("name=resty").Post("/users")
+	assertViolations(err,
+		"request header.X-Request-Id: must be a valid uuid",
+		`request header.Content-Type: content type "text/plain; charset=utf-8" is not documented`,
+	)
+
+	_, err = c.R().Post("/users")
+	assertViolations(err,
+		"request header.X-Request-Id: required parameter is missing",
+		"request body: required request body is missing",
+	)
+
+	_, err = c.R().Get("/orders")
+	assertViolations(err, "request path: path /orders is not defined")
+
+	_, err = c.R().Delete("/users/1")
+	assertViolations(err, "request method: operation DELETE /users/{id} is not defined")
+
+	t.Run("on violation", func(t *testing.T) {
+		var violations []*OpenAPIViolation
+		validator.SetOnViolation(func(res *Response, v []*OpenAPIViolation) {
+			violations = append(violations, v...)
+		})
+		defer validator.SetOnViolation(nil)
+
+		res, err := c.R().Get("/users/2")
+		assertNil(t, err)
+		assertEqual(t, http.StatusNotFound, res.StatusCode())
+		assertEqual(t, 1, len(violations))
+		assertEqual(t, "response", violations[0].In)
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/users", nil)
+		req.Header.Set("X-Request-Id", "0b4c0f5c-9a43-4b6e-8f3e-1e2f3a4b5c6d")
+		req.Header.Set(hdrContentTypeKey, jsonContentType)
+		violations := validator.ValidateRequest(req, []byte(`{"id": 1.5}`))
+		assertEqual(t, 2, len(violations))
+		assertEqual(t, "request body.name: required property is missing", violations[0].String())
+		assertEqual(t, "request body.id: expected type integer, got number", violations[1].String())
+
+		violations = validator.ValidateResponse(&http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Request:    req,
+		}, nil)
+		assertEqual(t, "response status: status code 500 is not documented", violations[0].String())
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		_, err := NewOpenAPIValidator([]byte(`{"swagger": "2.0"}`))
+		assertEqual(t, `resty: openapi: unsupported version ""`, err.Error())
+
+		_, err = NewOpenAPIValidator([]byte(`{`))
+		assertNotNil(t, err)
+	})
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var hdrLinkKey = http.CanonicalHeaderKey("Link")
+
+// NextPageFunc type is for the pagination, it prepares the request of the next
+// page, i.e., the clone of the previous page request, based on the previous page
+// response. It returns `false` if there are no more pages. See [Client.Paginate].
+//
+// The built-in implementations are [LinkHeaderNextPage], [CursorNextPage],
+// [PageNumberNextPage], and [OffsetNextPage].
+type NextPageFunc func(next *Request, prev *Response) (bool, error)
+
+// Paginator type lazily fetches the pages of the paginated API, see
+// [Client.Paginate].
+type Paginator struct {
+	req       *Request
+	next      NextPageFunc
+	itemsPath string
+	maxPages  int
+}
+
+// Paginate method returns the [Paginator] for the given request and next page
+// function. The request is the template of the first page, it must have the
+// method and URL, see [Request.SetMethod] and [Request.SetURL]; it is not sent
+// itself, the clone of it is sent for every page, see [Request.Clone].
+//
+//	pager := client.Paginate(
+//		client.R().SetMethod(resty.MethodGet).SetURL("/users").SetQueryParam("per_page", "100"),
+//		resty.LinkHeaderNextPage(),
+//	)
+//
+//	for users, err := range resty.PageItems[User](pager) {
+//		if err != nil {
+//			return err
+//		}
+//		// process the users of the page
+//	}
+//
+// The pagination stops when
+//   - The next page function returns `false` or an error.
+//   - The page is empty, i.e., no body, `null`, or `[]` on the items path, see [Paginator.SetItemsPath].
+//   - The page request fails or its response is an error, i.e., HTTP status code > 399;
+//     it is yielded as the [ResponseError] with [HTTPError].
+//   - The max pages is reached, see [Paginator.SetMaxPages].
+func (c *Client) Paginate(req *Request, next NextPageFunc) *Paginator {
+	return &Paginator{req: req, next: next}
+}
+
+// SetItemsPath method sets the dot-separated path of the items array in the
+// JSON page body, e.g., `data.items`, for the page wrapped in an object. By
+// default, the page body is the items array.
+//
+//	// {"data": {"items": [...]}, "meta": {"next_cursor": "abc"}}
+//	pager.SetItemsPath("data.items")
+func (p *Paginator) SetItemsPath(path string) *Paginator {
+	p.itemsPath = path
+	return p
+}
+
+// SetMaxPages method sets the maximum number of pages to be fetched, by
+// default, there is no limit.
+func (p *Paginator) SetMaxPages(n int) *Paginator {
+	p.maxPages = n
+	return p
+}
+
+// Pages method returns the iterator of the page responses, the pages are
+// fetched lazily while iterating. The error ends the iteration.
+//
+//	for res, err := range pager.Pages() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(res.String())
+//	}
+func (p *Paginator) Pages() iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		cur := p.req
+		for page := 1; p.maxPages <= 0 || page <= p.maxPages; page++ {
+			res, err := cur.Clone(cur.Context()).Send()
+			if err == nil && res.IsError() {
+				err = newHTTPError(res)
+			}
+			if err != nil {
+				yield(res, err)
+				return
+			}
+
+			items, err := pageItems(res, p.itemsPath)
+			if err != nil {
+				yield(res, err)
+				return
+			}
+			if isEmptyPage(items) {
+				return
+			}
+			if !yield(res, nil) || p.next == nil {
+				return
+			}
+
+			next := cur.Clone(cur.Context())
+			more, err := p.next(next, res)
+			if err != nil {
+				yield(res, err)
+				return
+			}
+			if !more {
+				return
+			}
+			cur = next
+		}
+	}
+}
+
+// PageItems function returns the iterator of the page items decoded into the
+// type T from the JSON page body, see [Client.Paginate] and
+// [Paginator.SetItemsPath]. The error ends the iteration.
+//
+//	for users, err := range resty.PageItems[User](pager) {
+//		// ...
+//	}
+func PageItems[T any](p *Paginator) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		for res, err := range p.Pages() {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			items, _ := pageItems(res, p.itemsPath)
+			var page []T
+			if err := json.Unmarshal(items, &page); err != nil {
+				yield(nil, fmt.Errorf("resty: pagination: decode page items: %w", err))
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+		}
+	}
+}
+
+// LinkHeaderNextPage function returns the [NextPageFunc] that follows the
+// `rel="next"` link of the `Link` response header ([RFC 8288], formerly
+// RFC 5988), e.g., the GitHub API.
+//
+//	Link: <https://api.example.com/users?page=2>; rel="next", <https://api.example.com/users?page=9>; rel="last"
+//
+// The next page URL replaces the request URL and the query params.
+//
+// [RFC 8288]: https://datatracker.ietf.org/doc/html/rfc8288
+func LinkHeaderNextPage() NextPageFunc {
+	return func(next *Request, prev *Response) (bool, error) {
+		link := findLinkRel(prev.Header().Values(hdrLinkKey), "next")
+		if len(link) == 0 {
+			return false, nil
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			return false, fmt.Errorf("resty: pagination: invalid next link: %w", err)
+		}
+		if prev.Request != nil && prev.Request.RawRequest != nil {
+			u = prev.Request.RawRequest.URL.ResolveReference(u)
+		}
+		next.SetURL(u.String())
+		next.QueryParams = url.Values{}
+		return true, nil
+	}
+}
+
+// CursorNextPage function returns the [NextPageFunc] that reads the cursor from
+// the dot-separated field path of the JSON page body and sets it as the given
+// query param of the next page request. The empty, `null`, or missing cursor
+// means no more pages.
+//
+//	// {"data": [...], "meta": {"next_cursor": "abc"}}
+//	client.Paginate(req, resty.CursorNextPage("cursor", "meta.next_cursor")).
+//		SetItemsPath("data")
+func CursorNextPage(param, field string) NextPageFunc {
+	return func(next *Request, prev *Response) (bool, error) {
+		raw, err := jsonPath(prev.Bytes(), field)
+		if err != nil {
+			return false, err
+		}
+		var cursor string
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &cursor); err != nil {
+				return false, fmt.Errorf("resty: pagination: decode cursor: %w", err)
+			}
+		} else if !bytes.Equal(raw, []byte("null")) {
+			cursor = string(raw)
+		}
+		if len(cursor) == 0 {
+			return false, nil
+		}
+		next.SetQueryParam(param, cursor)
+		return true, nil
+	}
+}
+
+// PageNumberNextPage function returns the [NextPageFunc] that increments the
+// page number query param, the first page number is the value set on the
+// request, otherwise `1`. The pagination stops on the empty page.
+//
+//	client.Paginate(client.R().SetMethod(resty.MethodGet).SetURL("/users"),
+//		resty.PageNumberNextPage("page"))
+func PageNumberNextPage(param string) NextPageFunc {
+	return func(next *Request, _ *Response) (bool, error) {
+		n, err := queryParamInt(next, param, 1)
+		if err != nil {
+			return false, err
+		}
+		next.SetQueryParam(param, strconv.Itoa(n+1))
+		return true, nil
+	}
+}
+
+// OffsetNextPage function returns the [NextPageFunc] that increments the offset
+// query param by the given limit, the first offset is the value set on the
+// request, otherwise `0`. The pagination stops on the empty page.
+//
+//	client.Paginate(client.R().SetMethod(resty.MethodGet).SetURL("/users").
+//		SetQueryParam("limit", "50"), resty.OffsetNextPage("offset", 50))
+func OffsetNextPage(param string, limit int) NextPageFunc {
+	return func(next *Request, _ *Response) (bool, error) {
+		n, err := queryParamInt(next, param, 0)
+		if err != nil {
+			return false, err
+		}
+		next.SetQueryParam(param, strconv.Itoa(n+limit))
+		return true, nil
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func queryParamInt(r *Request, param string, defaultValue int) (int, error) {
+	v := r.QueryParams.Get(param)
+	if len(v) == 0 {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("resty: pagination: invalid query param '%s': %w", param, err)
+	}
+	return n, nil
+}
+
+// pageItems returns the raw JSON of the items on the given path, the page body
+// is returned as-is for the empty path.
+func pageItems(res *Response, path string) ([]byte, error) {
+	body := bytes.TrimSpace(res.Bytes())
+	if len(path) == 0 || len(body) == 0 {
+		return body, nil
+	}
+	return jsonPath(body, path)
+}
+
+func isEmptyPage(items []byte) bool {
+	items = bytes.TrimSpace(items)
+	if len(items) == 0 || bytes.Equal(items, []byte("null")) {
+		return true
+	}
+	if items[0] == '[' {
+		return len(bytes.TrimSpace(items[1:len(items)-1])) == 0
+	}
+	return false
+}
+
+// jsonPath returns the raw JSON value of the dot-separated field path, or nil
+// if the field is missing.
+func jsonPath(body []byte, path string) ([]byte, error) {
+	raw := json.RawMessage(body)
+	for _, field := range strings.Split(path, ".") {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, fmt.Errorf("resty: pagination: field '%s' of '%s': %w", field, path, err)
+		}
+		v, found := obj[field]
+		if !found {
+			return nil, nil
+		}
+		raw = v
+	}
+	return raw, nil
+}
+
+// findLinkRel returns the target URL of the given relation type from the
+// `Link` header values, see RFC 8288.
+func findLinkRel(values []string, rel string) string {
+	for _, value := range values {
+		for len(value) > 0 {
+			start := strings.IndexByte(value, '<')
+			end := strings.IndexByte(value, '>')
+			if start == -1 || end < start {
+				break
+			}
+			target := value[start+1 : end]
+			value = value[end+1:]
+
+			// link params are up to the next link, i.e., `, <`
+			params := value
+			if idx := strings.Index(value, "<"); idx != -1 {
+				params = value[:idx]
+				value = value[idx:]
+			} else {
+				value = ""
+			}
+			for _, param := range strings.Split(params, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(k), "rel") {
+					continue
+				}
+				v = strings.Trim(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(v), ",")), `"`)
+				for _, r := range strings.Fields(v) {
+					if strings.EqualFold(r, rel) {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type paginationItem struct {
+	ID int `json:"id"`
+}
+
+func createPaginationServer() (*Client, func()) {
+	const total, size = 7, 3
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		start := 0
+		switch r.URL.Path {
+		case "/link", "/page":
+			page, _ := strconv.Atoi(q.Get("page"))
+			start = max(page-1, 0) * size
+		case "/cursor":
+			start, _ = strconv.Atoi(q.Get("cursor"))
+		case "/offset":
+			start, _ = strconv.Atoi(q.Get("offset"))
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		ids := make([]paginationItem, 0, size)
+		for i := start; i < min(start+size, total); i++ {
+			ids = append(ids, paginationItem{ID: i + 1})
+		}
+		w.Header().Set(hdrContentTypeKey, jsonContentType)
+
+		switch r.URL.Path {
+		case "/link":
+			if start+size < total {
+				page := start/size + 2
+				w.Header().Set(hdrLinkKey, fmt.Sprintf(`</link?page=%d>; rel="next", </link?page=3>; rel="last"`, page))
+			}
+			_ = encodeJSON(w, ids)
+		case "/cursor":
+			next := any(nil)
+			if start+size < total {
+				next = strconv.Itoa(start + size)
+			}
+			_ = encodeJSON(w, map[string]any{
+				"data": map[string]any{"items": ids},
+				"meta": map[string]any{"next_cursor": next},
+			})
+		default:
+			_ = encodeJSON(w, ids)
+		}
+	})
+	c := dcnl().SetBaseURL(ts.URL)
+	return c, func() {
+		c.Close()
+		ts.Close()
+	}
+}
+
+func collectPaginationIDs(t *testing.T, p *Paginator) []int {
+	t.Helper()
+	var ids []int
+	for items, err := range PageItems[paginationItem](p) {
+		assertNil(t, err)
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	c, closeFn := createPaginationServer()
+	defer closeFn()
+
+	all := []int{1, 2, 3, 4, 5, 6, 7}
+	tests := []struct {
+		name  string
+		pager *Paginator
+	}{
+		{"link header", c.Paginate(c.R().SetMethod(MethodGet).SetURL("/link"), LinkHeaderNextPage())},
+		{"cursor", c.Paginate(c.R().SetMethod(MethodGet).SetURL("/cursor"),
+			CursorNextPage("cursor", "meta.next_cursor")).SetItemsPath("data.items")},
+		{"page number", c.Paginate(c.R().SetMethod(MethodGet).SetURL("/page"), PageNumberNextPage("page"))},
+		{"offset", c.Paginate(c.R().SetMethod(MethodGet).SetURL("/offset"), OffsetNextPage("offset", 3))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqual(t, all, collectPaginationIDs(t, tc.pager))
+		})
+	}
+
+	t.Run("max pages and pages", func(t *testing.T) {
+		p := c.Paginate(c.R().SetMethod(MethodGet).SetURL("/page").SetQueryParam("page", "2"),
+			PageNumberNextPage("page")).SetMaxPages(1)
+		var pages int
+		for res, err := range p.Pages() {
+			assertNil(t, err)
+			assertEqual(t, `[{"id":4},{"id":5},{"id":6}]`, res.String())
+			pages++
+		}
+		assertEqual(t, 1, pages)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		p := c.Paginate(c.R().SetMethod(MethodGet).SetURL("/offset"), OffsetNextPage("offset", 3))
+		var ids []int
+		for items, err := range PageItems[paginationItem](p) {
+			assertNil(t, err)
+			ids = append(ids, items[0].ID)
+			break
+		}
+		assertEqual(t, []int{1}, ids)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		p := c.Paginate(c.R().SetMethod(MethodGet).SetURL("/error"), PageNumberNextPage("page"))
+		for _, err := range PageItems[paginationItem](p) {
+			var httpErr *HTTPError
+			assertEqual(t, true, errors.As(err, &httpErr))
+			assertEqual(t, http.StatusInternalServerError, httpErr.StatusCode)
+		}
+	})
+
+	t.Run("invalid page param", func(t *testing.T) {
+		p := c.Paginate(c.R().SetMethod(MethodGet).SetURL("/page").SetQueryParam("page", "x"),
+			PageNumberNextPage("page"))
+		var errs int
+		for _, err := range p.Pages() {
+			if err != nil {
+				errs++
+			}
+		}
+		assertEqual(t, 1, errs)
+	})
+}
+
+func TestFindLinkRel(t *testing.T) {
+	values := []string{
+		`<https://api.example.com/users?page=1>; rel="prev first"`,
+		`<https://api.example.com/users?page=3>; title="a;b"; rel=next, <https://api.example.com/users?page=9>; rel="last"`,
+	}
+	assertEqual(t, "https://api.example.com/users?page=3", findLinkRel(values, "next"))
+	assertEqual(t, "https://api.example.com/users?page=1", findLinkRel(values, "first"))
+	assertEqual(t, "https://api.example.com/users?page=9", findLinkRel(values, "LAST"))
+	assertEqual(t, "", findLinkRel(values, "self"))
+	assertEqual(t, "", findLinkRel([]string{"invalid"}, "next"))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedPathParamsKind error is returned when the path params value is
+// not a struct, see [Request.SetPathParamsFromStruct].
+var ErrUnsupportedPathParamsKind = errors.New("resty: unsupported path params kind, it must be struct")
+
+// PathParamFormatterFunc type is for formatting the typed path param values
+// into the string, it returns `false` to fall back to the default formatting.
+// See [Client.SetPathParamFormatter].
+type PathParamFormatterFunc func(v any) (string, bool)
+
+// SetPathParamFormatter method sets the custom formatter of the typed path param
+// values, see [Request.SetPathParamValue]. The values not handled by the
+// formatter are formatted by default as below
+//   - [time.Time] as per the time format, see [Client.SetTimeFormat]
+//   - [encoding.TextMarshaler], e.g., UUID types
+//   - [fmt.Stringer]
+//   - Numbers and booleans using the [strconv] package
+//
+// For example, to format the dates without the time
+//
+//	client.SetPathParamFormatter(func(v any) (string, bool) {
+//		if t, ok := v.(time.Time); ok {
+//			return t.Format(time.DateOnly), true
+//		}
+//		return "", false
+//	})
+func (c *Client) SetPathParamFormatter(fn PathParamFormatterFunc) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pathParamFormatter = fn
+	return c
+}
+
+// SetPathParamValue method sets a single URL path key-value pair of any type in
+// the current request, so the call sites do not have to convert the values using
+// [strconv] or [fmt.Sprint]. The value is formatted as per
+// [Client.SetPathParamFormatter] and escaped using [url.PathEscape].
+//
+//	client.R().
+//		SetPathParamValue("userId", 1001).
+//		SetPathParamValue("orderId", orderUUID).
+//		Get("/v1/users/{userId}/orders/{orderId}")
+//
+// It overrides the path parameter set at the client instance level.
+func (r *Request) SetPathParamValue(param string, value any) *Request {
+	s, err := r.formatPathParam(value)
+	if err != nil {
+		r.log.Errorf("path param '%s': %v", param, err)
+		return r
+	}
+	return r.SetPathParam(param, s)
+}
+
+// SetPathParamValues method sets multiple URL path key-value pairs of any type
+// at one go in the current request, see [Request.SetPathParamValue].
+//
+//	client.R().SetPathParamValues(map[string]any{
+//		"userId": 1001,
+//		"since":  time.Now().Add(-24 * time.Hour),
+//	})
+func (r *Request) SetPathParamValues(params map[string]any) *Request {
+	for p, v := range params {
+		r.SetPathParamValue(p, v)
+	}
+	return r
+}
+
+// SetPathParamsFromStruct method sets the URL path key-value pairs from the
+// fields of the given struct in the current request, see
+// [Request.SetPathParamValue].
+//
+//	type OrderPath struct {
+//		UserID  int       `path:"userId"`
+//		OrderID uuid.UUID `path:"orderId"`
+//		Path    string    `path:"path,raw"`
+//		Version string    `path:"version,omitempty"`
+//	}
+//
+//	client.R().
+//		SetPathParamsFromStruct(OrderPath{UserID: 1001, OrderID: id, Path: "a/b"}).
+//		Get("/{version}/users/{userId}/orders/{orderId}/{path}")
+//
+// The param name is taken from the `path` tag, falling back to the field name;
+// the tag options are
+//   - omitempty: the empty value is skipped
+//   - raw: the value is not escaped, see [Request.SetRawPathParam]
+func (r *Request) SetPathParamsFromStruct(v any) *Request {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return r
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || rv.Type() == timeType {
+		r.log.Errorf("%v", ErrUnsupportedPathParamsKind)
+		return r
+	}
+
+	t := rv.Type()
+	for _, f := range structFields(t, "path") {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		s, err := r.formatPathParam(fv.Interface())
+		if err != nil {
+			r.log.Errorf("path param '%s': %v", f.name, err)
+			continue
+		}
+		if isRawPathParamField(t.FieldByIndex(f.index)) {
+			r.SetRawPathParam(f.name, s)
+		} else {
+			r.SetPathParam(f.name, s)
+		}
+	}
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func isRawPathParamField(sf reflect.StructField) bool {
+	_, opts, _ := strings.Cut(sf.Tag.Get("path"), ",")
+	return slices.Contains(strings.Split(opts, ","), "raw")
+}
+
+func (r *Request) formatPathParam(v any) (string, error) {
+	if fn := r.pathParamFormatter; fn != nil {
+		if s, ok := fn(v); ok {
+			return s, nil
+		}
+	}
+
+	switch tv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return tv, nil
+	case []byte:
+		return string(tv), nil
+	case time.Time:
+		return formatTime(tv, r.timeFormat), nil
+	case encoding.TextMarshaler:
+		b, err := tv.MarshalText()
+		return string(b), err
+	case fmt.Stringer:
+		return tv.String(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "", nil
+		}
+		return r.formatPathParam(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value type %T", v)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPathID [2]byte
+
+func (id testPathID) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(id[:]))), nil
+}
+
+type testPathParams struct {
+	UserID  int64      `path:"userId"`
+	OrderID testPathID `path:"orderId"`
+	Path    string     `path:"path,raw"`
+	Version string     `path:"version,omitempty"`
+	Active  *bool      `path:"active"`
+	Ignored string     `path:"-"`
+	Amount  float64
+}
+
+func TestRequestPathParamValues(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.EscapedPath()))
+	})
+	defer ts.Close()
+
+	c, lb := dcldb()
+	c.SetBaseURL(ts.URL)
+	defer c.Close()
+
+	since := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	res, err := c.R().
+		SetPathParamValue("userId", 1001).
+		SetPathParamValues(map[string]any{
+			"since":  since,
+			"ratio":  float32(0.5),
+			"active": true,
+			"name":   "a/b",
+			"count":  uint8(7),
+		}).
+		Get("/users/{userId}/{since}/{ratio}/{active}/{name}/{count}")
+	assertNil(t, err)
+	assertEqual(t, "/users/1001/2024-03-01T10:30:00Z/0.5/true/a%2Fb/7", res.String())
+
+	active := false
+	res, err = c.R().
+		SetPathParamsFromStruct(&testPathParams{
+			UserID:  42,
+			OrderID: testPathID{'a', 'b'},
+			Path:    "x/y",
+			Active:  &active,
+			Amount:  9.99,
+		}).
+		SetPathParam("version", "v1").
+		Get("/{version}/users/{userId}/orders/{orderId}/{path}/{active}/{Amount}")
+	assertNil(t, err)
+	assertEqual(t, "/v1/users/42/orders/AB/x/y/false/9.99", res.String())
+
+	// custom formatter, not handled values fall back to the default formatting
+	c.SetPathParamFormatter(func(v any) (string, bool) {
+		if t, ok := v.(time.Time); ok {
+			return t.Format(time.DateOnly), true
+		}
+		return "", false
+	})
+	res, err = c.R().
+		SetPathParamValues(map[string]any{"since": since, "userId": 7}).
+		Get("/users/{userId}/{since}")
+	assertNil(t, err)
+	assertEqual(t, "/users/7/2024-03-01", res.String())
+
+	// invalid values
+	r := c.R().
+		SetPathParamValue("bad", map[string]string{}).
+		SetPathParamsFromStruct("not a struct").
+		SetPathParamsFromStruct((*testPathParams)(nil))
+	assertEqual(t, 0, len(r.PathParams))
+	assertEqual(t, true, strings.Contains(lb.String(), "path param 'bad': unsupported value type map[string]string"))
+	assertEqual(t, true, strings.Contains(lb.String(), ErrUnsupportedPathParamsKind.Error()))
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// NewPowerOfTwoChoices method creates the new latency-aware Power of Two
+// Choices(P2C) request load balancer instance with given base URLs.
+func NewPowerOfTwoChoices(baseURLs ...string) (*PowerOfTwoChoices, error) {
+	p2c := &PowerOfTwoChoices{
+		lock:         new(sync.Mutex),
+		decay:        10 * time.Second, // default is 10 seconds
+		errorPenalty: time.Second,      // default is 1 second
+		randIntN:     rand.IntN,
+		now:          time.Now,
+	}
+	if err := p2c.Refresh(baseURLs...); err != nil {
+		return p2c, err
+	}
+	return p2c, nil
+}
+
+var _ StatsLoadBalancer = (*PowerOfTwoChoices)(nil)
+
+// PowerOfTwoChoices struct used to implement the latency-aware Power of Two
+// Choices(P2C) request load balancer algorithm. It picks two Base URLs at
+// random and returns the one with the lower cost, i.e., the hosts' weights
+// are adjusted dynamically from the request feedback instead of the static weights.
+//
+// The cost of the host is its peak exponentially weighted moving average(EWMA)
+// latency plus the error penalty times its EWMA error rate
+//
+//	cost = latency + penalty * error rate
+//
+// The cost decays toward zero while the host receives no requests, so the
+// slow or failing hosts are retried after a while.
+type PowerOfTwoChoices struct {
+	lock         *sync.Mutex
+	hosts        []*p2cHost
+	decay        time.Duration
+	errorPenalty time.Duration
+	randIntN     func(n int) int
+	now          func() time.Time
+	stats        lbStats
+}
+
+// Next method returns the next Base URL based on the Power of Two Choices(P2C)
+func (p2c *PowerOfTwoChoices) Next() (string, error) {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+
+	var h *p2cHost
+	switch n := len(p2c.hosts); n {
+	case 0:
+		return "", ErrNoActiveHost
+	case 1:
+		h = p2c.hosts[0]
+	default:
+		i, j := p2c.randIntN(n), p2c.randIntN(n-1)
+		if j >= i {
+			j++
+		}
+		now := p2c.now()
+		h = p2c.hosts[i]
+		if b := p2c.hosts[j]; b.cost(now, p2c.decay, p2c.errorPenalty) < h.cost(now, p2c.decay, p2c.errorPenalty) {
+			h = b
+		}
+	}
+	p2c.stats.begin(h.baseURL)
+	return h.baseURL, nil
+}
+
+// Feedback method records the latency and result of the request into the
+// host's moving averages
+func (p2c *PowerOfTwoChoices) Feedback(f *RequestFeedback) {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	p2c.stats.end(f)
+
+	for _, h := range p2c.hosts {
+		if h.baseURL == f.BaseURL {
+			h.observe(p2c.now(), p2c.decay, f)
+			break
+		}
+	}
+}
+
+// Close method does nothing in Power of Two Choices(P2C) request load balancer
+func (p2c *PowerOfTwoChoices) Close() error { return nil }
+
+// Refresh method reset the existing Base URLs with the given Base URLs slice to
+// refresh it; the moving averages of the existing Base URLs are retained.
+func (p2c *PowerOfTwoChoices) Refresh(baseURLs ...string) error {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+
+	existing := make(map[string]*p2cHost, len(p2c.hosts))
+	for _, h := range p2c.hosts {
+		existing[h.baseURL] = h
+	}
+	hosts := make([]*p2cHost, 0, len(baseURLs))
+	for _, u := range baseURLs {
+		baseURL, err := extractBaseURL(u)
+		if err != nil {
+			return err
+		}
+		h, found := existing[baseURL]
+		if !found {
+			h = &p2cHost{baseURL: baseURL}
+		}
+		hosts = append(hosts, h)
+	}
+
+	// after processing, assign the updates
+	p2c.setHosts(hosts)
+	return nil
+}
+
+// AddTarget method adds the given Base URL, if not exists; the in-flight requests
+// are not affected. It is safe for concurrent use, e.g., to apply the endpoint
+// changes from the service discovery.
+func (p2c *PowerOfTwoChoices) AddTarget(baseURL string) error {
+	baseURL, err := extractBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	if !slices.ContainsFunc(p2c.hosts, func(h *p2cHost) bool { return h.baseURL == baseURL }) {
+		p2c.hosts = append(slices.Clip(p2c.hosts), &p2cHost{baseURL: baseURL})
+	}
+	return nil
+}
+
+// RemoveTarget method removes the given Base URL, if exists; the in-flight
+// requests to it are not affected.
+func (p2c *PowerOfTwoChoices) RemoveTarget(baseURL string) {
+	if u, err := extractBaseURL(baseURL); err == nil {
+		baseURL = u
+	}
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	p2c.setHosts(slices.DeleteFunc(slices.Clone(p2c.hosts), func(h *p2cHost) bool {
+		return h.baseURL == baseURL
+	}))
+}
+
+// SetTargets method replaces the Base URLs with the given ones, the moving
+// averages and statistics of the existing Base URLs are retained. See
+// [PowerOfTwoChoices.AddTarget]
+func (p2c *PowerOfTwoChoices) SetTargets(baseURLs ...string) error {
+	return p2c.Refresh(baseURLs...)
+}
+
+// Stats method returns the statistics of the Base URLs
+func (p2c *PowerOfTwoChoices) Stats() []TargetStats {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	result := make([]TargetStats, 0, len(p2c.hosts))
+	for _, h := range p2c.hosts {
+		result = append(result, p2c.stats.snapshot(h.baseURL, HostStateActive))
+	}
+	return result
+}
+
+// SetDecay method sets the decay time of the moving averages, default is `10` seconds;
+// the shorter decay reacts faster to the latency changes.
+func (p2c *PowerOfTwoChoices) SetDecay(d time.Duration) {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	if d > 0 {
+		p2c.decay = d
+	}
+}
+
+// SetErrorPenalty method sets the latency penalty of the failed requests,
+// default is `1` second; i.e., the host with all requests failed costs as
+// much as the host of latency `1` second.
+func (p2c *PowerOfTwoChoices) SetErrorPenalty(d time.Duration) {
+	p2c.lock.Lock()
+	defer p2c.lock.Unlock()
+	p2c.errorPenalty = d
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type p2cHost struct {
+	baseURL   string
+	latency   float64 // EWMA latency in nanoseconds
+	errorRate float64 // EWMA error rate in [0, 1]
+	updatedAt time.Time
+}
+
+// setHosts method replaces the hosts. The caller must hold the lock.
+func (p2c *PowerOfTwoChoices) setHosts(hosts []*p2cHost) {
+	p2c.hosts = hosts
+	retained := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		retained = append(retained, h.baseURL)
+	}
+	p2c.stats.retain(retained...)
+}
+
+// observe method updates the moving averages with the request feedback.
+func (h *p2cHost) observe(now time.Time, decay time.Duration, f *RequestFeedback) {
+	errSample := 0.0
+	if !f.Success {
+		errSample = 1
+	}
+	if h.updatedAt.IsZero() {
+		h.latency, h.errorRate, h.updatedAt = float64(f.Latency), errSample, now
+		return
+	}
+
+	// the sample weight grows with the time elapsed since the last one, and
+	// it is at least 10% for the bursts of requests
+	alpha := max(1-math.Exp(-float64(now.Sub(h.updatedAt))/float64(decay)), 0.1)
+	if sample := float64(f.Latency); sample > h.latency {
+		h.latency = sample // the latency spikes are reflected at once
+	} else if sample > 0 {
+		h.latency += (sample - h.latency) * alpha
+	}
+	h.errorRate += (errSample - h.errorRate) * alpha
+	h.updatedAt = now
+}
+
+// cost method returns the host cost, it decays while the host is idle.
+func (h *p2cHost) cost(now time.Time, decay, errorPenalty time.Duration) float64 {
+	if h.updatedAt.IsZero() {
+		return 0
+	}
+	c := h.latency + float64(errorPenalty)*h.errorRate
+	return c * math.Exp(-float64(now.Sub(h.updatedAt))/float64(decay))
+}
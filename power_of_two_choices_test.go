@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPowerOfTwoChoices(t *testing.T) {
+	p2c, err := NewPowerOfTwoChoices("https://api1.example.com", "https://api2.example.com", "https://api3.example.com")
+	assertNil(t, err)
+	defer p2c.Close()
+
+	now := time.Now()
+	p2c.now = func() time.Time { return now }
+	var picks []int
+	p2c.randIntN = func(n int) int {
+		v := picks[0]
+		picks = picks[1:]
+		return v
+	}
+	next := func(i, j int) string {
+		t.Helper()
+		picks = []int{i, j}
+		baseURL, err := p2c.Next()
+		assertNil(t, err)
+		return baseURL
+	}
+
+	p2c.Feedback(&RequestFeedback{BaseURL: "https://api1.example.com", Success: true, Latency: 10 * time.Millisecond})
+	p2c.Feedback(&RequestFeedback{BaseURL: "https://api2.example.com", Success: true, Latency: 200 * time.Millisecond})
+	p2c.Feedback(&RequestFeedback{BaseURL: "https://api3.example.com", Success: false, Latency: 5 * time.Millisecond})
+	p2c.Feedback(&RequestFeedback{BaseURL: "https://unknown.example.com", Success: true})
+
+	assertEqual(t, "https://api1.example.com", next(0, 0)) // api1 vs api2
+	assertEqual(t, "https://api1.example.com", next(1, 0)) // api2 vs api1
+	assertEqual(t, "https://api2.example.com", next(1, 1)) // api2 vs api3, error penalty
+	assertEqual(t, "https://api1.example.com", next(2, 0)) // api3 vs api1
+
+	// the latency spike is reflected at once
+	now = now.Add(10 * time.Millisecond)
+	p2c.Feedback(&RequestFeedback{BaseURL: "https://api1.example.com", Success: true, Latency: 500 * time.Millisecond})
+	assertEqual(t, "https://api2.example.com", next(0, 0))
+
+	// and recovers with the faster responses
+	for i := 0; i < 30; i++ {
+		now = now.Add(time.Second)
+		p2c.Feedback(&RequestFeedback{BaseURL: "https://api1.example.com", Success: true, Latency: 10 * time.Millisecond})
+		p2c.Feedback(&RequestFeedback{BaseURL: "https://api2.example.com", Success: true, Latency: 200 * time.Millisecond})
+	}
+	assertEqual(t, "https://api1.example.com", next(0, 0))
+
+	t.Run("refresh", func(t *testing.T) {
+		assertNil(t, p2c.Refresh("https://api1.example.com", "https://api4.example.com"))
+
+		// the new host is preferred until observed
+		assertEqual(t, "https://api4.example.com", next(0, 0))
+		assertEqual(t, true, p2c.hosts[0].latency > 0)
+
+		assertNotNil(t, p2c.Refresh("://api1.example.com"))
+	})
+
+	t.Run("single and no host", func(t *testing.T) {
+		p2c, _ := NewPowerOfTwoChoices("https://api1.example.com")
+		baseURL, err := p2c.Next()
+		assertNil(t, err)
+		assertEqual(t, "https://api1.example.com", baseURL)
+
+		p2c, _ = NewPowerOfTwoChoices()
+		_, err = p2c.Next()
+		assertErrorIs(t, ErrNoActiveHost, err)
+	})
+}
+
+func TestPowerOfTwoChoicesClient(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	p2c, err := NewPowerOfTwoChoices(ts.URL)
+	assertNil(t, err)
+	p2c.SetDecay(time.Second)
+	p2c.SetErrorPenalty(2 * time.Second)
+
+	c := dcnl().SetLoadBalancer(p2c)
+	defer c.Close()
+
+	res, err := c.R().Get("/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+	assertEqual(t, true, p2c.hosts[0].latency > 0)
+	assertEqual(t, float64(0), p2c.hosts[0].errorRate)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"sync"
+	"time"
+)
+
+// progressRateWindow is the window of the rolling transfer rate.
+const progressRateWindow = 3 * time.Second
+
+// DownloadProgress struct holds the live progress of the response body
+// download, see [Request.SetDownloadProgressCallback] and [DownloadOptions].OnProgress.
+type DownloadProgress struct {
+	// Written is the count of the bytes downloaded so far, it includes the
+	// bytes of the resumed download.
+	Written int64
+
+	// Total is the total bytes of the download; it is -1 if unknown.
+	Total int64
+
+	// Percent is the completion percentage in the range [0, 100];
+	// it is -1 if the total is unknown.
+	Percent float64
+
+	// Rate is the rolling transfer rate in bytes per second, computed
+	// over the last three seconds.
+	Rate float64
+
+	// Elapsed is the time elapsed since the download started.
+	Elapsed time.Duration
+}
+
+// ETA method returns the estimated time to complete the download based on the
+// rolling transfer rate. It returns -1 if the total is unknown or the transfer
+// rate is not yet known.
+//
+//	fmt.Printf("\r%.1f%% %.0f KB/s ETA %s", p.Percent, p.Rate/1024, p.ETA().Round(time.Second))
+func (p DownloadProgress) ETA() time.Duration {
+	if p.Total < 0 || p.Rate <= 0 {
+		return -1
+	}
+	remaining := max(p.Total-p.Written, 0)
+	return time.Duration(float64(remaining) / p.Rate * float64(time.Second))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type progressSample struct {
+	at      time.Time
+	written int64
+}
+
+// progressTracker computes the download progress; it is safe for the
+// concurrent use, e.g., the parallel chunks of [Client.Download].
+type progressTracker struct {
+	lock    sync.Mutex
+	fn      func(DownloadProgress)
+	total   int64
+	written int64
+	start   time.Time
+	samples []progressSample
+}
+
+func newProgressTracker(fn func(DownloadProgress), written, total int64) *progressTracker {
+	if total <= 0 {
+		total = -1
+	}
+	now := time.Now()
+	return &progressTracker{
+		fn:      fn,
+		total:   total,
+		written: written,
+		start:   now,
+		samples: []progressSample{{at: now, written: written}},
+	}
+}
+
+func (pt *progressTracker) add(n int64) {
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	now := time.Now()
+	pt.written += n
+	pt.samples = append(pt.samples, progressSample{at: now, written: pt.written})
+	for len(pt.samples) > 2 && now.Sub(pt.samples[1].at) >= progressRateWindow {
+		pt.samples = pt.samples[1:]
+	}
+
+	p := DownloadProgress{
+		Written: pt.written,
+		Total:   pt.total,
+		Percent: -1,
+		Elapsed: now.Sub(pt.start),
+	}
+	if pt.total > 0 {
+		p.Percent = min(float64(pt.written)*100/float64(pt.total), 100)
+	}
+	first := pt.samples[0]
+	if d := now.Sub(first.at).Seconds(); d > 0 {
+		p.Rate = float64(pt.written-first.written) / d
+	}
+
+	// invoked under the lock to report the progress in order
+	pt.fn(p)
+}
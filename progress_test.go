@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadProgressETA(t *testing.T) {
+	p := DownloadProgress{Written: 100, Total: 300, Rate: 50}
+	assertEqual(t, 4*time.Second, p.ETA())
+
+	p = DownloadProgress{Written: 100, Total: -1, Rate: 50}
+	assertEqual(t, time.Duration(-1), p.ETA())
+
+	p = DownloadProgress{Written: 100, Total: 300}
+	assertEqual(t, time.Duration(-1), p.ETA())
+}
+
+func TestProgressTracker(t *testing.T) {
+	var got []DownloadProgress
+	pt := newProgressTracker(func(p DownloadProgress) { got = append(got, p) }, 50, 200)
+	time.Sleep(10 * time.Millisecond)
+	pt.add(50)
+	pt.add(100)
+
+	assertEqual(t, 2, len(got))
+	assertEqual(t, int64(100), got[0].Written)
+	assertEqual(t, float64(50), got[0].Percent)
+	assertEqual(t, int64(200), got[1].Written)
+	assertEqual(t, float64(100), got[1].Percent)
+	assertEqual(t, true, got[1].Rate > 0)
+	assertEqual(t, true, got[1].Elapsed >= 10*time.Millisecond)
+
+	got = nil
+	pt = newProgressTracker(func(p DownloadProgress) { got = append(got, p) }, 0, 0)
+	pt.add(10)
+	assertEqual(t, int64(-1), got[0].Total)
+	assertEqual(t, float64(-1), got[0].Percent)
+}
+
+func TestRequestDownloadProgressCallback(t *testing.T) {
+	content := []byte(strings.Repeat("resty", 20000))
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	})
+	defer ts.Close()
+
+	var last DownloadProgress
+	calls := 0
+	fp := filepath.Join(t.TempDir(), "progress.bin")
+	c := dcnl()
+	res, err := c.R().
+		SetHeader(hdrAcceptEncodingKey, "identity").
+		SetOutputFileName(fp).
+		SetDownloadProgressCallback(func(p DownloadProgress) {
+			calls++
+			last = p
+		}).
+		Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, int64(len(content)), res.Size())
+	assertEqual(t, true, calls > 0)
+	assertEqual(t, int64(len(content)), last.Written)
+	assertEqual(t, int64(len(content)), last.Total)
+	assertEqual(t, float64(100), last.Percent)
+
+	b, err := os.ReadFile(fp)
+	assertNil(t, err)
+	assertEqual(t, content, b)
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// createSOCKS5TestServer function starts the minimal SOCKS5 server, it supports
+// only the CONNECT command with username/password authentication.
+func createSOCKS5TestServer(t *testing.T, username, password string) (string, *atomic.Int32) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	connects := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if target := socks5TestHandshake(conn, username, password); len(target) > 0 {
+					upstream, err := net.Dial("tcp", target)
+					if err != nil {
+						return
+					}
+					defer upstream.Close()
+					connects.Add(1)
+					_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+					go func() { _, _ = io.Copy(upstream, conn) }()
+					_, _ = io.Copy(conn, upstream)
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), connects
+}
+
+func socks5TestHandshake(conn net.Conn, username, password string) string {
+	buf := make([]byte, 256)
+	// greeting: version, methods
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return ""
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return ""
+	}
+	_, _ = conn.Write([]byte{5, 2}) // username/password
+
+	// auth: version, ulen, uname, plen, passwd
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return ""
+	}
+	u := make([]byte, buf[1])
+	_, _ = io.ReadFull(conn, u)
+	_, _ = io.ReadFull(conn, buf[:1])
+	p := make([]byte, buf[0])
+	_, _ = io.ReadFull(conn, p)
+	if string(u) != username || string(p) != password {
+		_, _ = conn.Write([]byte{1, 1})
+		return ""
+	}
+	_, _ = conn.Write([]byte{1, 0})
+
+	// request: version, cmd, rsv, atyp, addr, port
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return ""
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		_, _ = io.ReadFull(conn, buf[:4])
+		host = net.IP(buf[:4]).String()
+	case 3:
+		_, _ = io.ReadFull(conn, buf[:1])
+		n := int(buf[0])
+		_, _ = io.ReadFull(conn, buf[:n])
+		host = string(buf[:n])
+	default:
+		return ""
+	}
+	_, _ = io.ReadFull(conn, buf[:2])
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+}
+
+func TestClientSOCKS5Proxy(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	addr, connects := createSOCKS5TestServer(t, "user", "secret")
+
+	c := dcnl().SetProxy("socks5://user:secret@" + addr)
+	res, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+	assertEqual(t, int32(1), connects.Load())
+
+	// invalid credentials
+	c = dcnl().SetProxy("socks5h://user:invalid@" + addr)
+	_, err = c.R().Get(ts.URL + "/")
+	assertNotNil(t, err)
+	assertEqual(t, int32(1), connects.Load())
+}
+
+func TestClientSetProxyUnsupportedScheme(t *testing.T) {
+	var lb bytes.Buffer
+	c := dcnl().SetLogger(&logger{l: log.New(&lb, "", 0)})
+
+	c.SetProxy("ftp://proxyserver:2121")
+	assertNil(t, c.ProxyURL())
+	assertEqual(t, true, strings.Contains(lb.String(), ErrUnsupportedProxyScheme.Error()))
+
+	c.SetProxy(ProxyDirect)
+	assertNil(t, c.ProxyURL())
+
+	c.SetHostProxy("10.0.0.0/33", "http://proxyserver:8888")
+	assertEqual(t, true, strings.Contains(lb.String(), "invalid CIDR address"))
+	assertNil(t, c.proxySelector)
+}
+
+func TestClientSchemeHostProxy(t *testing.T) {
+	c := dcnl().
+		SetProxy("http://default:8888").
+		SetSchemeProxy("HTTPS", "socks5://secure:1080").
+		SetHostProxy("*.internal.example.com", ProxyDirect).
+		SetHostProxy(".corp.example.com", "http://corp:3128").
+		SetHostProxy("10.0.0.0/8", ProxyDirect).
+		SetHostProxy("[::1]", ProxyDirect).
+		SetHostProxy("api.example.com", "http://api:3128")
+
+	transport, err := c.HTTPTransport()
+	assertNil(t, err)
+
+	proxyFor := func(rawURL string) string {
+		u, _ := url.Parse(rawURL)
+		pURL, err := transport.Proxy(&http.Request{URL: u})
+		assertNil(t, err)
+		if pURL == nil {
+			return ""
+		}
+		return pURL.String()
+	}
+
+	assertEqual(t, "http://default:8888", proxyFor("http://example.com"))
+	assertEqual(t, "socks5://secure:1080", proxyFor("https://example.com"))
+	assertEqual(t, "", proxyFor("https://svc.internal.example.com"))
+	assertEqual(t, "", proxyFor("https://internal.example.com"))
+	assertEqual(t, "http://corp:3128", proxyFor("https://a.b.corp.example.com"))
+	assertEqual(t, "", proxyFor("http://10.1.2.3:8080"))
+	assertEqual(t, "http://default:8888", proxyFor("http://11.1.2.3:8080"))
+	assertEqual(t, "", proxyFor("http://[::1]:8080"))
+	assertEqual(t, "http://api:3128", proxyFor("https://API.example.com"))
+
+	// default proxy is updated, scheme and host proxies are retained
+	c.SetProxy("http://other:8888")
+	assertEqual(t, "http://other:8888", proxyFor("http://example.com"))
+	assertEqual(t, "", proxyFor("https://svc.internal.example.com"))
+
+	// selector is not shared with the clone
+	cc := c.Clone(c.Context())
+	cc.SetSchemeProxy("http", ProxyDirect)
+	assertEqual(t, "", proxyFor("http://example.com"))
+	assertEqual(t, "http://other:8888", c.proxySelector.defaultURL.String())
+	_, ok := c.proxySelector.schemes["http"]
+	assertEqual(t, false, ok)
+
+	c.RemoveProxy()
+	assertNil(t, c.proxySelector)
+	assertNil(t, transport.Proxy)
+}
+
+func createProxyTestServer(t *testing.T, name string) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// respond on behalf of the target server
+		_, _ = io.WriteString(w, name+":"+r.URL.Host+r.URL.Path)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestClientSetProxyFunc(t *testing.T) {
+	p1 := createProxyTestServer(t, "p1")
+	p2 := createProxyTestServer(t, "p2")
+
+	c := dcnl().SetProxyFunc(func(r *Request) (*url.URL, error) {
+		switch r.Method {
+		case MethodGet:
+			return url.Parse(p1.URL)
+		case MethodPost:
+			return url.Parse(p2.URL)
+		}
+		return nil, errors.New("no proxy for " + r.Method)
+	})
+
+	res, err := c.R().Get("http://example.com/get")
+	assertNil(t, err)
+	assertEqual(t, "p1:example.com/get", res.String())
+	assertEqual(t, p1.URL, res.Request.ProxyURL().String())
+
+	res, err = c.R().Post("http://example.com/post")
+	assertNil(t, err)
+	assertEqual(t, "p2:example.com/post", res.String())
+
+	_, err = c.R().Delete("http://example.com/delete")
+	assertNotNil(t, err)
+	assertEqual(t, true, strings.Contains(err.Error(), "no proxy for DELETE"))
+
+	// not a Resty request
+	transport, _ := c.HTTPTransport()
+	pURL, err := transport.Proxy(httptest.NewRequest(MethodGet, "http://example.com", nil))
+	assertNil(t, err)
+	assertNil(t, pURL)
+}
+
+func TestClientSetProxyPool(t *testing.T) {
+	p1 := createProxyTestServer(t, "p1")
+	p2 := createProxyTestServer(t, "p2")
+	broken := createProxyTestServer(t, "broken")
+	broken.Close()
+
+	pool, err := NewProxyPool(ProxyRoundRobin, p1.URL, broken.URL, p2.URL)
+	assertNil(t, err)
+	pool.SetMaxFailures(2).SetRecoveryDuration(time.Minute)
+
+	c := dcnl().SetProxyPool(pool)
+	var got []string
+	for i := 0; i < 6; i++ {
+		res, err := c.R().Get("http://example.com/")
+		if err != nil {
+			got = append(got, "err")
+			continue
+		}
+		got = append(got, res.String()[:2])
+	}
+	// broken proxy is marked inactive after 2 failures
+	assertEqual(t, []string{"p1", "err", "p2", "p1", "err", "p2"}, got)
+	assertEqual(t, 2, pool.ActiveCount())
+
+	res, err := c.R().Get("http://example.com/")
+	assertNil(t, err)
+	assertEqual(t, "p1", res.String()[:2])
+	res, err = c.R().Get("http://example.com/")
+	assertNil(t, err)
+	assertEqual(t, "p2", res.String()[:2])
+
+	// inactive proxy is active on success
+	bURL, _ := url.Parse(broken.URL)
+	pool.MarkSucceeded(bURL)
+	assertEqual(t, 3, pool.ActiveCount())
+
+	pURL, _ := url.Parse(p1.URL)
+	pool.MarkFailed(pURL)
+	pool.MarkFailed(pURL)
+	pool.MarkFailed(bURL)
+	pool.MarkFailed(bURL)
+	assertEqual(t, 1, pool.ActiveCount())
+
+	c.RemoveProxy()
+	assertNil(t, c.proxyPool)
+}
+
+func TestProxyPoolNext(t *testing.T) {
+	_, err := NewProxyPool(ProxyRandom, "ftp://proxy:21")
+	assertErrorIs(t, ErrUnsupportedProxyScheme, err)
+
+	_, err = NewProxyPool(ProxyRandom, ProxyDirect)
+	assertErrorIs(t, ErrUnsupportedProxyScheme, err)
+
+	pool, err := NewProxyPool(ProxyRandom, "http://proxy1:3128", "socks5://proxy2:1080")
+	assertNil(t, err)
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		u, err := pool.Next()
+		assertNil(t, err)
+		seen[u.Host] = true
+	}
+	assertEqual(t, 2, len(seen))
+
+	// the random pick uses the client's random source
+	picks := func() []string {
+		c := dcnl().SetRandSource(rand.NewPCG(1, 2))
+		var hosts []string
+		for i := 0; i < 20; i++ {
+			u, err := pool.Proxy(c.R())
+			assertNil(t, err)
+			hosts = append(hosts, u.Host)
+		}
+		return hosts
+	}
+	assertEqual(t, picks(), picks())
+
+	pool.SetMaxFailures(0)
+	u1, _ := url.Parse("http://proxy1:3128")
+	u2, _ := url.Parse("socks5://proxy2:1080")
+	pool.MarkFailed(u1)
+	pool.MarkFailed(u2)
+	pool.MarkFailed(nil)
+	_, err = pool.Next()
+	assertErrorIs(t, ErrNoActiveProxy, err)
+
+	// recovery
+	pool.SetRecoveryDuration(0)
+	pool.MarkFailed(u1)
+	u, err := pool.Next()
+	assertNil(t, err)
+	assertEqual(t, u1.String(), u.String())
+}
+
+func TestClientSetProxyFromEnvironment(t *testing.T) {
+	p1 := createProxyTestServer(t, "p1")
+	t.Setenv("HTTP_PROXY", p1.URL)
+	t.Setenv("HTTPS_PROXY", "socks5://secure:1080")
+	t.Setenv("NO_PROXY", "internal.test,*.svc.cluster.local,10.0.0.0/8,example.org:8080")
+
+	c := dcnl().SetProxy("http://other:8888").SetProxyFromEnvironment()
+	assertNil(t, c.ProxyURL())
+
+	res, err := c.R().Get("http://example.com/get")
+	assertNil(t, err)
+	assertEqual(t, "p1:example.com/get", res.String())
+
+	transport, err := c.HTTPTransport()
+	assertNil(t, err)
+	proxyFor := func(rawURL string) string {
+		pURL, err := transport.Proxy(httptest.NewRequest(MethodGet, rawURL, nil))
+		assertNil(t, err)
+		if pURL == nil {
+			return ""
+		}
+		return pURL.String()
+	}
+	assertEqual(t, "socks5://secure:1080", proxyFor("https://example.com"))
+	assertEqual(t, "", proxyFor("http://internal.test"))
+	assertEqual(t, "", proxyFor("http://api.internal.test"))
+	assertEqual(t, "", proxyFor("http://orders.svc.cluster.local"))
+	assertEqual(t, p1.URL, proxyFor("http://svc.cluster.local"))
+	assertEqual(t, "", proxyFor("http://10.1.2.3"))
+	assertEqual(t, "", proxyFor("http://example.org:8080"))
+	assertEqual(t, p1.URL, proxyFor("http://example.org"))
+
+	// environment variables are read on every call of SetProxyFromEnvironment
+	t.Setenv("NO_PROXY", "*")
+	assertEqual(t, p1.URL, proxyFor("http://example.com"))
+	c.SetProxyFromEnvironment()
+	assertEqual(t, "", proxyFor("http://example.com"))
+}
+
+func TestRequestDisableProxy(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+	p1 := createProxyTestServer(t, "p1")
+
+	assertProxy := func(c *Client) {
+		t.Helper()
+		res, err := c.R().Get(ts.URL + "/")
+		assertNil(t, err)
+		assertEqual(t, true, strings.HasPrefix(res.String(), "p1:"))
+
+		res, err = c.R().DisableProxy().Get(ts.URL + "/")
+		assertNil(t, err)
+		assertEqual(t, "TestGet: text response", res.String())
+	}
+
+	assertProxy(dcnl().SetProxy(p1.URL))
+	assertProxy(dcnl().SetHostProxy("127.0.0.1", p1.URL))
+	assertProxy(dcnl().SetProxyFunc(func(*Request) (*url.URL, error) {
+		return url.Parse(p1.URL)
+	}))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/url"
+	"strings"
+)
+
+// QuerySpaceEncoding type is to define the encoding of the space character in
+// the query string, see [Client.SetQuerySpaceEncoding].
+type QuerySpaceEncoding uint8
+
+const (
+	// QuerySpacePlus encodes the space as `+`, i.e., `q=a+b`. It is the default,
+	// the same as [url.Values.Encode].
+	QuerySpacePlus QuerySpaceEncoding = iota
+
+	// QuerySpacePercent encodes the space as `%20`, i.e., `q=a%20b`.
+	QuerySpacePercent
+)
+
+// SetQuerySpaceEncoding method sets the encoding of the space character in the
+// query params at the client level for all requests; some servers and the
+// signed URLs require `%20` instead of `+`.
+//
+//	client.SetQuerySpaceEncoding(resty.QuerySpacePercent)
+//
+// NOTE: It is not applied to the raw query, see [Request.SetRawQuery].
+//
+// It can be overridden at request level, see [Request.SetQuerySpaceEncoding]
+func (c *Client) SetQuerySpaceEncoding(enc QuerySpaceEncoding) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.querySpaceEncoding = enc
+	return c
+}
+
+// SetQuerySpaceEncoding method sets the encoding of the space character in the
+// query params of the current request, see [Client.SetQuerySpaceEncoding].
+//
+// It overrides the value set at the client instance level.
+func (r *Request) SetQuerySpaceEncoding(enc QuerySpaceEncoding) *Request {
+	r.querySpaceEncoding = enc
+	return r
+}
+
+// AddQueryParam method appends a single parameter and its value in the current
+// request. Unlike [Request.SetQueryParam], the duplicate parameters are kept, and
+// the parameters are encoded in the insertion order, after the other query params.
+//
+//	client.R().
+//		AddQueryParam("sort", "name").
+//		AddQueryParam("filter", "active").
+//		AddQueryParam("sort", "-created").
+//		Get("/users") // /users?sort=name&filter=active&sort=-created
+func (r *Request) AddQueryParam(param, value string) *Request {
+	r.orderedQueryParams = append(r.orderedQueryParams, queryParam{name: param, value: value})
+	return r
+}
+
+// SetRawQuery method sets the query string of the current request as-is, it is
+// neither parsed nor re-encoded, so the query string is byte-exact, e.g., for the
+// signed URLs. It replaces the query string of the request URL; the query params,
+// if any, are appended after it.
+//
+//	client.R().
+//		SetRawQuery("X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Signature=fe5f80f77d5fa3be").
+//		Get("https://bucket.s3.amazonaws.com/object")
+//
+// NOTE: The raw query must be a valid URL-encoded query string.
+func (r *Request) SetRawQuery(query string) *Request {
+	r.rawQuery = strings.TrimPrefix(query, "?")
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type queryParam struct {
+	name  string
+	value string
+}
+
+// encodeQuery method returns the URL-encoded given query params sorted by key,
+// followed by the ordered query params of the request.
+func (r *Request) encodeQuery(params url.Values) string {
+	var sb strings.Builder
+	sb.WriteString(params.Encode())
+	for _, p := range r.orderedQueryParams {
+		if sb.Len() > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(p.name))
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(p.value))
+	}
+
+	query := sb.String()
+	if r.querySpaceEncoding == QuerySpacePercent {
+		// the literal plus is escaped as `%2B`, so the plus is always the space
+		query = strings.ReplaceAll(query, "+", "%20")
+	}
+	return query
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"testing"
+)
+
+func TestRequestAddQueryParam(t *testing.T) {
+	c := dcnl().SetBaseURL("https://example.com")
+
+	r := c.R().
+		SetQueryParam("limit", "10").
+		AddQueryParam("sort", "name").
+		AddQueryParam("filter", "a b").
+		AddQueryParam("sort", "-created")
+	r.URL = "/users"
+	assertError(t, parseRequestURL(c, r))
+	assertEqual(t, "https://example.com/users?limit=10&sort=name&filter=a+b&sort=-created", r.URL)
+
+	t.Run("clone keeps ordered params isolated", func(t *testing.T) {
+		r1 := c.R().AddQueryParam("a", "1")
+		r2 := r1.Clone(r1.Context()).AddQueryParam("b", "2")
+		assertEqual(t, 1, len(r1.orderedQueryParams))
+		assertEqual(t, 2, len(r2.orderedQueryParams))
+	})
+}
+
+func TestRequestSetRawQuery(t *testing.T) {
+	c := dcnl().SetBaseURL("https://example.com")
+
+	raw := "X-Amz-Signature=fe5f80f77d5fa3be&b=%2Fx&a=1"
+	r := c.R().SetRawQuery("?"+raw).AddQueryParam("c", "3")
+	r.URL = "/object?ignored=true"
+	assertError(t, parseRequestURL(c, r))
+	assertEqual(t, "https://example.com/object?"+raw+"&c=3", r.URL)
+}
+
+func TestQuerySpaceEncoding(t *testing.T) {
+	c := dcnl().SetBaseURL("https://example.com").
+		SetQuerySpaceEncoding(QuerySpacePercent)
+
+	r := c.R().
+		SetQueryParam("q", "a b+c").
+		AddQueryParam("name", "x y")
+	r.URL = "/search"
+	assertError(t, parseRequestURL(c, r))
+	assertEqual(t, "https://example.com/search?q=a%20b%2Bc&name=x%20y", r.URL)
+
+	// request level override
+	r = c.R().
+		SetQuerySpaceEncoding(QuerySpacePlus).
+		SetQueryParam("q", "a b")
+	r.URL = "/search"
+	assertError(t, parseRequestURL(c, r))
+	assertEqual(t, "https://example.com/search?q=a+b", r.URL)
+}
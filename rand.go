@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/hex"
+	"math/rand/v2"
+	"sync"
+)
+
+// SetRandSource method sets the source of the random numbers used by the client,
+// i.e., the retry jitter, the multipart boundary, the request ID and the retry
+// trace ID, the debug sampling, the fault injection, the random proxy of the
+// [ProxyPool] and the [Canary] assignment; so the recorded traffic and the tests
+// are reproducible.
+//
+//	client.SetRandSource(rand.NewPCG(1, 2)) // math/rand/v2
+//
+// NOTE:
+//   - It is meant for the tests, the default is the non-deterministic source.
+//   - The nil value resets it to the default.
+func (c *Client) SetRandSource(src rand.Source) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if src == nil {
+		c.rnd = nil
+		return c
+	}
+	c.rnd = &lockedRand{lock: new(sync.Mutex), r: rand.New(src)}
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// lockedRand type is the goroutine-safe random number generator of the given
+// source, the nil value uses the default source.
+type lockedRand struct {
+	lock *sync.Mutex
+	r    *rand.Rand
+}
+
+func (c *Client) randSource() *lockedRand {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.rnd
+}
+
+func (lr *lockedRand) Float64() float64 {
+	if lr == nil {
+		return rand.Float64()
+	}
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	return lr.r.Float64()
+}
+
+func (lr *lockedRand) IntN(n int) int {
+	if lr == nil {
+		return rand.IntN(n)
+	}
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	return lr.r.IntN(n)
+}
+
+func (lr *lockedRand) Int64N(n int64) int64 {
+	if lr == nil {
+		return rand.Int64N(n)
+	}
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	return lr.r.Int64N(n)
+}
+
+// hex method returns the hex string of the given number of the random bytes
+func (lr *lockedRand) hex(n int) string {
+	b := make([]byte, n)
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	for i := range b {
+		b[i] = byte(lr.r.Uint32())
+	}
+	return hex.EncodeToString(b)
+}
+
+// newGUID method returns the new GUID, it is generated from the random
+// source if it is set, see [Client.SetRandSource].
+func (c *Client) newGUID() string {
+	if rnd := c.randSource(); rnd != nil {
+		return rnd.hex(12)
+	}
+	return newGUID()
+}
+
+// multipartBoundary method returns the given multipart boundary, or the one
+// generated from the random source if it is set; otherwise, the empty string
+// to use the random boundary of the [multipart.Writer].
+func (c *Client) multipartBoundary(boundary string) string {
+	if !isStringEmpty(boundary) {
+		return boundary
+	}
+	if rnd := c.randSource(); rnd != nil {
+		return rnd.hex(30)
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientSetRandSource(t *testing.T) {
+	type result struct {
+		contentType  string
+		requestID    string
+		retryTraceID string
+		waits        []time.Duration
+	}
+	run := func() *result {
+		mt := NewMockTransport()
+		mt.On("*", "*").Respond(
+			&MockResponse{StatusCode: http.StatusServiceUnavailable},
+			&MockResponse{StatusCode: http.StatusOK},
+		)
+		capture := NewRequestCapture(mt)
+		c := dcnl().
+			SetTransport(capture).
+			SetRandSource(rand.NewPCG(1, 2)).
+			SetRetryCount(1).
+			SetRetryWaitTime(time.Millisecond).
+			AddRequestMiddleware(RequestIDMiddleware)
+		defer c.Close()
+
+		res, err := c.R().
+			SetMultipartFormData(map[string]string{"name": "resty"}).
+			Put("https://api.example.com/upload")
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, res.StatusCode())
+
+		backoff := c.newBackoffWithJitter(100*time.Millisecond, time.Second)
+		r := &result{
+			contentType:  capture.Last().Request.Header.Get(hdrContentTypeKey),
+			requestID:    res.Request.RequestID(),
+			retryTraceID: res.Request.RetryTraceID,
+		}
+		for attempt := 1; attempt <= 3; attempt++ {
+			wait, _ := backoff.NextWaitDuration(c, nil, nil, attempt)
+			r.waits = append(r.waits, wait)
+		}
+		return r
+	}
+
+	first, second := run(), run()
+	assertEqual(t, first, second)
+	assertEqual(t, true, strings.HasPrefix(first.contentType, "multipart/form-data; boundary="))
+	assertEqual(t, 24, len(first.requestID))
+
+	t.Run("reset", func(t *testing.T) {
+		c := dcnl().SetRandSource(rand.NewPCG(1, 2))
+		assertNotNil(t, c.randSource())
+		c.SetRandSource(nil)
+		assertNil(t, c.randSource())
+		assertEqual(t, true, c.randSource().Float64() < 1)
+		assertEqual(t, true, c.multipartBoundary("") == "")
+		assertEqual(t, "custom", c.multipartBoundary("custom"))
+	})
+}
@@ -8,18 +8,23 @@ package resty
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
 	"net/url"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -79,28 +84,73 @@ type Request struct {
 	//	first attempt + retry count = total attempts
 	Attempt int
 
-	credentials         *credentials
-	isMultiPart         bool
-	isFormData          bool
-	setContentLength    bool
-	jsonEscapeHTML      bool
-	ctx                 context.Context
-	ctxCancelFunc       context.CancelFunc
-	values              map[string]any
-	client              *Client
-	bodyBuf             *bytes.Buffer
-	trace               *clientTrace
-	log                 Logger
-	baseURL             string
-	multipartBoundary   string
-	multipartFields     []*MultipartField
-	retryConditions     []RetryConditionFunc
-	retryHooks          []RetryHookFunc
-	resultCurlCmd       string
-	generateCurlCmd     bool
-	debugLogCurlCmd     bool
-	unescapeQueryParams bool
-	multipartErrChan    chan error
+	credentials           *credentials
+	isMultiPart           bool
+	isFormData            bool
+	setContentLength      bool
+	jsonEscapeHTML        bool
+	ctx                   context.Context
+	ctxCancelFunc         context.CancelFunc
+	values                map[any]any
+	name                  string
+	pathParamFormatter    PathParamFormatterFunc
+	timeFormat            string
+	rawQuery              string
+	orderedQueryParams    []queryParam
+	querySpaceEncoding    QuerySpaceEncoding
+	followCreated         bool
+	followPollInterval    time.Duration
+	followPollMaxAttempts int
+	client                *Client
+	bodyBuf               *bytes.Buffer
+	trace                 *clientTrace
+	traceHistory          []TraceInfo
+	requestID             string
+	connInfo              *ConnInfo
+	contentEncoding       string
+	log                   Logger
+	baseURL               string
+	multipartBoundary     string
+	multipartContentType  string
+	multipartLength       int64
+	multipartProgressFn   func(part string, sent, total int64)
+	uploadProgressFn      func(sent, total int64)
+	downloadProgressFn    func(DownloadProgress)
+	expectedDigests       []*contentDigest
+	expectDigestErr       error
+	multipartFields       []*MultipartField
+	retryConditions       []RetryConditionFunc
+	retryHooks            []RetryHookFunc
+	resultCurlCmd         string
+	generateCurlCmd       bool
+	debugLogCurlCmd       bool
+	unescapeQueryParams   bool
+	multipartErrChan      chan error
+	cacheMode             CacheMode
+	jsonArrayElement      any
+	jsonArrayElementFn    func(any) error
+	formBracketScheme     FormBracketScheme
+	charsetConversion     bool
+	errorTypes            map[int]reflect.Type
+	returnErrOnHTTPErr    bool
+	responseBodyWriter    io.Writer
+	responseBodyStream    bool
+	outputFileSync        bool
+	expectedContentTypes  []string
+	contentTypeSniffing   bool
+	soap                  *SOAP
+	proxyURL              *url.URL
+	proxyDisabled         bool
+	informationalFn       func(status int, header http.Header)
+	pinnedBaseURL         string
+	loadBalancerKey       string
+	loadBalanced          bool
+	canary                *bool
+	transport             http.RoundTripper
+	tlsClientConfig       *tls.Config
+	skipMiddlewares       []string
+	uriTemplate           string
+	uriTemplateVars       map[string]any
 }
 
 // SetMethod method used to set the HTTP verb for the request
@@ -140,6 +190,83 @@ func (r *Request) SetContext(ctx context.Context) *Request {
 	return r
 }
 
+// ApplyIf method calls the given function with the current request only if the
+// given condition is true, so the optional settings do not break the fluent
+// chain.
+//
+//	client.R().
+//		SetQueryParam("page", "1").
+//		ApplyIf(filter != nil, func(r *resty.Request) {
+//			r.SetQueryParams(filter.Params())
+//		}).
+//		Get("/users")
+func (r *Request) ApplyIf(cond bool, fn func(*Request)) *Request {
+	if cond {
+		fn(r)
+	}
+	return r
+}
+
+// SetName method sets the logical operation name of the request, e.g.,
+// `GetUser`; it is exposed in the debug log, the metrics, see [RequestMetrics],
+// and the trace info, see [TraceInfo], so the observability pipelines can
+// aggregate by the operation instead of the high-cardinality concrete URLs.
+//
+//	client.R().
+//		SetName("GetUser").
+//		SetPathParam("id", id).
+//		Get("/users/{id}")
+func (r *Request) SetName(name string) *Request {
+	r.name = name
+	return r
+}
+
+// Name method returns the logical operation name of the request; otherwise,
+// it is empty. See [Request.SetName].
+func (r *Request) Name() string {
+	return r.name
+}
+
+// SetValue method sets the request-scoped value of the given key; it is the
+// way for the middlewares to pass the per-request metadata, e.g., tenant or
+// operation name, to each other. Unlike the context values, it is preserved
+// across the retry attempts and visible in the response middlewares, hooks,
+// e.g., [Client.OnError], and the debug log callback, via [Response].Request.
+//
+//	type operationKey struct{}
+//
+//	client.R().SetValue(operationKey{}, "listUsers").Get("/users")
+//
+//	client.AddResponseMiddleware(func(c *resty.Client, res *resty.Response) error {
+//		op, _ := resty.RequestValue[string](res.Request, operationKey{})
+//		metrics.Observe(op, res.Duration())
+//		return nil
+//	})
+//
+// The key must be comparable; like [context.WithValue], use the unexported
+// type for the key to avoid collisions. The values are copied on [Request.Clone].
+func (r *Request) SetValue(key, value any) *Request {
+	r.initValuesMap()
+	r.values[key] = value
+	return r
+}
+
+// Value method returns the request-scoped value of the given key; otherwise,
+// nil. See [Request.SetValue] and [RequestValue].
+func (r *Request) Value(key any) any {
+	return r.values[key]
+}
+
+// RequestValue function returns the request-scoped value of the given key as
+// the type T, the boolean reports whether the value is found and of the type T.
+// See [Request.SetValue].
+//
+//	tenant, ok := resty.RequestValue[string](req, tenantKey{})
+func RequestValue[T any](r *Request, key any) (T, bool) {
+	v, ok := r.Value(key).(T)
+	return v, ok
+}
+
 // WithContext method returns a shallow copy of r with its context changed
 // to ctx. The provided ctx must be non-nil. It does not
 // affect the [Request].RawRequest that was already created.
@@ -180,6 +307,22 @@ func (r *Request) SetHeader(header, value string) *Request {
 	return r
 }
 
+// SetHeaderIf method sets a single header field and its value in the current
+// request only if the given condition is true, so the optional headers do not
+// break the fluent chain.
+//
+//	client.R().
+//		SetHeaderIf(len(etag) > 0, "If-None-Match", etag).
+//		Get("/users/1001")
+//
+// See [Request.SetHeader]
+func (r *Request) SetHeaderIf(cond bool, header, value string) *Request {
+	if cond {
+		r.SetHeader(header, value)
+	}
+	return r
+}
+
 // SetHeaders method sets multiple header fields and their values at one go in the current request.
 //
 // For Example: To set `Content-Type` and `Accept` as `application/json`
@@ -231,6 +374,53 @@ func (r *Request) SetHeaderVerbatim(header, value string) *Request {
 	return r
 }
 
+// SetCacheMode method sets the response cache mode for the current request,
+// similar to the Fetch API cache modes. By default, it is [CacheModeDefault],
+// which follows the HTTP caching semantics.
+//
+// For Example: To run against the previously cached data when the network is unavailable.
+//
+//	client.R().
+//		SetCacheMode(resty.CacheModeCacheOnly).
+//		Get("https://api.example.com/catalog")
+//
+// NOTE: [CacheModeCacheOnly] returns the [ErrCacheMiss] error if the response is
+// not in the cache or the response cache is not enabled on the client.
+//
+// See [Client.SetCache]
+func (r *Request) SetCacheMode(mode CacheMode) *Request {
+	r.cacheMode = mode
+	return r
+}
+
+// SetConditionalFrom method makes the current request conditional by copying the
+// validators from the given previous response; `ETag` into `If-None-Match` and
+// `Last-Modified` into `If-Modified-Since` headers. It is a no-op if the previous
+// response is nil or does not have validators.
+//
+// It is helpful for pollers that re-fetch the same resource.
+//
+//	res, err := client.R().
+//		SetConditionalFrom(prevRes).
+//		Get("https://api.example.com/feed")
+//	if res.IsNotModified() {
+//		// use the previous response
+//	}
+//
+// See [Response.IsNotModified]
+func (r *Request) SetConditionalFrom(prev *Response) *Request {
+	if prev == nil || prev.RawResponse == nil {
+		return r
+	}
+	if etag := prev.Header().Get(hdrETagKey); etag != "" {
+		r.Header.Set(hdrIfNoneMatchKey, etag)
+	}
+	if lastModified := prev.Header().Get(hdrLastModifiedKey); lastModified != "" {
+		r.Header.Set(hdrIfModifiedSinceKey, lastModified)
+	}
+	return r
+}
+
 // SetQueryParam method sets a single parameter and its value in the current request.
 // It will be formed as a query string for the request.
 //
@@ -246,6 +436,23 @@ func (r *Request) SetQueryParam(param, value string) *Request {
 	return r
 }
 
+// SetQueryParamIfNotEmpty method sets a single parameter and its value in the
+// current request only if the value is not empty, so the optional parameters
+// do not break the fluent chain.
+//
+//	client.R().
+//		SetQueryParamIfNotEmpty("search", filter.Search).
+//		SetQueryParamIfNotEmpty("cursor", cursor).
+//		Get("/users")
+//
+// See [Request.SetQueryParam]
+func (r *Request) SetQueryParamIfNotEmpty(param, value string) *Request {
+	if len(value) > 0 {
+		r.SetQueryParam(param, value)
+	}
+	return r
+}
+
 // SetQueryParams method sets multiple parameters and their values at one go in the current request.
 // It will be formed as a query string for the request.
 //
@@ -286,6 +493,34 @@ func (r *Request) SetQueryParamsFromValues(params url.Values) *Request {
 	return r
 }
 
+// SetQueryParamsNested method appends the query parameters from the given struct
+// or map with the nested values in the current request, the same as
+// [Request.SetFormDataNested] does for the form data.
+//
+//	type UserFilter struct {
+//		Name  string    `query:"name,omitempty"`
+//		Roles []string  `query:"roles"`
+//		Since time.Time `query:"since"`
+//	}
+//
+//	client.R().
+//		SetQueryParamsNested(UserFilter{Roles: []string{"admin"}, Since: since}).
+//		Get("/users") // /users?roles[]=admin&since=2024-03-01T10:30:00Z
+//
+// The struct field name is taken from the `query` tag, falling back to the `json`
+// tag and then the field name; the `omitempty` option is supported. The slice
+// values are encoded as per the [FormBracketScheme], and the [time.Time] values
+// as per the time format, set them before calling this method.
+//
+// See [Request.SetFormBracketScheme], [Request.SetTimeFormat]
+func (r *Request) SetQueryParamsNested(v any) *Request {
+	e := formEncoder{scheme: r.formBracketScheme, timeLayout: r.timeFormat, tagNames: queryTagNames}
+	if err := e.encode(r.QueryParams, v); err != nil {
+		r.log.Errorf("%v", err)
+	}
+	return r
+}
+
 // SetQueryString method provides the ability to use string as an input to set URL query string for the request.
 //
 //	client.R().
@@ -343,6 +578,46 @@ func (r *Request) SetFormDataFromValues(data url.Values) *Request {
 	return r
 }
 
+// SetFormDataNested method sets the form parameters from the given struct or map
+// with the nested values in the current request. The nested values are encoded
+// with the brackets (Rails/PHP style), i.e., `user[name]=resty&user[roles][]=admin`.
+// The request content type would be set as `application/x-www-form-urlencoded`.
+//
+//	client.R().
+//		SetFormDataNested(map[string]any{
+//			"user": map[string]any{
+//				"name":  "resty",
+//				"roles": []string{"admin", "dev"},
+//			},
+//		})
+//
+// The struct field name is taken from the `form` tag, falling back to the `json`
+// tag and then the field name; the `omitempty` option is supported. The slice
+// values are encoded as per the [FormBracketScheme], and the [time.Time] values
+// as per the time format, set them before calling this method.
+//
+// It appends to the form data; see [Request.SetFormBracketScheme], [Request.SetTimeFormat]
+func (r *Request) SetFormDataNested(v any) *Request {
+	e := formEncoder{scheme: r.formBracketScheme, timeLayout: r.timeFormat, tagNames: formTagNames}
+	if err := e.encode(r.FormData, v); err != nil {
+		r.log.Errorf("%v", err)
+	}
+	return r
+}
+
+// SetFormBracketScheme method sets the key scheme of the slice values for the
+// nested form data encoding in the current request.
+//
+//	client.R().
+//		SetFormBracketScheme(resty.FormBracketsIndexed).
+//		SetFormDataNested(map[string]any{"list": []string{"x", "y"}}) // list[0]=x&list[1]=y
+//
+// It overrides the value set at the client instance level, see [Client.SetFormBracketScheme]
+func (r *Request) SetFormBracketScheme(scheme FormBracketScheme) *Request {
+	r.formBracketScheme = scheme
+	return r
+}
+
 // SetBody method sets the request body for the request. It supports various practical needs as easy.
 // It's quite handy and powerful. Supported request body data types are `string`,
 // `[]byte`, `struct`, `map`, `slice` and [io.Reader].
@@ -424,6 +699,34 @@ func (r *Request) SetResult(v any) *Request {
 	return r
 }
 
+// ForEachJSONArrayElement method instructs Resty to decode the successful JSON array
+// response incrementally, element by element, into the given value and to invoke
+// the given function for each element; so the memory stays flat for the very large
+// array responses, unlike [Request.SetResult] reads the entire body.
+//
+// The given value must be a pointer; it is reset to the zero value before decoding
+// each element, so retain a copy of the element if needed beyond the function call.
+// The iteration stops on the first error returned by the function, and the error is
+// returned by the request execution.
+//
+//	user := &User{}
+//	res, err := client.R().
+//		ForEachJSONArrayElement(user, func(v any) error {
+//			fmt.Println(v.(*User).Name)
+//			return nil
+//		}).
+//		Get("https://example.com/users")
+//
+// NOTE:
+//   - It takes precedence over [Request.SetResult] for the JSON responses.
+//   - The cloned request decodes into a new value of the same type, see
+//     [Request.Clone].
+func (r *Request) ForEachJSONArrayElement(v any, fn func(v any) error) *Request {
+	r.jsonArrayElement = v
+	r.jsonArrayElementFn = fn
+	return r
+}
+
 // SetError method is to register the request `Error` object for automatic unmarshalling for the request,
 // if the response status code is greater than 399 and the content type is either JSON or XML.
 //
@@ -443,6 +746,52 @@ func (r *Request) SetError(err any) *Request {
 	return r
 }
 
+// SetReturnErrorOnHTTPError method enables returning the HTTP error response, i.e.,
+// the status code is greater than 399, as a non-nil error for the request.
+//
+//	res, err := client.R().
+//		SetReturnErrorOnHTTPError(true).
+//		SetError(&APIError{}).
+//		Get("https://example.com/users/1001")
+//
+// It overrides the value set at the client instance level, see [Client.SetReturnErrorOnHTTPError]
+func (r *Request) SetReturnErrorOnHTTPError(b bool) *Request {
+	r.returnErrOnHTTPErr = b
+	return r
+}
+
+// SetErrorFor method registers the `Error` object type for the given status code or
+// the status code range, i.e., [StatusRange4xx] or [StatusRange5xx] for the request.
+//
+//	res, err := client.R().
+//		SetErrorFor(http.StatusNotFound, &NotFound{}).
+//		SetErrorFor(resty.StatusRange4xx, &APIError{}).
+//		Get("https://example.com/users/1001")
+//
+//	var notFound *NotFound
+//	if errors.As(err, &notFound) { // if *NotFound implements the error interface
+//		fmt.Println(notFound.Resource)
+//	}
+//
+//	var httpErr *resty.HTTPError
+//	if errors.As(err, &httpErr) {
+//		fmt.Println(httpErr.StatusCode, httpErr.Body)
+//	}
+//
+// It takes precedence over [Request.SetError] and the registered types at the
+// client instance level, see [Client.SetErrorFor].
+func (r *Request) SetErrorFor(status int, v any) *Request {
+	if !isValidErrorStatus(status) {
+		r.log.Errorf("resty: invalid error status %d, it must be 400-599, StatusRange4xx, or StatusRange5xx", status)
+		return r
+	}
+	if r.errorTypes == nil {
+		r.errorTypes = make(map[int]reflect.Type)
+	}
+	r.errorTypes[status] = inferType(v)
+	return r
+}
+
 // SetFile method sets a single file field name and its path for multipart upload.
 //
 // Resty provides an optional multipart live upload progress callback;
@@ -574,6 +923,11 @@ func (r *Request) SetMultipartField(fieldName, fileName, contentType string, rea
 // If you have a `slice` of fields already, then call-
 //
 //	client.R().SetMultipartFields(fields...)
+//
+// The multipart body is streamed through [io.Pipe] without buffering in memory. It is
+// sent with the known content length when all the part sizes are known, i.e., the file
+// path, [os.File], [bytes.Reader], [strings.Reader], or [bytes.Buffer]; otherwise, the
+// chunked transfer encoding is used.
 func (r *Request) SetMultipartFields(fields ...*MultipartField) *Request {
 	r.isMultiPart = true
 	r.multipartFields = append(r.multipartFields, fields...)
@@ -587,6 +941,141 @@ func (r *Request) SetMultipartBoundary(boundary string) *Request {
 	return r
 }
 
+// SetMultipartContentType method sets the multipart content type for the multipart
+// request, such as `multipart/related`, `multipart/mixed`; the boundary parameter
+// is added by Resty. Default is `multipart/form-data`.
+//
+// For example, SOAP with attachments (MTOM/XOP):
+//
+//	client.R().
+//		SetMultipartContentType(`multipart/related; type="application/xop+xml"; start="<root>"`).
+//		SetMultipartFields(
+//			&resty.MultipartField{
+//				ContentType: `application/xop+xml; charset=UTF-8; type="text/xml"`,
+//				Header:      http.Header{"Content-ID": []string{"<root>"}},
+//				Reader:      strings.NewReader(envelope),
+//			},
+//			&resty.MultipartField{
+//				ContentType: "image/png",
+//				Header:      http.Header{"Content-ID": []string{"<image>"}},
+//				Reader:      bytes.NewReader(imageBytes),
+//			},
+//		)
+//
+// See [MultipartField].Header, [MultipartField].Parts
+func (r *Request) SetMultipartContentType(ct string) *Request {
+	r.multipartContentType = ct
+	return r
+}
+
+// SetMultipartProgressCallback method sets the callback function to receive the
+// live upload progress of each multipart file part, i.e., the part name (or file
+// name if name is empty), bytes sent, and total bytes. The total is the
+// [MultipartField].FileSize; it is zero if unknown.
+//
+//	client.R().
+//		SetFile("video", "/path/to/video.mp4").
+//		SetMultipartProgressCallback(func(part string, sent, total int64) {
+//			fmt.Printf("%s: %d/%d\n", part, sent, total)
+//		}).
+//		Post("https://example.com/upload")
+//
+// It works in addition to the [MultipartField].ProgressCallback.
+//
+// See [Request.SetUploadProgressCallback]
+func (r *Request) SetMultipartProgressCallback(fn func(part string, sent, total int64)) *Request {
+	r.multipartProgressFn = fn
+	return r
+}
+
+// SetUploadProgressCallback method sets the callback function to receive the
+// live upload progress of the whole request body, i.e., bytes sent and total bytes.
+// The total is the request content length; it is -1 if unknown, e.g., the streaming
+// multipart body.
+//
+//	client.R().
+//		SetBody(file).
+//		SetUploadProgressCallback(func(sent, total int64) {
+//			fmt.Printf("uploaded %d/%d\n", sent, total)
+//		}).
+//		Put("https://example.com/upload")
+//
+// NOTE: The progress starts afresh on every retry attempt.
+func (r *Request) SetUploadProgressCallback(fn func(sent, total int64)) *Request {
+	r.uploadProgressFn = fn
+	return r
+}
+
+// SetDownloadProgressCallback method sets the callback function to receive the
+// live download progress while the response body is saved to the file, i.e., bytes
+// written, percentage, rolling transfer rate, and ETA. See [DownloadProgress].
+//
+//	client.R().
+//		SetOutputFileName("/tmp/large.iso").
+//		SetDownloadProgressCallback(func(p resty.DownloadProgress) {
+//			fmt.Printf("\r%.1f%% %.0f KB/s ETA %s", p.Percent, p.Rate/1024, p.ETA().Round(time.Second))
+//		}).
+//		Get("https://example.com/large.iso")
+//
+// NOTE: It is applicable only with [Request.SetOutputFileName] or [Request.SetSaveResponse].
+func (r *Request) SetDownloadProgressCallback(fn func(DownloadProgress)) *Request {
+	r.downloadProgressFn = fn
+	return r
+}
+
+// OnInformationalResponse method sets the callback function to receive the 1xx
+// informational responses received before the final response, e.g., `103 Early Hints`
+// to preload the linked resources, and `102 Processing`.
+//
+//	client.R().
+//		OnInformationalResponse(func(status int, header http.Header) {
+//			if status == http.StatusEarlyHints {
+//				for _, link := range header.Values("Link") {
+//					preload(link)
+//				}
+//			}
+//		}).
+//		Get("https://example.com/index.html")
+//
+// NOTE: The `101 Switching Protocols` is the final response, it is not reported.
+//
+// See [httptrace.ClientTrace].Got1xxResponse
+func (r *Request) OnInformationalResponse(fn func(status int, header http.Header)) *Request {
+	r.informationalFn = fn
+	return r
+}
+
+// ExpectDigest method sets the expected digest of the response body; the digest
+// is verified while the body is read, e.g., streaming to the output file, and the
+// request fails with [ContentDigestError] on mismatch. The supported algorithms are
+// `sha-256`, `sha-512`, `sha-1`, and `md5`, and the value can be hex or base64 encoded.
+//
+//	client.R().
+//		SetOutputFileName("/tmp/resty.tar.gz").
+//		ExpectDigest("sha-256", "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c").
+//		Get("https://example.com/resty.tar.gz")
+//
+// The digest is computed over the decompressed body. Besides, the `Content-Digest`
+// and `Content-MD5` response headers are verified automatically.
+//
+// NOTE: On mismatch, the output file is not created or replaced.
+//
+// If the algorithm is not supported or the value is invalid, the request
+// execution fails with the error, e.g., [ErrContentDigestAlgNotSupported],
+// without sending the request.
+func (r *Request) ExpectDigest(algorithm, value string) *Request {
+	d, err := newContentDigest(algorithm, "ExpectDigest", value)
+	if err != nil {
+		// fail closed, the request is not sent without the verification
+		if r.expectDigestErr == nil {
+			r.expectDigestErr = err
+		}
+		return r
+	}
+	r.expectedDigests = append(r.expectedDigests, d)
+	return r
+}
+
 // SetContentLength method sets the current request's HTTP header `Content-Length` value.
 // By default, Resty won't set `Content-Length`.
 //
@@ -600,6 +1089,26 @@ func (r *Request) SetContentLength(l bool) *Request {
 	return r
 }
 
+// SetContentEncoding method compresses the current request body using the given
+// Content-Encoding directive and sets the `Content-Encoding` header. Resty
+// provides `gzip` and `deflate` out of the box; others can be added using
+// [Client.AddContentCompresser].
+//
+//	client.R().
+//		SetContentEncoding("gzip").
+//		SetBody(largePayload).
+//		Post("https://example.com/ingest")
+//
+// NOTE:
+//   - The [io.Reader] request body is read into memory for the compression,
+//     except the multipart streaming body, which is compressed on the fly.
+//   - It fails with [ErrContentCompresserNotFound] if no Compresser is registered
+//     for the given directive.
+func (r *Request) SetContentEncoding(enc string) *Request {
+	r.contentEncoding = enc
+	return r
+}
+
 // SetBasicAuth method sets the basic authentication header in the current HTTP request.
 //
 // For Example:
@@ -676,6 +1185,10 @@ func (r *Request) SetHeaderAuthorizationKey(k string) *Request {
 //		SetOutputFileName("/Users/jeeva/Downloads/ReplyWithHeader-v5.1-beta.zip").
 //		Get("http://bit.ly/1LouEKr")
 //
+// The response body is written into the temp file in the same directory and
+// renamed on success, so the interrupted download never leaves the truncated
+// file at the destination path, see [Request.SetOutputFileSync].
+//
 // NOTE: In this scenario
 //   - [Response.BodyBytes] might be nil.
 //   - [Response].Body might have been already read.
@@ -701,6 +1214,20 @@ func (r *Request) SetSaveResponse(save bool) *Request {
 	return r
 }
 
+// SetOutputFileSync method enables the `fsync` of the output file before it is
+// renamed to the destination, see [Client.SetOutputFileSync].
+//
+//	client.R().
+//		SetOutputFileName("/tmp/resty.tar.gz").
+//		SetOutputFileSync(true).
+//		Get("https://example.com/resty.tar.gz")
+//
+// It overrides the value set at the client instance level, see [Client.SetOutputFileSync]
+func (r *Request) SetOutputFileSync(sync bool) *Request {
+	r.outputFileSync = sync
+	return r
+}
+
 // SetCloseConnection method sets variable `Close` in HTTP request struct with the given
 // value. More info: https://golang.org/src/net/http/request.go
 //
@@ -723,6 +1250,34 @@ func (r *Request) SetDoNotParseResponse(notParse bool) *Request {
 	return r
 }
 
+// SetResponseBodyStream method enables the streaming of the response body; Resty
+// does not parse the response body, and the [Response].Body is the decompressed
+// [io.ReadCloser] that is closed automatically on reaching the end of the body or
+// the request context end, i.e., cancellation or timeout. So the connection does not
+// leak even if the body is not closed.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel() // closes the body, if not done yet
+//
+//	res, err := client.R().
+//		SetContext(ctx).
+//		SetResponseBodyStream(true).
+//		Get("https://example.com/events.ndjson")
+//
+//	dec := res.JSONDecoder()
+//	for dec.More() {
+//		// decode the values
+//	}
+//
+// See [Response.Pipe], [Response.JSONDecoder]
+//
+// NOTE: It implies [Request.SetDoNotParseResponse].
+func (r *Request) SetResponseBodyStream(stream bool) *Request {
+	r.responseBodyStream = stream
+	r.DoNotParseResponse = stream
+	return r
+}
+
 // SetResponseBodyLimit method sets a maximum body size limit in bytes on response,
 // avoid reading too much data to memory.
 //
@@ -739,6 +1294,75 @@ func (r *Request) SetResponseBodyLimit(v int64) *Request {
 	return r
 }
 
+// SetResponseBodyWriter method sets the writer to receive the response body bytes
+// while the body is read, e.g., [Request.SetResult] parsing, saving to the file. So
+// the raw payloads can be archived without [Request.SetDoNotParseResponse] and
+// reading the body twice.
+//
+//	var archive bytes.Buffer
+//	res, err := client.R().
+//		SetResult(&User{}).
+//		SetResponseBodyWriter(&archive).
+//		Get("https://example.com/users/1001")
+//
+// The body is written after the decompression and before the charset conversion;
+// the write error fails the request.
+//
+// NOTE: The body of every attempt is written on retries.
+func (r *Request) SetResponseBodyWriter(w io.Writer) *Request {
+	r.responseBodyWriter = w
+	return r
+}
+
+// ExpectContentType method sets the expected response content types; the request
+// fails fast with [ContentTypeError] before the automatic unmarshalling, if the
+// response `Content-Type` does not match any of them. It prevents, for example,
+// the HTML error page from being "successfully" parsed into an empty struct.
+//
+//	res, err := client.R().
+//		SetResult(&User{}).
+//		ExpectContentType("application/json", "application/problem+json").
+//		Get("https://example.com/users/1001")
+//
+//	var ctErr *resty.ContentTypeError
+//	if errors.As(err, &ctErr) {
+//		fmt.Println(ctErr.Actual, res.String())
+//	}
+//
+// The media type parameters, e.g., charset, are ignored, and the wildcard
+// subtype, e.g., `text/*`, is supported. The response without the content,
+// e.g., 204 No Content, is not verified.
+//
+// NOTE: If the server omits the `Content-Type` header, it fails unless the
+// sniffing is enabled, see [Request.SetContentTypeSniffing].
+func (r *Request) ExpectContentType(contentTypes ...string) *Request {
+	r.expectedContentTypes = slices.Clone(contentTypes)
+	return r
+}
+
+// SetContentTypeSniffing method enables the detection of the response content
+// type from the body for the current request, when the server omits the
+// `Content-Type` header.
+//
+//	client.R().SetContentTypeSniffing(true)
+//
+// It overrides the value set at the client instance level, see [Client.SetContentTypeSniffing]
+func (r *Request) SetContentTypeSniffing(b bool) *Request {
+	r.contentTypeSniffing = b
+	return r
+}
+
+// SetResponseCharsetConversion method enables the transcoding of the non-UTF-8
+// response body into UTF-8 for the current request.
+//
+//	client.R().SetResponseCharsetConversion(true)
+//
+// It overrides the value set at the client instance level, see [Client.SetResponseCharsetConversion]
+func (r *Request) SetResponseCharsetConversion(b bool) *Request {
+	r.charsetConversion = b
+	return r
+}
+
 // SetResponseBodyUnlimitedReads method is to turn on/off the response body in memory
 // that provides an ability to do unlimited reads.
 //
@@ -926,8 +1550,70 @@ func (r *Request) SetTimeout(timeout time.Duration) *Request {
 	return r
 }
 
+// SetTransport method sets the [http.RoundTripper] for the current request,
+// e.g., the one-off request that needs a special proxy or the client
+// certificate, without cloning the client or mutating its transport.
+//
+//	client.R().
+//		SetTransport(&http.Transport{
+//			Proxy: http.ProxyURL(specialProxyURL),
+//		}).
+//		Get("https://example.com/report")
+//
+// It overrides the transport set at the client instance level for this request
+// only; the client's cookie jar and redirect policy are still applied.
+// See [Client.SetTransport], [Request.SetTLSClientConfig]
+func (r *Request) SetTransport(transport http.RoundTripper) *Request {
+	r.transport = transport
+	return r
+}
+
+// SetTLSClientConfig method sets the [tls.Config] for the current request. The
+// request is sent using the clone of the request or client [http.Transport]
+// with the given TLS config, and the keep-alive disabled, so the connection is
+// not shared with the other requests.
+//
+//	// Disable SSL cert verification for the one-off request
+//	client.R().
+//		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+//		Get("https://self-signed.localhost")
+//
+// It returns [ErrNotHttpTransportType] on the request execution, if the
+// transport is not the [http.Transport]. See [Client.SetTLSClientConfig]
+func (r *Request) SetTLSClientConfig(tlsConfig *tls.Config) *Request {
+	r.tlsClientConfig = tlsConfig
+	return r
+}
+
+// SkipMiddleware method skips the named client request and response middlewares
+// for the current request, e.g., the token refresh request that must bypass
+// the auth middleware, see [Client.AddRequestMiddlewareNamed] and
+// [Client.AddResponseMiddlewareNamed].
+//
+//	client.R().
+//		SkipMiddleware("auth", "audit").
+//		Post("/oauth/token")
+//
+// NOTE: The [PrepareRequestMiddleware] cannot be skipped.
+func (r *Request) SkipMiddleware(names ...string) *Request {
+	r.skipMiddlewares = append(r.skipMiddlewares, names...)
+	return r
+}
+
+func (r *Request) isMiddlewareSkipped(name string) bool {
+	return len(name) > 0 && name != PrepareRequestMiddlewareName &&
+		slices.Contains(r.skipMiddlewares, name)
+}
+
 // SetLogger method sets given writer for logging Resty request and response details.
 // By default, requests and responses inherit their logger from the client.
+// It is helpful to route the debug and error output of the request to the
+// contextual logger of the caller, e.g., the logger tagged with the trace ID.
+//
+//	client.R().
+//		SetLogger(requestLogger).
+//		SetDebug(true).
+//		Get("/users")
 //
 // Compliant to interface [resty.Logger].
 //
@@ -937,6 +1623,24 @@ func (r *Request) SetLogger(l Logger) *Request {
 	return r
 }
 
+// SetSlogLogger method sets the given [slog.Logger] as the logger of the
+// current request, see [Client.SetSlogLogger].
+//
+//	client.R().
+//		SetSlogLogger(slog.Default().With("trace_id", traceID)).
+//		Get("/users")
+//
+// It overrides the logger value set at the client instance level.
+func (r *Request) SetSlogLogger(l *slog.Logger) *Request {
+	return r.SetLogger(NewSlogLogger(l))
+}
+
+// Logger method returns the logger of the current request, see
+// [Request.SetLogger].
+func (r *Request) Logger() Logger {
+	return r.log
+}
+
 // EnableDebug method is a helper method for [Request.SetDebug]
 func (r *Request) EnableDebug() *Request {
 	r.SetDebug(true)
@@ -1231,6 +1935,12 @@ func (r *Request) SetAllowMethodDeletePayload(allow bool) *Request {
 	return r
 }
 
+// RequestID method returns the request ID generated or propagated by the
+// [RequestIDMiddleware]; otherwise, it is empty.
+func (r *Request) RequestID() string {
+	return r.requestID
+}
+
 // TraceInfo method returns the trace info for the request.
 // If either the [Client.EnableTrace] or [Request.EnableTrace] function has not been called
 // before the request is made, an empty [resty.TraceInfo] object is returned.
@@ -1244,60 +1954,38 @@ func (r *Request) TraceInfo() TraceInfo {
 	ct.lock.RLock()
 	defer ct.lock.RUnlock()
 
-	ti := TraceInfo{
-		DNSLookup:      0,
-		TCPConnTime:    0,
-		ServerTime:     0,
-		IsConnReused:   ct.gotConnInfo.Reused,
-		IsConnWasIdle:  ct.gotConnInfo.WasIdle,
-		ConnIdleTime:   ct.gotConnInfo.IdleTime,
-		RequestAttempt: r.Attempt,
-	}
-
-	if !ct.dnsStart.IsZero() && !ct.dnsDone.IsZero() {
-		ti.DNSLookup = ct.dnsDone.Sub(ct.dnsStart)
-	}
-
-	if !ct.tlsHandshakeDone.IsZero() && !ct.tlsHandshakeStart.IsZero() {
-		ti.TLSHandshake = ct.tlsHandshakeDone.Sub(ct.tlsHandshakeStart)
-	}
-
-	if !ct.gotFirstResponseByte.IsZero() && !ct.gotConn.IsZero() {
-		ti.ServerTime = ct.gotFirstResponseByte.Sub(ct.gotConn)
-	}
-
-	// Calculate the total time accordingly when connection is reused,
-	// and DNS start and get conn time may be zero if the request is invalid.
-	// See issue #1016.
-	requestStartTime := r.Time
-	if ct.gotConnInfo.Reused && !ct.getConn.IsZero() {
-		requestStartTime = ct.getConn
-	} else if !ct.dnsStart.IsZero() {
-		requestStartTime = ct.dnsStart
-	}
-	ti.TotalTime = ct.endTime.Sub(requestStartTime)
+	return r.lastTraceInfo(ct)
+}
 
-	// Only calculate on successful connections
-	if !ct.connectDone.IsZero() {
-		ti.TCPConnTime = ct.connectDone.Sub(ct.dnsDone)
-	}
+// TraceInfos method returns the trace info of every request attempt and
+// redirect hop made during the request execution, in the order they happened.
+// The last entry is the same as [Request.TraceInfo].
+//
+//	client := resty.New().EnableTrace().SetRetryCount(2)
+//
+//	resp, err := client.R().Get("https://example.com")
+//	for _, ti := range resp.Request.TraceInfos() {
+//		fmt.Println(ti.RequestAttempt, ti.RedirectHop, ti.TotalTime)
+//	}
+//
+// NOTE: It returns nil when the trace is not enabled.
+func (r *Request) TraceInfos() []TraceInfo {
+	ct := r.trace
 
-	// Only calculate on successful connections
-	if !ct.gotConn.IsZero() {
-		ti.ConnTime = ct.gotConn.Sub(ct.getConn)
+	if ct == nil {
+		return nil
 	}
 
-	// Only calculate on successful connections
-	if !ct.gotFirstResponseByte.IsZero() {
-		ti.ResponseTime = ct.endTime.Sub(ct.gotFirstResponseByte)
-	}
+	ct.lock.RLock()
+	defer ct.lock.RUnlock()
 
-	// Capture remote address info when connection is non-nil
-	if ct.gotConnInfo.Conn != nil {
-		ti.RemoteAddr = ct.gotConnInfo.Conn.RemoteAddr().String()
+	tis := make([]TraceInfo, 0, len(r.traceHistory)+len(ct.hops)+1)
+	tis = append(tis, r.traceHistory...)
+	for _, ti := range ct.hops {
+		ti.Name = r.name
+		tis = append(tis, ti)
 	}
-
-	return ti
+	return append(tis, r.lastTraceInfo(ct))
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -1371,6 +2059,62 @@ func (r *Request) Send() (*Response, error) {
 	return r.Execute(r.Method, r.URL)
 }
 
+// DryRun method prepares the HTTP request using the method and URL already defined
+// for current [Request], i.e., it runs all the request middlewares, body marshaling,
+// and auth; however, it does not send it. It returns the prepared [http.Request],
+// so the tests and tools can inspect exactly what would be sent.
+//
+//	req, err := client.R().
+//		SetMethod(resty.MethodPost).
+//		SetURL("https://api.example.com/users").
+//		SetBody(user).
+//		DryRun()
+//
+// NOTE:
+//   - The request body is read into memory, and it can be re-read.
+//   - The load balancer does not receive the request feedback.
+func (r *Request) DryRun() (*http.Request, error) {
+	if isStringEmpty(r.Method) {
+		r.Method = MethodGet
+	}
+	r.Attempt = 1
+	r.loadBalanced = false
+	defer func() { backToBufPool(r.bodyBuf) }()
+
+	if err := r.client.executeRequestMiddlewares(r); err != nil {
+		if irErr, ok := err.(*invalidRequestError); ok {
+			err = irErr.Err
+		}
+		return nil, err
+	}
+
+	if hostHeader := r.Header.Get("Host"); hostHeader != "" {
+		r.RawRequest.Host = hostHeader
+	}
+
+	body, err := bufferRequestBody(r.RawRequest)
+	if r.multipartErrChan != nil {
+		if mErr := <-r.multipartErrChan; mErr != nil {
+			err = mErr
+		}
+	}
+	if r.isMultiPart {
+		for _, mf := range r.multipartFields {
+			mf.close()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		r.RawRequest.ContentLength = int64(len(body))
+		r.RawRequest.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return r.RawRequest, nil
+}
+
 // Execute method performs the HTTP request with the given HTTP method and URL
 // for current [Request].
 //
@@ -1387,7 +2131,14 @@ func (r *Request) Execute(method, url string) (res *Response, err error) {
 		}
 	}()
 
+	if r.client.IsClosed() {
+		return nil, ErrClientClosed
+	}
+
 	r.Method = method
+	startedAt := time.Now()
+	r.client.stats.inFlight.Add(1)
+	defer r.client.stats.inFlight.Add(-1)
 
 	if r.RetryCount < 0 {
 		r.RetryCount = 0 // default behavior is no retry
@@ -1396,8 +2147,8 @@ func (r *Request) Execute(method, url string) (res *Response, err error) {
 	isIdempotent := r.isIdempotent()
 	var backoff *backoffWithJitter
 	if r.RetryCount > 0 && isIdempotent {
-		backoff = newBackoffWithJitter(r.RetryWaitTime, r.RetryMaxWaitTime)
-		r.RetryTraceID = newGUID()
+		backoff = r.client.newBackoffWithJitter(r.RetryWaitTime, r.RetryMaxWaitTime)
+		r.RetryTraceID = r.client.newGUID()
 	}
 
 	isInvalidRequestErr := false
@@ -1406,7 +2157,9 @@ func (r *Request) Execute(method, url string) (res *Response, err error) {
 		r.Attempt++
 		err = nil
 		r.URL = url
+		r.loadBalanced = false
 		res, err = r.client.execute(r)
+		r.sendLoadBalancerFeedback(res, err)
 		if err != nil {
 			if irErr, ok := err.(*invalidRequestError); ok {
 				err = irErr.Err
@@ -1498,28 +2251,64 @@ func (r *Request) Execute(method, url string) (res *Response, err error) {
 	}
 
 	r.IsDone = true
+	r.client.stats.complete(r, res, err)
 
 	if isInvalidRequestErr {
 		r.client.onInvalidHooks(r, err)
 	} else {
 		r.client.onErrorHooks(r, res, err)
+		r.client.collectMetrics(r, res, err, url, startedAt)
 	}
 
-	r.sendLoadBalancerFeedback(res, err)
 	backToBufPool(r.bodyBuf)
+
+	res, err = r.followCreatedIfRequired(res, err)
 	return
 }
 
+// Reset method resets the request to the state of the new request created from
+// the client instance, see [Client.R]; everything set on the request, e.g.,
+// headers, query params, body, result, and the previous response, is dropped.
+// The request context is kept.
+//
+//	req := client.R()
+//	res, err := req.SetBody(user).Post("/users")
+//	// ...
+//	res, err = req.Reset().SetQueryParam("page", "2").Get("/users")
+//
+// NOTE: Do not reset the request while it is in flight, e.g., [Request.Async].
+func (r *Request) Reset() *Request {
+	nr := r.client.R()
+	nr.ctx = r.ctx
+	*r = *nr
+	return r
+}
+
 // Clone returns a deep copy of r with its context changed to ctx.
 // It does clone appropriate fields, reset, and reinitialize, so
 // [Request] can be used again.
 //
-// The body is not copied, but it's a reference to the original body.
+// The headers, query, form and path params, cookies, multipart fields,
+// retry conditions and hooks, and the body buffer are deep-copied, and the
+// `[]byte` body is copied; so the template request can be cloned and executed
+// concurrently, e.g., fan-out to many IDs.
+//
+//	tmpl := client.R().
+//		SetHeader("Accept", "application/json").
+//		SetResult(&User{})
+//
+//	for _, id := range ids {
+//		go func(id string) {
+//			res, err := tmpl.Clone(ctx).
+//				SetPathParam("id", id).
+//				Get("/users/{id}")
+//			// ...
+//		}(id)
+//	}
 //
-//	req := client.R().
-//		SetBody("body").
-//		SetHeader("header", "value")
-//	clonedRequest := req.Clone(context.Background())
+// NOTE: The [io.Reader] body and the multipart field readers are not copied,
+// but it's a reference to the original; those must not be read concurrently.
+// The cloning itself must not race with the modifications of the template.
 func (r *Request) Clone(ctx context.Context) *Request {
 	if ctx == nil {
 		panic("resty: Request.Clone nil context")
@@ -1538,6 +2327,25 @@ func (r *Request) Clone(ctx context.Context) *Request {
 	rr.FormData = cloneURLValues(r.FormData)
 	rr.QueryParams = cloneURLValues(r.QueryParams)
 	rr.PathParams = maps.Clone(r.PathParams)
+	rr.errorTypes = maps.Clone(r.errorTypes)
+	rr.retryConditions = slices.Clone(r.retryConditions)
+	rr.retryHooks = slices.Clone(r.retryHooks)
+	rr.expectedDigests = slices.Clone(r.expectedDigests)
+	rr.expectedContentTypes = slices.Clone(r.expectedContentTypes)
+	rr.skipMiddlewares = slices.Clone(r.skipMiddlewares)
+	rr.orderedQueryParams = slices.Clone(r.orderedQueryParams)
+	rr.uriTemplateVars = maps.Clone(r.uriTemplateVars)
+	if ev := reflect.ValueOf(r.jsonArrayElement); ev.Kind() == reflect.Pointer && !ev.IsNil() {
+		// the element is decoded into, so the clones must not share it
+		rr.jsonArrayElement = newInterface(r.jsonArrayElement)
+	}
+	if r.canary != nil {
+		enable := *r.canary
+		rr.canary = &enable
+	}
+	if b, ok := r.Body.([]byte); ok && b != nil {
+		rr.Body = slices.Clone(b)
+	}
 
 	// clone basic auth
 	if r.credentials != nil {
@@ -1546,7 +2354,7 @@ func (r *Request) Clone(ctx context.Context) *Request {
 
 	// clone cookies
 	if l := len(r.Cookies); l > 0 {
-		rr.Cookies = make([]*http.Cookie, l)
+		rr.Cookies = make([]*http.Cookie, 0, l)
 		for _, cookie := range r.Cookies {
 			rr.Cookies = append(rr.Cookies, cloneCookie(cookie))
 		}
@@ -1567,12 +2375,21 @@ func (r *Request) Clone(ctx context.Context) *Request {
 	// reset values
 	rr.Time = time.Time{}
 	rr.Attempt = 0
+	rr.IsDone = false
+	rr.RetryTraceID = ""
+	rr.requestID = ""
+	rr.resultCurlCmd = ""
+	rr.trace = nil
+	rr.traceHistory = nil
+	rr.connInfo = nil
 	rr.initTraceIfEnabled()
-	r.values = make(map[string]any)
-	r.multipartErrChan = nil
-	r.ctxCancelFunc = nil
+	rr.values = maps.Clone(r.values)
+	delete(rr.values, debugRequestLogKey{})
+	rr.multipartErrChan = nil
+	rr.ctxCancelFunc = nil
 
 	// copy bodyBuf
+	rr.bodyBuf = nil
 	if r.bodyBuf != nil {
 		rr.bodyBuf = acquireBuffer()
 		rr.bodyBuf.Write(r.bodyBuf.Bytes())
@@ -1620,6 +2437,10 @@ func (r *Request) fmtBodyString(sl int) (body string) {
 	}
 
 	// multipart or form-data
+	if (r.isMultiPart || r.isFormData) && len(r.contentEncoding) > 0 {
+		body = fmt.Sprintf("***** BODY IS %s COMPRESSED *****", r.contentEncoding)
+		return
+	}
 	if r.isMultiPart || r.isFormData {
 		bodySize := r.bodyBuf.Len()
 		if bodySize > sl {
@@ -1645,7 +2466,11 @@ func (r *Request) fmtBodyString(sl int) (body string) {
 		(kind == reflect.Struct || kind == reflect.Map || kind == reflect.Slice) {
 		buf := acquireBuffer()
 		defer releaseBuffer(buf)
-		if err = encodeJSONEscapeHTMLIndent(buf, &r.Body, false, "   "); err == nil {
+		if marshal := r.client.jsonMarshaler(); marshal != nil {
+			if prtBodyBytes, err = marshal(r.Body); err == nil {
+				prtBodyBytes = jsonIndent(prtBodyBytes)
+			}
+		} else if err = encodeJSONEscapeHTMLIndent(buf, &r.Body, false, "   "); err == nil {
 			prtBodyBytes = buf.Bytes()
 		}
 	} else if xmlKey == ctKey && kind == reflect.Struct {
@@ -1678,25 +2503,109 @@ func (r *Request) fmtBodyString(sl int) (body string) {
 
 func (r *Request) initValuesMap() {
 	if r.values == nil {
-		r.values = make(map[string]any)
+		r.values = make(map[any]any)
 	}
 }
 
 func (r *Request) initTraceIfEnabled() {
 	if r.IsTrace {
-		r.trace = new(clientTrace)
-		r.ctx = r.trace.createContext(r.Context())
+		if ct := r.trace; ct != nil {
+			// keep the trace info of the previous attempt
+			ct.lock.RLock()
+			for _, ti := range ct.hops {
+				ti.Name = r.name
+				r.traceHistory = append(r.traceHistory, ti)
+			}
+			r.traceHistory = append(r.traceHistory, r.lastTraceInfo(ct))
+			ct.lock.RUnlock()
+		}
+		r.trace = newClientTrace(r.Attempt)
 	}
 }
 
+// withTraceContext method returns the given context with the trace hooks of
+// the current request attempt.
+func (r *Request) withTraceContext(ctx context.Context) context.Context {
+	if r.IsTrace && r.trace != nil {
+		ctx = r.trace.createContext(ctx)
+	}
+	if r.client != nil {
+		if th := r.client.TraceHooks(); th != nil {
+			ctx = th.createContext(ctx, r)
+		}
+		ctx = r.client.stats.createContext(ctx, r)
+	}
+	if r.informationalFn != nil {
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+				r.informationalFn(code, http.Header(header))
+				return nil
+			},
+		})
+	}
+	return ctx
+}
+
+func (r *Request) setConnInfo(ci ConnInfo) {
+	r.connInfo = &ci
+}
+
+// lastTraceInfo method computes the [TraceInfo] of the last hop of the
+// given trace. The caller must hold the trace lock.
+func (r *Request) lastTraceInfo(ct *clientTrace) TraceInfo {
+	startTime := r.Time
+	if len(ct.hops) > 0 {
+		startTime = ct.hopStart
+	}
+	ti := ct.traceInfo(startTime)
+	ti.Name = r.name
+	ti.RequestAttempt = ct.attempt
+	ti.RedirectHop = len(ct.hops)
+	return ti
+}
+
 func (r *Request) isHeaderExists(k string) bool {
 	_, f := r.Header[k]
 	return f
 }
 
+// wrapUploadProgress method wraps the raw request body to report the upload
+// progress, if the callback is set.
+func (r *Request) wrapUploadProgress() {
+	fn := r.uploadProgressFn
+	rawReq := r.RawRequest
+	if fn == nil || rawReq.Body == nil || rawReq.Body == http.NoBody {
+		return
+	}
+
+	total := rawReq.ContentLength
+	if total <= 0 {
+		total = -1
+	}
+	wrap := func(body io.ReadCloser) io.ReadCloser {
+		var sent int64
+		return &countReadCloser{r: body, f: func(n int64) {
+			sent += n
+			fn(sent, total)
+		}}
+	}
+
+	rawReq.Body = wrap(rawReq.Body)
+	if getBody := rawReq.GetBody; getBody != nil {
+		// the body is sent again on redirects
+		rawReq.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil || body == http.NoBody {
+				return body, err
+			}
+			return wrap(body), nil
+		}
+	}
+}
+
 func (r *Request) writeFormData(w *multipart.Writer) error {
-	for k, v := range r.FormData {
-		for _, iv := range v {
+	for _, k := range slices.Sorted(maps.Keys(r.FormData)) {
+		for _, iv := range r.FormData[k] {
 			if err := w.WriteField(k, iv); err != nil {
 				return err
 			}
@@ -1723,11 +2632,13 @@ func (r *Request) isPayloadSupported() bool {
 		return true
 	}
 
-	return false
+	return isWebDAVPayloadMethod(r.Method)
 }
 
+// sendLoadBalancerFeedback method sends the feedback of the request attempt
+// to the load balancer, if it has selected the Base URL of the attempt.
 func (r *Request) sendLoadBalancerFeedback(res *Response, err error) {
-	if r.client.LoadBalancer() == nil {
+	if r.client.LoadBalancer() == nil || !r.loadBalanced {
 		return
 	}
 
@@ -1746,12 +2657,20 @@ func (r *Request) sendLoadBalancerFeedback(res *Response, err error) {
 	if success && res != nil &&
 		(res.StatusCode() >= 500 && res.StatusCode() != http.StatusNotImplemented) {
 		success = false
+		err = &HTTPError{StatusCode: res.StatusCode(), Status: res.Status()}
+	}
+
+	var latency time.Duration
+	if res != nil && !res.receivedAt.IsZero() && !r.Time.IsZero() {
+		latency = res.receivedAt.Sub(r.Time)
 	}
 
 	r.client.LoadBalancer().Feedback(&RequestFeedback{
 		BaseURL: r.baseURL,
 		Success: success,
 		Attempt: r.Attempt,
+		Latency: latency,
+		Err:     err,
 	})
 }
 
@@ -1785,7 +2704,7 @@ func (r *Request) withTimeout() *http.Request {
 		return r.RawRequest
 	}
 	if r.Timeout > 0 {
-		ctx, ctxCancelFunc := context.WithTimeout(r.Context(), r.Timeout)
+		ctx, ctxCancelFunc := context.WithTimeout(r.RawRequest.Context(), r.Timeout)
 		r.ctxCancelFunc = ctxCancelFunc
 		return r.RawRequest.WithContext(ctx)
 	}
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// NewRequestCapture function creates the new request capture, it captures the
+// outgoing requests, i.e., after all the middlewares, and sends them via the
+// given transport, e.g., [MockTransport]. It is set on the client via
+// [Client.SetTransport].
+//
+//	capture := resty.NewRequestCapture(client.Transport())
+//	client.SetTransport(capture)
+//
+//	// ... test code ...
+//
+//	capture.AssertCount(t, 1)
+//	req := capture.Last()
+//	req.AssertHeader(t, "Authorization", "Bearer token")
+//	req.AssertJSONBody(t, `{"name":"resty"}`)
+func NewRequestCapture(transport http.RoundTripper) *RequestCapture {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &RequestCapture{
+		lock:      new(sync.Mutex),
+		transport: transport,
+	}
+}
+
+var _ http.RoundTripper = (*RequestCapture)(nil)
+
+// RequestCapture struct is the [http.RoundTripper] that captures the outgoing
+// requests into an inspectable list, see [NewRequestCapture].
+type RequestCapture struct {
+	lock      *sync.Mutex
+	transport http.RoundTripper
+	requests  []*CapturedRequest
+}
+
+// CapturedRequest struct holds the outgoing request captured by the
+// [RequestCapture], the request body is read into the Body.
+type CapturedRequest struct {
+	Request *http.Request
+	Body    []byte
+}
+
+// Requests method returns the captured requests so far, in the order.
+func (rc *RequestCapture) Requests() []*CapturedRequest {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	return append([]*CapturedRequest{}, rc.requests...)
+}
+
+// Find method returns the captured requests matching the given method and URL
+// pattern, see [MockTransport.On] for the pattern syntax.
+func (rc *RequestCapture) Find(method, urlPattern string) []*CapturedRequest {
+	route := &MockRoute{
+		method:     strings.ToUpper(method),
+		urlPattern: urlPattern,
+		urlRegexp:  compileMockPattern(urlPattern),
+	}
+	var result []*CapturedRequest
+	for _, cr := range rc.Requests() {
+		if route.match(cr.Request, cr.Body) {
+			result = append(result, cr)
+		}
+	}
+	return result
+}
+
+// Last method returns the last captured request, or nil if none.
+func (rc *RequestCapture) Last() *CapturedRequest {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if len(rc.requests) == 0 {
+		return nil
+	}
+	return rc.requests[len(rc.requests)-1]
+}
+
+// Count method returns the number of the captured requests.
+func (rc *RequestCapture) Count() int {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	return len(rc.requests)
+}
+
+// Reset method removes the captured requests.
+func (rc *RequestCapture) Reset() {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	rc.requests = nil
+}
+
+// AssertCount method asserts the number of the captured requests.
+func (rc *RequestCapture) AssertCount(t MockT, n int) bool {
+	t.Helper()
+	if count := rc.Count(); count != n {
+		t.Errorf("resty: expected %d captured request(s), got %d", n, count)
+		return false
+	}
+	return true
+}
+
+// AssertCalled method asserts the number of the captured requests matching
+// the given method and URL pattern, see [RequestCapture.Find].
+func (rc *RequestCapture) AssertCalled(t MockT, method, urlPattern string, n int) bool {
+	t.Helper()
+	if count := len(rc.Find(method, urlPattern)); count != n {
+		t.Errorf("resty: expected %d captured request(s) of %s %s, got %d", n, method, urlPattern, count)
+		return false
+	}
+	return true
+}
+
+// RoundTrip method implements the [http.RoundTripper] interface.
+func (rc *RequestCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := bufferRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.lock.Lock()
+	rc.requests = append(rc.requests, &CapturedRequest{Request: req, Body: body})
+	rc.lock.Unlock()
+	return rc.transport.RoundTrip(req)
+}
+
+// AssertHeader method asserts the request header value, the empty value
+// asserts the header is present.
+func (cr *CapturedRequest) AssertHeader(t MockT, key, value string) bool {
+	t.Helper()
+	values := cr.Request.Header.Values(key)
+	switch {
+	case len(values) == 0:
+		t.Errorf("resty: expected header %s to be present", key)
+		return false
+	case value != "" && !slices.Contains(values, value):
+		t.Errorf("resty: expected header %s to be %q, got %q", key, value, values)
+		return false
+	}
+	return true
+}
+
+// AssertJSONBody method asserts the request body is JSON equal to the expected,
+// i.e., regardless of the formatting and the object keys order. The expected is
+// the JSON string or bytes, or the value to be marshaled.
+//
+//	req.AssertJSONBody(t, `{"id": 1, "name": "resty"}`)
+//	req.AssertJSONBody(t, &User{ID: 1, Name: "resty"})
+func (cr *CapturedRequest) AssertJSONBody(t MockT, expected any) bool {
+	t.Helper()
+	var expectedJSON []byte
+	switch v := expected.(type) {
+	case string:
+		expectedJSON = []byte(v)
+	case []byte:
+		expectedJSON = v
+	default:
+		var err error
+		if expectedJSON, err = json.Marshal(v); err != nil {
+			t.Errorf("resty: expected body: %v", err)
+			return false
+		}
+	}
+
+	var want, got any
+	if err := json.Unmarshal(expectedJSON, &want); err != nil {
+		t.Errorf("resty: expected body: %v", err)
+		return false
+	}
+	if err := json.Unmarshal(cr.Body, &got); err != nil {
+		t.Errorf("resty: request body is not JSON: %v", err)
+		return false
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("resty: expected JSON body %s, got %s", expectedJSON, bytes.TrimSpace(cr.Body))
+		return false
+	}
+	return true
+}
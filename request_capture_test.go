@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRequestCapture(t *testing.T) {
+	mt := NewMockTransport()
+	mt.On("*", "/users/*").RespondString(http.StatusOK, "ok")
+
+	capture := NewRequestCapture(mt)
+	c := dcnl().
+		SetTransport(capture).
+		SetBaseURL("https://api.example.com").
+		AddRequestMiddleware(func(_ *Client, r *Request) error {
+			r.SetHeader("X-Middleware", "applied")
+			return nil
+		})
+	defer c.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	_, err := c.R().SetAuthToken("token").Get("/users/1")
+	assertNil(t, err)
+	_, err = c.R().SetBody(&user{ID: 2, Name: "resty"}).Post("/users/2")
+	assertNil(t, err)
+
+	assertEqual(t, true, capture.AssertCount(t, 2))
+	assertEqual(t, true, capture.AssertCalled(t, http.MethodGet, "/users/{id}", 1))
+	assertEqual(t, "/users/1", capture.Requests()[0].Request.URL.Path)
+	assertEqual(t, true, capture.Requests()[0].AssertHeader(t, "Authorization", "Bearer token"))
+
+	last := capture.Last()
+	assertEqual(t, true, last.AssertHeader(t, "X-Middleware", ""))
+	assertEqual(t, true, last.AssertJSONBody(t, `{"name": "resty", "id": 2}`))
+	assertEqual(t, true, last.AssertJSONBody(t, &user{ID: 2, Name: "resty"}))
+	assertEqual(t, 2, len(mt.Calls()))
+
+	mockT := &testMockT{}
+	assertEqual(t, false, capture.AssertCount(mockT, 1))
+	assertEqual(t, false, capture.AssertCalled(mockT, http.MethodDelete, "*", 1))
+	assertEqual(t, false, last.AssertHeader(mockT, "X-Missing", ""))
+	assertEqual(t, false, last.AssertHeader(mockT, "X-Middleware", "other"))
+	assertEqual(t, false, last.AssertJSONBody(mockT, `{"id": 3}`))
+	assertEqual(t, false, capture.Requests()[0].AssertJSONBody(mockT, `{}`))
+	assertEqual(t, []string{
+		"resty: expected 1 captured request(s), got 2",
+		"resty: expected 1 captured request(s) of DELETE *, got 0",
+		"resty: expected header X-Missing to be present",
+		`resty: expected header X-Middleware to be "other", got ["applied"]`,
+		`resty: expected JSON body {"id": 3}, got {"id":2,"name":"resty"}`,
+		"resty: request body is not JSON: unexpected end of JSON input",
+	}, mockT.errors)
+
+	capture.Reset()
+	assertEqual(t, 0, capture.Count())
+	assertNil(t, capture.Last())
+}
@@ -7,11 +7,14 @@ package resty
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -21,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -628,6 +632,29 @@ func TestRequestInsecureBasicAuth(t *testing.T) {
 	t.Logf("captured request-level logs: %s", logBuf.String())
 }
 
+func TestRequestSlogLogger(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c, clientLogBuf := dcldb()
+	c.SetBaseURL(ts.URL)
+
+	var logBuf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})).
+		With("trace_id", "abc123")
+
+	r := c.R().SetSlogLogger(l)
+	_, ok := r.Logger().(*slogLogger)
+	assertEqual(t, true, ok)
+
+	res, err := r.SetDebug(true).Get("/")
+	assertError(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, true, strings.Contains(logBuf.String(), `"trace_id":"abc123"`))
+	assertEqual(t, true, strings.Contains(logBuf.String(), `"request"`))
+	assertEqual(t, 0, clientLogBuf.Len())
+}
+
 func TestRequestBasicAuthFail(t *testing.T) {
 	ts := createAuthServer(t)
 	defer ts.Close()
@@ -1906,6 +1933,51 @@ func TestTraceInfoWithoutEnableTrace(t *testing.T) {
 	}
 }
 
+func TestTraceInfos(t *testing.T) {
+	var attempts int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/final":
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl().
+		EnableTrace().
+		SetRetryCount(2).
+		SetRetryWaitTime(time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Millisecond)
+
+	res, err := c.R().Get(ts.URL + "/redirect")
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+
+	tis := res.Request.TraceInfos()
+	assertEqual(t, 4, len(tis))
+	for i, e := range []struct{ attempt, hop int }{{1, 0}, {1, 1}, {2, 0}, {2, 1}} {
+		assertEqual(t, e.attempt, tis[i].RequestAttempt)
+		assertEqual(t, e.hop, tis[i].RedirectHop)
+		assertEqual(t, true, tis[i].TotalTime > 0)
+		assertEqual(t, true, tis[i].RemoteAddr != "")
+	}
+	assertEqual(t, res.Request.TraceInfo(), tis[3])
+
+	// redirect hops reuse the connection
+	assertEqual(t, true, tis[1].IsConnReused)
+
+	// trace not enabled
+	res, err = dcnl().R().Get(ts.URL + "/final")
+	assertNil(t, err)
+	assertNil(t, res.Request.TraceInfos())
+}
+
 func TestTraceInfoOnTimeout(t *testing.T) {
 	client := NewWithTransportSettings(&TransportSettings{
 		DialerTimeout: 100 * time.Millisecond,
@@ -2180,6 +2252,131 @@ func TestRequestClone(t *testing.T) {
 	assertNotEqual(t, parent.RawRequest, clone.RawRequest)
 }
 
+type countingRoundTripper struct {
+	count atomic.Int32
+	next  http.RoundTripper
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.count.Add(1)
+	return rt.next.RoundTrip(req)
+}
+
+func TestRequestSetTransport(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	rt := &countingRoundTripper{next: http.DefaultTransport}
+	res, err := c.R().SetTransport(rt).Get("/")
+	assertError(t, err)
+	assertEqual(t, "TestGet: text response", res.String())
+	assertEqual(t, int32(1), rt.count.Load())
+
+	// client transport is untouched
+	_, err = c.R().Get("/")
+	assertError(t, err)
+	assertEqual(t, int32(1), rt.count.Load())
+	assertEqual(t, false, c.Transport() == http.RoundTripper(rt))
+}
+
+func TestRequestSetTLSClientConfig(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("TestTLS"))
+	}))
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+	defer c.Close()
+	tlsConfig := c.TLSClientConfig()
+
+	_, err := c.R().Get("/")
+	assertNotNil(t, err)
+
+	res, err := c.R().
+		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+		Get("/")
+	assertError(t, err)
+	assertEqual(t, "TestTLS", res.String())
+
+	// client TLS config is untouched
+	assertEqual(t, true, tlsConfig == c.TLSClientConfig())
+	assertEqual(t, false, tlsConfig.InsecureSkipVerify)
+	_, err = c.R().Get("/")
+	assertNotNil(t, err)
+
+	t.Run("with request transport", func(t *testing.T) {
+		res, err := c.R().
+			SetTransport(&http.Transport{}).
+			SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+			Get("/")
+		assertError(t, err)
+		assertEqual(t, "TestTLS", res.String())
+	})
+
+	t.Run("not http transport", func(t *testing.T) {
+		_, err := c.R().
+			SetTransport(&CustomRoundTripper1{}).
+			SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+			Get("/")
+		assertErrorIs(t, ErrNotHttpTransportType, err)
+	})
+}
+
+func TestRequestCloneConcurrent(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path + "|" + r.Header.Get("X-Header") + "|" + r.Header.Get("Cookie")))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	tmpl := c.R().
+		SetHeader("X-Header", "template").
+		SetCookie(&http.Cookie{Name: "session", Value: "abc"}).
+		SetQueryParam("q", "1").
+		SetRetryCount(1).
+		AddRetryConditions(func(*Response, error) bool { return false })
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := tmpl.Clone(context.Background()).
+				SetPathParam("id", strconv.Itoa(i)).
+				AddRetryConditions(func(*Response, error) bool { return false }).
+				Get("/users/{id}")
+			assertNil(t, err)
+			results[i] = res.String()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		assertEqual(t, "/users/"+strconv.Itoa(i)+"|template|session=abc", result)
+	}
+
+	// template is left untouched
+	assertEqual(t, 0, len(tmpl.PathParams))
+	assertEqual(t, 1, len(tmpl.retryConditions))
+	assertEqual(t, false, tmpl.IsDone)
+	assertNil(t, tmpl.values)
+
+	clone := tmpl.Clone(context.Background())
+	assertEqual(t, 1, len(clone.Cookies))
+
+	body := []byte("template")
+	tmpl.SetBody(body)
+	clone = tmpl.Clone(context.Background())
+	clone.Body.([]byte)[0] = 'T'
+	assertEqual(t, "template", string(body))
+}
+
 func TestResponseBodyUnlimitedReads(t *testing.T) {
 	ts := createPostServer(t)
 	defer ts.Close()
@@ -2213,6 +2410,127 @@ func TestResponseBodyUnlimitedReads(t *testing.T) {
 	logResponse(t, resp)
 }
 
+type errorWriter struct{}
+
+func (errorWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestRequestSetResponseBodyWriter(t *testing.T) {
+	body := `{"id":"success","message":"login successful"}` + "\n" + strings.Repeat(" ", 64)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "application/json")
+		if r.URL.Path == "/gzip" {
+			w.Header().Set(hdrContentEncodingKey, "gzip")
+			gw := gzip.NewWriter(w)
+			_, _ = gw.Write([]byte(body))
+			_ = gw.Close()
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	for _, p := range []string{"/plain", "/gzip"} {
+		archive := &bytes.Buffer{}
+		res, err := c.R().
+			SetResult(&AuthSuccess{}).
+			SetResponseBodyWriter(archive).
+			Get(ts.URL + p)
+		assertNil(t, err)
+		assertEqual(t, "login successful", res.Result().(*AuthSuccess).Message)
+		assertEqual(t, body, archive.String())
+	}
+
+	// body is not accessed
+	archive := &bytes.Buffer{}
+	_, err := c.R().SetResponseBodyWriter(archive).Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, body, archive.String())
+
+	_, err = c.R().
+		SetResult(&AuthSuccess{}).
+		SetResponseBodyWriter(errorWriter{}).
+		Get(ts.URL)
+	assertEqual(t, "write failed", err.Error())
+}
+
+func TestRequestSetResponseBodyStream(t *testing.T) {
+	lines := `{"id":1}` + "\n" + `{"id":2}` + "\n" + `{"id":3}` + "\n"
+	block := make(chan struct{})
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set(hdrContentEncodingKey, "gzip")
+			gw := gzip.NewWriter(w)
+			_, _ = gw.Write([]byte(lines))
+			_ = gw.Close()
+		case "/block":
+			_, _ = w.Write([]byte(`{"id":1}` + "\n"))
+			w.(http.Flusher).Flush()
+			select {
+			case <-block:
+			case <-r.Context().Done():
+			}
+		default:
+			_, _ = w.Write([]byte(lines))
+		}
+	})
+	defer ts.Close()
+	defer close(block)
+
+	c := dcnl()
+
+	t.Run("json decoder", func(t *testing.T) {
+		res, err := c.R().SetResponseBodyStream(true).Get(ts.URL + "/gzip")
+		assertNil(t, err)
+		assertEqual(t, true, res.Request.DoNotParseResponse)
+
+		dec := res.JSONDecoder()
+		var ids []int
+		for dec.More() {
+			v := struct{ ID int }{}
+			assertNil(t, dec.Decode(&v))
+			ids = append(ids, v.ID)
+		}
+		assertEqual(t, []int{1, 2, 3}, ids)
+
+		// closed on reaching the end of the body
+		_, err = res.RawResponse.Body.Read(make([]byte, 1))
+		assertNotNil(t, err)
+	})
+
+	t.Run("pipe", func(t *testing.T) {
+		res, err := c.R().SetResponseBodyStream(true).Get(ts.URL)
+		assertNil(t, err)
+
+		buf := &bytes.Buffer{}
+		n, err := res.Pipe(buf)
+		assertNil(t, err)
+		assertEqual(t, int64(len(lines)), n)
+		assertEqual(t, lines, buf.String())
+	})
+
+	t.Run("context cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		res, err := c.R().
+			SetContext(ctx).
+			SetResponseBodyStream(true).
+			Get(ts.URL + "/block")
+		assertNil(t, err)
+
+		dec := res.JSONDecoder()
+		v := struct{ ID int }{}
+		assertNil(t, dec.Decode(&v))
+		assertEqual(t, 1, v.ID)
+
+		cancel()
+		assertNotNil(t, dec.Decode(&v))
+		assertNil(t, res.Body.Close())
+	})
+}
+
 func TestRequestAllowPayload(t *testing.T) {
 	c := dcnl()
 
@@ -2602,3 +2920,349 @@ func TestRequestDataRace(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestRequestSetConditionalFrom(t *testing.T) {
+	lastModified := "Fri, 31 Dec 1999 23:59:59 GMT"
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(hdrIfNoneMatchKey) == `"v1"` &&
+			r.Header.Get(hdrIfModifiedSinceKey) == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set(hdrETagKey, `"v1"`)
+		w.Header().Set(hdrLastModifiedKey, lastModified)
+		_, _ = w.Write([]byte("feed"))
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	res1, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, false, res1.IsNotModified())
+	assertEqual(t, "feed", res1.String())
+
+	res2, err := c.R().SetConditionalFrom(res1).Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, true, res2.IsNotModified())
+	assertEqual(t, "", res2.String())
+
+	// no-op for nil or unexecuted response
+	req := c.R().SetConditionalFrom(nil).SetConditionalFrom(&Response{})
+	assertEqual(t, "", req.Header.Get(hdrIfNoneMatchKey))
+	assertEqual(t, "", req.Header.Get(hdrIfModifiedSinceKey))
+}
+
+func TestRequestForEachJSONArrayElement(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, jsonContentType)
+		if r.URL.Path == "/object" {
+			_, _ = w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		_, _ = w.Write([]byte("["))
+		for i := 1; i <= 100; i++ {
+			if i > 1 {
+				_, _ = w.Write([]byte(","))
+			}
+			if i%2 == 0 {
+				_, _ = fmt.Fprintf(w, `{"id": %d}`, i)
+			} else {
+				_, _ = fmt.Fprintf(w, `{"id": %d, "name": "user-%d"}`, i, i)
+			}
+		}
+		_, _ = w.Write([]byte("]"))
+	})
+	defer ts.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	c := dcnl()
+
+	var ids []int
+	var names int
+	res, err := c.R().
+		ForEachJSONArrayElement(&user{}, func(v any) error {
+			u := v.(*user)
+			ids = append(ids, u.ID)
+			if len(u.Name) > 0 {
+				names++
+			}
+			return nil
+		}).
+		Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, 100, len(ids))
+	assertEqual(t, 100, ids[99])
+	assertEqual(t, 50, names) // element is reset before decoding
+
+	errStop := errors.New("stop")
+	count := 0
+	_, err = c.R().
+		ForEachJSONArrayElement(&user{}, func(v any) error {
+			count++
+			if count == 3 {
+				return errStop
+			}
+			return nil
+		}).
+		Get(ts.URL)
+	assertErrorIs(t, errStop, err)
+	assertEqual(t, 3, count)
+
+	_, err = c.R().
+		ForEachJSONArrayElement(&user{}, func(any) error { return nil }).
+		Get(ts.URL + "/object")
+	assertEqual(t, "resty: expected JSON array, got {", err.Error())
+
+	_, err = c.R().
+		ForEachJSONArrayElement(user{}, func(any) error { return nil }).
+		Get(ts.URL)
+	assertEqual(t, "resty: decode JSON array element into non-pointer resty.user", err.Error())
+
+	t.Run("concurrent clones", func(t *testing.T) {
+		elem := &user{}
+		tmpl := c.R().ForEachJSONArrayElement(elem, func(v any) error {
+			if v.(*user).ID == 0 {
+				return errors.New("element is not decoded")
+			}
+			return nil
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				clone := tmpl.Clone(context.Background())
+				assertEqual(t, true, clone.jsonArrayElement != elem)
+				_, err := clone.Get(ts.URL)
+				assertNil(t, err)
+			}()
+		}
+		wg.Wait()
+		assertEqual(t, user{}, *elem)
+	})
+}
+
+func TestRequestOnInformationalResponse(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusProcessing)
+		w.Header().Set("Link", "</style.css>; rel=preload; as=style")
+		w.WriteHeader(http.StatusEarlyHints)
+		w.Header().Del("Link")
+		_, _ = w.Write([]byte("done"))
+	})
+	defer ts.Close()
+
+	var statuses []int
+	var links []string
+	res, err := dcnl().R().
+		OnInformationalResponse(func(status int, header http.Header) {
+			statuses = append(statuses, status)
+			links = append(links, header.Get("Link"))
+		}).
+		Get(ts.URL)
+
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+	assertEqual(t, "done", res.String())
+	assertEqual(t, []int{http.StatusProcessing, http.StatusEarlyHints}, statuses)
+	assertEqual(t, []string{"", "</style.css>; rel=preload; as=style"}, links)
+	assertEqual(t, "", res.Header().Get("Link"))
+}
+
+func TestRequestDryRun(t *testing.T) {
+	mt := NewMockTransport()
+	c := dcnl().
+		SetTransport(mt).
+		SetBaseURL("https://api.example.com").
+		SetAuthToken("token").
+		AddRequestMiddleware(func(_ *Client, r *Request) error {
+			r.SetHeader("X-Middleware", "applied")
+			return nil
+		})
+	defer c.Close()
+
+	req, err := c.R().
+		SetMethod(MethodPost).
+		SetURL("/users/{id}").
+		SetPathParam("id", "1").
+		SetQueryParam("q", "resty").
+		SetHeader("Host", "example.com").
+		SetBody(map[string]any{"name": "resty"}).
+		DryRun()
+	assertNil(t, err)
+	assertEqual(t, MethodPost, req.Method)
+	assertEqual(t, "https://api.example.com/users/1?q=resty", req.URL.String())
+	assertEqual(t, "example.com", req.Host)
+	assertEqual(t, "Bearer token", req.Header.Get(hdrAuthorizationKey))
+	assertEqual(t, "applied", req.Header.Get("X-Middleware"))
+	assertEqual(t, jsonContentType, req.Header.Get(hdrContentTypeKey))
+
+	body, _ := io.ReadAll(req.Body)
+	assertEqual(t, `{"name":"resty"}`, strings.TrimSpace(string(body)))
+	assertEqual(t, int64(len(body)), req.ContentLength)
+	rc, _ := req.GetBody()
+	again, _ := io.ReadAll(rc)
+	assertEqual(t, string(body), string(again))
+	assertEqual(t, 0, len(mt.Calls()))
+
+	t.Run("default method", func(t *testing.T) {
+		req, err := c.R().SetURL("/users").DryRun()
+		assertNil(t, err)
+		assertEqual(t, MethodGet, req.Method)
+		assertNil(t, req.Body)
+	})
+
+	t.Run("multipart streaming", func(t *testing.T) {
+		req, err := c.R().
+			SetMultipartField("file", "test.txt", "text/plain", strings.NewReader("content")).
+			SetURL("/upload").
+			SetMethod(MethodPost).
+			DryRun()
+		assertNil(t, err)
+		body, _ := io.ReadAll(req.Body)
+		assertEqual(t, true, strings.Contains(string(body), "content"))
+		assertEqual(t, true, strings.HasPrefix(req.Header.Get(hdrContentTypeKey), "multipart/form-data"))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := c.R().SetURL("/users").SetMethod(MethodPost).SetBody(make(chan int)).DryRun()
+		assertNotNil(t, err)
+		assertEqual(t, 0, len(mt.Calls()))
+	})
+}
+
+func TestResponseCookie(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "first"})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "abc123", HttpOnly: true})
+	})
+	defer ts.Close()
+
+	res, err := dcnl().R().Get(ts.URL)
+	assertNil(t, err)
+
+	c, found := res.Cookie("session_id")
+	assertEqual(t, true, found)
+	assertEqual(t, "abc123", c.Value)
+	assertEqual(t, true, c.HttpOnly)
+
+	c, found = res.Cookie("missing")
+	assertEqual(t, false, found)
+	assertNil(t, c)
+
+	assertEqual(t, map[string]string{"session_id": "abc123", "theme": "dark"}, res.SetCookieValues())
+
+	// no raw response
+	assertEqual(t, map[string]string{}, (&Response{}).SetCookieValues())
+}
+
+func TestRequestReset(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s|%s|%s|%s", r.Method, r.Header.Get("X-Custom"), r.URL.RawQuery, b)
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL).SetQueryParam("lang", "en")
+	defer c.Close()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	req := c.R().SetContext(ctx)
+	res, err := req.SetHeader("X-Custom", "yes").
+		SetQueryParam("page", "1").
+		SetBody("payload").
+		Post("/users")
+	assertNil(t, err)
+	assertEqual(t, "POST|yes|lang=en&page=1|payload", res.String())
+
+	res, err = req.Reset().Get("/users")
+	assertNil(t, err)
+	assertEqual(t, "GET||lang=en|", res.String())
+	assertNil(t, req.Body)
+	assertEqual(t, "value", req.Context().Value(ctxKey{}))
+	assertEqual(t, 1, req.Attempt)
+}
+
+func TestRequestValues(t *testing.T) {
+	var attempts int32
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer ts.Close()
+
+	type tenantKey struct{}
+	type attemptsKey struct{}
+
+	var seen []string
+	c, _ := dcldb()
+	c.SetRetryCount(1).
+		SetRetryWaitTime(time.Millisecond).
+		SetReturnErrorOnHTTPError(true).
+		AddRequestMiddleware(func(_ *Client, r *Request) error {
+			n, _ := RequestValue[int](r, attemptsKey{})
+			r.SetValue(attemptsKey{}, n+1)
+			return nil
+		}).
+		AddResponseMiddleware(func(_ *Client, res *Response) error {
+			tenant, _ := RequestValue[string](res.Request, tenantKey{})
+			seen = append(seen, "response:"+tenant)
+			return nil
+		}).
+		OnError(func(r *Request, _ error) {
+			seen = append(seen, "error:"+r.Value(tenantKey{}).(string))
+		}).
+		OnDebugLog(func(_ *DebugLog, res *Response) {
+			seen = append(seen, "debug:"+res.Request.Value(tenantKey{}).(string))
+		})
+	defer c.Close()
+
+	req := c.R().SetDebug(true).SetValue(tenantKey{}, "acme")
+	_, err := req.Get(ts.URL)
+	assertNotNil(t, err)
+	assertEqual(t, []string{"debug:acme", "response:acme", "debug:acme", "response:acme", "error:acme"}, seen)
+
+	// preserved across the retry attempts
+	n, ok := RequestValue[int](req, attemptsKey{})
+	assertEqual(t, true, ok)
+	assertEqual(t, 2, n)
+
+	// wrong type and missing key
+	_, ok = RequestValue[string](req, attemptsKey{})
+	assertEqual(t, false, ok)
+	assertNil(t, req.Value("missing"))
+
+	// copied on clone, the clone is independent
+	cr := req.Clone(context.Background()).SetValue(tenantKey{}, "other")
+	assertEqual(t, "other", cr.Value(tenantKey{}))
+	assertEqual(t, "acme", req.Value(tenantKey{}))
+	assertEqual(t, 2, cr.Value(attemptsKey{}))
+}
+
+func TestRequestConditionalSetters(t *testing.T) {
+	c := dcnl()
+	var applied bool
+	r := c.R().
+		SetHeaderIf(true, "X-Set", "yes").
+		SetHeaderIf(false, "X-Skipped", "no").
+		SetQueryParamIfNotEmpty("search", "resty").
+		SetQueryParamIfNotEmpty("cursor", "").
+		ApplyIf(true, func(r *Request) { r.SetQueryParam("page", "1") }).
+		ApplyIf(false, func(*Request) { applied = true })
+
+	assertEqual(t, "yes", r.Header.Get("X-Set"))
+	assertEqual(t, false, r.isHeaderExists("X-Skipped"))
+	assertEqual(t, "page=1&search=resty", r.QueryParams.Encode())
+	assertEqual(t, false, applied)
+}
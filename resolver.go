@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// Resolver interface is used to resolve the host name into IP addresses on
+// the client dials, see [Client.SetResolver]. The [net.Resolver] implements it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var _ Resolver = (*net.Resolver)(nil)
+
+// HostMapping method returns the static host mapping from the client instance.
+func (c *Client) HostMapping() map[string]string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.dialer == nil {
+		return nil
+	}
+	return maps.Clone(c.dialer.hostMapping)
+}
+
+// SetHostMapping method sets the static host mapping, like curl's `--resolve`,
+// so the given host names dial the fixed address instead of the DNS lookup.
+// The key is `host:port` or `host`, and the value is `ip:port` or `ip`; the
+// port of the request address is used if the value does not have one.
+//
+//	// test against the staging IP with the production SNI and Host header
+//	client.SetHostMapping(map[string]string{
+//		"api.example.com:443": "10.1.2.3:8443",
+//		"cdn.example.com":     "10.1.2.4",
+//	})
+//
+// NOTE: The TLS server name and `Host` header remain the request host name.
+func (c *Client) SetHostMapping(m map[string]string) *Client {
+	mapping := make(map[string]string, len(m))
+	for k, v := range m {
+		mapping[strings.ToLower(k)] = v
+	}
+	return c.updateDialer(func(d *transportDialer) {
+		d.hostMapping = mapping
+	})
+}
+
+// Resolver method returns the resolver from the client instance.
+func (c *Client) Resolver() Resolver {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.dialer == nil {
+		return nil
+	}
+	return c.dialer.resolver
+}
+
+// SetResolver method sets the custom resolver to resolve the host names on
+// the client dials, the resolved IP addresses are dialed in order until one
+// succeeds. The nil value resets it to the system resolver.
+//
+//	client.SetResolver(&net.Resolver{
+//		PreferGo: true,
+//		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+//			return (&net.Dialer{}).DialContext(ctx, network, "10.0.0.53:53")
+//		},
+//	})
+//
+// NOTE: The [httptrace.ClientTrace] DNS events are emitted for the custom resolver.
+func (c *Client) SetResolver(r Resolver) *Client {
+	return c.updateDialer(func(d *transportDialer) {
+		d.resolver = r
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// transportDialer struct wraps the transport dial function with the host
+// mapping, custom resolver, DNS cache, dual-stack settings and alternative
+// services; or dials the Unix domain socket for all the addresses.
+type transportDialer struct {
+	transport   *http.Transport
+	dial        dialContextFunc
+	unixSocket  string
+	hostMapping map[string]string
+	resolver    Resolver
+	cache       *DNSCache
+	altSvc      *AltSvcCache
+
+	preference      DialPreference
+	hostPreferences []dialPreferenceRule
+}
+
+// updateDialer method applies the given change on the copy of the transport
+// dialer, so the in-flight dials are not affected, and sets it on the transport.
+func (c *Client) updateDialer(fn func(*transportDialer)) *Client {
+	transport, err := c.HTTPTransport()
+	if err != nil {
+		c.Logger().Errorf("%v", err)
+		return c
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	d := &transportDialer{transport: transport}
+	if c.dialer != nil && c.dialer.transport == transport {
+		*d = *c.dialer
+	} else {
+		d.dial = transport.DialContext
+		if d.dial == nil {
+			d.dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+		}
+	}
+	fn(d)
+	c.dialer = d
+	transport.DialContext = d.DialContext
+	return c
+}
+
+func (d *transportDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(d.unixSocket) > 0 {
+		return d.dial(ctx, "unix", d.unixSocket)
+	}
+	if d.altSvc != nil {
+		if conn, ok := d.dialAltSvc(ctx, network, addr); ok {
+			return conn, nil
+		}
+	}
+	return d.dialAddr(ctx, network, addr)
+}
+
+func (d *transportDialer) dialAddr(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return d.dial(ctx, network, addr)
+	}
+
+	if mapped, found := d.lookupHostMapping(host, port); found {
+		return d.dial(ctx, network, mapped)
+	}
+
+	pref := d.dialPreference(host)
+	if (d.resolver == nil && d.cache == nil && pref.Family == IPFamilyAny) || net.ParseIP(host) != nil {
+		return d.dial(ctx, network, addr)
+	}
+
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+	primaries, fallbacks := pref.partition(network, ips)
+	if len(primaries) == 0 {
+		return nil, &net.OpError{Op: "dial", Net: network,
+			Err: &net.AddrError{Err: "no suitable address found", Addr: host}}
+	}
+	return d.dialParallel(ctx, network, port, primaries, fallbacks, pref.FallbackDelay)
+}
+
+func (d *transportDialer) lookupHostMapping(host, port string) (string, bool) {
+	if len(d.hostMapping) == 0 {
+		return "", false
+	}
+	host = strings.ToLower(host)
+	mapped, found := d.hostMapping[net.JoinHostPort(host, port)]
+	if !found {
+		if mapped, found = d.hostMapping[host]; !found {
+			return "", false
+		}
+	}
+	if _, _, err := net.SplitHostPort(mapped); err != nil {
+		mapped = net.JoinHostPort(strings.Trim(mapped, "[]"), port)
+	}
+	return mapped, true
+}
+
+// resolve method resolves the host via the custom resolver and DNS cache,
+// it emits the DNS trace events, if any.
+func (d *transportDialer) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+	var resolver Resolver = net.DefaultResolver
+	if d.resolver != nil {
+		resolver = d.resolver
+	}
+	var ips []net.IPAddr
+	var err error
+	if d.cache != nil {
+		ips, err = d.cache.resolve(ctx, resolver, host)
+	} else {
+		ips, err = resolver.LookupIPAddr(ctx, host)
+	}
+	if err == nil && len(ips) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	if trace != nil && trace.DNSDone != nil {
+		trace.DNSDone(httptrace.DNSDoneInfo{Addrs: ips, Err: err})
+	}
+	return ips, err
+}
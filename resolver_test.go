@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+)
+
+type testResolver struct {
+	addrs   map[string][]string
+	lookups atomic.Int32
+}
+
+func (tr *testResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	tr.lookups.Add(1)
+	addrs, found := tr.addrs[host]
+	if !found {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	ips := make([]net.IPAddr, 0, len(addrs))
+	for _, a := range addrs {
+		ips = append(ips, net.IPAddr{IP: net.ParseIP(a)})
+	}
+	return ips, nil
+}
+
+// createResolverTestServer function starts the TLS server, its certificate is
+// valid for `example.com`.
+func createResolverTestServer(t *testing.T) (*httptest.Server, string, *tls.Config) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Host+"|"+r.TLS.ServerName)
+	}))
+	t.Cleanup(ts.Close)
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	return ts, port, &tls.Config{RootCAs: pool}
+}
+
+func TestClientSetHostMapping(t *testing.T) {
+	ts, port, tlsConfig := createResolverTestServer(t)
+	addr := ts.Listener.Addr().String()
+
+	c := dcnl().SetTLSClientConfig(tlsConfig).SetHostMapping(map[string]string{
+		"Example.com:443": addr,
+		"example.com":     "[::1]",
+		"www.example.com": "127.0.0.1",
+	})
+	assertEqual(t, map[string]string{
+		"example.com:443": addr,
+		"example.com":     "[::1]",
+		"www.example.com": "127.0.0.1",
+	}, c.HostMapping())
+
+	// production SNI and Host header
+	res, err := c.R().Get("https://example.com/")
+	assertNil(t, err)
+	assertEqual(t, "example.com|example.com", res.String())
+
+	// port of the request address
+	res, err = c.R().Get("https://www.example.com:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, "www.example.com:"+port+"|www.example.com", res.String())
+
+	// IPv6 loopback is not listening
+	_, err = c.R().Get("https://example.com:" + port + "/")
+	assertNotNil(t, err)
+
+	// mapping is replaced
+	c.SetHostMapping(map[string]string{"example.com": "127.0.0.1"})
+	res, err = c.R().Get("https://example.com:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, "example.com:"+port+"|example.com", res.String())
+	assertEqual(t, 1, len(c.HostMapping()))
+}
+
+func TestClientSetResolver(t *testing.T) {
+	_, port, tlsConfig := createResolverTestServer(t)
+
+	resolver := &testResolver{addrs: map[string][]string{
+		// the first address is not listening
+		"example.com": {"127.0.0.2", "127.0.0.1"},
+	}}
+	var dnsHost string
+	var dnsAddrs []net.IPAddr
+	c := dcnl().
+		SetTLSClientConfig(tlsConfig).
+		SetResolver(resolver).
+		SetTraceHooks(&TraceHooks{
+			DNSStart: func(_ *Request, info httptrace.DNSStartInfo) { dnsHost = info.Host },
+			DNSDone:  func(_ *Request, info httptrace.DNSDoneInfo) { dnsAddrs = info.Addrs },
+		})
+	assertEqual(t, resolver, c.Resolver())
+
+	res, err := c.R().Get("https://example.com:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, "example.com:"+port+"|example.com", res.String())
+	assertEqual(t, int32(1), resolver.lookups.Load())
+	assertEqual(t, "example.com", dnsHost)
+	assertEqual(t, 2, len(dnsAddrs))
+
+	// not resolved
+	_, err = c.R().Get("https://unknown.example.com:" + port + "/")
+	var dnsErr *net.DNSError
+	assertEqual(t, true, errors.As(err, &dnsErr))
+	assertEqual(t, "unknown.example.com", dnsErr.Name)
+
+	// IP address is not resolved
+	_, err = c.R().Get("https://127.0.0.1:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, int32(2), resolver.lookups.Load())
+
+	// host mapping takes precedence
+	c.SetHostMapping(map[string]string{"example.com": "127.0.0.1"})
+	_, err = c.R().Get("https://example.com:" + port + "/")
+	assertNil(t, err)
+	assertEqual(t, int32(2), resolver.lookups.Load())
+
+	// reset to the system resolver
+	c.SetResolver(nil)
+	assertNil(t, c.Resolver())
+
+	// dialer is removed with the unix socket
+	c.SetUnixSocket("/tmp/resty.sock")
+	assertNil(t, c.HostMapping())
+}
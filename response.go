@@ -7,11 +7,14 @@ package resty
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,9 +33,19 @@ type Response struct {
 	// in the chain
 	Err error
 
-	bodyBytes  []byte
-	size       int64
-	receivedAt time.Time
+	bodyBytes    []byte
+	size         int64
+	bytesWritten int64
+	bytesRead    int64
+	receivedAt   time.Time
+	cacheStatus  CacheStatus
+	readToEOF    bool
+
+	sniffedContentType string
+
+	contentLength parsedHeader[int64]
+	contentType   parsedHeader[mediaType]
+	lastModified  parsedHeader[time.Time]
 }
 
 // Status method returns the HTTP status string for the executed request.
@@ -93,6 +106,37 @@ func (r *Response) Cookies() []*http.Cookie {
 	return r.RawResponse.Cookies()
 }
 
+// Cookie method returns the response cookie of the given name, i.e., the
+// `Set-Cookie` header. If the cookie is set more than once, the last one is
+// returned.
+//
+//	if c, found := res.Cookie("session_id"); found {
+//		fmt.Println(c.Value, c.Expires)
+//	}
+func (r *Response) Cookie(name string) (*http.Cookie, bool) {
+	var cookie *http.Cookie
+	for _, c := range r.Cookies() {
+		if c.Name == name {
+			cookie = c
+		}
+	}
+	return cookie, cookie != nil
+}
+
+// SetCookieValues method returns the response cookie values by the cookie name,
+// i.e., the `Set-Cookie` headers. If the cookie is set more than once, the last
+// value is returned.
+//
+//	sessionID := res.SetCookieValues()["session_id"]
+func (r *Response) SetCookieValues() map[string]string {
+	cookies := r.Cookies()
+	values := make(map[string]string, len(cookies))
+	for _, c := range cookies {
+		values[c.Name] = c.Value
+	}
+	return values
+}
+
 // String method returns the body of the HTTP response as a `string`.
 // It returns an empty string if it is nil or the body is zero length.
 //
@@ -143,6 +187,117 @@ func (r *Response) Size() int64 {
 	return r.size
 }
 
+// RequestID method returns the request ID of the request, see [Request.RequestID].
+// If it is empty, the value of the request ID header from the response is returned,
+// since the servers commonly echo the header.
+//
+// See [Client.SetRequestIDHeader]
+func (r *Response) RequestID() string {
+	if r.Request == nil {
+		return ""
+	}
+	if id := r.Request.RequestID(); len(id) > 0 {
+		return id
+	}
+	if r.Request.client == nil {
+		return ""
+	}
+	return r.Header().Get(r.Request.client.RequestIDHeader())
+}
+
+// BaseURL method returns the base URL the request is sent to, i.e., the target
+// chosen by the [Client.LoadBalancer], pinned via [Request.SetHost], or the
+// [Client.BaseURL]. It is empty for the absolute request URL.
+//
+//	log.Printf("served by %s", res.BaseURL())
+func (r *Response) BaseURL() string {
+	return r.Request.baseURL
+}
+
+// ConnInfo method returns the details of the connection used for the response,
+// such as whether it was reused, how long it was idle, and the local and remote
+// addresses. It returns nil if no connection was obtained, e.g., the response
+// was served from the [Cache].
+//
+// See [Client.OnConnection]
+func (r *Response) ConnInfo() *ConnInfo {
+	return r.Request.connInfo
+}
+
+// BytesWritten method returns the count of the request bytes written for
+// this response, i.e., the request line, headers, and body after the compression.
+//
+// See [Client.Stats]
+func (r *Response) BytesWritten() int64 {
+	return atomic.LoadInt64(&r.bytesWritten)
+}
+
+// BytesRead method returns the count of the response bytes read so far,
+// i.e., the status line, headers, and body before the decompression.
+//
+// See [Client.Stats]
+func (r *Response) BytesRead() int64 {
+	return atomic.LoadInt64(&r.bytesRead)
+}
+
+// Pipe method copies the response body into the given writer and closes the
+// body; it returns the count of the bytes copied. It is useful with
+// [Request.SetResponseBodyStream] and [Request.SetDoNotParseResponse].
+//
+//	res, err := client.R().SetResponseBodyStream(true).Get("https://example.com/report.csv")
+//	n, err := res.Pipe(os.Stdout)
+func (r *Response) Pipe(w io.Writer) (int64, error) {
+	if r.Body == nil {
+		return 0, nil
+	}
+	defer closeq(r.Body)
+	return io.Copy(w, r.Body)
+}
+
+// SaveToFile method saves the response body into the given file; the directory
+// is created if it does not exist. The body is written into the temp file in the
+// same directory and renamed on success, so the existing file is never left
+// truncated. See [Request.SetOutputFileSync].
+//
+//	res, err := client.R().SetDoNotParseResponse(true).Get("https://example.com/report.csv")
+//	err = res.SaveToFile("/tmp/report.csv")
+//
+// With [Request.SetDoNotParseResponse] or [Request.SetResponseBodyStream], the
+// body is streamed into the file and closed; otherwise, the read body bytes are
+// written, see [Response.Bytes].
+//
+// NOTE: Use [Request.SetOutputFileName] instead to save the body while it is
+// received, the body of such a response is already consumed.
+func (r *Response) SaveToFile(file string) error {
+	file = filepath.Clean(file)
+	if err := createDirectory(filepath.Dir(file)); err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if r.Body != nil && (r.Request.DoNotParseResponse || r.Request.responseBodyStream) {
+		defer closeq(r.Body)
+		body = r.Body
+	} else {
+		body = bytes.NewReader(r.Bytes())
+	}
+	_, err := writeFileAtomic(file, body, r.Request.outputFileSync)
+	return err
+}
+
+// JSONDecoder method returns the [json.Decoder] that reads the response body
+// incrementally. It is useful with [Request.SetResponseBodyStream] for the large
+// or the newline-delimited JSON responses.
+//
+// NOTE: The decoder may not read the body till the end, so close the body or
+// the request context once done.
+func (r *Response) JSONDecoder() *json.Decoder {
+	if r.Body == nil {
+		return json.NewDecoder(http.NoBody)
+	}
+	return json.NewDecoder(r.Body)
+}
+
 // IsSuccess method returns true if HTTP status `code >= 200 and <= 299` otherwise false.
 func (r *Response) IsSuccess() bool {
 	return r.StatusCode() > 199 && r.StatusCode() < 300
@@ -153,6 +308,37 @@ func (r *Response) IsError() bool {
 	return r.StatusCode() > 399
 }
 
+// IsNotModified method returns true if HTTP status code is 304 Not Modified;
+// otherwise, it is false.
+//
+// See [Request.SetConditionalFrom]
+func (r *Response) IsNotModified() bool {
+	return r.StatusCode() == http.StatusNotModified
+}
+
+// CacheStatus method returns how the response relates to the client response
+// cache. It is [CacheStatusNone] when the cache is not enabled or the request
+// is not cacheable.
+//
+// See [Client.SetCache]
+func (r *Response) CacheStatus() CacheStatus {
+	return r.cacheStatus
+}
+
+// IsFromCache method returns true if the response body is served from the
+// client response cache; otherwise, it is false.
+func (r *Response) IsFromCache() bool {
+	return r.cacheStatus == CacheStatusHit ||
+		r.cacheStatus == CacheStatusStale ||
+		r.cacheStatus == CacheStatusRevalidated
+}
+
+// IsStale method returns true if the response is a stale cached response served
+// per `stale-while-revalidate` or `stale-if-error` Cache-Control extensions.
+func (r *Response) IsStale() bool {
+	return r.cacheStatus == CacheStatusStale
+}
+
 // RedirectHistory method returns a redirect history slice with the URL and status code
 func (r *Response) RedirectHistory() []*RedirectInfo {
 	if r.RawResponse == nil {
@@ -271,6 +457,58 @@ func (r *Response) wrapCopyReadCloser() {
 	}
 }
 
+// wrapDigestVerifier method wraps the response body to verify the given digests
+// at the end of the body.
+func (r *Response) wrapDigestVerifier(digests []*contentDigest) {
+	if len(digests) == 0 || r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	r.Body = newDigestReadCloser(r.Body, digests)
+	r.readToEOF = true
+}
+
+// drainIfRequired method reads the remaining body after the decoding, since the
+// decoder may not read the body till the end, e.g., the digest verification and
+// the body writer require the whole body.
+func (r *Response) drainIfRequired(err error) error {
+	if err != nil || !r.readToEOF {
+		return err
+	}
+	_, err = io.Copy(io.Discard, r.Body)
+	return err
+}
+
+// wrapBodyWriter method wraps the response body to write the read bytes into
+// the given writer, see [Request.SetResponseBodyWriter].
+func (r *Response) wrapBodyWriter(w io.Writer) {
+	if w == nil || r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	r.Body = &teeReadCloser{r: r.Body, w: w}
+	r.readToEOF = true
+}
+
+// wrapBodyStream method wraps the response body to close it automatically on
+// reaching the end of the body or the context end.
+func (r *Response) wrapBodyStream(ctx context.Context) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	sr := &streamReadCloser{r: r.Body}
+	// the raw body close is safe for the concurrent use, unlike the decompressers
+	raw := r.RawResponse.Body
+	sr.stop = context.AfterFunc(ctx, func() { _ = raw.Close() })
+	r.Body = sr
+}
+
+// hasContent method reports whether the response is expected to have the
+// body content.
+func (r *Response) hasContent() bool {
+	sc := r.StatusCode()
+	return r.Request.Method != MethodHead && sc != http.StatusNoContent &&
+		sc != http.StatusNotModified && sc >= http.StatusOK
+}
+
 func (r *Response) wrapContentDecompresser() error {
 	ce := r.Header().Get(hdrContentEncodingKey)
 	if isStringEmpty(ce) {
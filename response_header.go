@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"maps"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentLengthHeader method returns the value of the response `Content-Length`
+// header. It returns false if the header is not present or invalid, e.g., on the
+// chunked response.
+//
+// NOTE: The header value may differ from [Response.Size], e.g., on the
+// compressed response, the transport removes the header.
+func (r *Response) ContentLengthHeader() (int64, bool) {
+	return r.contentLength.get(r.Header().Get(hdrContentLengthKey), parseContentLength)
+}
+
+// RetryAfter method returns the delay of the response `Retry-After` header, in
+// either the seconds or the HTTP-date format. The past date returns zero delay.
+// It returns false if the header is not present or invalid.
+//
+//	if res.StatusCode() == http.StatusTooManyRequests {
+//		if delay, ok := res.RetryAfter(); ok {
+//			time.Sleep(delay)
+//		}
+//	}
+func (r *Response) RetryAfter() (time.Duration, bool) {
+	// not cached, the delay of the HTTP-date is relative to the current time
+	return parseRetryAfterHeader(r.Header().Get(hdrRetryAfterKey))
+}
+
+// ContentType method returns the media type, in lowercase, and the params of
+// the response `Content-Type` header. It returns an empty media type if the
+// header is not present or invalid.
+//
+//	mediaType, params := res.ContentType() // "application/json", {"charset": "utf-8"}
+func (r *Response) ContentType() (string, map[string]string) {
+	ct, _ := r.contentType.get(r.Header().Get(hdrContentTypeKey), parseContentTypeHeader)
+	return ct.mediaType, maps.Clone(ct.params)
+}
+
+// LastModified method returns the time of the response `Last-Modified` header.
+// It returns false if the header is not present or invalid.
+func (r *Response) LastModified() (time.Time, bool) {
+	return r.lastModified.get(r.Header().Get(hdrLastModifiedKey), parseHTTPTime)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// parsedHeader type caches the parsed value of the header, it is parsed again
+// if the header value is changed, e.g., by the response middleware.
+type parsedHeader[T any] struct {
+	parsed bool
+	raw    string
+	value  T
+	ok     bool
+}
+
+func (p *parsedHeader[T]) get(raw string, parse func(string) (T, bool)) (T, bool) {
+	if !p.parsed || p.raw != raw {
+		p.value, p.ok = parse(raw)
+		p.raw = raw
+		p.parsed = true
+	}
+	return p.value, p.ok
+}
+
+type mediaType struct {
+	mediaType string
+	params    map[string]string
+}
+
+func parseContentTypeHeader(v string) (mediaType, bool) {
+	mt, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		// lenient on the malformed params, same as the content type validation
+		mt, params = parseMediaType(v), nil
+	}
+	if len(mt) == 0 {
+		return mediaType{}, false
+	}
+	return mediaType{mediaType: mt, params: params}, true
+}
+
+func parseContentLength(v string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func parseHTTPTime(v string) (time.Time, bool) {
+	if len(v) == 0 {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(v)
+	return t, err == nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseHeaderGetters(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	res := &Response{RawResponse: &http.Response{Header: http.Header{}}}
+	res.Header().Set(hdrContentLengthKey, "1024")
+	res.Header().Set(hdrRetryAfterKey, now.Add(2*time.Minute).Format(http.TimeFormat))
+	res.Header().Set(hdrContentTypeKey, "Application/JSON; charset=UTF-8")
+	res.Header().Set(hdrLastModifiedKey, "Wed, 21 Oct 2015 07:28:00 GMT")
+
+	n, ok := res.ContentLengthHeader()
+	assertEqual(t, true, ok)
+	assertEqual(t, int64(1024), n)
+
+	delay, ok := res.RetryAfter()
+	assertEqual(t, true, ok)
+	assertEqual(t, 2*time.Minute, delay)
+
+	mt, params := res.ContentType()
+	assertEqual(t, "application/json", mt)
+	assertEqual(t, map[string]string{"charset": "UTF-8"}, params)
+
+	// returned params are a copy
+	params["charset"] = "changed"
+	_, params = res.ContentType()
+	assertEqual(t, "UTF-8", params["charset"])
+
+	lm, ok := res.LastModified()
+	assertEqual(t, true, ok)
+	assertEqual(t, time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC), lm)
+
+	t.Run("header change is parsed again", func(t *testing.T) {
+		res.Header().Set(hdrContentTypeKey, "text/plain; charset")
+		mt, params := res.ContentType()
+		assertEqual(t, "text/plain", mt)
+		assertEqual(t, 0, len(params))
+
+		res.Header().Set(hdrRetryAfterKey, "120")
+		delay, ok := res.RetryAfter()
+		assertEqual(t, true, ok)
+		assertEqual(t, 120*time.Second, delay)
+	})
+
+	t.Run("missing or invalid", func(t *testing.T) {
+		res := &Response{}
+		_, ok := res.ContentLengthHeader()
+		assertEqual(t, false, ok)
+		_, ok = res.RetryAfter()
+		assertEqual(t, false, ok)
+		mt, params := res.ContentType()
+		assertEqual(t, "", mt)
+		assertNil(t, params)
+		_, ok = res.LastModified()
+		assertEqual(t, false, ok)
+
+		res = &Response{RawResponse: &http.Response{Header: http.Header{}}}
+		res.Header().Set(hdrContentLengthKey, "-1")
+		res.Header().Set(hdrLastModifiedKey, "yesterday")
+		_, ok = res.ContentLengthHeader()
+		assertEqual(t, false, ok)
+		_, ok = res.LastModified()
+		assertEqual(t, false, ok)
+	})
+}
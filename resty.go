@@ -17,6 +17,8 @@ import (
 	"time"
 
 	"golang.org/x/net/publicsuffix"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
 )
 
 // Version # of resty
@@ -55,10 +57,19 @@ func NewWithLocalAddr(localAddr net.Addr) *Client {
 // NewWithDialerAndTransportSettings method creates a new Resty client with given Local Address
 // to dial from.
 func NewWithDialerAndTransportSettings(dialer *net.Dialer, transportSettings *TransportSettings) *Client {
-	return createClient(&http.Client{
+	c := createClient(&http.Client{
 		Jar:       createCookieJar(),
 		Transport: createTransport(dialer, transportSettings),
 	})
+	c.SetAltSvcCache(NewAltSvcCache())
+	if transportSettings != nil && (transportSettings.FallbackDelay != 0 || transportSettings.ForceAttemptIPv4) {
+		p := DialPreference{FallbackDelay: transportSettings.FallbackDelay}
+		if transportSettings.ForceAttemptIPv4 {
+			p.Family = IPFamilyIPv4Only
+		}
+		c.SetDialPreference(p)
+	}
+	return c
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -88,9 +99,13 @@ func createTransport(dialer *net.Dialer, transportSettings *TransportSettings) *
 		dialer.KeepAlive = 30 * time.Second
 	}
 
+	if transportSettings.FallbackDelay != 0 {
+		dialer.FallbackDelay = transportSettings.FallbackDelay
+	}
+
 	// Transport
 	t := &http.Transport{
-		Proxy:              http.ProxyFromEnvironment,
+		Proxy:              wrapProxyFunc(http.ProxyFromEnvironment),
 		DialContext:        transportDialContext(dialer),
 		DisableKeepAlives:  transportSettings.DisableKeepAlives,
 		DisableCompression: true, // Resty handles it, see [Client.AddContentDecoder]
@@ -181,7 +196,21 @@ func createClient(hc *http.Client) *Client {
 		contentTypeDecoders:      make(map[string]ContentTypeDecoder),
 		contentDecompresserKeys:  make([]string, 0),
 		contentDecompressers:     make(map[string]ContentDecompresser),
-		certWatcherStopChan:      make(chan bool),
+		contentCompressers:       make(map[string]ContentCompresser),
+		charsetDecoders: map[string]CharsetDecoder{
+			// WHATWG Encoding Standard treats the iso-8859-1 as windows-1252
+			"iso-8859-1":   decodeWindows1252,
+			"windows-1252": decodeWindows1252,
+			"utf-16le":     decodeUTF16(false),
+			"utf-16be":     decodeUTF16(true),
+			"gbk":          decodeEncoding(simplifiedchinese.GBK),
+			"shift_jis":    decodeEncoding(japanese.ShiftJIS),
+		},
+		certWatcherStopChan: make(chan bool),
+		features:            make(map[Feature]struct{}),
+		stats:               &clientStats{},
+		requestIDHeader:     hdrRequestIDKey,
+		debugSampleRate:     1,
 	}
 
 	// Logger
@@ -190,14 +219,21 @@ func createClient(hc *http.Client) *Client {
 
 	c.AddContentTypeEncoder(jsonKey, encodeJSON)
 	c.AddContentTypeEncoder(xmlKey, encodeXML)
+	c.AddContentTypeEncoder(cborKey, encodeCBOR)
 
 	c.AddContentTypeDecoder(jsonKey, decodeJSON)
 	c.AddContentTypeDecoder(xmlKey, decodeXML)
+	c.AddContentTypeDecoder(cborKey, decodeCBOR)
 
 	// Order matter, giving priority to gzip
+	c.AddContentDecompresser("zstd", decompressZstd)
 	c.AddContentDecompresser("deflate", decompressDeflate)
 	c.AddContentDecompresser("gzip", decompressGzip)
 
+	c.AddContentCompresser("zstd", compressZstd)
+	c.AddContentCompresser("deflate", compressDeflate)
+	c.AddContentCompresser("gzip", compressGzip)
+
 	// request middlewares
 	c.SetRequestMiddlewares(
 		PrepareRequestMiddleware,
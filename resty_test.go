@@ -30,10 +30,8 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
-)
 
-var (
-	hdrLocationKey = http.CanonicalHeaderKey("Location")
+	"github.com/klauspost/compress/zstd"
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -634,6 +632,25 @@ func createGenericServer(t *testing.T) *httptest.Server {
 				w.Header().Set(hdrContentEncodingKey, "deflate")
 				// don't write body
 
+			// Zstd
+			case "/zstd-test":
+				w.Header().Set(hdrContentTypeKey, plainTextType)
+				w.Header().Set(hdrContentEncodingKey, "zstd")
+				zw, _ := zstd.NewWriter(w)
+				_, _ = zw.Write([]byte("This is Zstd response testing"))
+				zw.Close()
+			case "/zstd-test-empty-body":
+				w.Header().Set(hdrContentTypeKey, plainTextType)
+				w.Header().Set(hdrContentEncodingKey, "zstd")
+				zw, _ := zstd.NewWriter(w)
+				// write zstd empty body
+				_, _ = zw.Write([]byte(""))
+				zw.Close()
+			case "/zstd-test-no-body":
+				w.Header().Set(hdrContentTypeKey, plainTextType)
+				w.Header().Set(hdrContentEncodingKey, "zstd")
+				// don't write body
+
 			// LZW
 			case "/lzw-test":
 				w.Header().Set(hdrContentTypeKey, plainTextType)
@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+// Package restytest provides the fixture HTTP server for testing the Resty
+// based clients, i.e., echo, delay, status, redirect chains, and the
+// compressed response variants; so the downstream libraries do not have to
+// copy the fixture servers into their test suites.
+//
+//	ts := restytest.NewServer(t)
+//
+//	client := resty.New().SetBaseURL(ts.URL)
+//	defer client.Close()
+//
+//	res, err := client.R().Get("/redirect/3") // ends at /echo
+//
+// The server routes are:
+//
+//	ANY  /echo                    request method, URL, headers and body as JSON, see [EchoResponse]
+//	ANY  /status/{code}           responds with the given status code
+//	ANY  /delay/{duration}        responds after the given duration, e.g., 250ms, or milliseconds, e.g., 250
+//	GET  /redirect/{n}            redirect chain of n hops, ends at /echo
+//	GET  /redirect-to?url=&status= redirects to the given URL, default status is 302
+//	GET  /gzip                    gzip encoded body
+//	GET  /gzip/empty              gzip encoded empty body
+//	GET  /gzip/no-body            gzip Content-Encoding without the body
+//	GET  /deflate                 deflate encoded body, also /deflate/empty and /deflate/no-body
+//	GET  /lzw                     LZW (compress) encoded body, also /lzw/empty and /lzw/no-body
+//
+// The LZW variants need the decompresser registered on the client, see
+// the resty Client.AddContentDecompresser method.
+package restytest
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/lzw"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CompressedBody is the body content of the compressed response variants,
+// e.g., /gzip, /deflate, and /lzw.
+const CompressedBody = "This is compressed response testing"
+
+// EchoResponse type is the JSON body of the /echo route.
+type EchoResponse struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Path   string      `json:"path"`
+	Query  url.Values  `json:"query"`
+	Header http.Header `json:"header"`
+	Host   string      `json:"host"`
+	Body   string      `json:"body"`
+}
+
+// NewServer function starts the fixture server with [Handler] and closes it
+// on the test cleanup.
+func NewServer(tb testing.TB) *httptest.Server {
+	tb.Helper()
+	ts := httptest.NewServer(Handler())
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+// NewTLSServer function starts the fixture server with [Handler] over TLS and
+// closes it on the test cleanup. Use [httptest.Server.Client] or the server
+// certificate to trust it.
+func NewTLSServer(tb testing.TB) *httptest.Server {
+	tb.Helper()
+	ts := httptest.NewTLSServer(Handler())
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+// Handler function returns the [http.Handler] of the fixture server routes,
+// so it can be mounted on the user-defined server or mux.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", Echo)
+	mux.HandleFunc("/status/{code}", handleStatus)
+	mux.HandleFunc("/delay/{duration}", handleDelay)
+	mux.HandleFunc("GET /redirect/{n}", handleRedirect)
+	mux.HandleFunc("GET /redirect-to", handleRedirectTo)
+
+	mux.HandleFunc("GET /gzip", compressed("gzip", true, func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	}))
+	mux.HandleFunc("GET /gzip/empty", compressed("gzip", false, func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	}))
+	mux.HandleFunc("GET /gzip/no-body", compressed("gzip", false, nil))
+
+	mux.HandleFunc("GET /deflate", compressed("deflate", true, func(w io.Writer) io.WriteCloser {
+		zw, _ := flate.NewWriter(w, flate.BestSpeed)
+		return zw
+	}))
+	mux.HandleFunc("GET /deflate/empty", compressed("deflate", false, func(w io.Writer) io.WriteCloser {
+		zw, _ := flate.NewWriter(w, flate.BestSpeed)
+		return zw
+	}))
+	mux.HandleFunc("GET /deflate/no-body", compressed("deflate", false, nil))
+
+	mux.HandleFunc("GET /lzw", compressed("compress", true, func(w io.Writer) io.WriteCloser {
+		return lzw.NewWriter(w, lzw.LSB, 8)
+	}))
+	mux.HandleFunc("GET /lzw/empty", compressed("compress", false, func(w io.Writer) io.WriteCloser {
+		return lzw.NewWriter(w, lzw.LSB, 8)
+	}))
+	mux.HandleFunc("GET /lzw/no-body", compressed("compress", false, nil))
+	return mux
+}
+
+// Echo function is the [http.HandlerFunc] that writes the request as
+// [EchoResponse] JSON body.
+func Echo(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(&EchoResponse{
+		Method: r.Method,
+		URL:    r.URL.String(),
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Host:   r.Host,
+		Body:   string(body),
+	})
+}
+
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	code, err := strconv.Atoi(r.PathValue("code"))
+	if err != nil || code < 100 || code > 999 {
+		http.Error(w, "invalid status code", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(code)
+}
+
+func handleDelay(w http.ResponseWriter, r *http.Request) {
+	d, err := parseDelay(r.PathValue("duration"))
+	if err != nil {
+		http.Error(w, "invalid delay duration", http.StatusBadRequest)
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return
+	case <-timer.C:
+	}
+	Echo(w, r)
+}
+
+func handleRedirect(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 0 {
+		http.Error(w, "invalid redirect count", http.StatusBadRequest)
+		return
+	}
+	if n == 0 {
+		http.Redirect(w, r, "/echo", http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, "/redirect/"+strconv.Itoa(n-1), http.StatusFound)
+}
+
+func handleRedirectTo(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("url")
+	if target == "" {
+		http.Error(w, "missing url query parameter", http.StatusBadRequest)
+		return
+	}
+	status := http.StatusFound
+	if s := r.URL.Query().Get("status"); s != "" {
+		code, err := strconv.Atoi(s)
+		if err != nil || code < 300 || code > 399 {
+			http.Error(w, "invalid redirect status", http.StatusBadRequest)
+			return
+		}
+		status = code
+	}
+	http.Redirect(w, r, target, status)
+}
+
+// compressed returns the handler that writes the response with the given
+// Content-Encoding; the nil newWriter means no body is written at all.
+func compressed(encoding string, withBody bool, newWriter func(io.Writer) io.WriteCloser) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Encoding", encoding)
+		if newWriter == nil {
+			return
+		}
+		zw := newWriter(w)
+		if withBody {
+			_, _ = zw.Write([]byte(CompressedBody))
+		}
+		_ = zw.Close()
+	}
+}
+
+func parseDelay(s string) (time.Duration, error) {
+	if ms, err := strconv.Atoi(s); err == nil {
+		if ms < 0 {
+			return 0, strconv.ErrRange
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, strconv.ErrRange
+	}
+	return d, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package restytest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	resty "github.com/rockcookies/go-resty"
+	"github.com/rockcookies/go-resty/restytest"
+)
+
+func TestServerEcho(t *testing.T) {
+	ts := restytest.NewServer(t)
+	c := resty.New().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	var echo restytest.EchoResponse
+	res, err := c.R().
+		SetHeader("X-Custom", "value").
+		SetQueryParam("q", "1").
+		SetBody("hello").
+		SetResult(&echo).
+		Post("/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode())
+	}
+	if echo.Method != http.MethodPost || echo.Path != "/echo" || echo.Body != "hello" {
+		t.Errorf("unexpected echo: %+v", echo)
+	}
+	if echo.Query.Get("q") != "1" || echo.Header.Get("X-Custom") != "value" {
+		t.Errorf("unexpected echo query or header: %+v", echo)
+	}
+}
+
+func TestServerStatusAndRedirect(t *testing.T) {
+	ts := restytest.NewServer(t)
+	c := resty.New().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	res, err := c.R().Get("/status/418")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusTeapot {
+		t.Errorf("expected 418, got %d", res.StatusCode())
+	}
+
+	var echo restytest.EchoResponse
+	res, err = c.R().SetResult(&echo).Get("/redirect/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusOK || echo.Path != "/echo" {
+		t.Errorf("expected redirect chain to end at /echo, got %d %s", res.StatusCode(), echo.Path)
+	}
+
+	c.SetRedirectPolicy(resty.FlexibleRedirectPolicy(2))
+	_, err = c.R().Get("/redirect/3")
+	if err == nil {
+		t.Error("expected redirect policy error")
+	}
+
+	res, err = resty.New().SetRedirectPolicy(resty.NoRedirectPolicy()).
+		R().Get(ts.URL + "/redirect-to?url=/echo&status=307")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusTemporaryRedirect || res.Header().Get("Location") != "/echo" {
+		t.Errorf("expected 307 to /echo, got %d %s", res.StatusCode(), res.Header().Get("Location"))
+	}
+}
+
+func TestServerDelay(t *testing.T) {
+	ts := restytest.NewServer(t)
+	c := resty.New().SetBaseURL(ts.URL)
+	defer c.Close()
+
+	start := time.Now()
+	res, err := c.R().Get("/delay/50ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusOK || time.Since(start) < 50*time.Millisecond {
+		t.Errorf("expected delayed response, got %d after %v", res.StatusCode(), time.Since(start))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = c.R().SetContext(ctx).Get("/delay/1000")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	res, err = c.R().Get("/delay/forever")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", res.StatusCode())
+	}
+}
+
+func TestServerCompressed(t *testing.T) {
+	ts := restytest.NewTLSServer(t)
+	c := resty.New().SetBaseURL(ts.URL).SetTransport(ts.Client().Transport)
+	defer c.Close()
+
+	// LZW is not the built-in decompresser, see resty.Client.AddContentDecompresser
+	for _, p := range []string{"/gzip", "/deflate"} {
+		t.Run(p, func(t *testing.T) {
+			res, err := c.R().Get(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.String() != restytest.CompressedBody {
+				t.Errorf("expected %q, got %q", restytest.CompressedBody, res.String())
+			}
+
+			for _, v := range []string{"/empty", "/no-body"} {
+				res, err := c.R().Get(p + v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.String() != "" {
+					t.Errorf("expected empty body for %s%s, got %q", p, v, res.String())
+				}
+			}
+		})
+	}
+}
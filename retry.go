@@ -8,7 +8,7 @@ package resty
 import (
 	"crypto/tls"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -88,22 +88,33 @@ func newBackoffWithJitter(min, max time.Duration) *backoffWithJitter {
 
 	return &backoffWithJitter{
 		lock: new(sync.Mutex),
-		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		rnd:  rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
 		min:  min,
 		max:  max,
 	}
 }
 
+// newBackoffWithJitter method returns the backoff with the jitter from the
+// client random source if it is set, see [Client.SetRandSource].
+func (c *Client) newBackoffWithJitter(min, max time.Duration) *backoffWithJitter {
+	b := newBackoffWithJitter(min, max)
+	if rnd := c.randSource(); rnd != nil {
+		b.rnd = rnd
+	}
+	return b
+}
+
 type backoffWithJitter struct {
 	lock *sync.Mutex
-	rnd  *rand.Rand
+	rnd  interface{ Int64N(int64) int64 }
 	min  time.Duration
 	max  time.Duration
 }
 
 func (b *backoffWithJitter) NextWaitDuration(c *Client, res *Response, err error, attempt int) (time.Duration, error) {
 	if res != nil {
-		if res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() == http.StatusServiceUnavailable {
+		if res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() == http.StatusServiceUnavailable ||
+			(c != nil && c.IsFeatureEnabled(FeatureAdaptiveRetry)) {
 			if delay, ok := parseRetryAfterHeader(res.Header().Get(hdrRetryAfterKey)); ok {
 				return delay, nil
 			}
@@ -146,7 +157,7 @@ func (b *backoffWithJitter) randDuration(center time.Duration) time.Duration {
 	defer b.lock.Unlock()
 
 	var ri = int64(center)
-	var jitter = b.rnd.Int63n(ri)
+	var jitter = b.rnd.Int64N(ri)
 	return time.Duration(math.Abs(float64(ri + jitter)))
 }
 
@@ -1022,3 +1022,26 @@ func testStaticTime(t *testing.T) {
 		timeNow = time.Now
 	})
 }
+
+func TestClientRetryAdaptiveRetryAfter(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrRetryAfterKey, "1")
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	backoff := newBackoffWithJitter(10*time.Millisecond, 20*time.Millisecond)
+	res, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	assertEqual(t, http.StatusBadGateway, res.StatusCode())
+
+	wait, err := backoff.NextWaitDuration(c, res, nil, 1)
+	assertNil(t, err)
+	assertEqual(t, true, wait <= 20*time.Millisecond)
+
+	c.EnableFeature(FeatureAdaptiveRetry)
+	wait, err = backoff.NextWaitDuration(c, res, nil, 1)
+	assertNil(t, err)
+	assertEqual(t, time.Second, wait)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrSnapshotMismatch is returned by [RequestSnapshot.Compare] when the
+// snapshot of the request does not match the golden file.
+var ErrSnapshotMismatch = errors.New("resty: request snapshot does not match the golden file")
+
+// RequestSnapshot type serializes the prepared [http.Request] into the canonical
+// text form, i.e., the method and URL with the sorted query parameters, the
+// sorted headers, and the body; so the client wrappers can be tested against the
+// golden files. The volatile values are redacted, see [RequestSnapshot.SetRedaction].
+//
+//	req, _ := client.R().SetBody(user).SetMethod(resty.MethodPost).SetURL("/users").DryRun()
+//	snapshot := resty.NewRequestSnapshot().
+//		SetUpdate(os.Getenv("UPDATE_GOLDEN") != "")
+//	snapshot.AssertGolden(t, "testdata/create-user.golden", req)
+//
+// The canonical form is:
+//
+//	POST https://api.example.com/users?a=1&b=2
+//	Content-Type: application/json
+//	X-Request-Id: [REDACTED]
+//
+//	{
+//	  "name": "resty"
+//	}
+type RequestSnapshot struct {
+	lock      *sync.RWMutex
+	redaction *DebugLogRedaction
+	update    bool
+}
+
+// NewRequestSnapshot function creates the [RequestSnapshot], the default
+// redaction is [NewDebugLogRedaction] with the volatile headers, i.e.,
+// Date, X-Request-ID, Idempotency-Key, and Traceparent.
+func NewRequestSnapshot() *RequestSnapshot {
+	return &RequestSnapshot{
+		lock: new(sync.RWMutex),
+		redaction: NewDebugLogRedaction().
+			AddHeaders("Date", hdrRequestIDKey, "Idempotency-Key", "Traceparent"),
+	}
+}
+
+// SetRedaction method sets the rules to redact the sensitive and volatile
+// values, i.e., the headers, the query parameters, and the body patterns.
+//
+//	snapshot.SetRedaction(resty.NewDebugLogRedaction().
+//		AddHeaders("Date", "Idempotency-Key").
+//		AddBodyPatterns(regexp.MustCompile(`"nonce":"([^"]+)"`)))
+//
+// NOTE: The nil value disables the redaction.
+func (s *RequestSnapshot) SetRedaction(dr *DebugLogRedaction) *RequestSnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.redaction = dr
+	return s
+}
+
+// SetUpdate method sets whether [RequestSnapshot.AssertGolden] writes the
+// snapshot to the golden file instead of comparing it, it is used to create
+// or refresh the golden files.
+//
+//	snapshot.SetUpdate(os.Getenv("UPDATE_GOLDEN") != "")
+func (s *RequestSnapshot) SetUpdate(update bool) *RequestSnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.update = update
+	return s
+}
+
+// Snapshot method returns the canonical text form of the given request.
+// The request body is read into memory and restored, so the request can be sent.
+//
+// NOTE: The JSON body is indented, and the non-UTF-8 body is base64 encoded.
+func (s *RequestSnapshot) Snapshot(req *http.Request) (string, error) {
+	body, err := bufferRequestBody(req)
+	if err != nil {
+		return "", err
+	}
+
+	s.lock.RLock()
+	dr := s.redaction
+	s.lock.RUnlock()
+
+	u := *req.URL
+	u.RawQuery = u.Query().Encode()
+	uri := u.String()
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if req.Host != "" && req.Host != req.URL.Host {
+		header.Set("Host", req.Host)
+	}
+	bodyText, encoding := encodeCassetteBody(body)
+	if dr != nil {
+		for _, name := range dr.Headers {
+			dr.redactHeader(header, name)
+		}
+		uri = dr.redactQueryParams(uri)
+		if encoding == "" {
+			bodyText = dr.redactBody(bodyText)
+		}
+	}
+	if encoding == "" && isJSONContentType(header.Get(hdrContentTypeKey)) {
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+		if json.Indent(buf, []byte(bodyText), "", "  ") == nil {
+			bodyText = buf.String()
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(req.Method + " " + uri + "\n")
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		for _, v := range header[key] {
+			sb.WriteString(key + ": " + v + "\n")
+		}
+	}
+	if len(body) > 0 {
+		sb.WriteString("\n")
+		if encoding != "" {
+			sb.WriteString(encoding + ":")
+		}
+		sb.WriteString(bodyText)
+		if !strings.HasSuffix(bodyText, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// Compare method compares the snapshot of the given request with the golden
+// file; it returns [ErrSnapshotMismatch] with the first difference if they are
+// not equal. If the update is enabled, the golden file is written instead, see
+// [RequestSnapshot.SetUpdate].
+func (s *RequestSnapshot) Compare(path string, req *http.Request) error {
+	snapshot, err := s.Snapshot(req)
+	if err != nil {
+		return err
+	}
+
+	s.lock.RLock()
+	update := s.update
+	s.lock.RUnlock()
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(snapshot), 0o644)
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if diff := snapshotDiff(string(golden), snapshot); diff != "" {
+		return fmt.Errorf("%w %s: %s", ErrSnapshotMismatch, path, diff)
+	}
+	return nil
+}
+
+// AssertGolden method reports the error to t, if the snapshot of the given
+// request does not match the golden file, see [RequestSnapshot.Compare].
+func (s *RequestSnapshot) AssertGolden(t MockT, path string, req *http.Request) bool {
+	t.Helper()
+	if err := s.Compare(path, req); err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	return true
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// snapshotDiff function returns the first differing line of the expected
+// and actual snapshots, or the empty string if they are equal.
+func snapshotDiff(expected, actual string) string {
+	expected = strings.ReplaceAll(expected, "\r\n", "\n")
+	if expected == actual {
+		return ""
+	}
+	el, al := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	for i := 0; i < max(len(el), len(al)); i++ {
+		var e, a string
+		if i < len(el) {
+			e = el[i]
+		}
+		if i < len(al) {
+			a = al[i]
+		}
+		if e != a {
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, e, a)
+		}
+	}
+	return ""
+}
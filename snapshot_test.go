@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestSnapshot(t *testing.T) {
+	c := dcnl().
+		SetBaseURL("https://api.example.com").
+		AddRequestMiddleware(RequestIDMiddleware)
+	defer c.Close()
+
+	req, err := c.R().
+		SetMethod(MethodPost).
+		SetURL("/users").
+		SetQueryParam("z", "last").
+		SetQueryParam("api_key", "secret").
+		SetQueryParam("a", "first").
+		SetHeader("Date", "Sat, 17 Oct 2026 10:00:00 GMT").
+		SetHeader("Idempotency-Key", "0b4c0f5c").
+		SetAuthToken("token").
+		SetBody(map[string]any{"name": "resty", "nonce": "n-123"}).
+		DryRun()
+	assertNil(t, err)
+
+	snapshot := NewRequestSnapshot().SetRedaction(NewDebugLogRedaction().
+		AddHeaders("Date", hdrRequestIDKey, "Idempotency-Key").
+		AddQueryParams("api_key").
+		AddBodyPatterns(regexp.MustCompile(`"nonce":"([^"]+)"`)))
+	result, err := snapshot.Snapshot(req)
+	assertNil(t, err)
+	assertEqual(t, `POST https://api.example.com/users?a=first&api_key=%5BREDACTED%5D&z=last
+Accept-Encoding: gzip, deflate, zstd
+Authorization: [REDACTED]
+Content-Type: application/json
+Date: [REDACTED]
+Idempotency-Key: [REDACTED]
+User-Agent: `+hdrUserAgentValue+`
+X-Request-Id: [REDACTED]
+
+{
+  "name": "resty",
+  "nonce": "[REDACTED]"
+}
+`, result)
+
+	// the request body is restored
+	body, _ := io.ReadAll(req.Body)
+	assertEqual(t, `{"name":"resty","nonce":"n-123"}`, strings.TrimSpace(string(body)))
+
+	t.Run("golden file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "testdata", "create-user.golden")
+
+		assertErrorIs(t, os.ErrNotExist, snapshot.Compare(path, req))
+
+		snapshot.SetUpdate(true)
+		assertNil(t, snapshot.Compare(path, req))
+		snapshot.SetUpdate(false)
+		assertEqual(t, true, snapshot.AssertGolden(t, path, req))
+
+		req.Header.Set("X-Tenant", "acme")
+		mockT := &testMockT{}
+		assertEqual(t, false, snapshot.AssertGolden(mockT, path, req))
+		assertEqual(t, 1, len(mockT.errors))
+		assertEqual(t, true, strings.HasSuffix(mockT.errors[0],
+			`line 9: expected "", got "X-Tenant: acme"`))
+		assertErrorIs(t, ErrSnapshotMismatch, snapshot.Compare(path, req))
+	})
+
+	t.Run("binary body", func(t *testing.T) {
+		req, err := c.R().
+			SetURL("/upload").
+			SetMethod(MethodPut).
+			SetHeader(hdrContentTypeKey, "application/octet-stream").
+			SetHeader("Host", "upload.example.com").
+			SetBody([]byte{0xff, 0xfe}).
+			DryRun()
+		assertNil(t, err)
+		result, err := NewRequestSnapshot().SetRedaction(nil).Snapshot(req)
+		assertNil(t, err)
+		assertEqual(t, true, strings.Contains(result, "Host: upload.example.com\n"))
+		assertEqual(t, true, strings.HasSuffix(result, "\n\nbase64://4=\n"))
+	})
+}
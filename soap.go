@@ -0,0 +1,334 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SOAPVersion type is the SOAP protocol version, see [SOAP].
+type SOAPVersion int
+
+// The supported SOAP protocol versions.
+const (
+	SOAP11 SOAPVersion = iota
+	SOAP12
+)
+
+const (
+	soap11EnvelopeNS  = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12EnvelopeNS  = "http://www.w3.org/2003/05/soap-envelope"
+	soap11ContentType = "text/xml; charset=utf-8"
+	soap12ContentType = "application/soap+xml; charset=utf-8"
+
+	wsseNS           = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
+	wsuNS            = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	wssePasswordText = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordText"
+	wssePasswordDig  = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest"
+	wsseBase64Binary = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary"
+)
+
+var hdrSOAPActionKey = http.CanonicalHeaderKey("SOAPAction")
+
+// SOAP struct is the SOAP envelope details of the request, see [Request.SetSOAP].
+type SOAP struct {
+	// Version is the SOAP protocol version. Default is [SOAP11].
+	Version SOAPVersion
+
+	// Action is the SOAP action; it is sent as the `SOAPAction` header for
+	// SOAP 1.1, and the `action` parameter of the Content-Type for SOAP 1.2.
+	Action string
+
+	// Namespaces are declared on the envelope element as `xmlns:prefix="uri"`,
+	// so the prefixed names can be used in the header and body.
+	Namespaces map[string]string
+
+	// Header is marshalled into the envelope header, it can be struct or
+	// raw XML, i.e., string or []byte.
+	Header any
+
+	// Body is marshalled into the envelope body, it can be struct or
+	// raw XML, i.e., string or []byte.
+	Body any
+
+	// Security adds the WS-Security UsernameToken into the envelope header.
+	Security *SOAPUsernameToken
+
+	// FaultDetail is the type of the fault detail, i.e., [SOAPFault.Detail];
+	// the raw XML is available regardless, see [SOAPFault.RawDetail].
+	FaultDetail any
+}
+
+// SOAPUsernameToken struct is the WS-Security UsernameToken credentials,
+// see [SOAP].
+type SOAPUsernameToken struct {
+	Username string
+	Password string
+
+	// PasswordDigest sends the password digest along with the nonce and the
+	// created timestamp instead of the plain text password.
+	PasswordDigest bool
+}
+
+// SOAPFault is the error of the SOAP fault response, for both SOAP 1.1 and 1.2.
+//
+//	var fault *resty.SOAPFault
+//	if errors.As(err, &fault) {
+//		fmt.Println(fault.Code, fault.Reason, fault.Detail.(*ServiceError).Code)
+//	}
+type SOAPFault struct {
+	// Code is the `faultcode` (1.1) or `Code/Value` (1.2)
+	Code string
+
+	// Reason is the `faultstring` (1.1) or `Reason/Text` (1.2)
+	Reason string
+
+	// Actor is the `faultactor` (1.1) or `Role` (1.2)
+	Actor string
+
+	// Detail is the decoded fault detail, if [SOAP].FaultDetail is set
+	Detail any
+
+	// RawDetail is the raw XML of the fault detail
+	RawDetail string
+}
+
+func (f *SOAPFault) Error() string {
+	return "resty: soap fault: " + f.Code + ": " + f.Reason
+}
+
+func (f *SOAPFault) Unwrap() error {
+	if err, ok := f.Detail.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// SetSOAP method wraps the given body into the SOAP envelope and sets the
+// request headers as per the SOAP version. The response envelope is unwrapped,
+// i.e., the body content is decoded into [Request.SetResult], and the SOAP
+// fault is returned as [SOAPFault] error.
+//
+//	res, err := client.R().
+//		SetSOAP(&resty.SOAP{
+//			Action:     "http://tempuri.org/GetUser",
+//			Namespaces: map[string]string{"tns": "http://tempuri.org/"},
+//			Body:       &GetUser{ID: 1001},
+//			Security: &resty.SOAPUsernameToken{
+//				Username: "user",
+//				Password: "secret",
+//			},
+//			FaultDetail: &ServiceError{},
+//		}).
+//		SetResult(&GetUserResponse{}).
+//		Post("https://example.com/UserService.svc")
+//
+// NOTE: The envelope is created on every attempt, so the WS-Security nonce and
+// timestamp are fresh on the retries.
+func (r *Request) SetSOAP(s *SOAP) *Request {
+	r.soap = s
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+var soapNow = time.Now
+
+func handleSOAP(c *Client, r *Request) error {
+	s := r.soap
+	buf := acquireBuffer()
+	if err := s.writeEnvelope(buf); err != nil {
+		releaseBuffer(buf)
+		return err
+	}
+	r.bodyBuf = buf
+
+	if r.isHeaderExists(hdrContentTypeKey) {
+		return nil
+	}
+	if s.Version == SOAP12 {
+		ct := soap12ContentType
+		if len(s.Action) > 0 {
+			ct += "; action=" + strconv.Quote(s.Action)
+		}
+		r.Header.Set(hdrContentTypeKey, ct)
+	} else {
+		r.Header.Set(hdrContentTypeKey, soap11ContentType)
+		r.Header.Set(hdrSOAPActionKey, strconv.Quote(s.Action))
+	}
+	return nil
+}
+
+func (s *SOAP) writeEnvelope(buf *bytes.Buffer) error {
+	ns := soap11EnvelopeNS
+	if s.Version == SOAP12 {
+		ns = soap12EnvelopeNS
+	}
+
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<soap:Envelope xmlns:soap="` + ns + `"`)
+	prefixes := make([]string, 0, len(s.Namespaces))
+	for p := range s.Namespaces {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		buf.WriteString(` xmlns:` + p + `="`)
+		_ = xml.EscapeText(buf, []byte(s.Namespaces[p]))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(`>`)
+
+	if s.Header != nil || s.Security != nil {
+		buf.WriteString(`<soap:Header>`)
+		if s.Security != nil {
+			if err := s.Security.write(buf); err != nil {
+				return err
+			}
+		}
+		if err := writeSOAPContent(buf, s.Header); err != nil {
+			return err
+		}
+		buf.WriteString(`</soap:Header>`)
+	}
+
+	buf.WriteString(`<soap:Body>`)
+	if err := writeSOAPContent(buf, s.Body); err != nil {
+		return err
+	}
+	buf.WriteString(`</soap:Body></soap:Envelope>`)
+	return nil
+}
+
+func writeSOAPContent(buf *bytes.Buffer, v any) error {
+	switch c := v.(type) {
+	case nil:
+		return nil
+	case string:
+		buf.WriteString(c)
+		return nil
+	case []byte:
+		buf.Write(c)
+		return nil
+	}
+	return xml.NewEncoder(buf).Encode(v)
+}
+
+func (t *SOAPUsernameToken) write(buf *bytes.Buffer) error {
+	buf.WriteString(`<wsse:Security xmlns:wsse="` + wsseNS + `" xmlns:wsu="` + wsuNS +
+		`" soap:mustUnderstand="1"><wsse:UsernameToken><wsse:Username>`)
+	_ = xml.EscapeText(buf, []byte(t.Username))
+	buf.WriteString(`</wsse:Username>`)
+
+	if !t.PasswordDigest {
+		buf.WriteString(`<wsse:Password Type="` + wssePasswordText + `">`)
+		_ = xml.EscapeText(buf, []byte(t.Password))
+		buf.WriteString(`</wsse:Password></wsse:UsernameToken></wsse:Security>`)
+		return nil
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	created := soapNow().UTC().Format("2006-01-02T15:04:05.000Z")
+	buf.WriteString(`<wsse:Password Type="` + wssePasswordDig + `">` +
+		soapPasswordDigest(nonce, created, t.Password) + `</wsse:Password>` +
+		`<wsse:Nonce EncodingType="` + wsseBase64Binary + `">` +
+		base64.StdEncoding.EncodeToString(nonce) + `</wsse:Nonce>` +
+		`<wsu:Created>` + created + `</wsu:Created></wsse:UsernameToken></wsse:Security>`)
+	return nil
+}
+
+// soapPasswordDigest function returns Base64(SHA-1(nonce + created + password))
+func soapPasswordDigest(nonce []byte, created, password string) string {
+	h := sha1.New()
+	h.Write(nonce)
+	h.Write([]byte(created))
+	h.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+type soapInnerXML struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+type soapEnvelope struct {
+	Body struct {
+		Fault *soapFaultXML `xml:"Fault"`
+		Inner []byte        `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+type soapFaultXML struct {
+	// SOAP 1.1
+	FaultCode   string       `xml:"faultcode"`
+	FaultString string       `xml:"faultstring"`
+	FaultActor  string       `xml:"faultactor"`
+	Detail11    soapInnerXML `xml:"detail"`
+
+	// SOAP 1.2
+	Code struct {
+		Value string `xml:"Value"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+	Role     string       `xml:"Role"`
+	Detail12 soapInnerXML `xml:"Detail"`
+}
+
+// parseSOAPEnvelope method unwraps the response envelope into the result,
+// or the fault into the [SOAPFault] error.
+func (r *Response) parseSOAPEnvelope() (err error) {
+	defer closeq(r.Body)
+	env := &soapEnvelope{}
+	err = xml.NewDecoder(r.Body).Decode(env)
+	err = r.drainIfRequired(err)
+	r.IsRead = true
+	if errors.Is(err, io.EOF) {
+		// no envelope, e.g., one-way operation
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if f := env.Body.Fault; f != nil {
+		fault := &SOAPFault{
+			Code:      firstNonEmpty(f.FaultCode, f.Code.Value),
+			Reason:    firstNonEmpty(f.FaultString, f.Reason.Text),
+			Actor:     firstNonEmpty(f.FaultActor, f.Role),
+			RawDetail: string(bytes.TrimSpace(append(f.Detail11.Inner, f.Detail12.Inner...))),
+		}
+		if fd := r.Request.soap.FaultDetail; fd != nil && len(fault.RawDetail) > 0 {
+			detail := newInterface(fd)
+			if err := xml.Unmarshal([]byte(fault.RawDetail), detail); err != nil {
+				return err
+			}
+			fault.Detail = detail
+		}
+		r.Request.Error = fault
+		return fault
+	}
+
+	if r.IsSuccess() && r.Request.Result != nil && len(bytes.TrimSpace(env.Body.Inner)) > 0 {
+		return xml.Unmarshal(env.Body.Inner, r.Request.Result)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type soapTestGetUser struct {
+	XMLName xml.Name `xml:"tns:GetUser"`
+	ID      int      `xml:"tns:ID"`
+}
+
+type soapTestGetUserResponse struct {
+	XMLName xml.Name `xml:"GetUserResponse"`
+	Name    string   `xml:"Name"`
+}
+
+type soapTestServiceError struct {
+	ErrorCode string `xml:"ErrorCode"`
+}
+
+func (e *soapTestServiceError) Error() string {
+	return "service error " + e.ErrorCode
+}
+
+const (
+	soapTestFault11 = `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+		`<s:Fault><faultcode>s:Client</faultcode><faultstring>user not found</faultstring><faultactor>urn:users</faultactor>` +
+		`<detail><ServiceError><ErrorCode>E404</ErrorCode></ServiceError></detail></s:Fault></s:Body></s:Envelope>`
+	soapTestFault12 = `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>` +
+		`<env:Code><env:Value>env:Sender</env:Value></env:Code><env:Reason><env:Text xml:lang="en">invalid request</env:Text></env:Reason>` +
+		`</env:Fault></env:Body></env:Envelope>`
+)
+
+func createSOAPTestServer(t *testing.T) (string, *http.Request, *string) {
+	var (
+		lastReq  = &http.Request{}
+		lastBody = new(string)
+	)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*lastReq = *r
+		*lastBody = string(b)
+		switch r.URL.Path {
+		case "/fault11":
+			w.Header().Set(hdrContentTypeKey, soap11ContentType)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, soapTestFault11)
+		case "/fault12":
+			w.Header().Set(hdrContentTypeKey, soap12ContentType)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, soapTestFault12)
+		case "/one-way":
+			w.Header().Set(hdrContentTypeKey, soap11ContentType)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.Header().Set(hdrContentTypeKey, soap11ContentType)
+			_, _ = io.WriteString(w, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`+
+				`<GetUserResponse xmlns="http://tempuri.org/"><Name>resty</Name></GetUserResponse></soap:Body></soap:Envelope>`)
+		}
+	})
+	t.Cleanup(ts.Close)
+	return ts.URL, lastReq, lastBody
+}
+
+func TestSOAP11(t *testing.T) {
+	baseURL, lastReq, lastBody := createSOAPTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	res, err := c.R().
+		SetSOAP(&SOAP{
+			Action:     "http://tempuri.org/GetUser",
+			Namespaces: map[string]string{"tns": "http://tempuri.org/"},
+			Header:     `<tns:Trace>abc</tns:Trace>`,
+			Body:       &soapTestGetUser{ID: 1001},
+			Security:   &SOAPUsernameToken{Username: "user", Password: "s<cret"},
+		}).
+		SetResult(&soapTestGetUserResponse{}).
+		Post("/users")
+	assertNil(t, err)
+	assertEqual(t, "resty", res.Result().(*soapTestGetUserResponse).Name)
+
+	assertEqual(t, soap11ContentType, lastReq.Header.Get(hdrContentTypeKey))
+	assertEqual(t, `"http://tempuri.org/GetUser"`, lastReq.Header.Get(hdrSOAPActionKey))
+	assertEqual(t, true, strings.HasPrefix(*lastBody, xml.Header+
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:tns="http://tempuri.org/"><soap:Header>`))
+	assertEqual(t, true, strings.Contains(*lastBody, `<wsse:Username>user</wsse:Username><wsse:Password Type="`+
+		wssePasswordText+`">s&lt;cret</wsse:Password>`))
+	assertEqual(t, true, strings.HasSuffix(*lastBody, `<tns:Trace>abc</tns:Trace></soap:Header>`+
+		`<soap:Body><tns:GetUser><tns:ID>1001</tns:ID></tns:GetUser></soap:Body></soap:Envelope>`))
+
+	// one-way operation, no envelope
+	res, err = c.R().SetSOAP(&SOAP{Body: "<Ping/>"}).SetResult(&soapTestGetUserResponse{}).Post("/one-way")
+	assertNil(t, err)
+	assertEqual(t, http.StatusAccepted, res.StatusCode())
+	assertEqual(t, `""`, lastReq.Header.Get(hdrSOAPActionKey))
+}
+
+func TestSOAP12(t *testing.T) {
+	baseURL, lastReq, lastBody := createSOAPTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	_, err := c.R().
+		SetSOAP(&SOAP{Version: SOAP12, Action: "urn:GetUser", Body: []byte("<GetUser/>")}).
+		Post("/users")
+	assertNil(t, err)
+	assertEqual(t, soap12ContentType+`; action="urn:GetUser"`, lastReq.Header.Get(hdrContentTypeKey))
+	assertEqual(t, "", lastReq.Header.Get(hdrSOAPActionKey))
+	assertEqual(t, xml.Header+`<soap:Envelope xmlns:soap="`+soap12EnvelopeNS+`"><soap:Body><GetUser/></soap:Body></soap:Envelope>`, *lastBody)
+
+	_, err = c.R().SetSOAP(&SOAP{Version: SOAP12}).Post("/fault12")
+	var fault *SOAPFault
+	assertEqual(t, true, errors.As(err, &fault))
+	assertEqual(t, "env:Sender", fault.Code)
+	assertEqual(t, "invalid request", fault.Reason)
+	assertEqual(t, "resty: soap fault: env:Sender: invalid request", fault.Error())
+	assertNil(t, fault.Detail)
+	assertNil(t, fault.Unwrap())
+}
+
+func TestSOAPFault(t *testing.T) {
+	baseURL, _, _ := createSOAPTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	res, err := c.R().
+		SetSOAP(&SOAP{Body: "<GetUser/>", FaultDetail: soapTestServiceError{}}).
+		SetResult(&soapTestGetUserResponse{}).
+		Post("/fault11")
+	var fault *SOAPFault
+	assertEqual(t, true, errors.As(err, &fault))
+	assertEqual(t, "s:Client", fault.Code)
+	assertEqual(t, "user not found", fault.Reason)
+	assertEqual(t, "urn:users", fault.Actor)
+	assertEqual(t, "<ServiceError><ErrorCode>E404</ErrorCode></ServiceError>", fault.RawDetail)
+	assertEqual(t, fault, res.Error())
+
+	var svcErr *soapTestServiceError
+	assertEqual(t, true, errors.As(err, &svcErr))
+	assertEqual(t, "E404", svcErr.ErrorCode)
+	assertEqual(t, "", res.Result().(*soapTestGetUserResponse).Name)
+
+	// invalid body type
+	_, err = c.R().SetSOAP(&SOAP{Body: make(chan int)}).Post("/users")
+	assertNotNil(t, err)
+}
+
+func TestSOAPPasswordDigest(t *testing.T) {
+	defer func() { soapNow = time.Now }()
+	soapNow = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	s := &SOAP{Security: &SOAPUsernameToken{Username: "user", Password: "secret", PasswordDigest: true}}
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	assertNil(t, s.writeEnvelope(buf))
+
+	m := regexp.MustCompile(`<wsse:Password Type="[^"]+#PasswordDigest">([^<]+)</wsse:Password>` +
+		`<wsse:Nonce EncodingType="[^"]+">([^<]+)</wsse:Nonce><wsu:Created>([^<]+)</wsu:Created>`).
+		FindStringSubmatch(buf.String())
+	assertEqual(t, 4, len(m))
+	assertEqual(t, "2025-01-02T03:04:05.000Z", m[3])
+
+	nonce, err := base64.StdEncoding.DecodeString(m[2])
+	assertNil(t, err)
+	assertEqual(t, 16, len(nonce))
+	assertEqual(t, soapPasswordDigest(nonce, m[3], "secret"), m[1])
+}
@@ -8,6 +8,7 @@ package resty
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -72,6 +73,7 @@ type (
 		method           string
 		header           http.Header
 		body             io.Reader
+		bodyBytes        []byte
 		lastEventID      string
 		retryCount       int
 		retryWaitTime    time.Duration
@@ -83,13 +85,17 @@ type (
 		onRequestFailure EventRequestFailureFunc
 		onEvent          map[string]*callback
 		log              Logger
+		ctx              context.Context
 		closed           bool
+		closeChan        chan struct{}
+		autoReconnect    bool
 		httpClient       *http.Client
 	}
 
 	callback struct {
 		Func   EventMessageFunc
 		Result any
+		Typed  func(*Event) error
 	}
 )
 
@@ -119,6 +125,7 @@ func NewEventSource() *EventSource {
 		retryMaxWaitTime: defaultMaxWaitTime,
 		maxBufSize:       defaultSseMaxBufSize,
 		onEvent:          make(map[string]*callback),
+		log:              createLogger(),
 		httpClient: &http.Client{
 			Jar:       createCookieJar(),
 			Transport: createTransport(nil, nil),
@@ -283,6 +290,42 @@ func (es *EventSource) SetRetryMaxWaitTime(maxWaitTime time.Duration) *EventSour
 	return es
 }
 
+// SetAutoReconnect method enables the automatic reconnection when the stream
+// ends, e.g., the server closes the connection or a network error. Resty
+// reconnects with the `Last-Event-ID` header of the last received event, and
+// waits between the reconnections using the exponential backoff with jitter,
+// starting from the server-sent `retry` value, otherwise [EventSource.SetRetryWaitTime],
+// up to [EventSource.SetRetryMaxWaitTime]. By default, it is disabled.
+//
+//	es.SetAutoReconnect(true)
+//
+// NOTE:
+//   - The reconnection stops on [EventSource.Close], the context cancellation,
+//     see [EventSource.SetContext], or when the server responds with the HTTP
+//     status code 204 No Content, as per the specification.
+//   - The request body is sent again on every reconnection.
+func (es *EventSource) SetAutoReconnect(b bool) *EventSource {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	es.autoReconnect = b
+	return es
+}
+
+// SetContext method sets the context of the event source; the connection
+// requests, the stream, and the reconnection wait are canceled on the
+// context cancellation, and the [EventSource.Get] returns the context error.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//
+//	es.SetContext(ctx)
+func (es *EventSource) SetContext(ctx context.Context) *EventSource {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	es.ctx = ctx
+	return es
+}
+
 // SetMaxBufSize method sets the given buffer size into the SSE client
 //
 // Default is 32kb
@@ -438,6 +481,39 @@ func (es *EventSource) AddEventListener(eventName string, ef EventMessageFunc, r
 	return es
 }
 
+// AddTypedEventListener function registers a callback to consume a specific
+// event type messages from the server, the event data is decoded from JSON into
+// the type T, and the callback receives the event details as well.
+//
+//	resty.AddTypedEventListener(es, "completion",
+//		func(e *resty.Event, c *Completion) {
+//			fmt.Println(e.ID, c.Text)
+//		},
+//	)
+//
+// The decoding error is notified via [EventSource.OnError].
+//
+// See [EventSource.AddEventListener]
+func AddTypedEventListener[T any](es *EventSource, eventName string, fn func(*Event, *T)) *EventSource {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if e, found := es.onEvent[eventName]; found {
+		es.log.Warnf("Overwriting an existing OnEvent callback from=%s to=%s",
+			functionName(e), functionName(fn))
+	}
+	es.onEvent[eventName] = &callback{
+		Typed: func(e *Event) error {
+			v := new(T)
+			if err := decodeJSON(strings.NewReader(e.Data), v); err != nil {
+				return err
+			}
+			fn(e, v)
+			return nil
+		},
+	}
+	return es
+}
+
 // Get method establishes the connection with the server.
 //
 //	es := NewEventSource().
@@ -470,19 +546,47 @@ func (es *EventSource) Get() error {
 
 	// reset to begin
 	es.enableConnect()
+	if err := es.bufferBody(); err != nil {
+		return err
+	}
 
-	for {
+	ctx, closeChan := es.waitSignals()
+	var backoff *backoffWithJitter
+	for reconnects := 0; ; {
 		if es.isClosed() {
 			return nil
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		res, err := es.connect()
 		if err != nil {
+			if errors.Is(err, errEventSourceNoContent) {
+				return nil
+			}
 			return err
 		}
 		es.triggerOnOpen(res.Header.Clone())
-		if err := es.listenStream(res); err != nil {
+		received, err := es.listenStream(res)
+		if err == nil {
+			return nil
+		}
+		if !es.isAutoReconnect() || es.isClosed() {
 			return err
 		}
+
+		// the stream ended, reconnect after the wait; the backoff grows while
+		// the stream ends without any event
+		if received || backoff == nil {
+			reconnects = 0
+			backoff = es.newBackoff()
+		}
+		reconnects++
+		waitDuration, _ := backoff.NextWaitDuration(nil, nil, err, reconnects)
+		if !sleepContext(ctx, closeChan, waitDuration) {
+			// closed or the context is done
+			return ctx.Err()
+		}
 	}
 }
 
@@ -490,6 +594,9 @@ func (es *EventSource) Get() error {
 func (es *EventSource) Close() {
 	es.lock.Lock()
 	defer es.lock.Unlock()
+	if !es.closed && es.closeChan != nil {
+		close(es.closeChan)
+	}
 	es.closed = true
 }
 
@@ -497,6 +604,58 @@ func (es *EventSource) enableConnect() {
 	es.lock.Lock()
 	defer es.lock.Unlock()
 	es.closed = false
+	es.closeChan = make(chan struct{})
+}
+
+// waitSignals method returns the context and the close channel of the
+// current connection, which interrupt the waits.
+func (es *EventSource) waitSignals() (context.Context, <-chan struct{}) {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+	return es.context(), es.closeChan
+}
+
+// context method must be called with the lock held
+func (es *EventSource) context() context.Context {
+	if es.ctx == nil {
+		return context.Background()
+	}
+	return es.ctx
+}
+
+func (es *EventSource) isAutoReconnect() bool {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+	return es.autoReconnect
+}
+
+// bufferBody method reads the body once, so it can be sent again on
+// the retries and the reconnections
+func (es *EventSource) bufferBody() error {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if es.body == nil {
+		return nil
+	}
+	b, err := io.ReadAll(es.body)
+	if err != nil {
+		return err
+	}
+	es.body = nil
+	es.bodyBytes = b
+	return nil
+}
+
+// newBackoff method returns the exponential backoff starting from
+// the server-sent retry value, if present
+func (es *EventSource) newBackoff() *backoffWithJitter {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+	minWait := es.retryWaitTime
+	if es.serverSentRetry > 0 {
+		minWait = es.serverSentRetry
+	}
+	return newBackoffWithJitter(minWait, max(es.retryMaxWaitTime, minWait))
 }
 
 func (es *EventSource) isClosed() bool {
@@ -530,7 +689,11 @@ func (es *EventSource) triggerOnRequestFailure(err error, res *http.Response) {
 }
 
 func (es *EventSource) createRequest() (*http.Request, error) {
-	req, err := http.NewRequest(es.method, es.url, es.body)
+	var body io.Reader
+	if es.bodyBytes != nil {
+		body = bytes.NewReader(es.bodyBytes)
+	}
+	req, err := http.NewRequestWithContext(es.context(), es.method, es.url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -546,17 +709,14 @@ func (es *EventSource) createRequest() (*http.Request, error) {
 	return req, nil
 }
 
+var errEventSourceNoContent = errors.New("resty:sse: 204 No Content")
+
 func (es *EventSource) connect() (*http.Response, error) {
+	backoff := es.newBackoff()
+
 	es.lock.RLock()
 	defer es.lock.RUnlock()
 
-	var backoff *backoffWithJitter
-	if es.serverSentRetry > 0 {
-		backoff = newBackoffWithJitter(es.serverSentRetry, es.serverSentRetry)
-	} else {
-		backoff = newBackoffWithJitter(es.retryWaitTime, es.retryMaxWaitTime)
-	}
-
 	var (
 		err     error
 		attempt int
@@ -574,6 +734,12 @@ func (es *EventSource) connect() (*http.Response, error) {
 			return resp, nil
 		}
 
+		// the server instructs to stop the reconnection, as per the spec
+		if resp != nil && resp.StatusCode == http.StatusNoContent && es.autoReconnect {
+			closeq(resp.Body)
+			return nil, errEventSourceNoContent
+		}
+
 		// we have reached the maximum no. of requests
 		// first attempt + retry count = total attempts
 		if attempt-1 == es.retryCount {
@@ -601,9 +767,11 @@ func (es *EventSource) connect() (*http.Response, error) {
 		drainBody(rRes)
 
 		waitDuration, _ := backoff.NextWaitDuration(nil, rRes, doErr, attempt)
-		timer := time.NewTimer(waitDuration)
-		<-timer.C
-		timer.Stop()
+		// the close channel is not used, since Close waits for the lock
+		if !sleepContext(es.context(), nil, waitDuration) {
+			err = es.context().Err()
+			break
+		}
 	}
 
 	if err != nil {
@@ -613,7 +781,9 @@ func (es *EventSource) connect() (*http.Response, error) {
 	return nil, fmt.Errorf("resty:sse: unable to connect stream")
 }
 
-func (es *EventSource) listenStream(res *http.Response) error {
+// listenStream method reads the events from the stream until it ends or
+// the event source is closed; it reports whether any event was received.
+func (es *EventSource) listenStream(res *http.Response) (received bool, err error) {
 	defer closeq(res.Body)
 
 	scanner := bufio.NewScanner(res.Body)
@@ -636,12 +806,13 @@ func (es *EventSource) listenStream(res *http.Response) error {
 
 	for {
 		if es.isClosed() {
-			return nil
+			return received, nil
 		}
 
-		if err := es.processEvent(scanner); err != nil {
-			return err
+		if err = es.processEvent(scanner); err != nil {
+			return received, err
 		}
+		received = true
 	}
 }
 
@@ -700,6 +871,12 @@ func (es *EventSource) handleCallback(e *Event) {
 	es.lock.RUnlock()
 
 	if found {
+		if cb.Typed != nil {
+			if err := cb.Typed(e); err != nil {
+				es.triggerOnError(err)
+			}
+			return
+		}
 		if cb.Result == nil {
 			cb.Func(e)
 			return
@@ -805,3 +982,18 @@ func newRawEvent() *rawEvent {
 func putRawEvent(e *rawEvent) {
 	rawEventPool.Put(e)
 }
+
+// sleepContext function waits for the duration; it returns false if the given
+// stop channel is closed or the context is done in the meantime.
+func sleepContext(ctx context.Context, stop <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -8,6 +8,7 @@ package resty
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -666,3 +668,141 @@ func createMethodVerifyingSSETestServer(
 		}
 	}))
 }
+
+func TestEventSourceAutoReconnect(t *testing.T) {
+	var (
+		lock         sync.Mutex
+		lastEventIDs []string
+		bodies       []string
+		conns        int
+	)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		conns++
+		n := conns
+		lastEventIDs = append(lastEventIDs, r.Header.Get(hdrLastEvevntID))
+		bodies = append(bodies, string(b))
+		lock.Unlock()
+
+		switch n {
+		case 2:
+			// stream ends without any event
+			w.Header().Set(hdrContentTypeKey, "text/event-stream")
+			return
+		case 4:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set(hdrContentTypeKey, "text/event-stream")
+		for i := 0; i < 2; i++ {
+			id := (n/2)*2 + i + 1
+			_, _ = fmt.Fprintf(w, "id: %d\nretry: 10\ndata: event %d\n\n", id, id)
+		}
+	})
+	defer ts.Close()
+
+	var events []string
+	es := createEventSource(t, ts.URL, func(e any) {
+		events = append(events, e.(*Event).Data)
+	}, nil)
+	es.SetMethod(MethodPost).
+		SetBody(strings.NewReader("resty")).
+		SetAutoReconnect(true)
+
+	err := es.Get()
+	assertNil(t, err)
+	assertEqual(t, []string{"event 1", "event 2", "event 3", "event 4"}, events)
+	assertEqual(t, []string{"", "2", "2", "4"}, lastEventIDs)
+	assertEqual(t, []string{"resty", "resty", "resty", "resty"}, bodies)
+
+	// disabled, the stream end is returned
+	conns = 0
+	err = es.SetAutoReconnect(false).Get()
+	assertErrorIs(t, io.EOF, err)
+}
+
+func TestEventSourceReconnectWaitInterrupted(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "text/event-stream")
+		_, _ = fmt.Fprint(w, "id: 1\nretry: 60000\ndata: event 1\n\n")
+	})
+	defer ts.Close()
+
+	newEventSource := func(fn EventMessageFunc) *EventSource {
+		return createEventSource(t, ts.URL, fn, nil).
+			SetRetryMaxWaitTime(time.Minute).
+			SetAutoReconnect(true)
+	}
+
+	t.Run("close", func(t *testing.T) {
+		var es *EventSource
+		es = newEventSource(func(any) {
+			time.AfterFunc(50*time.Millisecond, es.Close)
+		})
+		start := time.Now()
+		err := es.Get()
+		assertNil(t, err)
+		assertEqual(t, true, time.Since(start) < 5*time.Second)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		es := newEventSource(func(any) {
+			time.AfterFunc(50*time.Millisecond, cancel)
+		}).SetContext(ctx)
+		start := time.Now()
+		err := es.Get()
+		assertErrorIs(t, context.Canceled, err)
+		assertEqual(t, true, time.Since(start) < 5*time.Second)
+	})
+}
+
+func TestEventSourceNewBackoff(t *testing.T) {
+	es := createEventSource(t, "", nil, nil)
+	b := es.newBackoff()
+	assertEqual(t, 200*time.Millisecond, b.min)
+	assertEqual(t, time.Second, b.max)
+
+	// server-sent retry value takes precedence
+	es.serverSentRetry = 50 * time.Millisecond
+	b = es.newBackoff()
+	assertEqual(t, 50*time.Millisecond, b.min)
+	assertEqual(t, time.Second, b.max)
+
+	es.serverSentRetry = 5 * time.Second
+	b = es.newBackoff()
+	assertEqual(t, 5*time.Second, b.max)
+}
+
+func TestEventSourceTypedEventListener(t *testing.T) {
+	type completion struct {
+		Text string `json:"text"`
+	}
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrContentTypeKey, "text/event-stream")
+		_, _ = fmt.Fprint(w, "id: 1\nevent: completion\ndata: {\"text\":\"hello\"}\n\n")
+		_, _ = fmt.Fprint(w, "id: 2\nevent: completion\ndata: [DONE\n\n")
+		_, _ = fmt.Fprint(w, "id: 3\nevent: completion\ndata: {\"text\":\"world\"}\n\n")
+	})
+	defer ts.Close()
+
+	var (
+		texts []string
+		ids   []string
+		errs  []error
+	)
+	es := NewEventSource().SetURL(ts.URL).OnError(func(err error) { errs = append(errs, err) })
+	AddTypedEventListener(es, "completion", func(e *Event, c *completion) {
+		ids = append(ids, e.ID)
+		texts = append(texts, c.Text)
+	})
+
+	err := es.Get()
+	assertErrorIs(t, io.EOF, err)
+	assertEqual(t, []string{"hello", "world"}, texts)
+	assertEqual(t, []string{"1", "3"}, ids)
+	assertEqual(t, 1, len(errs))
+}
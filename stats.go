@@ -0,0 +1,355 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats struct holds the cumulative request and response size
+// accounting of the client.
+//
+// See [Client.Stats]
+type ClientStats struct {
+	// Requests is the count of the requests that got the response
+	// over the network.
+	Requests int64 `json:"requests"`
+
+	// BytesWritten is the count of the request bytes written, i.e., the
+	// request line, headers, and body after the compression.
+	BytesWritten int64 `json:"bytes_written"`
+
+	// BytesRead is the count of the response bytes read, i.e., the status
+	// line, headers, and body before the decompression.
+	BytesRead int64 `json:"bytes_read"`
+}
+
+// Stats method returns the cumulative request and response size accounting
+// of the client; so the bandwidth budgeting and egress cost attribution are
+// possible without a custom transport.
+//
+//	stats := client.Stats()
+//	fmt.Println(stats.Requests, stats.BytesWritten, stats.BytesRead)
+//
+// NOTE:
+//   - The header sizes are computed in the HTTP/1.1 wire format; the header
+//     compression of HTTP/2 is not taken into account.
+//   - Responses served from the [Cache] are not counted.
+//
+// See [Response.BytesWritten], [Response.BytesRead]
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Requests:     c.stats.requests.Load(),
+		BytesWritten: c.stats.bytesWritten.Load(),
+		BytesRead:    c.stats.bytesRead.Load(),
+	}
+}
+
+// StatsSnapshot struct holds the point-in-time operational statistics of
+// the client.
+//
+// See [Client.StatsSnapshot]
+type StatsSnapshot struct {
+	ClientStats
+
+	// InFlight is the count of the request executions in progress.
+	InFlight int64 `json:"in_flight"`
+
+	// Total is the count of the completed request executions, including
+	// the failed ones. Retries are not counted separately.
+	Total int64 `json:"total"`
+
+	// Errors is the count of the failed request executions by class, i.e.,
+	// `4xx`, `5xx`, and `error` for the ones without response.
+	Errors map[string]int64 `json:"errors"`
+
+	// Retries is the count of the retry attempts.
+	Retries int64 `json:"retries"`
+
+	// CircuitBreakerState is the current state of the circuit breaker, i.e.,
+	// `closed`, `open`, or `half-open`. It is empty if none is set.
+	CircuitBreakerState string `json:"circuit_breaker_state,omitempty"`
+
+	// Connections is the connection pool statistics.
+	Connections ConnStats `json:"connections"`
+}
+
+// ConnStats struct holds the connection pool statistics of the client,
+// observed on every connection obtained by the transport.
+type ConnStats struct {
+	// New is the count of the newly established connections.
+	New int64 `json:"new"`
+
+	// Reused is the count of the connections reused from the pool.
+	Reused int64 `json:"reused"`
+
+	// WasIdle is the count of the reused connections obtained from the
+	// idle pool.
+	WasIdle int64 `json:"was_idle"`
+
+	// MaxIdleTime is the longest duration a reused connection was idle.
+	MaxIdleTime time.Duration `json:"max_idle_time"`
+}
+
+// ConnInfo struct holds the details of the connection obtained by the
+// transport for the request.
+//
+// See [Client.OnConnection], [Response.ConnInfo]
+type ConnInfo struct {
+	// Reused is whether this connection has been previously used for
+	// another HTTP request.
+	Reused bool `json:"reused"`
+
+	// WasIdle is whether this connection was obtained from the idle pool.
+	WasIdle bool `json:"was_idle"`
+
+	// IdleTime is the duration the connection was previously idle,
+	// if WasIdle is true.
+	IdleTime time.Duration `json:"idle_time"`
+
+	// LocalAddr is the local network address of the connection.
+	LocalAddr string `json:"local_address"`
+
+	// RemoteAddr is the remote network address of the connection.
+	RemoteAddr string `json:"remote_address"`
+}
+
+// String method returns the JSON string of the stats snapshot.
+func (ss StatsSnapshot) String() string {
+	return toJSON(ss)
+}
+
+// StatsSnapshot method returns the point-in-time operational statistics of
+// the client, such as in-flight requests, errors by class, retries, circuit
+// breaker state, and connection pool statistics.
+//
+//	snapshot := client.StatsSnapshot()
+//	fmt.Println(snapshot.InFlight, snapshot.Errors["5xx"])
+//
+// See [Client.StatsVar]
+func (c *Client) StatsSnapshot() StatsSnapshot {
+	cs := c.stats
+	ss := StatsSnapshot{
+		ClientStats: c.Stats(),
+		InFlight:    cs.inFlight.Load(),
+		Total:       cs.total.Load(),
+		Errors: map[string]int64{
+			"4xx":   cs.errors4xx.Load(),
+			"5xx":   cs.errors5xx.Load(),
+			"error": cs.errorsOther.Load(),
+		},
+		Retries: cs.retries.Load(),
+		Connections: ConnStats{
+			New:         cs.connNew.Load(),
+			Reused:      cs.connReused.Load(),
+			WasIdle:     cs.connWasIdle.Load(),
+			MaxIdleTime: time.Duration(cs.connMaxIdle.Load()),
+		},
+	}
+	if cb := c.circuitBreaker; cb != nil {
+		ss.CircuitBreakerState = cb.getState().String()
+	}
+	return ss
+}
+
+// StatsVar method returns the [StatsVar] of the client, which can be
+// published with the `expvar` package; so the operational dashboards can
+// scrape the client health.
+//
+//	expvar.Publish("resty_client", client.StatsVar())
+func (c *Client) StatsVar() StatsVar {
+	return StatsVar{c: c}
+}
+
+// StatsVar type implements the `expvar.Var` interface, it exports the
+// client [StatsSnapshot] in JSON format.
+type StatsVar struct {
+	c *Client
+}
+
+// String method returns the JSON string of the client stats snapshot.
+func (sv StatsVar) String() string {
+	return sv.c.StatsSnapshot().String()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type clientStats struct {
+	requests     atomic.Int64
+	bytesWritten atomic.Int64
+	bytesRead    atomic.Int64
+	inFlight     atomic.Int64
+	total        atomic.Int64
+	errors4xx    atomic.Int64
+	errors5xx    atomic.Int64
+	errorsOther  atomic.Int64
+	retries      atomic.Int64
+	connNew      atomic.Int64
+	connReused   atomic.Int64
+	connWasIdle  atomic.Int64
+	connMaxIdle  atomic.Int64
+}
+
+// complete method accounts the completed request execution.
+func (cs *clientStats) complete(r *Request, res *Response, err error) {
+	cs.total.Add(1)
+	cs.retries.Add(int64(max(0, r.Attempt-1)))
+	switch {
+	case res == nil || res.RawResponse == nil:
+		if err != nil {
+			cs.errorsOther.Add(1)
+		}
+	case res.StatusCode() >= 500:
+		cs.errors5xx.Add(1)
+	case res.StatusCode() >= 400:
+		cs.errors4xx.Add(1)
+	case err != nil:
+		cs.errorsOther.Add(1)
+	}
+}
+
+func (cs *clientStats) createContext(ctx context.Context, r *Request) context.Context {
+	return httptrace.WithClientTrace(
+		ctx,
+		&httptrace.ClientTrace{
+			GotConn: func(gci httptrace.GotConnInfo) {
+				ci := ConnInfo{
+					Reused:   gci.Reused,
+					WasIdle:  gci.WasIdle,
+					IdleTime: gci.IdleTime,
+				}
+				if gci.Conn != nil {
+					ci.LocalAddr = gci.Conn.LocalAddr().String()
+					ci.RemoteAddr = gci.Conn.RemoteAddr().String()
+				}
+				cs.trackConn(ci)
+				r.setConnInfo(ci)
+				if r.client != nil {
+					r.client.onConnectionHooks(r, ci)
+				}
+			},
+		},
+	)
+}
+
+func (cs *clientStats) trackConn(ci ConnInfo) {
+	if !ci.Reused {
+		cs.connNew.Add(1)
+		return
+	}
+	cs.connReused.Add(1)
+	if !ci.WasIdle {
+		return
+	}
+	cs.connWasIdle.Add(1)
+	for {
+		cur := cs.connMaxIdle.Load()
+		if int64(ci.IdleTime) <= cur || cs.connMaxIdle.CompareAndSwap(cur, int64(ci.IdleTime)) {
+			return
+		}
+	}
+}
+
+func (cs *clientStats) addWritten(res *Response, n int64) {
+	atomic.AddInt64(&res.bytesWritten, n)
+	cs.bytesWritten.Add(n)
+}
+
+func (cs *clientStats) addRead(res *Response, n int64) {
+	atomic.AddInt64(&res.bytesRead, n)
+	cs.bytesRead.Add(n)
+}
+
+// wrapRequestBody method wraps the request body to count the bytes
+// written by the transport.
+func (cs *clientStats) wrapRequestBody(res *Response) {
+	rawReq := res.Request.RawRequest
+	if rawReq.Body != nil && rawReq.Body != http.NoBody {
+		rawReq.Body = &countReadCloser{r: rawReq.Body, f: func(n int64) { cs.addWritten(res, n) }}
+	}
+	if getBody := rawReq.GetBody; getBody != nil {
+		// the body is sent again on redirects
+		rawReq.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil || body == http.NoBody {
+				return body, err
+			}
+			return &countReadCloser{r: body, f: func(n int64) { cs.addWritten(res, n) }}, nil
+		}
+	}
+}
+
+// track method accounts the request and response headers of the exchange
+// and wraps the response body to count the bytes read from the transport.
+func (cs *clientStats) track(res *Response) {
+	cs.requests.Add(1)
+	cs.addWritten(res, requestHeaderSize(res.Request.RawRequest))
+
+	rawRes := res.RawResponse
+	cs.addRead(res, responseHeaderSize(rawRes))
+	if rawRes.Body != nil && rawRes.Body != http.NoBody {
+		rawRes.Body = &countReadCloser{r: rawRes.Body, f: func(n int64) { cs.addRead(res, n) }}
+	}
+}
+
+type countReadCloser struct {
+	r io.ReadCloser
+	f func(n int64)
+}
+
+func (cr *countReadCloser) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	if n > 0 {
+		cr.f(int64(n))
+	}
+	return n, err
+}
+
+func (cr *countReadCloser) Close() error {
+	return cr.r.Close()
+}
+
+// requestHeaderSize returns the size of request line and headers
+// in the HTTP/1.1 wire format.
+func requestHeaderSize(r *http.Request) int64 {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	// METHOD SP request-target SP HTTP/1.1 CRLF Host: host CRLF ... CRLF
+	size := len(r.Method) + 1 + len(r.URL.RequestURI()) + len(" HTTP/1.1\r\n") +
+		len("Host: \r\n") + len(host) + headerSize(r.Header) + 2
+	if r.ContentLength > 0 && r.Header.Get(hdrContentLengthKey) == "" {
+		size += len(hdrContentLengthKey) + 4 + len(strconv.FormatInt(r.ContentLength, 10))
+	}
+	return int64(size)
+}
+
+// responseHeaderSize returns the size of status line and headers
+// in the HTTP/1.1 wire format.
+func responseHeaderSize(r *http.Response) int64 {
+	// HTTP/1.1 SP 200 OK CRLF ... CRLF
+	return int64(len(r.Proto) + 1 + len(r.Status) + 2 + headerSize(r.Header) + 2)
+}
+
+func headerSize(h http.Header) int {
+	size := 0
+	for k, values := range h {
+		for _, v := range values {
+			// key: value CRLF
+			size += len(k) + 2 + len(v) + 2
+		}
+	}
+	return size
+}
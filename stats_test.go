@@ -0,0 +1,202 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientStats(t *testing.T) {
+	var gzipBody bytes.Buffer
+	gw := gzip.NewWriter(&gzipBody)
+	_, _ = gw.Write([]byte(strings.Repeat("resty ", 100)))
+	_ = gw.Close()
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set(hdrContentEncodingKey, "gzip")
+			_, _ = w.Write(gzipBody.Bytes())
+		default:
+			b, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(b)
+		}
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	assertEqual(t, ClientStats{}, c.Stats())
+
+	res, err := c.R().SetBody("hello resty").Post(ts.URL + "/echo")
+	assertNil(t, err)
+	assertEqual(t, "hello resty", res.String())
+
+	reqHeaderSize := requestHeaderSize(res.Request.RawRequest)
+	resHeaderSize := responseHeaderSize(res.RawResponse)
+	assertEqual(t, reqHeaderSize+11, res.BytesWritten())
+	assertEqual(t, resHeaderSize+11, res.BytesRead())
+	assertEqual(t, ClientStats{
+		Requests:     1,
+		BytesWritten: res.BytesWritten(),
+		BytesRead:    res.BytesRead(),
+	}, c.Stats())
+
+	// body is counted before the decompression
+	res, err = c.R().Get(ts.URL + "/gzip")
+	assertNil(t, err)
+	assertEqual(t, int64(600), res.Size())
+	assertEqual(t, true, res.BytesRead() > int64(gzipBody.Len()))
+	assertEqual(t, true, res.BytesRead() < res.Size())
+	assertEqual(t, int64(2), c.Stats().Requests)
+
+	// cloned client starts afresh
+	assertEqual(t, ClientStats{}, c.Clone(context.Background()).Stats())
+}
+
+func TestClientStatsFromCache(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(hdrCacheControlKey, "max-age=60")
+		_, _ = w.Write([]byte("cached"))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetCache(NewCache())
+
+	res, err := c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, CacheStatusMiss, res.CacheStatus())
+
+	res, err = c.R().Get(ts.URL)
+	assertNil(t, err)
+	assertEqual(t, true, res.IsFromCache())
+	assertEqual(t, int64(0), res.BytesRead())
+	assertEqual(t, int64(1), c.Stats().Requests)
+}
+
+func TestRequestHeaderSize(t *testing.T) {
+	req, _ := http.NewRequest(MethodPost, "http://example.com/path?q=1", strings.NewReader("body"))
+	req.Header.Set("X-Test", "value")
+
+	expected := "POST /path?q=1 HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"X-Test: value\r\n" +
+		"Content-Length: 4\r\n" +
+		"\r\n"
+	assertEqual(t, int64(len(expected)), requestHeaderSize(req))
+}
+
+func TestClientStatsSnapshot(t *testing.T) {
+	inHandler := make(chan struct{})
+	release := make(chan struct{})
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/not-found":
+			w.WriteHeader(http.StatusNotFound)
+		case "/block":
+			inHandler <- struct{}{}
+			<-release
+		}
+	})
+	defer ts.Close()
+
+	var gotConns atomic.Int32
+	c := dcnl().
+		SetCircuitBreaker(NewCircuitBreaker().SetFailureThreshold(10)).
+		SetTraceHooks(&TraceHooks{
+			GotConn: func(_ *Request, _ httptrace.GotConnInfo) { gotConns.Add(1) },
+		})
+
+	_, err := c.R().
+		SetRetryCount(2).
+		SetRetryWaitTime(time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Millisecond).
+		Get(ts.URL + "/fail")
+	assertNil(t, err)
+	assertEqual(t, int32(3), gotConns.Load())
+
+	_, err = c.R().Get(ts.URL + "/not-found")
+	assertNil(t, err)
+
+	_, err = c.R().Get("http://127.0.0.1:1/unreachable")
+	assertNotNil(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.R().Get(ts.URL + "/block")
+	}()
+	<-inHandler
+	assertEqual(t, int64(1), c.StatsSnapshot().InFlight)
+	close(release)
+	<-done
+
+	ss := c.StatsSnapshot()
+	assertEqual(t, int64(0), ss.InFlight)
+	assertEqual(t, int64(4), ss.Total)
+	assertEqual(t, int64(5), ss.Requests)
+	assertEqual(t, int64(2), ss.Retries)
+	assertEqual(t, map[string]int64{"4xx": 1, "5xx": 1, "error": 1}, ss.Errors)
+	assertEqual(t, "closed", ss.CircuitBreakerState)
+	assertEqual(t, int64(1), ss.Connections.New)
+	assertEqual(t, int64(4), ss.Connections.Reused)
+	assertEqual(t, true, ss.Connections.WasIdle > 0)
+
+	// expvar compatible
+	var v interface{ String() string } = c.StatsVar()
+	result := StatsSnapshot{}
+	assertNil(t, json.Unmarshal([]byte(v.String()), &result))
+	assertEqual(t, ss, result)
+}
+
+func TestClientOnConnection(t *testing.T) {
+	ts := createGetServer(t)
+	defer ts.Close()
+
+	var infos []ConnInfo
+	c := dcnl().OnConnection(func(r *Request, ci ConnInfo) {
+		assertEqual(t, MethodGet, r.Method)
+		infos = append(infos, ci)
+	})
+
+	res1, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+	time.Sleep(5 * time.Millisecond)
+	res2, err := c.R().Get(ts.URL + "/")
+	assertNil(t, err)
+
+	assertEqual(t, 2, len(infos))
+	assertEqual(t, false, infos[0].Reused)
+	assertEqual(t, true, infos[1].Reused)
+	assertEqual(t, true, infos[1].WasIdle)
+	assertEqual(t, true, infos[1].IdleTime > 0)
+	assertEqual(t, strings.TrimPrefix(ts.URL, "http://"), infos[1].RemoteAddr)
+	assertEqual(t, infos[0].LocalAddr, infos[1].LocalAddr)
+
+	assertEqual(t, infos[0], *res1.ConnInfo())
+	assertEqual(t, infos[1], *res2.ConnInfo())
+
+	cs := c.StatsSnapshot().Connections
+	assertEqual(t, int64(1), cs.New)
+	assertEqual(t, int64(1), cs.WasIdle)
+	assertEqual(t, infos[1].IdleTime, cs.MaxIdleTime)
+
+	// no connection obtained
+	res, err := c.R().Get("http://127.0.0.1:1/unreachable")
+	assertNotNil(t, err)
+	assertNil(t, res.ConnInfo())
+}
@@ -12,12 +12,17 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 	"sync"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 var (
 	ErrContentDecompresserNotFound = errors.New("resty: content decoder not found")
+	ErrContentCompresserNotFound   = errors.New("resty: content compresser not found")
 )
 
 type (
@@ -34,6 +39,15 @@ type (
 	//
 	// [RFC 9110]: https://datatracker.ietf.org/doc/html/rfc9110
 	ContentDecompresser func(io.ReadCloser) (io.ReadCloser, error)
+
+	// ContentCompresser type is for compressing the request body based on
+	// [Request.SetContentEncoding] ([RFC 9110]). The returned writer is closed
+	// once the body is written, so it must flush the remaining data on close.
+	//
+	// For example, gzip, deflate, etc.
+	//
+	// [RFC 9110]: https://datatracker.ietf.org/doc/html/rfc9110
+	ContentCompresser func(io.Writer) (io.WriteCloser, error)
 )
 
 func encodeJSON(w io.Writer, v any) error {
@@ -89,6 +103,38 @@ func decodeJSON(r io.Reader, v any) error {
 	return nil
 }
 
+// decodeJSONArray function decodes the JSON array incrementally using
+// [json.Decoder] tokens, each element is decoded into v and passed to fn.
+func decodeJSONArray(r io.Reader, v any, fn func(any) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("resty: decode JSON array element into non-pointer %T", v)
+	}
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("resty: expected JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		rv.Elem().SetZero()
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	// consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
+
 func encodeXML(w io.Writer, v any) error {
 	return xml.NewEncoder(w).Encode(v)
 }
@@ -105,6 +151,31 @@ func decodeXML(r io.Reader, v any) error {
 	return nil
 }
 
+// marshalEncoder function adapts the given marshal function into
+// the [ContentTypeEncoder].
+func marshalEncoder(marshal func(any) ([]byte, error)) ContentTypeEncoder {
+	return func(w io.Writer, v any) error {
+		b, err := marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+}
+
+// unmarshalDecoder function adapts the given unmarshal function into
+// the [ContentTypeDecoder].
+func unmarshalDecoder(unmarshal func([]byte, any) error) ContentTypeDecoder {
+	return func(r io.Reader, v any) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return unmarshal(b, v)
+	}
+}
+
 var gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
 
 func decompressGzip(r io.ReadCloser) (io.ReadCloser, error) {
@@ -153,6 +224,124 @@ func (d *deflateReader) Close() error {
 	return nil
 }
 
+// the decoder concurrency 1 decodes in the caller goroutine, so the pooled
+// decoders do not hold the background goroutines
+var zstdPool = sync.Pool{New: func() any {
+	zr, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	return zr
+}}
+
+func decompressZstd(r io.ReadCloser) (io.ReadCloser, error) {
+	zr := zstdPool.Get().(*zstd.Decoder)
+	err := zr.Reset(r)
+	return &zstdReader{s: r, r: zr}, err
+}
+
+type zstdReader struct {
+	s io.ReadCloser
+	r *zstd.Decoder
+}
+
+func (z *zstdReader) Read(p []byte) (n int, err error) {
+	return z.r.Read(p)
+}
+
+func (z *zstdReader) Close() error {
+	_ = z.r.Reset(nil)
+	zstdPool.Put(z.r)
+	closeq(z.s)
+	return nil
+}
+
+var gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+func compressGzip(w io.Writer) (io.WriteCloser, error) {
+	gw := gzipWriterPool.Get().(*gzip.Writer)
+	gw.Reset(w)
+	return &gzipWriter{w: gw}, nil
+}
+
+type gzipWriter struct {
+	w *gzip.Writer
+}
+
+func (gz *gzipWriter) Write(p []byte) (n int, err error) {
+	return gz.w.Write(p)
+}
+
+func (gz *gzipWriter) Close() error {
+	err := gz.w.Close()
+	gz.w.Reset(nil)
+	gzipWriterPool.Put(gz.w)
+	return err
+}
+
+var flateWriterPool = sync.Pool{New: func() any {
+	fw, _ := flate.NewWriter(nil, flate.DefaultCompression)
+	return fw
+}}
+
+func compressDeflate(w io.Writer) (io.WriteCloser, error) {
+	fw := flateWriterPool.Get().(*flate.Writer)
+	fw.Reset(w)
+	return &deflateWriter{w: fw}, nil
+}
+
+type deflateWriter struct {
+	w *flate.Writer
+}
+
+func (d *deflateWriter) Write(p []byte) (n int, err error) {
+	return d.w.Write(p)
+}
+
+func (d *deflateWriter) Close() error {
+	err := d.w.Close()
+	d.w.Reset(nil)
+	flateWriterPool.Put(d.w)
+	return err
+}
+
+var zstdWriterPool = sync.Pool{New: func() any {
+	zw, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return zw
+}}
+
+func compressZstd(w io.Writer) (io.WriteCloser, error) {
+	zw := zstdWriterPool.Get().(*zstd.Encoder)
+	zw.Reset(w)
+	return &zstdWriter{w: zw}, nil
+}
+
+type zstdWriter struct {
+	w *zstd.Encoder
+}
+
+func (z *zstdWriter) Write(p []byte) (n int, err error) {
+	return z.w.Write(p)
+}
+
+func (z *zstdWriter) Close() error {
+	err := z.w.Close()
+	z.w.Reset(nil)
+	zstdWriterPool.Put(z.w)
+	return err
+}
+
+// compressTo function compresses the given reader into the writer using
+// the given content compresser.
+func compressTo(w io.Writer, r io.Reader, cc ContentCompresser) error {
+	cw, err := cc(w)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(cw, r); err != nil {
+		closeq(cw)
+		return err
+	}
+	return cw.Close()
+}
+
 var ErrReadExceedsThresholdLimit = errors.New("resty: read exceeds the threshold limit")
 
 var _ io.ReadCloser = (*limitReadCloser)(nil)
@@ -241,6 +430,60 @@ func (r *nopReadCloser) Reset() {
 
 var _ flate.Reader = (*nopReader)(nil)
 
+// teeReadCloser writes the read bytes into the writer, like [io.TeeReader];
+// the write error is sticky, since the decoders may ignore the error of the
+// read that completes the value.
+type teeReadCloser struct {
+	r   io.ReadCloser
+	w   io.Writer
+	err error
+}
+
+func (tr *teeReadCloser) Read(p []byte) (n int, err error) {
+	if tr.err != nil {
+		return 0, tr.err
+	}
+	n, err = tr.r.Read(p)
+	if n > 0 {
+		if _, werr := tr.w.Write(p[:n]); werr != nil {
+			tr.err = werr
+			return n, werr
+		}
+	}
+	return
+}
+
+func (tr *teeReadCloser) Close() error {
+	return tr.r.Close()
+}
+
+// streamReadCloser closes the underlying reader on reaching the end of it, and
+// stops closing the raw body on the context end.
+type streamReadCloser struct {
+	r    io.ReadCloser
+	once sync.Once
+	stop func() bool
+	err  error
+}
+
+func (sr *streamReadCloser) Read(p []byte) (n int, err error) {
+	n, err = sr.r.Read(p)
+	if err == io.EOF {
+		_ = sr.Close()
+	}
+	return
+}
+
+func (sr *streamReadCloser) Close() error {
+	sr.once.Do(func() {
+		if sr.stop != nil {
+			sr.stop()
+		}
+		sr.err = sr.r.Close()
+	})
+	return sr.err
+}
+
 type nopReader struct{}
 
 func (nopReader) Read([]byte) (int, error) { return 0, io.EOF }
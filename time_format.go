@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Time formats, besides the [time.Time.Format] layouts, for the query params,
+// headers, and form data, see [Client.SetTimeFormat].
+const (
+	// TimeFormatUnix formats the time as the Unix time in seconds.
+	TimeFormatUnix = "unix"
+
+	// TimeFormatUnixMilli formats the time as the Unix time in milliseconds.
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// SetTimeFormat method sets the default time format of the [time.Time] values
+// at the client level for all requests; it is applied when the structs and maps
+// are encoded into the query params and form data, see
+// [Request.SetQueryParamsNested] and [Request.SetFormDataNested], the typed path
+// params, see [Request.SetPathParamValue], and [Request.SetQueryTime].
+//
+// The format is either the [time.Time.Format] layout, [TimeFormatUnix], or
+// [TimeFormatUnixMilli]. Default is [time.RFC3339].
+//
+//	client.SetTimeFormat(resty.TimeFormatUnixMilli)
+//
+// It can be overridden at request level, see [Request.SetTimeFormat]
+func (c *Client) SetTimeFormat(format string) *Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.timeFormat = format
+	return c
+}
+
+// SetTimeFormat method sets the default time format of the [time.Time] values in
+// the current request, see [Client.SetTimeFormat].
+//
+//	client.R().
+//		SetTimeFormat(time.DateOnly).
+//		SetQueryParamsNested(filter)
+//
+// It overrides the value set at the client instance level.
+func (r *Request) SetTimeFormat(format string) *Request {
+	r.timeFormat = format
+	return r
+}
+
+// SetQueryTime method sets a single query parameter of the given time value,
+// formatted with the given layout in the current request. The layout is either
+// the [time.Time.Format] layout, [TimeFormatUnix], or [TimeFormatUnixMilli]; the
+// empty layout means the default time format, see [Client.SetTimeFormat].
+//
+//	client.R().
+//		SetQueryTime("since", since, time.RFC3339).
+//		SetQueryTime("until", until, resty.TimeFormatUnix).
+//		Get("/events")
+func (r *Request) SetQueryTime(param string, t time.Time, layout string) *Request {
+	if len(layout) == 0 {
+		layout = r.timeFormat
+	}
+	return r.SetQueryParam(param, formatTime(t, layout))
+}
+
+// SetHeaderTime method sets a single header field of the given time value,
+// formatted with the given layout in the current request. The layout is either
+// the [time.Time.Format] layout, [TimeFormatUnix], or [TimeFormatUnixMilli]; the
+// empty layout means the HTTP date format, see [http.TimeFormat].
+//
+//	client.R().
+//		SetHeaderTime("If-Modified-Since", lastModified, "").
+//		Get("/users")
+func (r *Request) SetHeaderTime(header string, t time.Time, layout string) *Request {
+	if len(layout) == 0 {
+		layout = http.TimeFormat
+	}
+	return r.SetHeader(header, formatTime(t, layout))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func formatTime(t time.Time, layout string) string {
+	switch layout {
+	case "":
+		return t.Format(time.RFC3339)
+	case TimeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case http.TimeFormat:
+		// HTTP date is always in GMT
+		return t.UTC().Format(http.TimeFormat)
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeFormat(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("IST", 5*3600+1800))
+
+	type filter struct {
+		Name  string    `query:"name,omitempty"`
+		Since time.Time `query:"since"`
+		Until time.Time `json:"until"`
+	}
+
+	c := dcnl()
+	r := c.R().
+		SetQueryTime("a", ts, "").
+		SetQueryTime("b", ts, TimeFormatUnix).
+		SetQueryTime("c", ts, time.DateOnly).
+		SetHeaderTime("If-Modified-Since", ts, "").
+		SetHeaderTime("X-Since-Millis", ts, TimeFormatUnixMilli).
+		SetQueryParamsNested(filter{Since: ts, Until: ts})
+	assertEqual(t, url.Values{
+		"a":     {"2024-03-01T10:30:00+05:30"},
+		"b":     {"1709269200"},
+		"c":     {"2024-03-01"},
+		"since": {"2024-03-01T10:30:00+05:30"},
+		"until": {"2024-03-01T10:30:00+05:30"},
+	}, r.QueryParams)
+	assertEqual(t, "Fri, 01 Mar 2024 05:00:00 GMT", r.Header.Get("If-Modified-Since"))
+	assertEqual(t, "1709269200000", r.Header.Get("X-Since-Millis"))
+
+	// client default format
+	c.SetTimeFormat(TimeFormatUnixMilli)
+	r = c.R().
+		SetQueryTime("a", ts, "").
+		SetQueryParamsNested(filter{Since: ts}).
+		SetFormDataNested(map[string]any{"at": ts}).
+		SetPathParamValue("day", ts)
+	assertEqual(t, "1709269200000", r.QueryParams.Get("a"))
+	assertEqual(t, "1709269200000", r.QueryParams.Get("since"))
+	assertEqual(t, "1709269200000", r.FormData.Get("at"))
+	assertEqual(t, "1709269200000", r.PathParams["day"])
+
+	// request level override
+	r = c.R().SetTimeFormat(time.DateOnly).SetFormDataNested(map[string]any{"at": ts})
+	assertEqual(t, "2024-03-01", r.FormData.Get("at"))
+
+	// invalid kind
+	c2, lb := dcldb()
+	c2.R().SetQueryParamsNested("text")
+	assertEqual(t, true, len(lb.String()) > 0)
+}
@@ -17,6 +17,9 @@ import (
 // TraceInfo struct is used to provide request trace info such as DNS lookup
 // duration, Connection obtain duration, Server processing duration, etc.
 type TraceInfo struct {
+	// Name is the logical operation name of the request, see [Request.SetName].
+	Name string `json:"name,omitempty"`
+
 	// DNSLookup is the duration that transport took to perform
 	// DNS lookup.
 	DNSLookup time.Duration `json:"dns_lookup_time"`
@@ -56,6 +59,10 @@ type TraceInfo struct {
 	// request execution flow, including retry count.
 	RequestAttempt int `json:"request_attempt"`
 
+	// RedirectHop is the index of the redirect hop within the request
+	// attempt, the initial request is zero.
+	RedirectHop int `json:"redirect_hop"`
+
 	// RemoteAddr returns the remote network address.
 	RemoteAddr string `json:"remote_address"`
 }
@@ -74,10 +81,11 @@ func (ti TraceInfo) String() string {
   IsConnWasIdle : %v
   ConnIdleTime  : %v
   RequestAttempt: %v
+  RedirectHop   : %v
   RemoteAddr    : %v`, ti.DNSLookup, ti.ConnTime, ti.TCPConnTime,
 		ti.TLSHandshake, ti.ServerTime, ti.ResponseTime, ti.TotalTime,
 		ti.IsConnReused, ti.IsConnWasIdle, ti.ConnIdleTime, ti.RequestAttempt,
-		ti.RemoteAddr)
+		ti.RedirectHop, ti.RemoteAddr)
 }
 
 // JSON method returns the JSON string of request trace information
@@ -92,6 +100,56 @@ func (ti TraceInfo) Clone() *TraceInfo {
 	return ti2
 }
 
+// TraceHooks struct holds the per-event callbacks of the HTTP connection
+// lifecycle, see [httptrace.ClientTrace]. Each callback receives the Resty
+// [Request], so the events can be correlated, e.g., by the APM agents.
+// Unset callbacks are ignored.
+//
+// See [Client.SetTraceHooks]
+type TraceHooks struct {
+	DNSStart             func(r *Request, info httptrace.DNSStartInfo)
+	DNSDone              func(r *Request, info httptrace.DNSDoneInfo)
+	ConnectStart         func(r *Request, network, addr string)
+	ConnectDone          func(r *Request, network, addr string, err error)
+	TLSHandshakeStart    func(r *Request)
+	TLSHandshakeDone     func(r *Request, state tls.ConnectionState, err error)
+	GotConn              func(r *Request, info httptrace.GotConnInfo)
+	WroteRequest         func(r *Request, info httptrace.WroteRequestInfo)
+	GotFirstResponseByte func(r *Request)
+}
+
+func (th *TraceHooks) createContext(ctx context.Context, r *Request) context.Context {
+	ct := &httptrace.ClientTrace{}
+	if th.DNSStart != nil {
+		ct.DNSStart = func(info httptrace.DNSStartInfo) { th.DNSStart(r, info) }
+	}
+	if th.DNSDone != nil {
+		ct.DNSDone = func(info httptrace.DNSDoneInfo) { th.DNSDone(r, info) }
+	}
+	if th.ConnectStart != nil {
+		ct.ConnectStart = func(network, addr string) { th.ConnectStart(r, network, addr) }
+	}
+	if th.ConnectDone != nil {
+		ct.ConnectDone = func(network, addr string, err error) { th.ConnectDone(r, network, addr, err) }
+	}
+	if th.TLSHandshakeStart != nil {
+		ct.TLSHandshakeStart = func() { th.TLSHandshakeStart(r) }
+	}
+	if th.TLSHandshakeDone != nil {
+		ct.TLSHandshakeDone = func(state tls.ConnectionState, err error) { th.TLSHandshakeDone(r, state, err) }
+	}
+	if th.GotConn != nil {
+		ct.GotConn = func(info httptrace.GotConnInfo) { th.GotConn(r, info) }
+	}
+	if th.WroteRequest != nil {
+		ct.WroteRequest = func(info httptrace.WroteRequestInfo) { th.WroteRequest(r, info) }
+	}
+	if th.GotFirstResponseByte != nil {
+		ct.GotFirstResponseByte = func() { th.GotFirstResponseByte(r) }
+	}
+	return httptrace.WithClientTrace(ctx, ct)
+}
+
 // clientTrace struct maps the [httptrace.ClientTrace] hooks into Fields
 // with the same naming for easy understanding. Plus additional insights
 // [Request].
@@ -107,6 +165,13 @@ type clientTrace struct {
 	gotFirstResponseByte time.Time
 	endTime              time.Time
 	gotConnInfo          httptrace.GotConnInfo
+	attempt              int
+	hopStart             time.Time
+	hops                 []TraceInfo
+}
+
+func newClientTrace(attempt int) *clientTrace {
+	return &clientTrace{attempt: attempt, hopStart: time.Now()}
 }
 
 func (t *clientTrace) createContext(ctx context.Context) context.Context {
@@ -140,7 +205,12 @@ func (t *clientTrace) createContext(ctx context.Context) context.Context {
 			},
 			GetConn: func(_ string) {
 				t.lock.Lock()
-				t.getConn = time.Now()
+				now := time.Now()
+				if !t.gotFirstResponseByte.IsZero() {
+					// previous hop got its response, so it is a redirect
+					t.nextHop(now)
+				}
+				t.getConn = now
 				t.lock.Unlock()
 			},
 			GotConn: func(ci httptrace.GotConnInfo) {
@@ -167,3 +237,81 @@ func (t *clientTrace) createContext(ctx context.Context) context.Context {
 		},
 	)
 }
+
+// nextHop method records the completed redirect hop and resets the
+// timings for the next one. The caller must hold the lock.
+func (t *clientTrace) nextHop(now time.Time) {
+	t.endTime = now
+	ti := t.traceInfo(t.hopStart)
+	ti.RequestAttempt = t.attempt
+	ti.RedirectHop = len(t.hops)
+	t.hops = append(t.hops, ti)
+
+	t.getConn = time.Time{}
+	t.dnsStart = time.Time{}
+	t.dnsDone = time.Time{}
+	t.connectDone = time.Time{}
+	t.tlsHandshakeStart = time.Time{}
+	t.tlsHandshakeDone = time.Time{}
+	t.gotConn = time.Time{}
+	t.gotFirstResponseByte = time.Time{}
+	t.endTime = time.Time{}
+	t.gotConnInfo = httptrace.GotConnInfo{}
+	t.hopStart = now
+}
+
+// traceInfo method computes the [TraceInfo] of the current hop, startTime
+// is used when the transport did not report the start of the hop.
+// The caller must hold the lock.
+func (t *clientTrace) traceInfo(startTime time.Time) TraceInfo {
+	ti := TraceInfo{
+		IsConnReused:  t.gotConnInfo.Reused,
+		IsConnWasIdle: t.gotConnInfo.WasIdle,
+		ConnIdleTime:  t.gotConnInfo.IdleTime,
+	}
+
+	if !t.dnsStart.IsZero() && !t.dnsDone.IsZero() {
+		ti.DNSLookup = t.dnsDone.Sub(t.dnsStart)
+	}
+
+	if !t.tlsHandshakeDone.IsZero() && !t.tlsHandshakeStart.IsZero() {
+		ti.TLSHandshake = t.tlsHandshakeDone.Sub(t.tlsHandshakeStart)
+	}
+
+	if !t.gotFirstResponseByte.IsZero() && !t.gotConn.IsZero() {
+		ti.ServerTime = t.gotFirstResponseByte.Sub(t.gotConn)
+	}
+
+	// Calculate the total time accordingly when connection is reused,
+	// and DNS start and get conn time may be zero if the request is invalid.
+	// See issue #1016.
+	requestStartTime := startTime
+	if t.gotConnInfo.Reused && !t.getConn.IsZero() {
+		requestStartTime = t.getConn
+	} else if !t.dnsStart.IsZero() {
+		requestStartTime = t.dnsStart
+	}
+	ti.TotalTime = t.endTime.Sub(requestStartTime)
+
+	// Only calculate on successful connections
+	if !t.connectDone.IsZero() {
+		ti.TCPConnTime = t.connectDone.Sub(t.dnsDone)
+	}
+
+	// Only calculate on successful connections
+	if !t.gotConn.IsZero() {
+		ti.ConnTime = t.gotConn.Sub(t.getConn)
+	}
+
+	// Only calculate on successful connections
+	if !t.gotFirstResponseByte.IsZero() {
+		ti.ResponseTime = t.endTime.Sub(t.gotFirstResponseByte)
+	}
+
+	// Capture remote address info when connection is non-nil
+	if t.gotConnInfo.Conn != nil {
+		ti.RemoteAddr = t.gotConnInfo.Conn.RemoteAddr().String()
+	}
+
+	return ti
+}
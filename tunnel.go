@@ -0,0 +1,262 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// ErrTunnelRefused error returned when the proxy responds to the CONNECT
+// request with a non-2xx status, see [Request.Tunnel].
+var ErrTunnelRefused = errors.New("resty: proxy refused the tunnel")
+
+var hdrProxyAuthorizationKey = http.CanonicalHeaderKey("Proxy-Authorization")
+
+// Tunnel method opens the tunnel to the given `host:port` address through the
+// proxy of the client and returns the raw tunneled connection for the custom
+// protocols riding over the HTTP proxies, e.g., SSH or database protocols.
+// The proxy is selected the same way as the requests, e.g., [Client.SetProxy],
+// [Client.SetProxyFunc]; the connection is dialed directly if there is no proxy.
+//
+//	conn, err := client.R().
+//		SetContext(ctx).
+//		SetHeader("X-Tunnel-Purpose", "ssh").
+//		Tunnel("git.example.com:22")
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+//
+// The HTTP and HTTPS proxies are sent the CONNECT request with the request
+// headers, and the SOCKS5 proxies are used as-is. The HTTPS proxy connection
+// uses the client's TLS configuration. The context and timeout of the request
+// apply to establishing the tunnel only.
+//
+// The proxy responds to the CONNECT with a non-2xx status returns the
+// [ErrTunnelRefused] wrapped error.
+//
+// See [Request.TunnelTLS]
+func (r *Request) Tunnel(addr string) (net.Conn, error) {
+	transport, err := r.client.HTTPTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := r.Context()
+	if _, found := ctx.Deadline(); !found && r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	r.proxyURL = nil
+	conn, err := r.dialTunnel(ctx, transport, addr)
+	r.client.sendProxyPoolFeedback(r, err)
+	return conn, err
+}
+
+// TunnelTLS method opens the tunnel the same as [Request.Tunnel], then performs
+// the TLS handshake over it using the client's TLS configuration; the server name
+// is the address host, unless it is set on the TLS configuration.
+//
+//	conn, err := client.R().TunnelTLS("smtp.example.com:465")
+func (r *Request) TunnelTLS(addr string) (*tls.Conn, error) {
+	conn, err := r.Tunnel(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, tunnelTLSConfig(r.client.TLSClientConfig(), host))
+	ctx := r.Context()
+	if _, found := ctx.Deadline(); !found && r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	if err = tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// tunnelConn struct is the tunneled connection; it reads the bytes buffered
+// while reading the CONNECT response first.
+type tunnelConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	if c.br != nil {
+		if c.br.Buffered() > 0 {
+			return c.br.Read(p)
+		}
+		c.br = nil
+	}
+	return c.Conn.Read(p)
+}
+
+func (r *Request) dialTunnel(ctx context.Context, transport *http.Transport, addr string) (net.Conn, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
+	}
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	var proxyURL *url.URL
+	if transport.Proxy != nil {
+		// the proxy is selected as for the HTTPS request to the address
+		req, err := http.NewRequestWithContext(
+			context.WithValue(ctx, requestContextKey{}, r),
+			http.MethodConnect, "https://"+addr, nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL, err = transport.Proxy(req); err != nil {
+			return nil, err
+		}
+	}
+	if proxyURL == nil {
+		return dial(ctx, "tcp", addr)
+	}
+
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+		d, err := proxy.SOCKS5("tcp", canonicalProxyAddr(proxyURL), auth, dialContextFunc(dial))
+		if err != nil {
+			return nil, err
+		}
+		return d.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+	}
+
+	conn, err := dial(ctx, "tcp", canonicalProxyAddr(proxyURL))
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(proxyURL.Scheme, "https") {
+		tlsConn := tls.Client(conn, tunnelTLSConfig(transport.TLSClientConfig, proxyURL.Hostname()))
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	tc, err := r.sendConnect(ctx, conn, transport, proxyURL, addr)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
+
+// sendConnect method sends the CONNECT request over the proxy connection and
+// reads the response.
+func (r *Request) sendConnect(ctx context.Context, conn net.Conn, transport *http.Transport,
+	proxyURL *url.URL, addr string) (net.Conn, error) {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for k, v := range transport.ProxyConnectHeader {
+		header[k] = v
+	}
+	if proxyURL.User != nil && header.Get(hdrProxyAuthorizationKey) == "" {
+		password, _ := proxyURL.User.Password()
+		auth := proxyURL.User.Username() + ":" + password
+		header.Set(hdrProxyAuthorizationKey, "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: header,
+	}
+
+	// close the connection on the context done to unblock the read and write
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	err := connectReq.Write(conn)
+	var res *http.Response
+	br := bufio.NewReader(conn)
+	if err == nil {
+		res, err = http.ReadResponse(br, connectReq)
+	}
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s", ErrTunnelRefused, res.Status)
+	}
+	return &tunnelConn{Conn: conn, br: br}, nil
+}
+
+func canonicalProxyAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch strings.ToLower(u.Scheme) {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func tunnelTLSConfig(cfg *tls.Config, serverName string) *tls.Config {
+	if cfg == nil {
+		return &tls.Config{ServerName: serverName}
+	}
+	cfg = cfg.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = serverName
+	}
+	return cfg
+}
+
+// Dial and DialContext methods implement the [proxy.Dialer] and [proxy.ContextDialer].
+func (fn dialContextFunc) Dial(network, addr string) (net.Conn, error) {
+	return fn(context.Background(), network, addr)
+}
+
+func (fn dialContextFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return fn(ctx, network, addr)
+}
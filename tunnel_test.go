@@ -0,0 +1,176 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createEchoTestServer function starts the TCP server, it echoes the
+// received bytes back.
+func createEchoTestServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// createConnectProxyTestServer function starts the HTTP proxy server, it
+// supports only the CONNECT method and requires the basic authorization.
+func createConnectProxyTestServer(t *testing.T) (*httptest.Server, *[]http.Header) {
+	var headers []http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get(hdrProxyAuthorizationKey) != "Basic dXNlcjpzZWNyZXQ=" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		conn, brw, _ := w.(http.Hijacker).Hijack()
+		defer conn.Close()
+		// the first bytes of the tunnel are sent with the response
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		go func() { _, _ = io.Copy(upstream, brw) }()
+		_, _ = io.Copy(conn, upstream)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &headers
+}
+
+func TestRequestTunnel(t *testing.T) {
+	echoAddr := createEchoTestServer(t)
+	proxy, headers := createConnectProxyTestServer(t)
+
+	c := dcnl().SetProxy(strings.Replace(proxy.URL, "http://", "http://user:secret@", 1))
+	conn, err := c.R().SetHeader("X-Purpose", "echo").Tunnel(echoAddr)
+	assertNil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping\n"))
+	assertNil(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assertNil(t, err)
+	assertEqual(t, "ping\n", line)
+	assertEqual(t, "echo", (*headers)[0].Get("X-Purpose"))
+
+	t.Run("refused", func(t *testing.T) {
+		c := dcnl().SetProxy(proxy.URL)
+		_, err := c.R().Tunnel(echoAddr)
+		assertErrorIs(t, ErrTunnelRefused, err)
+		assertEqual(t, "resty: proxy refused the tunnel: 407 Proxy Authentication Required", err.Error())
+	})
+
+	t.Run("direct", func(t *testing.T) {
+		c := dcnl().SetProxy(proxy.URL)
+		conn, err := c.R().DisableProxy().Tunnel(echoAddr)
+		assertNil(t, err)
+		defer conn.Close()
+		_, _ = conn.Write([]byte("direct\n"))
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		assertEqual(t, "direct\n", line)
+	})
+
+	t.Run("socks5", func(t *testing.T) {
+		addr, connects := createSOCKS5TestServer(t, "user", "secret")
+		c := dcnl().SetProxy("socks5://user:secret@" + addr)
+		conn, err := c.R().Tunnel(echoAddr)
+		assertNil(t, err)
+		defer conn.Close()
+		_, _ = conn.Write([]byte("socks\n"))
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		assertEqual(t, "socks\n", line)
+		assertEqual(t, int32(1), connects.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assertNil(t, err)
+		defer ln.Close()
+		go func() {
+			// accepts, but never responds
+			conn, err := ln.Accept()
+			if err == nil {
+				defer conn.Close()
+				_, _ = io.Copy(io.Discard, conn)
+			}
+		}()
+
+		c := dcnl().SetProxy("http://" + ln.Addr().String())
+		start := time.Now()
+		_, err = c.R().SetTimeout(50 * time.Millisecond).Tunnel(echoAddr)
+		assertErrorIs(t, context.DeadlineExceeded, err)
+		assertEqual(t, true, time.Since(start) < time.Second)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := dcnl().R().Tunnel("example.com")
+		assertNotNil(t, err)
+	})
+}
+
+func TestRequestTunnelTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "tunneled "+r.Proto)
+	}))
+	defer ts.Close()
+	proxy, _ := createConnectProxyTestServer(t)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	c := dcnl().
+		SetProxy(strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)).
+		SetTLSClientConfig(&tls.Config{RootCAs: pool})
+
+	conn, err := c.R().TunnelTLS(ts.Listener.Addr().String())
+	assertNil(t, err)
+	defer conn.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	assertNil(t, req.Write(conn))
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	assertNil(t, err)
+	body, _ := io.ReadAll(res.Body)
+	assertEqual(t, "tunneled HTTP/1.1", string(body))
+
+	// untrusted certificate
+	c.SetTLSClientConfig(&tls.Config{})
+	_, err = c.R().TunnelTLS(ts.Listener.Addr().String())
+	assertNotNil(t, err)
+}
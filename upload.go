@@ -0,0 +1,383 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// UploadProtocol type is to define the protocol of the chunked upload,
+// see [Client.Upload].
+type UploadProtocol uint8
+
+const (
+	// UploadContentRange protocol uploads the chunks using the `Content-Range`
+	// header on the same URL, e.g., Google Cloud Storage resumable upload.
+	// The server responds with `308 Resume Incomplete` until the last chunk.
+	UploadContentRange UploadProtocol = iota
+
+	// UploadTus protocol uploads the chunks using the tus resumable upload
+	// protocol v1.0.0, see https://tus.io/protocols/resumable-upload
+	UploadTus
+)
+
+const (
+	defaultUploadChunkSize = 8 << 20 // 8 MiB
+	tusVersion             = "1.0.0"
+)
+
+var (
+	hdrContentRangeKey   = http.CanonicalHeaderKey("Content-Range")
+	hdrRangeKey          = http.CanonicalHeaderKey("Range")
+	hdrTusResumableKey   = http.CanonicalHeaderKey("Tus-Resumable")
+	hdrUploadOffsetKey   = http.CanonicalHeaderKey("Upload-Offset")
+	hdrUploadLengthKey   = http.CanonicalHeaderKey("Upload-Length")
+	hdrUploadMetadataKey = http.CanonicalHeaderKey("Upload-Metadata")
+	rangeResponseRegexp  = regexp.MustCompile(`^bytes=0-(\d+)$`)
+)
+
+// UploadCheckpoint struct holds the upload state to resume from, it is
+// reported after every uploaded chunk, see [UploadOptions].OnCheckpoint
+type UploadCheckpoint struct {
+	// URL is the upload URL; for the tus protocol, it is the URL of the
+	// created upload resource.
+	URL string `json:"url"`
+
+	// Offset is the count of the bytes uploaded and acknowledged by the server.
+	Offset int64 `json:"offset"`
+
+	// Size is the total size of the upload content.
+	Size int64 `json:"size"`
+}
+
+// UploadOptions struct is used to configure the chunked upload,
+// see [Client.Upload].
+type UploadOptions struct {
+	// Protocol is the upload protocol; default is [UploadContentRange].
+	Protocol UploadProtocol
+
+	// ChunkSize is the size of the chunk in bytes; default is 8 MiB.
+	ChunkSize int64
+
+	// Method is the HTTP method for the [UploadContentRange] protocol;
+	// default is `PUT`.
+	Method string
+
+	// MaxChunkRetries is the count of the retries of a failed chunk,
+	// independent of the other chunks; default is 3.
+	MaxChunkRetries int
+
+	// RetryWaitTime and RetryMaxWaitTime are used to compute the capped
+	// exponential backoff with jitter between the chunk retries.
+	RetryWaitTime    time.Duration
+	RetryMaxWaitTime time.Duration
+
+	// Header is the additional headers of every upload request.
+	Header http.Header
+
+	// Context is the context of the upload requests; default is the client
+	// context.
+	Context context.Context
+
+	// Checkpoint is the saved upload state to resume from. The server is asked
+	// for the acknowledged offset before resuming.
+	Checkpoint *UploadCheckpoint
+
+	// OnCheckpoint is called after every uploaded chunk; persist the checkpoint
+	// to resume the interrupted upload later.
+	OnCheckpoint func(UploadCheckpoint)
+
+	// OnProgress is called after every uploaded chunk with the bytes
+	// acknowledged by the server and the total size.
+	OnProgress func(sent, total int64)
+}
+
+// Upload method uploads the given file to the URL in chunks using the
+// `Content-Range` header or the tus protocol, retries the failed chunks
+// independently, and resumes from the checkpoint. It returns the response of
+// the last upload request.
+//
+//	res, err := client.Upload("https://example.com/files", "/path/to/video.mp4", resty.UploadOptions{
+//		Protocol:  resty.UploadTus,
+//		ChunkSize: 16 << 20,
+//		OnCheckpoint: func(cp resty.UploadCheckpoint) {
+//			// persist the checkpoint to resume later
+//		},
+//	})
+//
+// See [Client.UploadReaderAt]
+func (c *Client) Upload(url, filePath string, opts UploadOptions) (*Response, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeq(f)
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Header == nil {
+		opts.Header = http.Header{}
+	}
+	if opts.Protocol == UploadTus && len(opts.Header.Get(hdrUploadMetadataKey)) == 0 {
+		opts.Header.Set(hdrUploadMetadataKey,
+			"filename "+base64.StdEncoding.EncodeToString([]byte(filepath.Base(filePath))))
+	}
+	return c.UploadReaderAt(url, f, fi.Size(), opts)
+}
+
+// UploadReaderAt method uploads the content of the given [io.ReaderAt] of the
+// given size to the URL in chunks, see [Client.Upload].
+func (c *Client) UploadReaderAt(url string, r io.ReaderAt, size int64, opts UploadOptions) (*Response, error) {
+	u := &uploader{c: c, r: r, opts: opts, cp: UploadCheckpoint{URL: url, Size: size}}
+	if u.opts.ChunkSize <= 0 {
+		u.opts.ChunkSize = defaultUploadChunkSize
+	}
+	if u.opts.MaxChunkRetries == 0 {
+		u.opts.MaxChunkRetries = 3
+	}
+	if isStringEmpty(u.opts.Method) {
+		u.opts.Method = MethodPut
+	}
+	return u.upload()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+type uploader struct {
+	c    *Client
+	r    io.ReaderAt
+	opts UploadOptions
+	cp   UploadCheckpoint
+}
+
+func (u *uploader) newRequest() *Request {
+	r := u.c.R()
+	if u.opts.Context != nil {
+		r.SetContext(u.opts.Context)
+	}
+	for k, v := range u.opts.Header {
+		r.Header[k] = v
+	}
+	if u.opts.Protocol == UploadTus {
+		r.SetHeader(hdrTusResumableKey, tusVersion)
+	}
+	return r
+}
+
+func (u *uploader) upload() (*Response, error) {
+	var res *Response
+	var err error
+
+	if cp := u.opts.Checkpoint; cp != nil && !isStringEmpty(cp.URL) {
+		u.cp.URL = cp.URL
+		if res, err = u.syncOffset(); err != nil {
+			return res, err
+		}
+	} else if u.opts.Protocol == UploadTus {
+		if res, err = u.createTus(); err != nil {
+			return res, err
+		}
+	}
+
+	for u.cp.Offset < u.cp.Size || (u.cp.Size == 0 && res == nil) {
+		if res, err = u.uploadChunkWithRetry(); err != nil {
+			return res, err
+		}
+		if u.opts.OnCheckpoint != nil {
+			u.opts.OnCheckpoint(u.cp)
+		}
+		if u.opts.OnProgress != nil {
+			u.opts.OnProgress(u.cp.Offset, u.cp.Size)
+		}
+	}
+	return res, nil
+}
+
+// createTus method creates the upload resource on the server.
+func (u *uploader) createTus() (*Response, error) {
+	res, err := u.newRequest().
+		SetHeader(hdrUploadLengthKey, strconv.FormatInt(u.cp.Size, 10)).
+		Post(u.cp.URL)
+	if err != nil {
+		return res, err
+	}
+	if res.StatusCode() != http.StatusCreated {
+		return res, fmt.Errorf("resty: upload create: unexpected status %s", res.Status())
+	}
+
+	loc, err := resolveLocation(u.cp.URL, res.Header().Get(hdrLocationKey))
+	if err != nil {
+		return res, err
+	}
+	u.cp.URL = loc
+	return res, nil
+}
+
+// syncOffset method asks the server for the acknowledged offset to resume.
+func (u *uploader) syncOffset() (*Response, error) {
+	if u.opts.Protocol == UploadTus {
+		res, err := u.newRequest().Head(u.cp.URL)
+		if err != nil {
+			return res, err
+		}
+		if !res.IsSuccess() {
+			return res, fmt.Errorf("resty: upload resume: unexpected status %s", res.Status())
+		}
+		offset, err := strconv.ParseInt(res.Header().Get(hdrUploadOffsetKey), 10, 64)
+		if err != nil {
+			return res, fmt.Errorf("resty: upload resume: invalid %s: %w", hdrUploadOffsetKey, err)
+		}
+		u.cp.Offset = offset
+		return res, nil
+	}
+
+	res, err := u.newRequest().
+		SetHeader(hdrContentRangeKey, "bytes */"+strconv.FormatInt(u.cp.Size, 10)).
+		Execute(u.opts.Method, u.cp.URL)
+	if err != nil {
+		return res, err
+	}
+	switch {
+	case res.IsSuccess(): // already completed
+		u.cp.Offset = u.cp.Size
+		return res, nil
+	case res.StatusCode() == http.StatusPermanentRedirect:
+		return res, u.parseRangeOffset(res)
+	}
+	return res, fmt.Errorf("resty: upload resume: unexpected status %s", res.Status())
+}
+
+func (u *uploader) parseRangeOffset(res *Response) error {
+	rng := res.Header().Get(hdrRangeKey)
+	if isStringEmpty(rng) {
+		u.cp.Offset = 0 // nothing is persisted yet
+		return nil
+	}
+	m := rangeResponseRegexp.FindStringSubmatch(rng)
+	if m == nil {
+		return fmt.Errorf("resty: upload: invalid %s: %s", hdrRangeKey, rng)
+	}
+	end, _ := strconv.ParseInt(m[1], 10, 64)
+	u.cp.Offset = end + 1
+	return nil
+}
+
+func (u *uploader) uploadChunkWithRetry() (*Response, error) {
+	backoff := u.c.newBackoffWithJitter(u.opts.RetryWaitTime, u.opts.RetryMaxWaitTime)
+	for attempt := 0; ; attempt++ {
+		res, err := u.uploadChunk()
+		if err == nil || attempt >= u.opts.MaxChunkRetries {
+			return res, err
+		}
+
+		waitTime, werr := backoff.NextWaitDuration(u.c, res, err, attempt)
+		if werr != nil {
+			return res, werr
+		}
+		select {
+		case <-time.After(waitTime):
+		case <-u.context().Done():
+			return res, u.context().Err()
+		}
+
+		// the chunk may be persisted partially, resync with the server;
+		// on failure, the chunk is retried from the current offset
+		_, _ = u.syncOffset()
+	}
+}
+
+func (u *uploader) context() context.Context {
+	if u.opts.Context != nil {
+		return u.opts.Context
+	}
+	if ctx := u.c.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func (u *uploader) uploadChunk() (*Response, error) {
+	start := u.cp.Offset
+	n := min(u.opts.ChunkSize, u.cp.Size-start)
+	chunk := make([]byte, n)
+	if _, err := u.r.ReadAt(chunk, start); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if u.opts.Protocol == UploadTus {
+		res, err := u.newRequest().
+			SetHeader(hdrUploadOffsetKey, strconv.FormatInt(start, 10)).
+			SetHeader(hdrContentTypeKey, "application/offset+octet-stream").
+			SetBody(chunk).
+			Patch(u.cp.URL)
+		if err != nil {
+			return res, err
+		}
+		if res.StatusCode() != http.StatusNoContent {
+			return res, fmt.Errorf("resty: upload chunk at offset %d: unexpected status %s", start, res.Status())
+		}
+		offset, err := strconv.ParseInt(res.Header().Get(hdrUploadOffsetKey), 10, 64)
+		if err != nil {
+			return res, fmt.Errorf("resty: upload chunk at offset %d: invalid %s: %w", start, hdrUploadOffsetKey, err)
+		}
+		u.cp.Offset = offset
+		return res, nil
+	}
+
+	contentRange := "bytes */0"
+	if n > 0 {
+		contentRange = fmt.Sprintf("bytes %d-%d/%d", start, start+n-1, u.cp.Size)
+	}
+	res, err := u.newRequest().
+		SetHeader(hdrContentRangeKey, contentRange).
+		SetBody(chunk).
+		Execute(u.opts.Method, u.cp.URL)
+	if err != nil {
+		return res, err
+	}
+	switch {
+	case res.IsSuccess():
+		u.cp.Offset = u.cp.Size
+		return res, nil
+	case res.StatusCode() == http.StatusPermanentRedirect: // resume incomplete
+		if isStringEmpty(res.Header().Get(hdrRangeKey)) {
+			u.cp.Offset = start + n
+			return res, nil
+		}
+		return res, u.parseRangeOffset(res)
+	}
+	return res, fmt.Errorf("resty: upload chunk at offset %d: unexpected status %s", start, res.Status())
+}
+
+func resolveLocation(base, loc string) (string, error) {
+	if isStringEmpty(loc) {
+		return "", fmt.Errorf("resty: upload create: missing %s header", hdrLocationKey)
+	}
+	bu, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	lu, err := url.Parse(loc)
+	if err != nil {
+		return "", err
+	}
+	return bu.ResolveReference(lu).String(), nil
+}
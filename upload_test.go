@@ -0,0 +1,209 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// contentRangeUploadServer mimics the resumable upload server using the
+// Content-Range header; it fails the given chunk offsets once.
+func contentRangeUploadServer(t *testing.T, failOnce map[int64]bool) (*bytes.Buffer, func() string) {
+	store := &bytes.Buffer{}
+	var lock sync.Mutex
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		cr := r.Header.Get(hdrContentRangeKey)
+		b, _ := io.ReadAll(r.Body)
+		var start, end, total int64
+		if strings.HasPrefix(cr, "bytes */") {
+			total, _ = strconv.ParseInt(strings.TrimPrefix(cr, "bytes */"), 10, 64)
+			if int64(store.Len()) == total {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		} else {
+			_, err := fmt.Sscanf(cr, "bytes %d-%d/%d", &start, &end, &total)
+			assertNil(t, err)
+			if failOnce[start] {
+				delete(failOnce, start)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			assertEqual(t, int64(store.Len()), start)
+			assertEqual(t, end-start+1, int64(len(b)))
+			store.Write(b)
+			if end+1 == total {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+		if store.Len() > 0 {
+			w.Header().Set(hdrRangeKey, fmt.Sprintf("bytes=0-%d", store.Len()-1))
+		}
+		w.WriteHeader(http.StatusPermanentRedirect)
+	})
+	t.Cleanup(ts.Close)
+	return store, func() string { return ts.URL }
+}
+
+func TestClientUploadContentRange(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 25)) // 250 bytes
+	store, url := contentRangeUploadServer(t, map[int64]bool{100: true})
+
+	var checkpoints []UploadCheckpoint
+	var progress []int64
+	c := dcnl()
+	res, err := c.UploadReaderAt(url(), bytes.NewReader(content), int64(len(content)), UploadOptions{
+		ChunkSize:     100,
+		RetryWaitTime: time.Millisecond,
+		OnCheckpoint:  func(cp UploadCheckpoint) { checkpoints = append(checkpoints, cp) },
+		OnProgress:    func(sent, total int64) { progress = append(progress, sent) },
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusCreated, res.StatusCode())
+	assertEqual(t, content, store.Bytes())
+	assertEqual(t, []int64{100, 200, 250}, progress)
+	assertEqual(t, UploadCheckpoint{URL: url(), Offset: 250, Size: 250}, checkpoints[2])
+}
+
+func TestClientUploadContentRangeResume(t *testing.T) {
+	content := []byte(strings.Repeat("abcdefghij", 30)) // 300 bytes
+	store, url := contentRangeUploadServer(t, map[int64]bool{})
+	store.Write(content[:120]) // persisted by the interrupted upload
+
+	var sent []int64
+	c := dcnl()
+	res, err := c.UploadReaderAt(url(), bytes.NewReader(content), int64(len(content)), UploadOptions{
+		ChunkSize:  100,
+		Checkpoint: &UploadCheckpoint{URL: url(), Offset: 100, Size: 300},
+		OnProgress: func(s, _ int64) { sent = append(sent, s) },
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusCreated, res.StatusCode())
+	assertEqual(t, content, store.Bytes())
+	assertEqual(t, []int64{220, 300}, sent) // resumed from the server offset
+
+	// already completed
+	res, err = c.UploadReaderAt(url(), bytes.NewReader(content), int64(len(content)), UploadOptions{
+		Checkpoint: &UploadCheckpoint{URL: url(), Offset: 300, Size: 300},
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusOK, res.StatusCode())
+}
+
+func TestClientUploadChunkRetriesExhausted(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer ts.Close()
+
+	c := dcnl()
+	res, err := c.UploadReaderAt(ts.URL, strings.NewReader("resty"), 5, UploadOptions{
+		MaxChunkRetries: 1,
+		RetryWaitTime:   time.Millisecond,
+	})
+	assertEqual(t, "resty: upload chunk at offset 0: unexpected status 400 Bad Request", err.Error())
+	assertEqual(t, http.StatusBadRequest, res.StatusCode())
+}
+
+func TestClientUploadTus(t *testing.T) {
+	var lock sync.Mutex
+	uploads := map[string]*bytes.Buffer{}
+	lengths := map[string]int64{}
+	failed := false
+	var metadata string
+
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.Header.Get(hdrTusResumableKey) != tusVersion {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set(hdrTusResumableKey, tusVersion)
+
+		switch r.Method {
+		case MethodPost:
+			id := "/files/" + strconv.Itoa(len(uploads)+1)
+			uploads[id] = &bytes.Buffer{}
+			lengths[id], _ = strconv.ParseInt(r.Header.Get(hdrUploadLengthKey), 10, 64)
+			metadata = r.Header.Get(hdrUploadMetadataKey)
+			w.Header().Set(hdrLocationKey, id)
+			w.WriteHeader(http.StatusCreated)
+		case MethodHead:
+			w.Header().Set(hdrUploadOffsetKey, strconv.Itoa(uploads[r.URL.Path].Len()))
+			w.Header().Set(hdrUploadLengthKey, strconv.FormatInt(lengths[r.URL.Path], 10))
+		case MethodPatch:
+			buf := uploads[r.URL.Path]
+			offset, _ := strconv.Atoi(r.Header.Get(hdrUploadOffsetKey))
+			if offset != buf.Len() || r.Header.Get(hdrContentTypeKey) != "application/offset+octet-stream" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			if offset == 4 && !failed {
+				// persist partially and fail
+				failed = true
+				buf.Write(b[:2])
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			buf.Write(b)
+			w.Header().Set(hdrUploadOffsetKey, strconv.Itoa(buf.Len()))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer ts.Close()
+
+	fp := filepath.Join(t.TempDir(), "video.bin")
+	assertNil(t, os.WriteFile(fp, []byte("0123456789"), 0o600))
+
+	var checkpoints []UploadCheckpoint
+	c := dcnl()
+	res, err := c.Upload(ts.URL+"/files", fp, UploadOptions{
+		Protocol:      UploadTus,
+		ChunkSize:     4,
+		RetryWaitTime: time.Millisecond,
+		OnCheckpoint:  func(cp UploadCheckpoint) { checkpoints = append(checkpoints, cp) },
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusNoContent, res.StatusCode())
+	assertEqual(t, "0123456789", uploads["/files/1"].String())
+	assertEqual(t, int64(10), lengths["/files/1"])
+	assertEqual(t, "filename dmlkZW8uYmlu", metadata)
+	assertEqual(t, []UploadCheckpoint{
+		{URL: ts.URL + "/files/1", Offset: 4, Size: 10},
+		{URL: ts.URL + "/files/1", Offset: 10, Size: 10}, // resynced to 6 after the partial chunk
+	}, checkpoints)
+
+	// resume from the checkpoint
+	uploads["/files/9"] = bytes.NewBufferString("01234")
+	lengths["/files/9"] = 10
+	res, err = c.Upload(ts.URL+"/files", fp, UploadOptions{
+		Protocol:   UploadTus,
+		Checkpoint: &UploadCheckpoint{URL: ts.URL + "/files/9"},
+	})
+	assertNil(t, err)
+	assertEqual(t, http.StatusNoContent, res.StatusCode())
+	assertEqual(t, "0123456789", uploads["/files/9"].String())
+
+	_, err = c.Upload(ts.URL+"/files", filepath.Join(t.TempDir(), "not-exists"), UploadOptions{})
+	assertNotNil(t, err)
+}
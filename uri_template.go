@@ -0,0 +1,378 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidURITemplate is returned when the URI template is malformed,
+// see [Request.SetURITemplate] and [ExpandURITemplate].
+var ErrInvalidURITemplate = errors.New("resty: invalid URI template")
+
+// SetURITemplate method sets the URI template ([RFC 6570]) as the request URL,
+// it is expanded with the variables set by [Request.SetURITemplateVar] and
+// [Request.SetURITemplateVars] while composing the request URL. All the
+// expression types of the level 4 are supported, i.e., the reserved,
+// fragment, label, path segment, path-style parameter, query, and query
+// continuation expansion; with the prefix and explode modifiers.
+//
+//	client.R().
+//		SetURITemplate("/repos/{owner}/{repo}/issues{?state,labels*}").
+//		SetURITemplateVars(map[string]any{
+//			"owner":  "go-resty",
+//			"repo":   "resty",
+//			"state":  "open",
+//			"labels": []string{"bug", "help wanted"},
+//		}).
+//		Get("")
+//
+//	Result:
+//	   Composed URL - /repos/go-resty/resty/issues?state=open&labels=bug&labels=help%20wanted
+//
+// The variable value can be a string, number, boolean, [fmt.Stringer], slice,
+// or map; the map keys are expanded in the sorted order. The nil value, empty
+// slice, and empty map are undefined and skipped, as per the RFC.
+//
+// The template is expanded on [Request.Send], or the HTTP verb method with the
+// empty URL, e.g., Get(""); the other URL given to the HTTP verb method is
+// used as-is instead of the template.
+//
+// NOTE: The [Request.SetPathParam] and [Client.SetPathParam] are not applied
+// on the URI template.
+//
+// [RFC 6570]: https://datatracker.ietf.org/doc/html/rfc6570
+func (r *Request) SetURITemplate(tmpl string) *Request {
+	r.URL = tmpl
+	r.uriTemplate = tmpl
+	return r
+}
+
+// SetURITemplateVar method sets a single URI template variable, see
+// [Request.SetURITemplate].
+//
+//	client.R().SetURITemplateVar("labels", []string{"bug", "help wanted"})
+func (r *Request) SetURITemplateVar(name string, value any) *Request {
+	if r.uriTemplateVars == nil {
+		r.uriTemplateVars = make(map[string]any)
+	}
+	r.uriTemplateVars[name] = value
+	return r
+}
+
+// SetURITemplateVars method sets multiple URI template variables at one go,
+// see [Request.SetURITemplate].
+func (r *Request) SetURITemplateVars(vars map[string]any) *Request {
+	for name, value := range vars {
+		r.SetURITemplateVar(name, value)
+	}
+	return r
+}
+
+// ExpandURITemplate function expands the given URI template ([RFC 6570]) with
+// the given variables, see [Request.SetURITemplate] for the supported values.
+//
+//	u, err := resty.ExpandURITemplate("/search{?q,lang}", map[string]any{
+//		"q":    "go resty",
+//		"lang": "en",
+//	})
+//	fmt.Println(u) // /search?q=go%20resty&lang=en
+//
+// [RFC 6570]: https://datatracker.ietf.org/doc/html/rfc6570
+func ExpandURITemplate(tmpl string, vars map[string]any) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(tmpl); {
+		start := strings.IndexByte(tmpl[i:], '{')
+		if start == -1 {
+			if err := writeURITemplateLiteral(&sb, tmpl[i:]); err != nil {
+				return "", err
+			}
+			break
+		}
+		start += i
+		if err := writeURITemplateLiteral(&sb, tmpl[i:start]); err != nil {
+			return "", err
+		}
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end == -1 {
+			return "", fmt.Errorf("%w: unclosed expression at offset %d", ErrInvalidURITemplate, start)
+		}
+		end += start
+		if err := expandURITemplateExpr(&sb, tmpl[start+1:end], vars); err != nil {
+			return "", err
+		}
+		i = end + 1
+	}
+	return sb.String(), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+// uriTemplateOp holds the expansion behavior of the expression operator,
+// see RFC 6570 Appendix A.
+type uriTemplateOp struct {
+	first    string
+	sep      string
+	named    bool
+	ifEmpty  string
+	reserved bool
+}
+
+var uriTemplateOps = map[byte]uriTemplateOp{
+	'+': {first: "", sep: ",", reserved: true},
+	'#': {first: "#", sep: ",", reserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+func expandURITemplateExpr(sb *strings.Builder, expr string, vars map[string]any) error {
+	if len(expr) == 0 {
+		return fmt.Errorf("%w: empty expression", ErrInvalidURITemplate)
+	}
+	op, found := uriTemplateOps[expr[0]]
+	if found {
+		expr = expr[1:]
+	} else {
+		op = uriTemplateOp{sep: ","}
+	}
+
+	first := true
+	for _, spec := range strings.Split(expr, ",") {
+		name, explode, prefix, err := parseURITemplateVarSpec(spec)
+		if err != nil {
+			return err
+		}
+		value, defined := uriTemplateValue(vars[name])
+		if !defined {
+			continue
+		}
+		if first {
+			sb.WriteString(op.first)
+			first = false
+		} else {
+			sb.WriteString(op.sep)
+		}
+
+		switch v := value.(type) {
+		case string:
+			if prefix > 0 {
+				v = truncateRunes(v, prefix)
+			}
+			if op.named {
+				sb.WriteString(name)
+				if len(v) == 0 {
+					sb.WriteString(op.ifEmpty)
+					continue
+				}
+				sb.WriteByte('=')
+			}
+			sb.WriteString(uriTemplateEscape(v, op.reserved))
+		case []string:
+			if prefix > 0 {
+				return fmt.Errorf("%w: prefix modifier on the list variable '%s'", ErrInvalidURITemplate, name)
+			}
+			if !explode {
+				if op.named {
+					sb.WriteString(name + "=")
+				}
+				for i, item := range v {
+					if i > 0 {
+						sb.WriteByte(',')
+					}
+					sb.WriteString(uriTemplateEscape(item, op.reserved))
+				}
+				continue
+			}
+			for i, item := range v {
+				if i > 0 {
+					sb.WriteString(op.sep)
+				}
+				if op.named {
+					sb.WriteString(name)
+					if len(item) == 0 {
+						sb.WriteString(op.ifEmpty)
+						continue
+					}
+					sb.WriteByte('=')
+				}
+				sb.WriteString(uriTemplateEscape(item, op.reserved))
+			}
+		case [][2]string:
+			if prefix > 0 {
+				return fmt.Errorf("%w: prefix modifier on the map variable '%s'", ErrInvalidURITemplate, name)
+			}
+			if !explode {
+				if op.named {
+					sb.WriteString(name + "=")
+				}
+				for i, kv := range v {
+					if i > 0 {
+						sb.WriteByte(',')
+					}
+					sb.WriteString(uriTemplateEscape(kv[0], op.reserved))
+					sb.WriteByte(',')
+					sb.WriteString(uriTemplateEscape(kv[1], op.reserved))
+				}
+				continue
+			}
+			for i, kv := range v {
+				if i > 0 {
+					sb.WriteString(op.sep)
+				}
+				sb.WriteString(uriTemplateEscape(kv[0], op.reserved))
+				if op.named && len(kv[1]) == 0 {
+					sb.WriteString(op.ifEmpty)
+					continue
+				}
+				sb.WriteByte('=')
+				sb.WriteString(uriTemplateEscape(kv[1], op.reserved))
+			}
+		}
+	}
+	return nil
+}
+
+func parseURITemplateVarSpec(spec string) (name string, explode bool, prefix int, err error) {
+	name = spec
+	if strings.HasSuffix(name, "*") {
+		name, explode = name[:len(name)-1], true
+	} else if idx := strings.IndexByte(name, ':'); idx != -1 {
+		prefix, err = strconv.Atoi(name[idx+1:])
+		if err != nil || prefix <= 0 || prefix > 9999 {
+			return "", false, 0, fmt.Errorf("%w: invalid prefix modifier '%s'", ErrInvalidURITemplate, spec)
+		}
+		name = name[:idx]
+	}
+	if !isURITemplateVarName(name) {
+		return "", false, 0, fmt.Errorf("%w: invalid variable name '%s'", ErrInvalidURITemplate, spec)
+	}
+	return name, explode, prefix, nil
+}
+
+func isURITemplateVarName(name string) bool {
+	if len(name) == 0 || name[0] == '.' || name[len(name)-1] == '.' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isAlphaNum(c), c == '_', c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// uriTemplateValue normalizes the variable value into a string, []string, or
+// sorted key-value pairs; it returns `false` if the value is undefined.
+func uriTemplateValue(v any) (any, bool) {
+	switch t := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return t, true
+	case fmt.Stringer:
+		return t.String(), true
+	case []string:
+		return t, len(t) > 0
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes()), true
+		}
+		items := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, fmt.Sprint(rv.Index(i).Interface()))
+		}
+		return items, len(items) > 0
+	case reflect.Map:
+		pairs := make([][2]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			pairs = append(pairs, [2]string{fmt.Sprint(iter.Key().Interface()), fmt.Sprint(iter.Value().Interface())})
+		}
+		slices.SortFunc(pairs, func(a, b [2]string) int { return strings.Compare(a[0], b[0]) })
+		return pairs, len(pairs) > 0
+	}
+	return fmt.Sprint(rv.Interface()), true
+}
+
+const uriTemplateReservedChars = ":/?#[]@!$&'()*+,;="
+
+// uriTemplateEscape percent-encodes the value, only the unreserved characters
+// are allowed; with `reserved`, the reserved characters and the existing
+// percent-encoded triplets are allowed as well.
+func uriTemplateEscape(s string, reserved bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isAlphaNum(c), c == '-', c == '.', c == '_', c == '~':
+			sb.WriteByte(c)
+		case reserved && strings.IndexByte(uriTemplateReservedChars, c) != -1:
+			sb.WriteByte(c)
+		case reserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			sb.WriteString(s[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func writeURITemplateLiteral(sb *strings.Builder, s string) error {
+	if strings.IndexByte(s, '}') != -1 {
+		return fmt.Errorf("%w: unexpected '}' in literal '%s'", ErrInvalidURITemplate, s)
+	}
+	sb.WriteString(uriTemplateEscape(s, true))
+	return nil
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for j := range s {
+		if i == n {
+			return s[:j]
+		}
+		i++
+	}
+	return s
+}
+
+func isAlphaNum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestExpandURITemplate(t *testing.T) {
+	// RFC 6570 section 3.2 examples, the map keys are expanded in the sorted order
+	vars := map[string]any{
+		"count":      []string{"one", "two", "three"},
+		"dom":        []string{"example", "com"},
+		"dub":        "me/too",
+		"hello":      "Hello World!",
+		"half":       "50%",
+		"var":        "value",
+		"who":        "fred",
+		"base":       "http://example.com/home/",
+		"path":       "/foo/bar",
+		"list":       []string{"red", "green", "blue"},
+		"keys":       map[string]string{"semi": ";", "dot": ".", "comma": ","},
+		"v":          "6",
+		"x":          1024,
+		"y":          768,
+		"empty":      "",
+		"empty_keys": map[string]string{},
+		"undef":      nil,
+	}
+
+	tests := []struct {
+		tmpl   string
+		expect string
+	}{
+		{"{count}", "one,two,three"},
+		{"{count*}", "one,two,three"},
+		{"{/count}", "/one,two,three"},
+		{"{/count*}", "/one/two/three"},
+		{"{;count}", ";count=one,two,three"},
+		{"{;count*}", ";count=one;count=two;count=three"},
+		{"{?count}", "?count=one,two,three"},
+		{"{?count*}", "?count=one&count=two&count=three"},
+		{"{&count*}", "&count=one&count=two&count=three"},
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"O{empty}X", "OX"},
+		{"O{undef}X", "OX"},
+		{"{x,y}", "1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"?{x,empty}", "?1024,"},
+		{"?{x,undef}", "?1024"},
+		{"?{undef,y}", "?768"},
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+half}", "50%25"},
+		{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"{+base}index", "http://example.com/home/index"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+keys*}", "comma=,,dot=.,semi=;"},
+		{"{#var}", "#value"},
+		{"{#hello}", "#Hello%20World!"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list*}", "#red,green,blue"},
+		{"X{.var}", "X.value"},
+		{"X{.x,y}", "X.1024.768"},
+		{"www{.dom*}", "www.example.com"},
+		{"X{.empty_keys}", "X"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys*}", "/comma=%2C/dot=./semi=%3B"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;keys*}", ";comma=%2C;dot=.;semi=%3B"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"{?var:3}", "?var=val"},
+		{"{?keys}", "?keys=comma,%2C,dot,.,semi,%3B"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&var:3}", "&var=val"},
+		{"{+dub}{?who}", "me/too?who=fred"},
+		{"/no/expression", "/no/expression"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			result, err := ExpandURITemplate(tc.tmpl, vars)
+			assertNil(t, err)
+			assertEqual(t, tc.expect, result)
+		})
+	}
+
+	for _, tmpl := range []string{
+		"/users/{id", "/users/id}", "/users/{}", "{var:0}", "{var:abc}",
+		"{va r}", "{.}", "{list:2}", "{keys:2}",
+	} {
+		t.Run("invalid "+tmpl, func(t *testing.T) {
+			_, err := ExpandURITemplate(tmpl, vars)
+			assertErrorIs(t, ErrInvalidURITemplate, err)
+		})
+	}
+}
+
+func TestRequestSetURITemplate(t *testing.T) {
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	})
+	defer ts.Close()
+
+	c := dcnl().SetBaseURL(ts.URL).SetPathParam("repo", "not-used")
+	defer c.Close()
+
+	req := c.R().
+		SetURITemplate("/repos/{owner}/{repo}/issues{?state,labels*}").
+		SetURITemplateVars(map[string]any{
+			"owner":  "go-resty",
+			"repo":   "resty",
+			"labels": []string{"bug", "help wanted"},
+		})
+
+	res, err := req.Clone(context.Background()).SetURITemplateVar("state", "open").Get("")
+	assertNil(t, err)
+	assertEqual(t, "/repos/go-resty/resty/issues?state=open&labels=bug&labels=help%20wanted", res.String())
+
+	res, err = req.SetURITemplateVar("labels", nil).Get("")
+	assertNil(t, err)
+	assertEqual(t, "/repos/go-resty/resty/issues", res.String())
+
+	_, err = c.R().SetURITemplate("/repos/{owner").Get("")
+	assertErrorIs(t, ErrInvalidURITemplate, err)
+}
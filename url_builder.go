@@ -0,0 +1,259 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrInvalidURL error is returned by [URL.Build] when the built URL cannot
+	// be parsed.
+	ErrInvalidURL = errors.New("resty: invalid URL")
+
+	// ErrMissingURLScheme error is returned by [URL.Build] when the built URL
+	// has no scheme, e.g., `example.com/users`.
+	ErrMissingURLScheme = errors.New("resty: URL scheme is missing")
+
+	// ErrMissingURLHost error is returned by [URL.Build] when the built URL has
+	// no host, e.g., `https:///users`.
+	ErrMissingURLHost = errors.New("resty: URL host is missing")
+
+	// ErrUnresolvedPathParam error is returned by [URL.Build] when the built URL
+	// has the path param placeholder without the value, e.g., `{userId}`.
+	ErrUnresolvedPathParam = errors.New("resty: unresolved path param")
+)
+
+// URL type is the builder of the absolute request URL; it joins the paths
+// safely, expands the path params, and sets the query params and fragment. The
+// final URL is validated by [URL.Build], so the mistakes are caught with the
+// descriptive error before the request is sent.
+//
+//	u, err := resty.NewURL("https://api.example.com/v1/").
+//		JoinPath("/users/", "{userId}", "orders").
+//		SetPathParam("userId", "sample@sample.com").
+//		SetQueryParam("status", "open").
+//		Build()
+//	if err != nil {
+//		return err
+//	}
+//	// https://api.example.com/v1/users/sample@sample.com/orders?status=open
+//
+//	res, err := client.R().Get(u)
+type URL struct {
+	base       string
+	elems      []string
+	pathParams map[string]string
+	query      url.Values
+	fragment   *string
+}
+
+// NewURL function creates the [URL] builder from the given base URL, it may
+// have the path, query string, fragment, and path param placeholders.
+func NewURL(base string) *URL {
+	return &URL{
+		base:       base,
+		pathParams: make(map[string]string),
+		query:      make(url.Values),
+	}
+}
+
+// JoinPath method appends the given path elements to the URL path. The
+// duplicate slashes between the elements are collapsed, and the trailing
+// slash of the last element is kept. The elements may have the path param
+// placeholders, see [URL.SetPathParam].
+//
+//	resty.NewURL("https://example.com/api/").JoinPath("/v1", "users/") // https://example.com/api/v1/users/
+func (u *URL) JoinPath(elem ...string) *URL {
+	u.elems = append(u.elems, elem...)
+	return u
+}
+
+// SetPathParam method sets the value of the path param placeholder, the value
+// is escaped using [url.PathEscape].
+func (u *URL) SetPathParam(param, value string) *URL {
+	u.pathParams[param] = url.PathEscape(value)
+	return u
+}
+
+// SetRawPathParam method sets the value of the path param placeholder as-is,
+// i.e., the value is not escaped.
+func (u *URL) SetRawPathParam(param, value string) *URL {
+	u.pathParams[param] = value
+	return u
+}
+
+// SetQueryParam method sets the query param, it replaces the existing values
+// of the param; the query string of the base URL is kept as-is.
+func (u *URL) SetQueryParam(param, value string) *URL {
+	u.query.Set(param, value)
+	return u
+}
+
+// AddQueryParam method appends the value to the query param.
+func (u *URL) AddQueryParam(param, value string) *URL {
+	u.query.Add(param, value)
+	return u
+}
+
+// SetQueryParams method sets multiple query params at one go, see
+// [URL.SetQueryParam].
+func (u *URL) SetQueryParams(params map[string]string) *URL {
+	for p, v := range params {
+		u.SetQueryParam(p, v)
+	}
+	return u
+}
+
+// SetFragment method sets the URL fragment, it replaces the fragment of the
+// base URL. The fragment is not sent to the server; it is useful for the
+// URLs built for other purposes, e.g., the redirect URLs.
+func (u *URL) SetFragment(fragment string) *URL {
+	u.fragment = &fragment
+	return u
+}
+
+// Build method returns the built URL after validating it. The error wraps one
+// of [ErrUnresolvedPathParam], [ErrInvalidURL], [ErrMissingURLScheme], or
+// [ErrMissingURLHost].
+func (u *URL) Build() (string, error) {
+	s, fragment, hasFragment := strings.Cut(u.base, "#")
+	s, query, _ := strings.Cut(s, "?")
+
+	s = joinURLPath(s, u.elems...)
+	s = expandPathParams(s, u.pathParams)
+	if param := findPathParamPlaceholder(s); len(param) > 0 {
+		return "", fmt.Errorf("%w '%s' in %s", ErrUnresolvedPathParam, param, s)
+	}
+
+	if q := u.query.Encode(); len(q) > 0 {
+		if len(query) > 0 {
+			query += "&"
+		}
+		query += q
+	}
+	if len(query) > 0 {
+		s += "?" + query
+	}
+
+	if u.fragment != nil {
+		fragment = (&url.URL{Fragment: *u.fragment}).EscapedFragment()
+		hasFragment = len(fragment) > 0
+	}
+	if hasFragment {
+		s += "#" + fragment
+	}
+
+	pu, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if len(pu.Scheme) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingURLScheme, s)
+	}
+	if len(pu.Host) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingURLHost, s)
+	}
+	return s, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//_______________________________________________________________________
+
+func joinURLPath(base string, elem ...string) string {
+	var sb strings.Builder
+	sb.WriteString(base)
+	trailingSlash := strings.HasSuffix(base, "/")
+	for _, e := range elem {
+		e = strings.Trim(e, "/")
+		if len(e) == 0 {
+			continue
+		}
+		if !trailingSlash {
+			sb.WriteByte('/')
+		}
+		sb.WriteString(e)
+		trailingSlash = false
+	}
+	if len(elem) > 0 && strings.HasSuffix(elem[len(elem)-1], "/") && !trailingSlash {
+		sb.WriteByte('/')
+	}
+	return sb.String()
+}
+
+// expandPathParams function replaces the path param placeholders, i.e.,
+// `{name}`, with the given values; the placeholders without the value are
+// kept as-is.
+func expandPathParams(s string, params map[string]string) string {
+	var prev int
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	// search for the next or first opened curly bracket
+	for curr := strings.Index(s, "{"); curr == 0 || curr > prev; curr = prev + strings.Index(s[prev:], "{") {
+		// write everything from the previous position up to the current
+		if curr > prev {
+			buf.WriteString(s[prev:curr])
+		}
+		// search for the closed curly bracket from current position
+		next := curr + strings.Index(s[curr:], "}")
+		// if not found, then write the remainder and exit
+		if next < curr {
+			buf.WriteString(s[curr:])
+			prev = len(s)
+			break
+		}
+		// special case for {}, without parameter's name
+		if next == curr+1 {
+			buf.WriteString("{}")
+		} else {
+			// check for the replacement
+			key := s[curr+1 : next]
+			value, ok := params[key]
+			// keep the original string if the replacement not found
+			if !ok {
+				value = s[curr : next+1]
+			}
+			buf.WriteString(value)
+		}
+
+		// set the previous position after the closed curly bracket
+		prev = next + 1
+		if prev >= len(s) {
+			break
+		}
+	}
+	if buf.Len() == 0 {
+		return s
+	}
+	// write remainder
+	if prev < len(s) {
+		buf.WriteString(s[prev:])
+	}
+	return buf.String()
+}
+
+// findPathParamPlaceholder function returns the name of the first path param
+// placeholder in the given string, otherwise empty.
+func findPathParamPlaceholder(s string) string {
+	for {
+		curr := strings.Index(s, "{")
+		if curr < 0 {
+			return ""
+		}
+		next := strings.Index(s[curr:], "}")
+		if next < 0 {
+			return ""
+		}
+		if next > 1 {
+			return s[curr+1 : curr+next]
+		}
+		s = s[curr+next+1:]
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"testing"
+)
+
+func TestURLBuilder(t *testing.T) {
+	u, err := NewURL("https://example.com/api/").
+		JoinPath("/v1/", "//users", "{userId}", "orders/").
+		SetPathParam("userId", "a b/c").
+		SetQueryParam("status", "open").
+		AddQueryParam("tag", "x").
+		AddQueryParam("tag", "y").
+		SetFragment("top section").
+		Build()
+	assertError(t, err)
+	assertEqual(t, "https://example.com/api/v1/users/a%20b%2Fc/orders/?status=open&tag=x&tag=y#top%20section", u)
+
+	t.Run("base query and fragment kept", func(t *testing.T) {
+		u, err := NewURL("https://example.com/{version}?key=abc#frag").
+			SetRawPathParam("version", "v1/beta").
+			SetQueryParams(map[string]string{"page": "2"}).
+			Build()
+		assertError(t, err)
+		assertEqual(t, "https://example.com/v1/beta?key=abc&page=2#frag", u)
+	})
+
+	t.Run("unresolved path param", func(t *testing.T) {
+		_, err := NewURL("https://example.com").JoinPath("users", "{userId}").Build()
+		assertErrorIs(t, ErrUnresolvedPathParam, err)
+		assertEqual(t, "resty: unresolved path param 'userId' in https://example.com/users/{userId}", err.Error())
+	})
+
+	t.Run("missing scheme", func(t *testing.T) {
+		_, err := NewURL("example.com").JoinPath("users").Build()
+		assertErrorIs(t, ErrMissingURLScheme, err)
+	})
+
+	t.Run("missing host", func(t *testing.T) {
+		_, err := NewURL("https:///users").Build()
+		assertErrorIs(t, ErrMissingURLHost, err)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := NewURL("https://example.com/%zz").Build()
+		assertErrorIs(t, ErrInvalidURL, err)
+	})
+}
+
+func TestExpandPathParams(t *testing.T) {
+	params := map[string]string{"a": "1", "b": "2"}
+	assertEqual(t, "/1/2", expandPathParams("/{a}/{b}", params))
+	assertEqual(t, "/1/{c}/{}", expandPathParams("/{a}/{c}/{}", params))
+	assertEqual(t, "/1/{b", expandPathParams("/{a}/{b", params))
+	assertEqual(t, "/users", expandPathParams("/users", params))
+}
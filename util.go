@@ -15,11 +15,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -69,6 +72,31 @@ func (l *logger) output(format string, v ...any) {
 	l.l.Printf(format, v...)
 }
 
+// NewSlogLogger method creates a [Logger] backed by the given [slog.Logger].
+// With it, Resty emits the request and response debug log as structured
+// attributes instead of a formatted text, see [Client.SetSlogLogger].
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+var _ Logger = (*slogLogger)(nil)
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (l *slogLogger) Errorf(format string, v ...any) {
+	l.l.Error(fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) Warnf(format string, v ...any) {
+	l.l.Warn(fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) Debugf(format string, v ...any) {
+	l.l.Debug(fmt.Sprintf(format, v...))
+}
+
 // credentials type is to hold an username and password information
 type credentials struct {
 	Username, Password string
@@ -97,6 +125,10 @@ func isStringEmpty(str string) bool {
 
 // detectContentType method is used to figure out `Request.Body` content type for request header
 func detectContentType(body any) string {
+	if isProtoMessage(body) {
+		return protobufContentType
+	}
+
 	contentType := plainTextType
 	kind := inferKind(body)
 	switch kind {
@@ -123,11 +155,39 @@ func isXMLContentType(ct string) bool {
 	return strings.Contains(ct, xmlKey)
 }
 
+func isMsgpackContentType(ct string) bool {
+	return strings.Contains(ct, msgpackKey)
+}
+
+func isProtobufContentType(ct string) bool {
+	return strings.Contains(ct, protobufKey)
+}
+
+func isCBORContentType(ct string) bool {
+	return strings.Contains(ct, cborKey)
+}
+
+// isProtoMessage function reports whether the given value implements the
+// `proto.Message` interface, without depending on the protobuf package.
+func isProtoMessage(v any) bool {
+	if v == nil {
+		return false
+	}
+	m, found := reflect.TypeOf(v).MethodByName("ProtoReflect")
+	return found && m.Type.NumIn() == 1 && m.Type.NumOut() == 1
+}
+
 func inferContentTypeMapKey(v string) string {
 	if isJSONContentType(v) {
 		return jsonKey
 	} else if isXMLContentType(v) {
 		return xmlKey
+	} else if isMsgpackContentType(v) {
+		return msgpackKey
+	} else if isProtobufContentType(v) {
+		return protobufKey
+	} else if isCBORContentType(v) {
+		return cborKey
 	}
 	return ""
 }
@@ -142,11 +202,51 @@ func firstNonEmpty(v ...string) string {
 }
 
 var (
-	mkdirAll   = os.MkdirAll
-	createFile = os.Create
-	ioCopy     = io.Copy
+	mkdirAll       = os.MkdirAll
+	createTempFile = os.CreateTemp
+	ioCopy         = io.Copy
 )
 
+// writeFileAtomic writes the content into a temp file in the same directory and
+// renames it to the given file on success, so an interrupted write never leaves
+// a truncated file at the destination. The temp file is removed on failure.
+func writeFileAtomic(file string, r io.Reader, sync bool) (n int64, err error) {
+	f, err := createTempFile(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			closeq(f)
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	// keep the mode of the existing file, the temp file is created with 0600
+	mode := os.FileMode(0o644)
+	if fi, serr := os.Stat(file); serr == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err = f.Chmod(mode); err != nil {
+		return
+	}
+
+	// io.Copy reads maximum 32kb size, it is perfect for large file download too
+	if n, err = ioCopy(f, r); err != nil {
+		return
+	}
+	if sync {
+		if err = f.Sync(); err != nil {
+			return
+		}
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	err = os.Rename(f.Name(), file)
+	return
+}
+
 func createDirectory(dir string) (err error) {
 	if _, err = os.Stat(dir); err != nil {
 		if os.IsNotExist(err) {
@@ -330,6 +430,21 @@ func drainBody(res *Response) {
 	}
 }
 
+// bufferRequestBody function reads the request body and replaces it with
+// the buffered one, so the transport wrappers can inspect it.
+func bufferRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
 func toJSON(v any) string {
 	buf := acquireBuffer()
 	defer releaseBuffer(buf)
@@ -420,3 +535,80 @@ func readMachineID() []byte {
 	// This panic would happen at program startup, so no worries at runtime panic.
 	panic(errors.New("resty - guid: unable to get hostname and random bytes"))
 }
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields function returns the exported fields of the struct type, the
+// field name is taken from the first non-empty tag of the given tag names,
+// falling back to the field name. The embedded struct fields are flattened.
+func structFields(t reflect.Type, tagNames ...string) []structField {
+	fields := make([]structField, 0, t.NumField())
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		var tag string
+		for _, tn := range tagNames {
+			if tag = sf.Tag.Get(tn); len(tag) > 0 {
+				break
+			}
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// flatten the embedded struct fields
+		if sf.Anonymous && len(name) == 0 && sf.Type.Kind() == reflect.Struct {
+			for _, f := range structFields(sf.Type, tagNames...) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = sf.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	return fields
+}
+
+// fieldByName function finds the field by the exact name, falling back
+// to the case-insensitive match.
+func fieldByName(fields []structField, name string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
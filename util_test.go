@@ -199,3 +199,10 @@ func TestUtilMiscTestCoverage(t *testing.T) {
 	ireErr := &invalidRequestError{Err: errors.New("test coverage")}
 	assertEqual(t, "test coverage", ireErr.Error())
 }
+
+func TestIsProtoMessage(t *testing.T) {
+	assertEqual(t, true, isProtoMessage(&testProtoMessage{}))
+	assertEqual(t, false, isProtoMessage(testProtoMessage{}))
+	assertEqual(t, false, isProtoMessage(nil))
+	assertEqual(t, false, isProtoMessage(map[string]string{}))
+}
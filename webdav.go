@@ -0,0 +1,326 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebDAV HTTP methods, see [RFC 4918].
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918
+const (
+	// MethodPropFind WebDAV method
+	MethodPropFind = "PROPFIND"
+
+	// MethodPropPatch WebDAV method
+	MethodPropPatch = "PROPPATCH"
+
+	// MethodMkcol WebDAV method
+	MethodMkcol = "MKCOL"
+
+	// MethodCopy WebDAV method
+	MethodCopy = "COPY"
+
+	// MethodMove WebDAV method
+	MethodMove = "MOVE"
+
+	// MethodLock WebDAV method
+	MethodLock = "LOCK"
+
+	// MethodUnlock WebDAV method
+	MethodUnlock = "UNLOCK"
+)
+
+// WebDAV `Depth` header values, see [Request.SetDepth].
+const (
+	DepthZero     = "0"
+	DepthOne      = "1"
+	DepthInfinity = "infinity"
+)
+
+var (
+	hdrDepthKey       = http.CanonicalHeaderKey("Depth")
+	hdrDestinationKey = http.CanonicalHeaderKey("Destination")
+	hdrOverwriteKey   = http.CanonicalHeaderKey("Overwrite")
+	hdrIfKey          = http.CanonicalHeaderKey("If")
+	hdrLockTokenKey   = http.CanonicalHeaderKey("Lock-Token")
+	hdrTimeoutKey     = http.CanonicalHeaderKey("Timeout")
+)
+
+// MultiStatus struct is the WebDAV multi-status (207) response body,
+// see section 13 of [RFC 4918].
+//
+//	res, err := client.R().
+//		SetDepth(resty.DepthOne).
+//		SetResult(&resty.MultiStatus{}).
+//		PropFind("https://example.com/remote.php/dav/files/user/")
+//
+//	for _, r := range res.Result().(*resty.MultiStatus).Responses {
+//		fmt.Println(r.Href, r.Prop().DisplayName, r.Prop().IsCollection())
+//	}
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-13
+type MultiStatus struct {
+	XMLName             xml.Name              `xml:"DAV: multistatus"`
+	Responses           []MultiStatusResponse `xml:"DAV: response"`
+	ResponseDescription string                `xml:"DAV: responsedescription"`
+	SyncToken           string                `xml:"DAV: sync-token"`
+}
+
+// MultiStatusResponse struct is the status of the resource(s) in the
+// [MultiStatus] response.
+type MultiStatusResponse struct {
+	Href                []string   `xml:"DAV: href"`
+	Status              string     `xml:"DAV: status"`
+	PropStats           []PropStat `xml:"DAV: propstat"`
+	ResponseDescription string     `xml:"DAV: responsedescription"`
+	Location            string     `xml:"DAV: location>href"`
+}
+
+// StatusCode method returns the HTTP status code of the response; it is
+// the status of the first successful property status if the response
+// status is absent, e.g., PROPFIND.
+func (r *MultiStatusResponse) StatusCode() int {
+	if len(r.Status) > 0 {
+		return parseDAVStatus(r.Status)
+	}
+	if ps := r.propStat(); ps != nil {
+		return ps.StatusCode()
+	}
+	return 0
+}
+
+// Prop method returns the properties of the successful property status;
+// it is empty if none.
+func (r *MultiStatusResponse) Prop() *DAVProp {
+	if ps := r.propStat(); ps != nil && ps.StatusCode() < 300 {
+		return &ps.Prop
+	}
+	return &DAVProp{}
+}
+
+func (r *MultiStatusResponse) propStat() *PropStat {
+	for i := range r.PropStats {
+		if c := r.PropStats[i].StatusCode(); c > 199 && c < 300 {
+			return &r.PropStats[i]
+		}
+	}
+	if len(r.PropStats) > 0 {
+		return &r.PropStats[0]
+	}
+	return nil
+}
+
+// PropStat struct is the properties and their status in the [MultiStatusResponse].
+type PropStat struct {
+	Prop                DAVProp `xml:"DAV: prop"`
+	Status              string  `xml:"DAV: status"`
+	ResponseDescription string  `xml:"DAV: responsedescription"`
+}
+
+// StatusCode method returns the HTTP status code of the property status.
+func (p *PropStat) StatusCode() int {
+	return parseDAVStatus(p.Status)
+}
+
+// DAVProp struct is the WebDAV live properties, see section 15 of [RFC 4918];
+// the other properties, e.g., Nextcloud `oc:fileid`, can be decoded from
+// the Raw XML.
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-15
+type DAVProp struct {
+	DisplayName     string `xml:"DAV: displayname"`
+	ContentLength   int64  `xml:"DAV: getcontentlength"`
+	ContentType     string `xml:"DAV: getcontenttype"`
+	ContentLanguage string `xml:"DAV: getcontentlanguage"`
+	ETag            string `xml:"DAV: getetag"`
+	LastModified    string `xml:"DAV: getlastmodified"`
+	CreationDate    string `xml:"DAV: creationdate"`
+	ResourceType    struct {
+		Collection *struct{} `xml:"DAV: collection"`
+	} `xml:"DAV: resourcetype"`
+
+	// Raw is the inner XML of the `prop` element
+	Raw string `xml:",innerxml"`
+}
+
+// IsCollection method returns true if the resource is a collection, i.e., directory.
+func (p *DAVProp) IsCollection() bool {
+	return p.ResourceType.Collection != nil
+}
+
+// LastModifiedTime method parses the `getlastmodified` property value.
+func (p *DAVProp) LastModifiedTime() (time.Time, error) {
+	return http.ParseTime(p.LastModified)
+}
+
+// ErrNotMultiStatus is returned by [Response.MultiStatus] if the response
+// status code is not 207 Multi-Status.
+var ErrNotMultiStatus = errors.New("resty: response is not multi-status")
+
+// MultiStatus method returns the parsed WebDAV multi-status (207) response;
+// it is the result, if [MultiStatus] is set via [Request.SetResult], otherwise,
+// the response body is parsed.
+//
+//	res, err := client.R().
+//		SetDestination("https://example.com/dav/archive/").
+//		Copy("https://example.com/dav/docs/")
+//
+//	ms, err := res.MultiStatus()
+//
+// NOTE: The body is not available to parse on auto-unmarshal scenarios, unless
+// [Request.SetResponseBodyUnlimitedReads] is set.
+func (r *Response) MultiStatus() (*MultiStatus, error) {
+	if r.StatusCode() != http.StatusMultiStatus {
+		return nil, ErrNotMultiStatus
+	}
+	if ms, ok := r.Result().(*MultiStatus); ok {
+		return ms, nil
+	}
+	ms := &MultiStatus{}
+	if err := xml.Unmarshal(r.Bytes(), ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// PropFind method does PROPFIND WebDAV request. It's defined in section 9.1 of [RFC 4918].
+//
+// See [Request.SetDepth], [MultiStatus]
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.1
+func (r *Request) PropFind(url string) (*Response, error) {
+	return r.Execute(MethodPropFind, url)
+}
+
+// PropPatch method does PROPPATCH WebDAV request. It's defined in section 9.2 of [RFC 4918].
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.2
+func (r *Request) PropPatch(url string) (*Response, error) {
+	return r.Execute(MethodPropPatch, url)
+}
+
+// Mkcol method does MKCOL WebDAV request. It's defined in section 9.3 of [RFC 4918].
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.3
+func (r *Request) Mkcol(url string) (*Response, error) {
+	return r.Execute(MethodMkcol, url)
+}
+
+// Copy method does COPY WebDAV request. It's defined in section 9.8 of [RFC 4918].
+//
+// See [Request.SetDestination], [Request.SetOverwrite]
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.8
+func (r *Request) Copy(url string) (*Response, error) {
+	return r.Execute(MethodCopy, url)
+}
+
+// Move method does MOVE WebDAV request. It's defined in section 9.9 of [RFC 4918].
+//
+// See [Request.SetDestination], [Request.SetOverwrite]
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.9
+func (r *Request) Move(url string) (*Response, error) {
+	return r.Execute(MethodMove, url)
+}
+
+// Lock method does LOCK WebDAV request. It's defined in section 9.10 of [RFC 4918].
+// The lock token is returned in the `Lock-Token` response header.
+//
+// See [Request.SetLockTimeout]
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.10
+func (r *Request) Lock(url string) (*Response, error) {
+	return r.Execute(MethodLock, url)
+}
+
+// Unlock method does UNLOCK WebDAV request with the given lock token. It's
+// defined in section 9.11 of [RFC 4918].
+//
+// [RFC 4918]: https://datatracker.ietf.org/doc/html/rfc4918#section-9.11
+func (r *Request) Unlock(url, lockToken string) (*Response, error) {
+	r.Header.Set(hdrLockTokenKey, "<"+strings.Trim(lockToken, "<>")+">")
+	return r.Execute(MethodUnlock, url)
+}
+
+// SetDepth method sets the WebDAV `Depth` header, i.e., [DepthZero], [DepthOne],
+// or [DepthInfinity].
+//
+//	client.R().SetDepth(resty.DepthOne)
+func (r *Request) SetDepth(depth string) *Request {
+	r.Header.Set(hdrDepthKey, depth)
+	return r
+}
+
+// SetDestination method sets the WebDAV `Destination` header for the COPY and
+// MOVE requests.
+//
+//	client.R().SetDestination("https://example.com/dav/archive/report.pdf")
+func (r *Request) SetDestination(destination string) *Request {
+	r.Header.Set(hdrDestinationKey, destination)
+	return r
+}
+
+// SetOverwrite method sets the WebDAV `Overwrite` header for the COPY and
+// MOVE requests.
+//
+//	client.R().SetOverwrite(false)
+func (r *Request) SetOverwrite(overwrite bool) *Request {
+	v := "F"
+	if overwrite {
+		v = "T"
+	}
+	r.Header.Set(hdrOverwriteKey, v)
+	return r
+}
+
+// SetLockToken method sets the WebDAV `If` header with the given lock token
+// to modify the locked resource.
+//
+//	client.R().SetLockToken("opaquelocktoken:e71d4fae-5dec-22d6-fea5-00a0c91e6be4")
+func (r *Request) SetLockToken(lockToken string) *Request {
+	r.Header.Set(hdrIfKey, "(<"+strings.Trim(lockToken, "<>")+">)")
+	return r
+}
+
+// SetLockTimeout method sets the WebDAV `Timeout` header for the LOCK
+// request; zero or a negative value denotes infinite.
+//
+//	client.R().SetLockTimeout(10 * time.Minute)
+func (r *Request) SetLockTimeout(timeout time.Duration) *Request {
+	v := "Infinite"
+	if timeout > 0 {
+		v = "Second-" + strconv.FormatInt(int64(timeout/time.Second), 10)
+	}
+	r.Header.Set(hdrTimeoutKey, v)
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//_______________________________________________________________________
+
+// parseDAVStatus function parses the status code from the status line,
+// e.g., `HTTP/1.1 200 OK`.
+func parseDAVStatus(status string) int {
+	parts := strings.Fields(status)
+	if len(parts) < 2 {
+		return 0
+	}
+	code, _ := strconv.Atoi(parts[1])
+	return code
+}
+
+func isWebDAVPayloadMethod(method string) bool {
+	return method == MethodPropFind || method == MethodPropPatch || method == MethodLock
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Jeevanandam M (jeeva@myjeeva.com), All rights reserved.
+// resty source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+// SPDX-License-Identifier: MIT
+
+package resty
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const webDAVTestMultiStatus = `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+	<d:response>
+		<d:href>/dav/files/user/</d:href>
+		<d:propstat>
+			<d:prop><d:resourcetype><d:collection/></d:resourcetype><d:displayname>user</d:displayname></d:prop>
+			<d:status>HTTP/1.1 200 OK</d:status>
+		</d:propstat>
+	</d:response>
+	<d:response>
+		<d:href>/dav/files/user/report.pdf</d:href>
+		<d:propstat>
+			<d:prop><oc:fileid>42</oc:fileid></d:prop>
+			<d:status>HTTP/1.1 404 Not Found</d:status>
+		</d:propstat>
+		<d:propstat>
+			<d:prop>
+				<d:resourcetype/>
+				<d:getcontentlength>1024</d:getcontentlength>
+				<d:getcontenttype>application/pdf</d:getcontenttype>
+				<d:getetag>"abc"</d:getetag>
+				<d:getlastmodified>Thu, 02 Jan 2025 03:04:05 GMT</d:getlastmodified>
+			</d:prop>
+			<d:status>HTTP/1.1 200 OK</d:status>
+		</d:propstat>
+	</d:response>
+	<d:response>
+		<d:href>/dav/files/user/locked</d:href>
+		<d:status>HTTP/1.1 423 Locked</d:status>
+	</d:response>
+</d:multistatus>`
+
+func createWebDAVTestServer(t *testing.T) (string, *http.Request, *string) {
+	var (
+		lastReq  = &http.Request{}
+		lastBody = new(string)
+	)
+	ts := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*lastReq = *r
+		*lastBody = string(b)
+		switch r.Method {
+		case MethodPropFind, MethodCopy:
+			w.Header().Set(hdrContentTypeKey, "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = io.WriteString(w, webDAVTestMultiStatus)
+		case MethodLock:
+			w.Header().Set(hdrLockTokenKey, "<opaquelocktoken:1234>")
+			w.WriteHeader(http.StatusOK)
+		case MethodMkcol:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	t.Cleanup(ts.Close)
+	return ts.URL, lastReq, lastBody
+}
+
+func TestWebDAVPropFind(t *testing.T) {
+	baseURL, lastReq, lastBody := createWebDAVTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	body := `<d:propfind xmlns:d="DAV:"><d:allprop/></d:propfind>`
+	res, err := c.R().
+		SetDepth(DepthOne).
+		SetHeader(hdrContentTypeKey, "application/xml").
+		SetBody(body).
+		SetResult(&MultiStatus{}).
+		PropFind("/dav/files/user/")
+	assertNil(t, err)
+	assertEqual(t, MethodPropFind, lastReq.Method)
+	assertEqual(t, DepthOne, lastReq.Header.Get(hdrDepthKey))
+	assertEqual(t, body, *lastBody)
+
+	ms, err := res.MultiStatus()
+	assertNil(t, err)
+	assertEqual(t, res.Result(), ms)
+	assertEqual(t, 3, len(ms.Responses))
+
+	dir := ms.Responses[0]
+	assertEqual(t, "/dav/files/user/", dir.Href[0])
+	assertEqual(t, http.StatusOK, dir.StatusCode())
+	assertEqual(t, true, dir.Prop().IsCollection())
+	assertEqual(t, "user", dir.Prop().DisplayName)
+
+	file := ms.Responses[1]
+	assertEqual(t, http.StatusOK, file.StatusCode())
+	assertEqual(t, http.StatusNotFound, file.PropStats[0].StatusCode())
+	prop := file.Prop()
+	assertEqual(t, false, prop.IsCollection())
+	assertEqual(t, int64(1024), prop.ContentLength)
+	assertEqual(t, "application/pdf", prop.ContentType)
+	assertEqual(t, `"abc"`, prop.ETag)
+	lm, err := prop.LastModifiedTime()
+	assertNil(t, err)
+	assertEqual(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), lm)
+
+	locked := ms.Responses[2]
+	assertEqual(t, http.StatusLocked, locked.StatusCode())
+	assertEqual(t, "", locked.Prop().DisplayName)
+}
+
+func TestWebDAVCopyMove(t *testing.T) {
+	baseURL, lastReq, _ := createWebDAVTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	// multi-status parsed from the body
+	res, err := c.R().
+		SetDestination(baseURL + "/dav/archive/").
+		SetOverwrite(false).
+		SetDepth(DepthInfinity).
+		Copy("/dav/docs/")
+	assertNil(t, err)
+	assertEqual(t, MethodCopy, lastReq.Method)
+	assertEqual(t, baseURL+"/dav/archive/", lastReq.Header.Get(hdrDestinationKey))
+	assertEqual(t, "F", lastReq.Header.Get(hdrOverwriteKey))
+	assertEqual(t, DepthInfinity, lastReq.Header.Get(hdrDepthKey))
+
+	ms, err := res.MultiStatus()
+	assertNil(t, err)
+	assertEqual(t, 3, len(ms.Responses))
+
+	res, err = c.R().
+		SetDestination(baseURL + "/dav/new.pdf").
+		SetOverwrite(true).
+		Move("/dav/old.pdf")
+	assertNil(t, err)
+	assertEqual(t, MethodMove, lastReq.Method)
+	assertEqual(t, "T", lastReq.Header.Get(hdrOverwriteKey))
+
+	_, err = res.MultiStatus()
+	assertErrorIs(t, ErrNotMultiStatus, err)
+}
+
+func TestWebDAVMkcolLock(t *testing.T) {
+	baseURL, lastReq, lastBody := createWebDAVTestServer(t)
+	c := dcnl().SetBaseURL(baseURL)
+
+	res, err := c.R().Mkcol("/dav/new/")
+	assertNil(t, err)
+	assertEqual(t, MethodMkcol, lastReq.Method)
+	assertEqual(t, http.StatusCreated, res.StatusCode())
+
+	body := `<d:lockinfo xmlns:d="DAV:"><d:lockscope><d:exclusive/></d:lockscope></d:lockinfo>`
+	res, err = c.R().SetLockTimeout(10 * time.Minute).SetBody(body).Lock("/dav/file")
+	assertNil(t, err)
+	assertEqual(t, "Second-600", lastReq.Header.Get(hdrTimeoutKey))
+	assertEqual(t, body, *lastBody)
+	token := res.Header().Get(hdrLockTokenKey)
+
+	_, err = c.R().SetLockToken(token).SetBody("<d:propertyupdate/>").PropPatch("/dav/file")
+	assertNil(t, err)
+	assertEqual(t, MethodPropPatch, lastReq.Method)
+	assertEqual(t, "(<opaquelocktoken:1234>)", lastReq.Header.Get(hdrIfKey))
+	assertEqual(t, "<d:propertyupdate/>", *lastBody)
+
+	_, err = c.R().Unlock("/dav/file", token)
+	assertNil(t, err)
+	assertEqual(t, MethodUnlock, lastReq.Method)
+	assertEqual(t, "<opaquelocktoken:1234>", lastReq.Header.Get(hdrLockTokenKey))
+
+	_, err = c.R().SetLockTimeout(0).Lock("/dav/file")
+	assertNil(t, err)
+	assertEqual(t, "Infinite", lastReq.Header.Get(hdrTimeoutKey))
+}